@@ -0,0 +1,141 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// The relative tolerances that AlmostEqual uses when no epsilon is
+// given. They leave room for a few rounding errors in the least
+// significant bits of each size of float.
+const (
+	float64Tolerance = 1e-9
+	float32Tolerance = 1e-6
+)
+
+type almostEqual struct{}
+
+// AlmostEqual checker checks that the obtained float is within a
+// tolerance of the expected one. An optional epsilon after the expected
+// value is the largest difference allowed between them. Without one the
+// difference may be at most a relative tolerance of the larger of the
+// two values, 1e-9 for float64 values or 1e-6 if either is a float32,
+// so an epsilon is needed to compare values with zero. Slices and arrays
+// of floats are compared element by element.
+//
+//	t.Check(0.1+0.2, checkers.AlmostEqual, 0.3)
+//	t.Check(mean, checkers.AlmostEqual, 2.5, 0.01)
+//	t.Check(weights, checkers.AlmostEqual, []float64{0.25, 0.75})
+var AlmostEqual Checker = almostEqual{}
+
+func (almostEqual) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if len(extras) == 0 {
+		return errors.New("missing 'expected' value")
+	}
+	expected, extras := extras[0], extras[1:]
+	epsilon := -1.0
+	if len(extras) > 0 {
+		v := reflect.ValueOf(extras[0])
+		switch kindFamily(v.Kind()) {
+		case reflect.Float64:
+			epsilon = v.Float()
+		case reflect.Int:
+			epsilon = float64(v.Int())
+		case reflect.Uint:
+			epsilon = float64(v.Uint())
+		}
+		if !(epsilon >= 0) {
+			return fmt.Errorf("epsilon must be a non-negative number, got %s", describe(extras[0]))
+		}
+		extras = extras[1:]
+	}
+	if err := unexpectedExtras("AlmostEqual", extras); err != nil {
+		return err
+	}
+	value := reflect.ValueOf(obtained)
+	exValue := reflect.ValueOf(expected)
+	if isFloat(value) {
+		if !isFloat(exValue) {
+			return fmt.Errorf("expected value must be a float, got %s", describeType(expected))
+		}
+		if d, ok := floatsDiffer(value, exValue, epsilon); ok {
+			return failf("obtained %v differs from expected %v by %v, more than %s", display(obtained), display(expected), d, describeTolerance(value, exValue, epsilon))
+		}
+		return nil
+	}
+	if !isFloats(value) {
+		return fmt.Errorf("AlmostEqual checker expected a float or a slice of floats, obtained was %s", describeType(obtained))
+	}
+	if !isFloats(exValue) {
+		return fmt.Errorf("expected value must be a slice of floats, got %s", describeType(expected))
+	}
+	if value.Len() != exValue.Len() {
+		return failf("obtained %d elements, expected %d; obtained %v; expected %v", value.Len(), exValue.Len(), display(obtained), display(expected))
+	}
+	var differences []string
+	for i := 0; i < value.Len(); i++ {
+		a, b := value.Index(i), exValue.Index(i)
+		if d, ok := floatsDiffer(a, b, epsilon); ok {
+			differences = append(differences, fmt.Sprintf("[%d]: obtained %v, expected %v, differing by %v", i, a, b, d))
+		}
+	}
+	switch len(differences) {
+	case 0:
+		return nil
+	case 1:
+		return failf("element %s, more than %s", differences[0], describeTolerance(value.Index(0), exValue.Index(0), epsilon))
+	}
+	return failf("%d elements differ by more than %s:\n\t%s", len(differences), describeTolerance(value.Index(0), exValue.Index(0), epsilon), strings.Join(differences, "\n\t"))
+}
+
+func isFloat(v reflect.Value) bool {
+	return v.Kind() == reflect.Float32 || v.Kind() == reflect.Float64
+}
+
+// isFloats reports whether the value is a slice or array of floats.
+func isFloats(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		k := v.Type().Elem().Kind()
+		return k == reflect.Float32 || k == reflect.Float64
+	}
+	return false
+}
+
+// floatsDiffer returns the difference between the floats, and whether
+// it is more than the epsilon, or than the relative tolerance for their
+// sizes if the epsilon is negative. Equal values never differ, so that
+// equal infinities are almost equal, and NaN always does.
+func floatsDiffer(a, b reflect.Value, epsilon float64) (float64, bool) {
+	x, y := a.Float(), b.Float()
+	if x == y {
+		return 0, false
+	}
+	d := math.Abs(x - y)
+	if epsilon < 0 {
+		epsilon = relativeTolerance(a, b) * math.Max(math.Abs(x), math.Abs(y))
+	}
+	return d, !(d <= epsilon)
+}
+
+func relativeTolerance(a, b reflect.Value) float64 {
+	if a.Kind() == reflect.Float32 || b.Kind() == reflect.Float32 {
+		return float32Tolerance
+	}
+	return float64Tolerance
+}
+
+func describeTolerance(a, b reflect.Value, epsilon float64) string {
+	if epsilon < 0 {
+		return fmt.Sprintf("the relative tolerance of %v", relativeTolerance(a, b))
+	}
+	return fmt.Sprintf("the epsilon of %v", epsilon)
+}
@@ -0,0 +1,130 @@
+// Add a copyright
+// Add a licence
+
+package checkers_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/howbazaar/checkers"
+)
+
+func TestAlmostEqual(t *testing.T) {
+	for _, test := range []struct {
+		description string
+		obtained    interface{}
+		extras      []interface{}
+		err         string
+	}{
+		{
+			description: "rounding error",
+			obtained:    0.1 + 0.2,
+			extras:      []interface{}{0.3},
+		}, {
+			description: "float32 against float64",
+			obtained:    float32(0.1),
+			extras:      []interface{}{0.1},
+		}, {
+			description: "beyond relative tolerance",
+			obtained:    1.0,
+			extras:      []interface{}{1.001},
+			err:         "obtained 1 differs from expected 1.001 by 0.0009999999999998899, more than the relative tolerance of 1e-09",
+		}, {
+			description: "zero needs an epsilon",
+			obtained:    1e-20,
+			extras:      []interface{}{0.0},
+			err:         "obtained 1e-20 differs from expected 0 by 1e-20, more than the relative tolerance of 1e-09",
+		}, {
+			description: "within epsilon",
+			obtained:    2.49,
+			extras:      []interface{}{2.5, 0.01},
+		}, {
+			description: "integer epsilon",
+			obtained:    float32(99.5),
+			extras:      []interface{}{100.0, 1},
+		}, {
+			description: "beyond epsilon",
+			obtained:    2.4,
+			extras:      []interface{}{2.5, 0.01, checkers.Commentf("mean")},
+			err:         "obtained 2.4 differs from expected 2.5 by 0.10000000000000009, more than the epsilon of 0.01\ncomment: mean",
+		}, {
+			description: "infinities",
+			obtained:    math.Inf(1),
+			extras:      []interface{}{math.Inf(1)},
+		}, {
+			description: "NaN",
+			obtained:    math.NaN(),
+			extras:      []interface{}{math.NaN(), 1.0},
+			err:         "obtained NaN differs from expected NaN by NaN, more than the epsilon of 1",
+		}, {
+			description: "slices",
+			obtained:    []float64{0.1 + 0.2, 0.25},
+			extras:      []interface{}{[2]float32{0.3, 0.25}},
+		}, {
+			description: "slice element differs",
+			obtained:    []float64{0.5, 0.25},
+			extras:      []interface{}{[]float64{0.5, 0.3}, 0.01},
+			err:         "element [1]: obtained 0.25, expected 0.3, differing by 0.04999999999999999, more than the epsilon of 0.01",
+		}, {
+			description: "slice elements differ",
+			obtained:    []float64{1, 2, 3},
+			extras:      []interface{}{[]float64{1.5, 2, 2.5}},
+			err: "2 elements differ by more than the relative tolerance of 1e-09:\n" +
+				"\t[0]: obtained 1, expected 1.5, differing by 0.5\n" +
+				"\t[2]: obtained 3, expected 2.5, differing by 0.5",
+		}, {
+			description: "slice lengths differ",
+			obtained:    []float64{1, 2},
+			extras:      []interface{}{[]float64{1}},
+			err:         "obtained 2 elements, expected 1; obtained [1 2]; expected [1]",
+		}, {
+			description: "negative epsilon",
+			obtained:    1.0,
+			extras:      []interface{}{1.0, -0.1},
+			err:         "epsilon must be a non-negative number, got float64 value -0.1",
+		}, {
+			description: "epsilon not a number",
+			obtained:    1.0,
+			extras:      []interface{}{1.0, "0.1"},
+			err:         `epsilon must be a non-negative number, got string value 0.1`,
+		}, {
+			description: "expected not a float",
+			obtained:    1.0,
+			extras:      []interface{}{1},
+			err:         "expected value must be a float, got type int",
+		}, {
+			description: "obtained not a float",
+			obtained:    []int{1},
+			extras:      []interface{}{[]float64{1}},
+			err:         "AlmostEqual checker expected a float or a slice of floats, obtained was type []int",
+		}, {
+			description: "expected not floats",
+			obtained:    []float64{1},
+			extras:      []interface{}{1.0},
+			err:         "expected value must be a slice of floats, got type float64",
+		}, {
+			description: "too many extras",
+			obtained:    1.0,
+			extras:      []interface{}{1.0, 0.1, 0.2},
+			err:         "too many arguments to checker AlmostEqual, unexpected float64(0.2)",
+		}, {
+			description: "missing expected",
+			obtained:    1.0,
+			err:         "missing 'expected' value",
+		},
+	} {
+		err := checkers.AlmostEqual.Check(test.obtained, test.extras...)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+		} else {
+			if test.err == "" {
+				t.Errorf("%s: unexpected error: %v", test.description, err)
+			} else if err.Error() != test.err {
+				t.Errorf("%s: error mismatch: \n\tobtained: %q\n\texpected: %q", test.description, err.Error(), test.err)
+			}
+		}
+	}
+}
@@ -0,0 +1,126 @@
+// Add a copyright
+// Add a licence
+
+// Package assert provides plain functions that check values with the
+// checkers of the checkers package, for tests that want the failure
+// descriptions of the checkers without using checkers.Test or suites.
+//
+//	func TestParse(t *testing.T) {
+//		v, err := Parse("1.2")
+//		assert.NoError(t, err)
+//		assert.Equal(t, v.Minor, 2)
+//	}
+//
+// Each function reports a failure to t with Error, so the test
+// continues, and returns whether the check passed, so that a test can
+// stop when later checks depend on it:
+//
+//	if !assert.NoError(t, err) {
+//		t.FailNow()
+//	}
+//
+// Any extra values, such as checkers.Comment values or options, are
+// passed on to the checker after the expected value.
+package assert
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/howbazaar/checkers"
+)
+
+// That checks the obtained value with the checker and extra values,
+// with checkers.Test.Check.
+func That(t testing.TB, obtained interface{}, checker checkers.Checker, extras ...interface{}) bool {
+	t.Helper()
+	return (&checkers.Test{TB: t}).Check(obtained, checker, extras...)
+}
+
+// Equal checks the obtained value with checkers.Equals.
+func Equal(t testing.TB, obtained, expected interface{}, extras ...interface{}) bool {
+	t.Helper()
+	return That(t, obtained, checkers.Equals, append([]interface{}{expected}, extras...)...)
+}
+
+// DeepEqual checks the obtained value with checkers.DeepEquals.
+func DeepEqual(t testing.TB, obtained, expected interface{}, extras ...interface{}) bool {
+	t.Helper()
+	return That(t, obtained, checkers.DeepEquals, append([]interface{}{expected}, extras...)...)
+}
+
+// Matches checks the obtained string, or Stringer, with
+// checkers.Matches.
+func Matches(t testing.TB, obtained interface{}, pattern string, extras ...interface{}) bool {
+	t.Helper()
+	return That(t, obtained, checkers.Matches, append([]interface{}{pattern}, extras...)...)
+}
+
+// HasLen checks the obtained value with checkers.HasLen.
+func HasLen(t testing.TB, obtained interface{}, n int, extras ...interface{}) bool {
+	t.Helper()
+	return That(t, obtained, checkers.HasLen, append([]interface{}{n}, extras...)...)
+}
+
+// IsNil checks the obtained value with checkers.IsNil.
+func IsNil(t testing.TB, obtained interface{}, extras ...interface{}) bool {
+	t.Helper()
+	return That(t, obtained, checkers.IsNil, extras...)
+}
+
+// IsTrue checks the obtained value with checkers.IsTrue.
+func IsTrue(t testing.TB, obtained interface{}, extras ...interface{}) bool {
+	t.Helper()
+	return That(t, obtained, checkers.IsTrue, extras...)
+}
+
+// IsFalse checks the obtained value with checkers.IsFalse.
+func IsFalse(t testing.TB, obtained interface{}, extras ...interface{}) bool {
+	t.Helper()
+	return That(t, obtained, checkers.IsFalse, extras...)
+}
+
+// NoError checks that err is nil.
+func NoError(t testing.TB, err error, extras ...interface{}) bool {
+	t.Helper()
+	if err != nil {
+		return fail(t, fmt.Sprintf("unexpected error: %v", err), extras)
+	}
+	return true
+}
+
+// ErrorMatches checks that err is not nil, and that its message matches
+// the pattern in full, as with checkers.Matches.
+func ErrorMatches(t testing.TB, err error, pattern string, extras ...interface{}) bool {
+	t.Helper()
+	if err == nil {
+		return fail(t, fmt.Sprintf("expected an error matching %q, got nil", pattern), extras)
+	}
+	return Matches(t, err.Error(), pattern, extras...)
+}
+
+// PanicMatches checks that f panics with a value that matches the
+// pattern, with checkers.PanicMatches.
+func PanicMatches(t testing.TB, f func(), pattern string, extras ...interface{}) bool {
+	t.Helper()
+	return That(t, f, checkers.PanicMatches, append([]interface{}{pattern}, extras...)...)
+}
+
+// fail reports the failure to t, with any comments in the extra values
+// added as the checkers add them.
+func fail(t testing.TB, message string, extras []interface{}) bool {
+	t.Helper()
+	return That(t, nil, failure(message), extras...)
+}
+
+// failure is a checker that always fails with its description, so that
+// failures found without a checker are reported as others are. Extra
+// values other than comments are ignored.
+type failure string
+
+func (f failure) Check(obtained interface{}, extras ...interface{}) (err error) {
+	_, comments := checkers.SplitComments(extras)
+	defer checkers.AddComments(&err, comments)
+	return errors.New(string(f))
+}
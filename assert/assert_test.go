@@ -0,0 +1,102 @@
+// Add a copyright
+// Add a licence
+
+package assert_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/howbazaar/checkers"
+	"github.com/howbazaar/checkers/assert"
+)
+
+func TestAssertions(t *testing.T) {
+	for _, test := range []struct {
+		description string
+		assert      func(t testing.TB) bool
+		err         string
+	}{
+		{
+			description: "equal",
+			assert:      func(t testing.TB) bool { return assert.Equal(t, 1, 1) },
+		}, {
+			description: "not equal",
+			assert:      func(t testing.TB) bool { return assert.Equal(t, 1, 2) },
+			err:         "expected int value 2, got 1",
+		}, {
+			description: "not equal with comment",
+			assert: func(t testing.TB) bool {
+				return assert.Equal(t, 1, 2, checkers.Commentf("case %d", 3))
+			},
+			err: "expected int value 2, got 1\ncomment: case 3",
+		}, {
+			description: "deep equal",
+			assert:      func(t testing.TB) bool { return assert.DeepEqual(t, []int{1}, []int{1}) },
+		}, {
+			description: "not deep equal",
+			assert:      func(t testing.TB) bool { return assert.DeepEqual(t, []int{1}, []int{2}) },
+			err:         "mismatch at [0]: unequal; obtained 1; expected 2\ndiff (-obtained +expected):\n []int{\n-\t1,\n+\t2,\n }",
+		}, {
+			description: "matches",
+			assert:      func(t testing.TB) bool { return assert.Matches(t, "abc", "a.c") },
+		}, {
+			description: "has len",
+			assert:      func(t testing.TB) bool { return assert.HasLen(t, "abc", 2) },
+			err:         "expected length 2, obtained 3",
+		}, {
+			description: "is nil",
+			assert:      func(t testing.TB) bool { return assert.IsNil(t, nil) },
+		}, {
+			description: "is true",
+			assert:      func(t testing.TB) bool { return assert.IsTrue(t, true) },
+		}, {
+			description: "is false",
+			assert:      func(t testing.TB) bool { return assert.IsFalse(t, false) },
+		}, {
+			description: "no error",
+			assert:      func(t testing.TB) bool { return assert.NoError(t, nil) },
+		}, {
+			description: "unexpected error",
+			assert: func(t testing.TB) bool {
+				return assert.NoError(t, errors.New("boom"), checkers.Commentf("opening"))
+			},
+			err: "unexpected error: boom\ncomment: opening",
+		}, {
+			description: "error matches",
+			assert:      func(t testing.TB) bool { return assert.ErrorMatches(t, errors.New("no such file"), "no such .*") },
+		}, {
+			description: "error does not match",
+			assert:      func(t testing.TB) bool { return assert.ErrorMatches(t, errors.New("denied"), "no such .*") },
+			err:         `"denied" did not match pattern "^no such .*$"`,
+		}, {
+			description: "no error to match",
+			assert:      func(t testing.TB) bool { return assert.ErrorMatches(t, nil, "no such .*") },
+			err:         `expected an error matching "no such .*", got nil`,
+		}, {
+			description: "panic matches",
+			assert:      func(t testing.TB) bool { return assert.PanicMatches(t, func() { panic("oops") }, "oops") },
+		}, {
+			description: "that",
+			assert: func(t testing.TB) bool {
+				return assert.That(t, 2, checkers.EqualsNumeric, 2.0)
+			},
+		},
+	} {
+		var passed bool
+		r := checkers.NewRecordingTB()
+		r.Run(func(tb testing.TB) {
+			passed = test.assert(tb)
+		})
+		if passed != (test.err == "") {
+			t.Errorf("%s: unexpected result %v", test.description, passed)
+		}
+		var expected []string
+		if test.err != "" {
+			expected = []string{test.err}
+		}
+		if err := checkers.DeepEquals.Check(r.Errors(), expected); err != nil {
+			t.Errorf("%s: %v", test.description, err)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// assertionCounts holds the number of checks made with Test.Check and
+// Test.Assert for each test, from its first check or call to
+// RequireAssertions until it is cleaned up.
+var assertionCounts = struct {
+	sync.Mutex
+	byTB map[testing.TB]int
+}{byTB: make(map[testing.TB]int)}
+
+// trackAssertions starts counting the checks made for tb, if they are
+// not already counted, and reports whether they can be. It must be
+// called with assertionCounts locked.
+func trackAssertions(tb testing.TB) bool {
+	if !reflect.TypeOf(tb).Comparable() {
+		return false
+	}
+	if _, ok := assertionCounts.byTB[tb]; !ok {
+		assertionCounts.byTB[tb] = 0
+		tb.Cleanup(func() {
+			assertionCounts.Lock()
+			defer assertionCounts.Unlock()
+			delete(assertionCounts.byTB, tb)
+		})
+	}
+	return true
+}
+
+// countAssertion adds one to the number of checks made for tb.
+func countAssertion(tb testing.TB) {
+	assertionCounts.Lock()
+	defer assertionCounts.Unlock()
+	if trackAssertions(tb) {
+		assertionCounts.byTB[tb]++
+	}
+}
+
+// Assertions returns the number of checks made so far by the test with
+// Check and Assert, whether they passed or not. Checks made through
+// another Test wrapping the same testing.TB, such as those made by the
+// assert and gccompat packages, are included.
+func (t *Test) Assertions() int {
+	assertionCounts.Lock()
+	defer assertionCounts.Unlock()
+	return assertionCounts.byTB[t.testingTB()]
+}
+
+// RequireAssertions has the test fail when it is cleaned up if it has
+// made fewer than n checks with Check and Assert, to catch tests that
+// have silently stopped checking anything, such as when a refactor
+// leaves a loop over no cases. Tests that have already failed or been
+// skipped are not failed again.
+func (t *Test) RequireAssertions(n int) {
+	tb := t.testingTB()
+	tb.Helper()
+	assertionCounts.Lock()
+	ok := trackAssertions(tb)
+	assertionCounts.Unlock()
+	if !ok {
+		tb.Fatalf("cannot count assertions made with a testing.TB of type %T", tb)
+		return
+	}
+	// The count is removed by a cleanup registered before this one, so
+	// is still there when this runs.
+	tb.Cleanup(func() {
+		if tb.Failed() || tb.Skipped() {
+			return
+		}
+		if made := t.Assertions(); made < n {
+			tb.Errorf("test made %d assertions, expected at least %d", made, n)
+		}
+	})
+}
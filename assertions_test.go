@@ -0,0 +1,78 @@
+// Add a copyright
+// Add a licence
+
+package checkers_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/howbazaar/checkers"
+	"github.com/howbazaar/checkers/assert"
+)
+
+func TestAssertions(t *testing.T) {
+	r := checkers.NewRecordingTB()
+	var counts []int
+	r.Run(func(tb testing.TB) {
+		ct := &checkers.Test{TB: tb}
+		counts = append(counts, ct.Assertions())
+		ct.Check(1, checkers.Equals, 1)
+		ct.Check(1, checkers.Equals, 2)
+		counts = append(counts, ct.Assertions())
+		assert.Equal(tb, 1, 1)
+		counts = append(counts, (&checkers.Test{TB: tb}).Assertions())
+	})
+	if fmt.Sprint(counts) != "[0 2 3]" {
+		t.Errorf("unexpected counts: %v", counts)
+	}
+	if n := (&checkers.Test{TB: r}).Assertions(); n != 0 {
+		t.Errorf("count not removed at cleanup, got %d", n)
+	}
+}
+
+func TestRequireAssertions(t *testing.T) {
+	for _, test := range []struct {
+		description string
+		test        func(ct *checkers.Test)
+		errors      string
+	}{
+		{
+			description: "enough",
+			test: func(ct *checkers.Test) {
+				ct.RequireAssertions(1)
+				ct.Check(true, checkers.IsTrue)
+			},
+			errors: "[]",
+		}, {
+			description: "checks before requiring count",
+			test: func(ct *checkers.Test) {
+				ct.Check(true, checkers.IsTrue)
+				ct.RequireAssertions(2)
+				ct.Check(false, checkers.IsFalse)
+			},
+			errors: "[]",
+		}, {
+			description: "none",
+			test: func(ct *checkers.Test) {
+				ct.RequireAssertions(1)
+			},
+			errors: "[test made 0 assertions, expected at least 1]",
+		}, {
+			description: "already failed",
+			test: func(ct *checkers.Test) {
+				ct.RequireAssertions(1)
+				ct.TB.Fatal("setting up")
+			},
+			errors: "[setting up]",
+		},
+	} {
+		r := checkers.NewRecordingTB()
+		r.Run(func(tb testing.TB) {
+			test.test(&checkers.Test{TB: tb})
+		})
+		if fmt.Sprint(r.Errors()) != test.errors {
+			t.Errorf("%s: unexpected errors: %q", test.description, r.Errors())
+		}
+	}
+}
@@ -0,0 +1,173 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// callArgs checks that the function can be called with the arguments,
+// returning them as the values to call it with. Numbers are converted
+// to the type of their parameter when they can be without changing
+// their value, so that untyped constants can be passed for any number
+// parameter, and nil is accepted for any parameter that can be
+// nil.
+func callArgs(f reflect.Value, args []interface{}) ([]reflect.Value, error) {
+	t := f.Type()
+	n := t.NumIn()
+	if t.IsVariadic() {
+		if len(args) < n-1 {
+			return nil, fmt.Errorf("function takes at least %d arguments, got %d", n-1, len(args))
+		}
+	} else if len(args) != n {
+		return nil, fmt.Errorf("function takes %d arguments, got %d", n, len(args))
+	}
+	values := make([]reflect.Value, len(args))
+	for i, arg := range args {
+		var param reflect.Type
+		if t.IsVariadic() && i >= n-1 {
+			param = t.In(n - 1).Elem()
+		} else {
+			param = t.In(i)
+		}
+		v, ok := convertValue(arg, param)
+		if !ok {
+			return nil, fmt.Errorf("argument %d must be %s, got %s", i, param, describe(arg))
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+// convertValue returns the value as one of the type, as callArgs
+// converts arguments, reporting whether it could.
+func convertValue(value interface{}, typ reflect.Type) (reflect.Value, bool) {
+	if value == nil {
+		switch typ.Kind() {
+		case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Ptr, reflect.Slice, reflect.UnsafePointer:
+			return reflect.Zero(typ), true
+		}
+		return reflect.Value{}, false
+	}
+	v := reflect.ValueOf(value)
+	switch {
+	case v.Type().AssignableTo(typ):
+		return v, true
+	case isNumber(v.Kind()) && isNumber(typ.Kind()) && convertsExactly(v, typ):
+		return v.Convert(typ), true
+	}
+	return reflect.Value{}, false
+}
+
+// convertsExactly reports whether the number keeps its value when
+// converted to the type.
+func convertsExactly(v reflect.Value, t reflect.Type) bool {
+	return v.Convert(t).Convert(v.Type()).Interface() == v.Interface()
+}
+
+// isNumber reports whether values of the kind are numbers.
+func isNumber(kind reflect.Kind) bool {
+	switch kindFamily(kind) {
+	case reflect.Int, reflect.Uint, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+type panicsWhenCalled struct {
+	args []interface{}
+}
+
+// PanicsWhenCalled returns a checker that calls the obtained function
+// with the arguments, and matches the error or string it panics with
+// against the expected pattern as PanicMatches does. The function may
+// take any parameters, and the arguments are checked against them
+// before it is called.
+//
+//	t.Check(strings.Repeat, checkers.PanicsWhenCalled("x", -1), ".*negative Repeat count")
+func PanicsWhenCalled(args ...interface{}) Checker {
+	return panicsWhenCalled{args: args}
+}
+
+func (c panicsWhenCalled) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if len(extras) == 0 {
+		return errors.New("missing 'expected' value")
+	}
+	expected, extras := extras[0], extras[1:]
+	if err := unexpectedExtras("PanicsWhenCalled", extras); err != nil {
+		return err
+	}
+	pattern, ok := expected.(string)
+	if !ok {
+		return errors.New("expected value must be a string containing a regexp pattern")
+	}
+	re, err := compilePattern(pattern)
+	if err != nil {
+		return err
+	}
+	f := reflect.ValueOf(obtained)
+	if f.Kind() != reflect.Func || f.IsNil() {
+		return fmt.Errorf("PanicsWhenCalled checker expected a function, obtained was %s", describe(obtained))
+	}
+	args, err := callArgs(f, c.args)
+	if err != nil {
+		return err
+	}
+	return panicMatch(f, args, re)
+}
+
+type returnsError struct {
+	args []interface{}
+}
+
+// ReturnsError returns a checker that calls the obtained function with
+// the arguments, and checks the error it returns as its last result
+// with the checker that is the expected value, given the extra values
+// that follow it.
+//
+//	conn.Close()
+//	t.Check(conn.Close, checkers.ReturnsError(), checkers.ErrorChainMatches, []interface{}{ErrClosed})
+//	t.Check(os.Open, checkers.ReturnsError("missing"), checkers.IsNil)
+func ReturnsError(args ...interface{}) Checker {
+	return returnsError{args: args}
+}
+
+func (c returnsError) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if len(extras) == 0 {
+		return errors.New("missing 'checker' value")
+	}
+	checker, ok := extras[0].(Checker)
+	if !ok {
+		return fmt.Errorf("expected value must be a Checker, got %s", describeType(extras[0]))
+	}
+	extras = extras[1:]
+	f := reflect.ValueOf(obtained)
+	if f.Kind() != reflect.Func || f.IsNil() {
+		return fmt.Errorf("ReturnsError checker expected a function, obtained was %s", describe(obtained))
+	}
+	t := f.Type()
+	if t.NumOut() == 0 || t.Out(t.NumOut()-1) != errorType {
+		return fmt.Errorf("function must return an error as its last result, has type %s", t)
+	}
+	args, err := callArgs(f, c.args)
+	if err != nil {
+		return err
+	}
+	results := f.Call(args)
+	returned, _ := results[len(results)-1].Interface().(error)
+	if err := checker.Check(returned, extras...); err != nil {
+		return lazyFailure(func() string {
+			return fmt.Sprintf("function returned %s: %v", describe(returned), err)
+		})
+	}
+	return nil
+}
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
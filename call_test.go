@@ -0,0 +1,232 @@
+// Add a copyright
+// Add a licence
+
+package checkers_test
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/howbazaar/checkers"
+)
+
+func divide(a, b int64) int64 {
+	return a / b
+}
+
+func mustPositive(name string, values ...float64) {
+	for _, v := range values {
+		if v <= 0 {
+			panic(fmt.Errorf("%s: %v is not positive", name, v))
+		}
+	}
+}
+
+func TestPanicsWhenCalled(t *testing.T) {
+	for _, test := range []struct {
+		description string
+		obtained    interface{}
+		checker     checkers.Checker
+		expected    interface{}
+		err         string
+	}{
+		{
+			description: "converted arguments",
+			obtained:    divide,
+			checker:     checkers.PanicsWhenCalled(1, 0),
+			expected:    "runtime error: integer divide by zero",
+		}, {
+			description: "string panic",
+			obtained:    strings.Repeat,
+			checker:     checkers.PanicsWhenCalled("x", -1),
+			expected:    ".*negative Repeat count",
+		}, {
+			description: "variadic",
+			obtained:    mustPositive,
+			checker:     checkers.PanicsWhenCalled("sizes", 1, 2.5, -3),
+			expected:    "sizes: -3 is not positive",
+		}, {
+			description: "nil argument",
+			obtained:    func(err error) { panic(err) },
+			checker:     checkers.PanicsWhenCalled(nil),
+			expected:    ".*",
+			err:         "recovered panic value nil is not a string nor an error",
+		}, {
+			description: "no panic",
+			obtained:    divide,
+			checker:     checkers.PanicsWhenCalled(4, 2),
+			expected:    ".*",
+			err:         "no panic",
+		}, {
+			description: "mismatch",
+			obtained:    func(err error) { panic(err) },
+			checker:     checkers.PanicsWhenCalled(errors.New("bang")),
+			expected:    "boom",
+			err:         `"bang" did not match pattern "^boom$"`,
+		}, {
+			description: "too few arguments",
+			obtained:    divide,
+			checker:     checkers.PanicsWhenCalled(1),
+			expected:    ".*",
+			err:         "function takes 2 arguments, got 1",
+		}, {
+			description: "too few variadic arguments",
+			obtained:    mustPositive,
+			checker:     checkers.PanicsWhenCalled(),
+			expected:    ".*",
+			err:         "function takes at least 1 arguments, got 0",
+		}, {
+			description: "wrong argument type",
+			obtained:    divide,
+			checker:     checkers.PanicsWhenCalled(1, 2.5),
+			expected:    ".*",
+			err:         "argument 1 must be int64, got float64 value 2.5",
+		}, {
+			description: "number out of range",
+			obtained:    func(uint8) {},
+			checker:     checkers.PanicsWhenCalled(-1),
+			expected:    ".*",
+			err:         "argument 0 must be uint8, got int value -1",
+		}, {
+			description: "wrong variadic argument type",
+			obtained:    mustPositive,
+			checker:     checkers.PanicsWhenCalled("sizes", 1.5, "2"),
+			expected:    ".*",
+			err:         "argument 2 must be float64, got string value 2",
+		}, {
+			description: "nil for a value",
+			obtained:    divide,
+			checker:     checkers.PanicsWhenCalled(1, nil),
+			expected:    ".*",
+			err:         "argument 1 must be int64, got nil",
+		}, {
+			description: "not a function",
+			obtained:    42,
+			checker:     checkers.PanicsWhenCalled(),
+			expected:    ".*",
+			err:         "PanicsWhenCalled checker expected a function, obtained was int value 42",
+		}, {
+			description: "invalid pattern",
+			obtained:    divide,
+			checker:     checkers.PanicsWhenCalled(1, 0),
+			expected:    "(",
+			err:         `invalid regexp pattern "(": missing closing ): "(" at position 0`,
+		},
+	} {
+		err := test.checker.Check(test.obtained, test.expected)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+		} else {
+			if test.err == "" {
+				t.Errorf("%s: unexpected error: %v", test.description, err)
+			} else {
+				if err.Error() != test.err {
+					t.Errorf("%s: error mismatch: \n\tobtained: %q\n\texpected: %q", test.description, err.Error(), test.err)
+				}
+			}
+		}
+	}
+}
+
+var errClosed = errors.New("closed")
+
+type closer struct {
+	closed bool
+}
+
+func (c *closer) Close() error {
+	if c.closed {
+		return errClosed
+	}
+	c.closed = true
+	return nil
+}
+
+func TestReturnsError(t *testing.T) {
+	c := &closer{}
+	for _, test := range []struct {
+		description string
+		obtained    interface{}
+		checker     checkers.Checker
+		extras      []interface{}
+		err         string
+	}{
+		{
+			description: "first close",
+			obtained:    c.Close,
+			checker:     checkers.ReturnsError(),
+			extras:      []interface{}{checkers.IsNil},
+		}, {
+			description: "second close",
+			obtained:    c.Close,
+			checker:     checkers.ReturnsError(),
+			extras:      []interface{}{checkers.ErrorChainMatches, []interface{}{errClosed}},
+		}, {
+			description: "with arguments and other results",
+			obtained:    strconv.Atoi,
+			checker:     checkers.ReturnsError("x"),
+			extras:      []interface{}{checkers.ErrorChainMatches, []interface{}{`strconv.Atoi: parsing "x": .*`, strconv.ErrSyntax}},
+		}, {
+			description: "nested failure",
+			obtained:    c.Close,
+			checker:     checkers.ReturnsError(),
+			extras:      []interface{}{checkers.IsNil},
+			err:         "function returned *errors.errorString value closed: obtained value is non-nil",
+		}, {
+			description: "nested failure for nil",
+			obtained:    strconv.Atoi,
+			checker:     checkers.ReturnsError("1"),
+			extras:      []interface{}{checkers.Matches, ".*"},
+			err:         "function returned nil: nil is neither a string nor has a 'String() string' method",
+		}, {
+			description: "no error result",
+			obtained:    divide,
+			checker:     checkers.ReturnsError(1, 1),
+			extras:      []interface{}{checkers.IsNil},
+			err:         "function must return an error as its last result, has type func(int64, int64) int64",
+		}, {
+			description: "bad arguments",
+			obtained:    strconv.Atoi,
+			checker:     checkers.ReturnsError(),
+			extras:      []interface{}{checkers.IsNil},
+			err:         "function takes 1 arguments, got 0",
+		}, {
+			description: "not a checker",
+			obtained:    c.Close,
+			checker:     checkers.ReturnsError(),
+			extras:      []interface{}{errClosed},
+			err:         "expected value must be a Checker, got type *errors.errorString",
+		}, {
+			description: "missing checker",
+			obtained:    c.Close,
+			checker:     checkers.ReturnsError(),
+			err:         "missing 'checker' value",
+		}, {
+			description: "not a function",
+			obtained:    errClosed,
+			checker:     checkers.ReturnsError(),
+			extras:      []interface{}{checkers.IsNil},
+			err:         "ReturnsError checker expected a function, obtained was *errors.errorString value closed",
+		},
+	} {
+		err := test.checker.Check(test.obtained, test.extras...)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+		} else {
+			if test.err == "" {
+				t.Errorf("%s: unexpected error: %v", test.description, err)
+			} else {
+				if err.Error() != test.err {
+					t.Errorf("%s: error mismatch: \n\tobtained: %q\n\texpected: %q", test.description, err.Error(), test.err)
+				}
+			}
+		}
+	}
+}
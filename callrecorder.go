@@ -0,0 +1,114 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"fmt"
+	"sync"
+)
+
+// CallRecorder is implemented by test doubles that record the calls
+// made to their methods, so that ReceivedCall can check them. Stubs
+// generated by tools such as gomock, moq or counterfeiter keep their
+// own records of calls, which a small adapter can return as Calls, and
+// hand-written ones can embed a CallLog.
+type CallRecorder interface {
+	// RecordedCalls returns the calls made to the named method, in the
+	// order they were made.
+	RecordedCalls(method string) []Call
+}
+
+// CallRecorderFunc is a function that implements CallRecorder, for
+// adapting the records of other test doubles:
+//
+//	// With a stub generated by moq:
+//	recorder := checkers.CallRecorderFunc(func(method string) []checkers.Call {
+//		var calls []checkers.Call
+//		if method == "Send" {
+//			for _, c := range store.SendCalls() {
+//				calls = append(calls, checkers.Call{Args: []interface{}{c.To, c.Body}})
+//			}
+//		}
+//		return calls
+//	})
+type CallRecorderFunc func(method string) []Call
+
+// RecordedCalls returns f(method).
+func (f CallRecorderFunc) RecordedCalls(method string) []Call {
+	return f(method)
+}
+
+// CallLog is a CallRecorder for hand-written test doubles, which record
+// each call to their methods with Record. The zero value is ready to
+// use, and it is safe for concurrent use.
+//
+//	type fakeMailer struct {
+//		checkers.CallLog
+//	}
+//
+//	func (m *fakeMailer) Send(to, body string) error {
+//		m.Record("Send", to, body)
+//		return nil
+//	}
+type CallLog struct {
+	mu    sync.Mutex
+	calls map[string][]Call
+}
+
+// Record records a call to the method with the arguments.
+func (l *CallLog) Record(method string, args ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.calls == nil {
+		l.calls = make(map[string][]Call)
+	}
+	l.calls[method] = append(l.calls[method], Call{Args: args})
+}
+
+// RecordedCalls returns the calls recorded to the method.
+func (l *CallLog) RecordedCalls(method string) []Call {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]Call(nil), l.calls[method]...)
+}
+
+type receivedCall struct {
+	method string
+	args   []interface{}
+}
+
+// ReceivedCall returns a checker that checks that the obtained
+// CallRecorder has recorded a call to the method. If any args are
+// given, they must match the arguments of the call as with CalledWith,
+// one for each argument.
+//
+//	t.Check(mailer, checkers.ReceivedCall("Send", "alice@example.com", checkers.Not(checkers.IsZero)))
+//	t.Check(mailer, checkers.ReceivedCall("Close"))
+func ReceivedCall(method string, args ...interface{}) Checker {
+	return receivedCall{method: method, args: args}
+}
+
+func (c receivedCall) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if err := unexpectedExtras("ReceivedCall", extras); err != nil {
+		return err
+	}
+	recorder, ok := obtained.(CallRecorder)
+	if !ok || isTypedNil(obtained) {
+		return fmt.Errorf("ReceivedCall checker expected a CallRecorder, obtained was %s", describe(obtained))
+	}
+	calls := recorder.RecordedCalls(c.method)
+	for _, call := range calls {
+		if len(c.args) == 0 || argsMatch(call.Args, c.args) {
+			return nil
+		}
+	}
+	return lazyFailure(func() string {
+		if len(c.args) == 0 {
+			return fmt.Sprintf("no call to %s\n%s", c.method, describeCalls(calls))
+		}
+		return fmt.Sprintf("no call to %s with arguments %s\n%s", c.method, formatArgs(c.args), describeCalls(calls))
+	})
+}
@@ -0,0 +1,117 @@
+// Add a copyright
+// Add a licence
+
+package checkers_test
+
+import (
+	"testing"
+
+	"github.com/howbazaar/checkers"
+)
+
+type fakeMailer struct {
+	checkers.CallLog
+}
+
+func (m *fakeMailer) Send(to, body string) error {
+	m.Record("Send", to, body)
+	return nil
+}
+
+func (m *fakeMailer) Close() {
+	m.Record("Close")
+}
+
+// generatedMailer records calls as stub generators such as moq do.
+type generatedMailer struct {
+	sendCalls []struct{ To, Body string }
+}
+
+func (m *generatedMailer) Send(to, body string) error {
+	m.sendCalls = append(m.sendCalls, struct{ To, Body string }{to, body})
+	return nil
+}
+
+func TestReceivedCall(t *testing.T) {
+	mailer := &fakeMailer{}
+	mailer.Send("alice", "hello")
+	mailer.Send("bob", "")
+	mailer.Close()
+
+	generated := &generatedMailer{}
+	generated.Send("carol", "hi")
+	adapted := checkers.CallRecorderFunc(func(method string) []checkers.Call {
+		var calls []checkers.Call
+		if method == "Send" {
+			for _, call := range generated.sendCalls {
+				calls = append(calls, checkers.Call{Args: []interface{}{call.To, call.Body}})
+			}
+		}
+		return calls
+	})
+
+	for _, test := range []struct {
+		description string
+		checker     checkers.Checker
+		obtained    interface{}
+		extras      []interface{}
+		err         string
+	}{
+		{
+			description: "received",
+			checker:     checkers.ReceivedCall("Close"),
+			obtained:    mailer,
+		}, {
+			description: "received with arguments",
+			checker:     checkers.ReceivedCall("Send", "bob", ""),
+			obtained:    mailer,
+		}, {
+			description: "received with checkers",
+			checker:     checkers.ReceivedCall("Send", "alice", checkers.Not(checkers.IsZero)),
+			obtained:    mailer,
+		}, {
+			description: "not received with arguments",
+			checker:     checkers.ReceivedCall("Send", "bob", checkers.Not(checkers.IsZero)),
+			obtained:    mailer,
+			extras:      []interface{}{checkers.Commentf("empty body")},
+			err:         "no call to Send with arguments (\"bob\", checkers.not)\ncalls:\n\t(\"alice\", \"hello\")\n\t(\"bob\", \"\")\ncomment: empty body",
+		}, {
+			description: "not received",
+			checker:     checkers.ReceivedCall("Open"),
+			obtained:    mailer,
+			err:         "no call to Open\nno calls",
+		}, {
+			description: "adapted",
+			checker:     checkers.ReceivedCall("Send", "carol", "hi"),
+			obtained:    adapted,
+		}, {
+			description: "adapted not received",
+			checker:     checkers.ReceivedCall("Send", "dave", "hi"),
+			obtained:    adapted,
+			err:         "no call to Send with arguments (\"dave\", \"hi\")\ncalls:\n\t(\"carol\", \"hi\")",
+		}, {
+			description: "not a recorder",
+			checker:     checkers.ReceivedCall("Send"),
+			obtained:    "mailer",
+			err:         "ReceivedCall checker expected a CallRecorder, obtained was string value mailer",
+		}, {
+			description: "nil recorder",
+			checker:     checkers.ReceivedCall("Send"),
+			obtained:    (*fakeMailer)(nil),
+			err:         "ReceivedCall checker expected a CallRecorder, obtained was nil *checkers_test.fakeMailer",
+		},
+	} {
+		err := test.checker.Check(test.obtained, test.extras...)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+		} else {
+			if test.err == "" {
+				t.Errorf("%s: unexpected error: %v", test.description, err)
+			} else if err.Error() != test.err {
+				t.Errorf("%s: error mismatch: \n\tobtained: %q\n\texpected: %q", test.description, err.Error(), test.err)
+			}
+		}
+	}
+}
@@ -0,0 +1,112 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+type chanSize struct {
+	capacity bool
+}
+
+// ChanLenEquals checker checks that the obtained channel holds the
+// expected number of buffered elements, without receiving any of them.
+var ChanLenEquals Checker = chanSize{}
+
+// ChanCapEquals checker checks that the obtained channel has a buffer
+// of the expected capacity.
+var ChanCapEquals Checker = chanSize{capacity: true}
+
+func (c chanSize) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if len(extras) == 0 {
+		return errors.New("missing 'expected' value")
+	}
+	expected, extras := extras[0], extras[1:]
+	if err := unexpectedExtras(c.name(), extras); err != nil {
+		return err
+	}
+	sizeValue := reflect.ValueOf(expected)
+	if kindFamily(sizeValue.Kind()) != reflect.Int {
+		return fmt.Errorf("expected %s must be an int, got %s", c.measure(), describeType(expected))
+	}
+	size := sizeValue.Int()
+
+	value := reflect.ValueOf(obtained)
+	if value.Kind() != reflect.Chan {
+		return fmt.Errorf("%s checker expected a channel, obtained was %s", c.name(), describeType(obtained))
+	}
+	actual := value.Len()
+	if c.capacity {
+		actual = value.Cap()
+	}
+	if int64(actual) != size {
+		return failf("expected channel %s %d, obtained %d", c.measure(), size, actual)
+	}
+	return nil
+}
+
+func (c chanSize) name() string {
+	if c.capacity {
+		return "ChanCapEquals"
+	}
+	return "ChanLenEquals"
+}
+
+func (c chanSize) measure() string {
+	if c.capacity {
+		return "capacity"
+	}
+	return "length"
+}
+
+type chanReceives struct{}
+
+// ChanReceives checker receives a value from the obtained channel, and
+// checks that it deeply equals the expected value. It waits for a value
+// until a context.Context among the extra values, such as one from
+// Test.ContextWithTimeout, is done, or for five seconds without one.
+// A channel that is closed before a value is received fails the check.
+//
+//	t.Assert(results, checkers.ChanReceives, want, t.ContextWithTimeout(time.Second))
+var ChanReceives Checker = chanReceives{}
+
+func (chanReceives) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	extras, ctx := contextArg(extras)
+	if len(extras) == 0 {
+		return errors.New("missing 'expected' value")
+	}
+	expected, extras := extras[0], extras[1:]
+	if err := unexpectedExtras("ChanReceives", extras); err != nil {
+		return err
+	}
+	value := reflect.ValueOf(obtained)
+	if value.Kind() != reflect.Chan || value.Type().ChanDir()&reflect.RecvDir == 0 {
+		return fmt.Errorf("ChanReceives checker expected a channel to receive from, obtained was %s", describeType(obtained))
+	}
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), waitTimeout)
+		defer cancel()
+	}
+
+	chosen, received, ok := reflect.Select([]reflect.SelectCase{
+		{Dir: reflect.SelectRecv, Chan: value},
+		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())},
+	})
+	switch {
+	case chosen == 1:
+		return failf("no value received from channel: %v", ctx.Err())
+	case !ok:
+		return failf("channel closed without a value being received")
+	}
+	return DeepEquals.Check(received.Interface(), expected)
+}
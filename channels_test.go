@@ -0,0 +1,196 @@
+// Add a copyright
+// Add a licence
+
+package checkers_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/howbazaar/checkers"
+)
+
+func TestChannelCheckers(t *testing.T) {
+	buffered := make(chan int, 3)
+	buffered <- 1
+	buffered <- 2
+	var receiveOnly <-chan int = buffered
+	for _, test := range []struct {
+		description string
+		checker     checkers.Checker
+		obtained    interface{}
+		extras      []interface{}
+		err         string
+	}{
+		{
+			description: "length",
+			checker:     checkers.ChanLenEquals,
+			obtained:    buffered,
+			extras:      []interface{}{2},
+		}, {
+			description: "length of receive only channel",
+			checker:     checkers.ChanLenEquals,
+			obtained:    receiveOnly,
+			extras:      []interface{}{int64(2)},
+		}, {
+			description: "length of nil channel",
+			checker:     checkers.ChanLenEquals,
+			obtained:    (chan int)(nil),
+			extras:      []interface{}{0},
+		}, {
+			description: "length mismatch",
+			checker:     checkers.ChanLenEquals,
+			obtained:    buffered,
+			extras:      []interface{}{3},
+			err:         "expected channel length 3, obtained 2",
+		}, {
+			description: "capacity",
+			checker:     checkers.ChanCapEquals,
+			obtained:    buffered,
+			extras:      []interface{}{3},
+		}, {
+			description: "capacity of unbuffered channel",
+			checker:     checkers.ChanCapEquals,
+			obtained:    make(chan struct{}),
+			extras:      []interface{}{1},
+			err:         "expected channel capacity 1, obtained 0",
+		}, {
+			description: "not a channel",
+			checker:     checkers.ChanCapEquals,
+			obtained:    []int{1},
+			extras:      []interface{}{1},
+			err:         "ChanCapEquals checker expected a channel, obtained was type []int",
+		}, {
+			description: "not an int",
+			checker:     checkers.ChanLenEquals,
+			obtained:    buffered,
+			extras:      []interface{}{"2"},
+			err:         "expected length must be an int, got type string",
+		}, {
+			description: "too many arguments",
+			checker:     checkers.ChanLenEquals,
+			obtained:    buffered,
+			extras:      []interface{}{2, 3},
+			err:         "too many arguments to checker ChanLenEquals, unexpected int(3)",
+		},
+	} {
+		err := test.checker.Check(test.obtained, test.extras...)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+		} else {
+			if test.err == "" {
+				t.Errorf("%s: unexpected error: %v", test.description, err)
+			} else {
+				if err.Error() != test.err {
+					t.Errorf("%s: error mismatch: \n\tobtained: %q\n\texpected: %q", test.description, err.Error(), test.err)
+				}
+			}
+		}
+	}
+	if len(buffered) != 2 {
+		t.Errorf("checking drained the channel")
+	}
+}
+
+func TestChanReceives(t *testing.T) {
+	ready := func(values ...int) chan int {
+		c := make(chan int, len(values))
+		for _, v := range values {
+			c <- v
+		}
+		return c
+	}
+	pairs := make(chan []int, 1)
+	pairs <- []int{1, 2}
+	var receiveOnly <-chan []int = pairs
+	closed := make(chan int)
+	close(closed)
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, test := range []struct {
+		description string
+		obtained    interface{}
+		extras      []interface{}
+		err         string
+	}{
+		{
+			description: "received",
+			obtained:    ready(1),
+			extras:      []interface{}{1},
+		}, {
+			description: "received with context",
+			obtained:    ready(1),
+			extras:      []interface{}{1, context.Background()},
+		}, {
+			description: "received from receive only channel",
+			obtained:    receiveOnly,
+			extras:      []interface{}{[]int{1, 2}},
+		}, {
+			description: "mismatch",
+			obtained:    ready(2),
+			extras:      []interface{}{1},
+			err:         "mismatch at top level: unequal; obtained 2; expected 1",
+		}, {
+			description: "nothing received",
+			obtained:    make(chan int),
+			extras:      []interface{}{1, cancelled},
+			err:         "no value received from channel: context canceled",
+		}, {
+			description: "closed",
+			obtained:    closed,
+			extras:      []interface{}{1},
+			err:         "channel closed without a value being received",
+		}, {
+			description: "with comment",
+			obtained:    ready(2),
+			extras:      []interface{}{1, checkers.Commentf("first result")},
+			err:         "mismatch at top level: unequal; obtained 2; expected 1\ncomment: first result",
+		}, {
+			description: "send only channel",
+			obtained:    make(chan<- int),
+			extras:      []interface{}{1},
+			err:         "ChanReceives checker expected a channel to receive from, obtained was type chan<- int",
+		}, {
+			description: "not a channel",
+			obtained:    1,
+			extras:      []interface{}{1},
+			err:         "ChanReceives checker expected a channel to receive from, obtained was type int",
+		}, {
+			description: "missing expected",
+			obtained:    ready(1),
+			err:         "missing 'expected' value",
+		}, {
+			description: "too many arguments",
+			obtained:    ready(1),
+			extras:      []interface{}{1, 2},
+			err:         "too many arguments to checker ChanReceives, unexpected int(2)",
+		},
+	} {
+		err := checkers.ChanReceives.Check(test.obtained, test.extras...)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+		} else {
+			if test.err == "" {
+				t.Errorf("%s: unexpected error: %v", test.description, err)
+			} else {
+				if err.Error() != test.err {
+					t.Errorf("%s: error mismatch: \n\tobtained: %q\n\texpected: %q", test.description, err.Error(), test.err)
+				}
+			}
+		}
+	}
+}
+
+func TestChanReceivesWithTestContext(t *testing.T) {
+	ct := &checkers.Test{T: t}
+	results := make(chan string)
+	go func() {
+		results <- "done"
+	}()
+	ct.Check(results, checkers.ChanReceives, "done", ct.ContextWithTimeout(5*time.Second))
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"reflect"
 	"regexp"
+	"regexp/syntax"
 	"strings"
 )
 
@@ -22,34 +23,126 @@ type Checker interface {
 type isNil struct{}
 
 // IsNil checker will return an error if the obtained value is not nil.
+// The nil value of a pointer, map, slice, channel, function or
+// interface type is nil too, so a (*T)(nil) passed in as the obtained
+// value passes.
 var IsNil Checker = isNil{}
 
-func (isNil) Check(obtained interface{}, extras ...interface{}) error {
-	if obtained == nil {
+func (isNil) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if err := unexpectedExtras("IsNil", extras); err != nil {
+		return err
+	}
+	if obtained == nil || isTypedNil(obtained) {
 		return nil
 	}
 	return errors.New("obtained value is non-nil")
 }
 
-type equals struct{}
+type isNotNil struct{}
+
+// IsNotNil checker is the inverse of IsNil, returning an error if the
+// obtained value is nil, or the nil value of a type that can be nil.
+var IsNotNil Checker = isNotNil{}
+
+func (isNotNil) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if err := unexpectedExtras("IsNotNil", extras); err != nil {
+		return err
+	}
+	if obtained == nil || isTypedNil(obtained) {
+		return fmt.Errorf("expected a non-nil value, obtained %s", describe(obtained))
+	}
+	return nil
+}
+
+type isZero struct{}
+
+// IsZero checker checks that the obtained value is the zero value of
+// its type, as reflect.Value.IsZero reports: 0, "", false, a nil
+// pointer, map, slice, channel or function, or a struct or array whose
+// fields or elements are all zero. A nil interface value is zero too.
+// An empty slice or map that is not nil is not zero.
+//
+//	t.Check(cfg.Retry, checkers.IsZero)
+var IsZero Checker = isZero{}
+
+func (isZero) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if err := unexpectedExtras("IsZero", extras); err != nil {
+		return err
+	}
+	if obtained == nil || reflect.ValueOf(obtained).IsZero() {
+		return nil
+	}
+	return failf("expected the zero value of %T, obtained %s", obtained, describe(obtained))
+}
+
+type equals struct {
+	converted bool
+	numeric   bool
+}
 
-// Equals checker tests for equality.
+// Equals checker tests for equality of values of the same type.
 var Equals Checker = equals{}
 
+// EqualsConverted checker tests for equality like Equals, but allows
+// the values to be of different types of the same sort, comparing their
+// values as converted to the largest such type. Any two signed integers
+// may be compared, as may any two unsigned integers, floats, strings or
+// bools, including named types such as time.Duration.
+var EqualsConverted Checker = equals{converted: true}
+
+// EqualsNumeric checker tests for equality of two numbers of any kinds,
+// signed, unsigned or floating point, by their exact values. Unlike
+// EqualsConverted, neither value is converted to the type of the other,
+// so values that would overflow or lose precision in the conversion
+// are never taken to be equal. NaN is not equal to anything.
+var EqualsNumeric Checker = equals{numeric: true}
+
 // TODO: add describer interface, and pass failing values to the describers
 // in the checkers.
 
-func (equals) Check(obtained interface{}, extras ...interface{}) error {
+func (c equals) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
 	if len(extras) == 0 {
 		return errors.New("missing 'expected' value")
 	}
 	expected, extras := extras[0], extras[1:]
+	if err := unexpectedExtras(c.name(), extras); err != nil {
+		return err
+	}
+	if c.numeric {
+		return numericEquals(obtained, expected)
+	}
+	if equal, ok := fastEquals(obtained, expected); ok {
+		if equal {
+			return nil
+		}
+		return equalsFailure(obtained, expected)
+	}
+	if obtained == nil || expected == nil {
+		if obtained == expected {
+			return nil
+		}
+		return lazyFailure(func() string {
+			return fmt.Sprintf("expected %s, got %s", describe(expected), describe(obtained))
+		})
+	}
 	exValue := reflect.ValueOf(expected)
 	value := reflect.ValueOf(obtained)
-	if value.Kind() != exValue.Kind() {
+	if c.converted {
+		if kindFamily(value.Kind()) != kindFamily(exValue.Kind()) {
+			return fmt.Errorf("obtained type %T cannot be compared with expected type %T", obtained, expected)
+		}
+	} else if reflect.TypeOf(obtained) != reflect.TypeOf(expected) {
 		return fmt.Errorf("obtained type %T does not match expected type %T", obtained, expected)
 	}
-	switch value.Kind() {
+	switch kindFamily(value.Kind()) {
 	case reflect.Bool:
 		if value.Bool() == exValue.Bool() {
 			return nil
@@ -58,37 +151,97 @@ func (equals) Check(obtained interface{}, extras ...interface{}) error {
 		if value.String() == exValue.String() {
 			return nil
 		}
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+	case reflect.Int:
 		if value.Int() == exValue.Int() {
 			return nil
 		}
-	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+	case reflect.Uint:
 		if value.Uint() == exValue.Uint() {
 			return nil
 		}
-	case reflect.Float32, reflect.Float64:
+	case reflect.Float64:
 		if value.Float() == exValue.Float() {
 			return nil
 		}
 	default:
 		return fmt.Errorf("Equals checker does not support type %T", obtained)
 	}
-	return fmt.Errorf("expected %T value %v, got %v", expected, expected, obtained)
+	return equalsFailure(obtained, expected)
+}
+
+// equalsFailure describes unequal values, showing where strings first
+// differ.
+func equalsFailure(obtained, expected interface{}) error {
+	return lazyFailure(func() string {
+		message := fmt.Sprintf("expected %T value %v, got %v", expected, display(expected), display(obtained))
+		value, exValue := reflect.ValueOf(obtained), reflect.ValueOf(expected)
+		// Strings with their own printer are only shown as it renders
+		// them.
+		if value.Kind() == reflect.String && exValue.Kind() == reflect.String &&
+			!hasPrinter(value.Type()) && !hasPrinter(exValue.Type()) {
+			message += "\n" + stringDiff(value.String(), exValue.String())
+		}
+		return message
+	})
+}
+
+func (c equals) name() string {
+	switch {
+	case c.converted:
+		return "EqualsConverted"
+	case c.numeric:
+		return "EqualsNumeric"
+	}
+	return "Equals"
+}
+
+// kindFamily groups the kinds whose values Equals compares in the same
+// way, returning Int for all the signed integer kinds, Uint for the
+// unsigned ones and Float64 for the floats.
+func kindFamily(kind reflect.Kind) reflect.Kind {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return reflect.Int
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return reflect.Uint
+	case reflect.Float32, reflect.Float64:
+		return reflect.Float64
+	}
+	return kind
 }
 
 type deepEquals struct{}
 
-// DeepEquals checker tests for equality of complex types.
+// DeepEquals checker tests for equality of complex types. Any
+// DeepEqualOption values following the expected value alter how the
+// comparison is made. All the differences found are reported together,
+// up to the limit set by MaxMismatches, followed by a line based diff of
+// the two values when they are too large to show on a single line.
 var DeepEquals Checker = deepEquals{}
 
-func (deepEquals) Check(obtained interface{}, extras ...interface{}) error {
+func (deepEquals) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
 	if len(extras) == 0 {
 		return errors.New("missing 'expected' value")
 	}
 	expected, extras := extras[0], extras[1:]
+	var options []DeepEqualOption
+	for i, extra := range extras {
+		option, ok := extra.(DeepEqualOption)
+		if !ok {
+			return unexpectedExtras("DeepEquals", extras[i:])
+		}
+		options = append(options, option)
+	}
 
-	if ok, err := DeepEqual(obtained, expected); !ok {
-		return err
+	if ok, err := DeepEqualWithOptions(obtained, expected, options...); !ok {
+		return &diffError{
+			err:      err,
+			obtained: obtained,
+			expected: expected,
+			config:   newDeepEqualConfig(options),
+		}
 	}
 	return nil
 }
@@ -99,7 +252,12 @@ type isFalse struct{}
 // or if the bool value is true.
 var IsFalse Checker = isFalse{}
 
-func (isFalse) Check(obtained interface{}, extras ...interface{}) error {
+func (isFalse) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if err := unexpectedExtras("IsFalse", extras); err != nil {
+		return err
+	}
 	value := reflect.ValueOf(obtained)
 	switch value.Kind() {
 	case reflect.Bool:
@@ -107,7 +265,7 @@ func (isFalse) Check(obtained interface{}, extras ...interface{}) error {
 			return nil
 		}
 	default:
-		return fmt.Errorf("IsFalse checker expected bool, obtained was type %T", obtained)
+		return fmt.Errorf("IsFalse checker expected bool, obtained was %s", describeType(obtained))
 	}
 	return errors.New("obtained value is true")
 }
@@ -118,7 +276,12 @@ type isTrue struct{}
 // of if the bool value is false.
 var IsTrue Checker = isTrue{}
 
-func (isTrue) Check(obtained interface{}, extras ...interface{}) error {
+func (isTrue) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if err := unexpectedExtras("IsTrue", extras); err != nil {
+		return err
+	}
 	value := reflect.ValueOf(obtained)
 	switch value.Kind() {
 	case reflect.Bool:
@@ -126,7 +289,7 @@ func (isTrue) Check(obtained interface{}, extras ...interface{}) error {
 			return nil
 		}
 	default:
-		return fmt.Errorf("IsTrue checker expected bool, obtained was type %T", obtained)
+		return fmt.Errorf("IsTrue checker expected bool, obtained was %s", describeType(obtained))
 	}
 	return errors.New("obtained value is false")
 }
@@ -138,13 +301,21 @@ type hasLen struct{}
 // match the specified value.
 var HasLen Checker = hasLen{}
 
-func (hasLen) Check(obtained interface{}, extras ...interface{}) error {
+func (hasLen) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
 	if len(extras) == 0 {
 		return errors.New("missing 'expected' value")
 	}
 	expected, extras := extras[0], extras[1:]
-	// TODO: deal with panics of wrong types of unexpected types
-	size := reflect.ValueOf(expected).Int()
+	if err := unexpectedExtras("HasLen", extras); err != nil {
+		return err
+	}
+	sizeValue := reflect.ValueOf(expected)
+	if kindFamily(sizeValue.Kind()) != reflect.Int {
+		return fmt.Errorf("expected length must be an int, got %s", describeType(expected))
+	}
+	size := sizeValue.Int()
 
 	value := reflect.ValueOf(obtained)
 	var length int
@@ -152,11 +323,11 @@ func (hasLen) Check(obtained interface{}, extras ...interface{}) error {
 	case reflect.Array, reflect.Chan, reflect.Map, reflect.Slice, reflect.String:
 		length = value.Len()
 	default:
-		return fmt.Errorf("HasLen checker expected array, channel, map, slice or string, obtained was type %T", obtained)
+		return fmt.Errorf("HasLen checker expected array, channel, map, slice or string, obtained was %s", describeType(obtained))
 	}
 
 	if int64(length) != size {
-		return fmt.Errorf("expected length %d, obtained %d", size, length)
+		return failf("expected length %d, obtained %d", size, length)
 	}
 
 	return nil
@@ -167,42 +338,134 @@ type matches struct{}
 // Matches checker will use regex to match against a string, or Stringer.
 var Matches Checker = matches{}
 
-func (matches) Check(obtained interface{}, extras ...interface{}) error {
+func (matches) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
 	if len(extras) == 0 {
 		return errors.New("missing 'expected' value")
 	}
 	expected, extras := extras[0], extras[1:]
+	if err := unexpectedExtras("Matches", extras); err != nil {
+		return err
+	}
 	pattern, ok := expected.(string)
 	if !ok {
 		return errors.New("expected value must be a string containing a regexp pattern")
 	}
-	var value string
+	// Check the pattern first, so that mistakes in it are reported
+	// rather than whatever is wrong with the obtained value.
+	re, err := compilePattern(pattern)
+	if err != nil {
+		return err
+	}
+	value, err := matchable(obtained)
+	if err != nil {
+		return err
+	}
+
+	return matchRegexp(value, re)
+}
+
+type matchesRegexp struct {
+	re *regexp.Regexp
+}
+
+// MatchesRegexp returns a checker that matches a string, or Stringer,
+// against the precompiled regexp. Unlike Matches, the pattern is used
+// as it is, so it needs to be anchored with ^ and $ to match the whole
+// string.
+func MatchesRegexp(re *regexp.Regexp) Checker {
+	return matchesRegexp{re}
+}
+
+func (c matchesRegexp) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if err := unexpectedExtras("MatchesRegexp", extras); err != nil {
+		return err
+	}
+	value, err := matchable(obtained)
+	if err != nil {
+		return err
+	}
+	return matchRegexp(value, c.re)
+}
+
+// matchable returns the string to match a pattern against.
+func matchable(obtained interface{}) (string, error) {
 	if s, ok := obtained.(string); ok {
-		value = s
+		return s, nil
 	} else if s, ok := obtained.(fmt.Stringer); ok {
-		value = s.String()
-	} else {
-		return fmt.Errorf("%T(%#v) is neither a string nor has a 'String() string' method", obtained, obtained)
+		return safeString(s)
+	}
+	if obtained == nil {
+		return "", errors.New("nil is neither a string nor has a 'String() string' method")
 	}
+	return "", fmt.Errorf("%T(%#v) is neither a string nor has a 'String() string' method", obtained, obtained)
+}
 
-	return checkMatch(value, pattern)
+// safeString calls the String method of the value. Values that are only
+// partly constructed can have String methods that panic, so a panic is
+// recovered and reported as a failure rather than ending the test.
+func safeString(s fmt.Stringer) (str string, err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = failf("String method of %T panicked: %v", s, v)
+		}
+	}()
+	return s.String(), nil
 }
 
-func checkMatch(obtained, pattern string) error {
-	if !strings.HasPrefix(pattern, "^") {
-		pattern = "^" + pattern
+// compilePattern anchors the pattern to match whole strings and
+// compiles it. A pattern that fails to compile is described in terms of
+// the pattern as given, with the position of the problem in it.
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	anchored := pattern
+	prefix := 0
+	if !strings.HasPrefix(anchored, "^") {
+		anchored = "^" + anchored
+		prefix = 1
 	}
-	if !strings.HasSuffix(pattern, "$") {
-		pattern = pattern + "$"
+	if !strings.HasSuffix(anchored, "$") {
+		anchored = anchored + "$"
 	}
-	ok, err := regexp.MatchString(pattern, obtained)
-	if err != nil {
-		return fmt.Errorf("unable to compile regexp: %v", err)
+	if trailing := len(pattern) - len(strings.TrimRight(pattern, `\`)); trailing%2 == 1 {
+		// Anchoring would escape the "$" rather than report the
+		// unfinished escape.
+		return nil, fmt.Errorf("invalid regexp pattern %q: %s at position %d",
+			pattern, syntax.ErrTrailingBackslash, len(pattern)-1)
+	}
+	re, err := compiledRegexps.compile(anchored)
+	if err == nil {
+		return re, nil
+	}
+	syntaxErr, ok := err.(*syntax.Error)
+	if !ok {
+		return nil, fmt.Errorf("invalid regexp pattern %q: %v", pattern, err)
+	}
+	// The expression in error is part of the anchored pattern, so
+	// map it back on to the pattern as given.
+	start := strings.Index(anchored, syntaxErr.Expr)
+	if start < 0 {
+		return nil, fmt.Errorf("invalid regexp pattern %q: %s", pattern, syntaxErr.Code)
+	}
+	end := start + len(syntaxErr.Expr) - prefix
+	start -= prefix
+	if start < 0 {
+		start = 0
+	}
+	if end > len(pattern) {
+		end = len(pattern)
 	}
-	if ok {
+	return nil, fmt.Errorf("invalid regexp pattern %q: %s: %q at position %d",
+		pattern, syntaxErr.Code, pattern[start:end], start)
+}
+
+func matchRegexp(obtained string, re *regexp.Regexp) error {
+	if re.MatchString(obtained) {
 		return nil
 	}
-	return fmt.Errorf("%q did not match pattern %q", obtained, pattern)
+	return failf("%q did not match pattern %q", obtained, re.String())
 }
 
 type panicMatches struct{}
@@ -212,32 +475,83 @@ type panicMatches struct{}
 var PanicMatches Checker = panicMatches{}
 
 func (panicMatches) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
 	if len(extras) == 0 {
 		return errors.New("missing 'expected' value")
 	}
 	expected, extras := extras[0], extras[1:]
+	if err := unexpectedExtras("PanicMatches", extras); err != nil {
+		return err
+	}
 	pattern, ok := expected.(string)
 	if !ok {
 		return errors.New("expected value must be a string containing a regexp pattern")
 	}
+	re, err := compilePattern(pattern)
+	if err != nil {
+		return err
+	}
 	// First arg must be a function with no args.
 	f := reflect.ValueOf(obtained)
 	if f.Kind() != reflect.Func || f.Type().NumIn() != 0 {
 		return errors.New("first arg must be a function that takes no args")
 	}
 
+	return panicMatch(f, nil, re)
+}
+
+// panicMatch calls the function with the arguments, and checks that it
+// panics with an error or string that matches the regexp.
+func panicMatch(f reflect.Value, args []reflect.Value, re *regexp.Regexp) (err error) {
+	panicked := true
 	defer func() {
 		v := recover()
-		if v == nil {
+		if !panicked {
 			// No panic, that's bad, but the error already says that.
+		} else if v == nil {
+			err = errors.New("recovered panic value nil is not a string nor an error")
 		} else if e, ok := v.(error); ok {
-			err = checkMatch(e.Error(), pattern)
+			err = matchRegexp(e.Error(), re)
 		} else if s, ok := v.(string); ok {
-			err = checkMatch(s, pattern)
+			err = matchRegexp(s, re)
 		} else {
-			err = fmt.Errorf("recovered panic value %T(%#v) is not a string nor an error", v, v)
+			err = failf("recovered panic value %T(%#v) is not a string nor an error", v, v)
 		}
 	}()
-	f.Call(nil)
+	f.Call(args)
+	panicked = false
 	return errors.New("no panic")
 }
+
+// describe returns a description of the value for failure messages,
+// telling apart an untyped nil and the nil value of a type.
+func describe(v interface{}) string {
+	if v == nil {
+		return "nil"
+	}
+	if isTypedNil(v) {
+		return fmt.Sprintf("nil %T", v)
+	}
+	return fmt.Sprintf("%T value %v", v, display(v))
+}
+
+// isTypedNil reports whether the value is the nil value of a type that
+// can be nil, as opposed to an untyped nil.
+func isTypedNil(v interface{}) bool {
+	value := reflect.ValueOf(v)
+	switch value.Kind() {
+	case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Ptr, reflect.Slice, reflect.UnsafePointer:
+		return value.IsNil()
+	}
+	return false
+}
+
+// describeType returns a description of the type of the value for
+// failure messages about unsupported types.
+func describeType(v interface{}) string {
+	if v == nil {
+		return "nil"
+	}
+	return fmt.Sprintf("type %T", v)
+}
@@ -2,7 +2,11 @@ package checkers_test
 
 import (
 	"errors"
+	"math"
+	"regexp"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/howbazaar/checkers"
 )
@@ -22,6 +26,142 @@ func TestIsNil(t *testing.T) {
 	if err == nil {
 		t.Fatal("IsNil(&anything{}) should return an error")
 	}
+	// The nil values of types that can be nil are nil.
+	for _, typedNil := range []interface{}{(*anything)(nil), map[string]int(nil), []int(nil), (chan int)(nil), (func())(nil)} {
+		if err := checkers.IsNil.Check(typedNil); err != nil {
+			t.Errorf("IsNil(%T(nil)) returned error: %v", typedNil, err)
+		}
+	}
+}
+
+func TestIsNotNil(t *testing.T) {
+	type anything struct{}
+	for _, test := range []struct {
+		description string
+		obtained    interface{}
+		extras      []interface{}
+		err         string
+	}{
+		{
+			description: "value",
+			obtained:    anything{},
+		}, {
+			description: "pointer",
+			obtained:    &anything{},
+		}, {
+			description: "empty slice",
+			obtained:    []int{},
+		}, {
+			description: "nil",
+			obtained:    nil,
+			err:         "expected a non-nil value, obtained nil",
+		}, {
+			description: "nil pointer",
+			obtained:    (*anything)(nil),
+			err:         "expected a non-nil value, obtained nil *checkers_test.anything",
+		}, {
+			description: "nil map",
+			obtained:    map[string]int(nil),
+			err:         "expected a non-nil value, obtained nil map[string]int",
+		}, {
+			description: "nil slice with comment",
+			obtained:    []int(nil),
+			extras:      []interface{}{checkers.Commentf("loaded")},
+			err:         "expected a non-nil value, obtained nil []int\ncomment: loaded",
+		}, {
+			description: "too many",
+			obtained:    1,
+			extras:      []interface{}{2},
+			err:         "too many arguments to checker IsNotNil, unexpected int(2)",
+		},
+	} {
+		err := checkers.IsNotNil.Check(test.obtained, test.extras...)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+		} else {
+			if test.err == "" {
+				t.Errorf("%s: unexpected error: %v", test.description, err)
+			} else {
+				if err.Error() != test.err {
+					t.Errorf("%s: error mismatch: \n\tobtained: %q\n\texpected: %q", test.description, err.Error(), test.err)
+				}
+			}
+		}
+	}
+}
+
+func TestIsZero(t *testing.T) {
+	type point struct {
+		X, Y int
+		Tags []string
+	}
+	for _, test := range []struct {
+		description string
+		obtained    interface{}
+		extras      []interface{}
+		err         string
+	}{
+		{
+			description: "nil",
+			obtained:    nil,
+		}, {
+			description: "number",
+			obtained:    0.0,
+		}, {
+			description: "string",
+			obtained:    "",
+		}, {
+			description: "struct",
+			obtained:    point{},
+		}, {
+			description: "nil pointer",
+			obtained:    (*point)(nil),
+		}, {
+			description: "zero time",
+			obtained:    time.Time{},
+		}, {
+			description: "non-zero number",
+			obtained:    3,
+			err:         "expected the zero value of int, obtained int value 3",
+		}, {
+			description: "non-zero string",
+			obtained:    " ",
+			err:         "expected the zero value of string, obtained string value  ",
+		}, {
+			description: "non-zero struct",
+			obtained:    point{Y: 1},
+			extras:      []interface{}{checkers.Commentf("origin")},
+			err:         "expected the zero value of checkers_test.point, obtained checkers_test.point value checkers_test.point{X: 0, Y: 1, Tags: []string(nil)}\ncomment: origin",
+		}, {
+			description: "pointer to zero struct",
+			obtained:    &point{},
+			err:         "expected the zero value of *checkers_test.point, obtained *checkers_test.point value &checkers_test.point{X: 0, Y: 0, Tags: []string(nil)}",
+		}, {
+			description: "empty slice",
+			obtained:    []int{},
+			err:         "expected the zero value of []int, obtained []int value []",
+		}, {
+			description: "too many",
+			obtained:    0,
+			extras:      []interface{}{0},
+			err:         "too many arguments to checker IsZero, unexpected int(0)",
+		},
+	} {
+		err := checkers.IsZero.Check(test.obtained, test.extras...)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+		} else {
+			if test.err == "" {
+				t.Errorf("%s: unexpected error: %v", test.description, err)
+			} else if err.Error() != test.err {
+				t.Errorf("%s: error mismatch: \n\tobtained: %q\n\texpected: %q", test.description, err.Error(), test.err)
+			}
+		}
+	}
 }
 
 func TestEquals(t *testing.T) {
@@ -61,7 +201,11 @@ func TestEquals(t *testing.T) {
 			description: "string, different",
 			obtained:    "something",
 			expected:    "different",
-			err:         "expected string value different, got something",
+			err: "expected string value different, got something\n" +
+				"strings differ at byte 0 (rune 0):\n" +
+				"\tobtained: \"something\"\n" +
+				"\texpected: \"different\"\n" +
+				"\t           ^",
 		}, {
 			description: "int, same",
 			obtained:    1234,
@@ -76,6 +220,62 @@ func TestEquals(t *testing.T) {
 			obtained:    int32(1234),
 			expected:    int64(1234),
 			err:         "obtained type int32 does not match expected type int64",
+		}, {
+			description: "uint8, different",
+			obtained:    uint8(1),
+			expected:    uint8(2),
+			err:         "expected uint8 value 2, got 1",
+		}, {
+			description: "float64, same",
+			obtained:    1.5,
+			expected:    1.5,
+		}, {
+			description: "float64, NaN",
+			obtained:    math.NaN(),
+			expected:    math.NaN(),
+			err:         "expected float64 value NaN, got NaN",
+		}, {
+			description: "duration, same",
+			obtained:    time.Second,
+			expected:    time.Second,
+		}, {
+			description: "duration, different",
+			obtained:    time.Second,
+			expected:    time.Minute,
+			err:         "expected time.Duration value 1m0s, got 1s",
+		}, {
+			description: "duration and int64 of the same kind",
+			obtained:    time.Second,
+			expected:    int64(time.Second),
+			err:         "obtained type time.Duration does not match expected type int64",
+		}, {
+			description: "named types of the same kind",
+			obtained:    aString("something"),
+			expected:    "something",
+			err:         "obtained type checkers_test.aString does not match expected type string",
+		}, {
+			description: "named types, same",
+			obtained:    aString("something"),
+			expected:    aString("something"),
+		}, {
+			description: "both nil",
+			obtained:    nil,
+			expected:    nil,
+		}, {
+			description: "obtained nil",
+			obtained:    nil,
+			expected:    1,
+			err:         "expected int value 1, got nil",
+		}, {
+			description: "expected nil",
+			obtained:    "something",
+			expected:    nil,
+			err:         "expected nil, got string value something",
+		}, {
+			description: "expected nil, obtained typed nil",
+			obtained:    (*int)(nil),
+			expected:    nil,
+			err:         "expected nil, got nil *int",
 		},
 	} {
 		err := checkers.Equals.Check(test.obtained, test.expected)
@@ -95,6 +295,176 @@ func TestEquals(t *testing.T) {
 	}
 }
 
+type aString string
+
+func TestEqualsConverted(t *testing.T) {
+	for _, test := range []struct {
+		description string
+		obtained    interface{}
+		expected    interface{}
+		err         string
+	}{
+		{
+			description: "same type",
+			obtained:    1234,
+			expected:    1234,
+		}, {
+			description: "different sized ints",
+			obtained:    int32(1234),
+			expected:    int64(1234),
+		}, {
+			description: "different sized ints, different values",
+			obtained:    int8(-1),
+			expected:    int64(255),
+			err:         "expected int64 value 255, got -1",
+		}, {
+			description: "duration and int64",
+			obtained:    time.Second,
+			expected:    int64(time.Second),
+		}, {
+			description: "uints",
+			obtained:    uint8(3),
+			expected:    uint64(3),
+		}, {
+			description: "floats",
+			obtained:    float32(0.5),
+			expected:    0.5,
+		}, {
+			description: "named strings",
+			obtained:    aString("something"),
+			expected:    "something",
+		}, {
+			description: "signed and unsigned",
+			obtained:    1,
+			expected:    uint(1),
+			err:         "obtained type int cannot be compared with expected type uint",
+		}, {
+			description: "int and float",
+			obtained:    1,
+			expected:    1.0,
+			err:         "obtained type int cannot be compared with expected type float64",
+		},
+	} {
+		err := checkers.EqualsConverted.Check(test.obtained, test.expected)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+		} else {
+			if test.err == "" {
+				t.Errorf("%s: unexpected error: %v", test.description, err)
+			} else {
+				if err.Error() != test.err {
+					t.Errorf("%s: error mismatch: \n\tobtained: %q\n\texpected: %q", test.description, err.Error(), test.err)
+				}
+			}
+		}
+	}
+}
+
+func TestEqualsNumeric(t *testing.T) {
+	for _, test := range []struct {
+		description string
+		obtained    interface{}
+		expected    interface{}
+		err         string
+	}{
+		{
+			description: "same type",
+			obtained:    1234,
+			expected:    1234,
+		}, {
+			description: "int and int64",
+			obtained:    int64(1234),
+			expected:    1234,
+		}, {
+			description: "signed and unsigned",
+			obtained:    uint8(200),
+			expected:    200,
+		}, {
+			description: "int and float",
+			obtained:    3,
+			expected:    3.0,
+		}, {
+			description: "float32 and float64",
+			obtained:    float32(0.5),
+			expected:    0.5,
+		}, {
+			description: "duration and int",
+			obtained:    time.Second,
+			expected:    1000000000,
+		}, {
+			description: "different values",
+			obtained:    int32(7),
+			expected:    8,
+			err:         "expected int value 8, got int32 value 7",
+		}, {
+			description: "negative and unsigned",
+			obtained:    uint64(math.MaxUint64),
+			expected:    -1,
+			err:         "expected int value -1, got uint64 value 18446744073709551615",
+		}, {
+			description: "fraction",
+			obtained:    1,
+			expected:    1.5,
+			err:         "expected float64 value 1.5, got int value 1",
+		}, {
+			description: "precision beyond float64",
+			obtained:    int64(1<<53 + 1),
+			expected:    float64(1 << 53),
+			err:         "expected float64 value 9.007199254740992e+15, got int64 value 9007199254740993",
+		}, {
+			description: "NaN",
+			obtained:    math.NaN(),
+			expected:    math.NaN(),
+			err:         "expected float64 value NaN, got float64 value NaN",
+		}, {
+			description: "obtained not a number",
+			obtained:    "1",
+			expected:    1,
+			err:         "EqualsNumeric checker expected a number, obtained was type string",
+		}, {
+			description: "expected not a number",
+			obtained:    1,
+			expected:    true,
+			err:         "expected value must be a number, got type bool",
+		},
+	} {
+		err := checkers.EqualsNumeric.Check(test.obtained, test.expected)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+		} else {
+			if test.err == "" {
+				t.Errorf("%s: unexpected error: %v", test.description, err)
+			} else {
+				if err.Error() != test.err {
+					t.Errorf("%s: error mismatch: \n\tobtained: %q\n\texpected: %q", test.description, err.Error(), test.err)
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkEquals(b *testing.B) {
+	for _, bench := range []struct {
+		name               string
+		obtained, expected interface{}
+	}{
+		{"int", 1234, 1234},
+		{"string", "something", "something"},
+		{"duration", time.Second, time.Second},
+		{"named", aString("something"), aString("something")},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				checkers.Equals.Check(bench.obtained, bench.expected)
+			}
+		})
+	}
+}
+
 func TestDeepEquals(t *testing.T) {
 	err := checkers.DeepEquals.Check(nil)
 	if err.Error() != "missing 'expected' value" {
@@ -110,7 +480,57 @@ func TestDeepEquals(t *testing.T) {
 	}
 	// The rest of the deep equals checks are done in deepequal_test.go
 	err = checkers.DeepEquals.Check(obtained, expected)
-	if err.Error() != `mismatch at ["bar"]: unequal; obtained "result"; expected "something"` {
+	if err.Error() != `mismatch at ["bar"]: unequal; obtained "result"; expected "something"
+diff (-obtained +expected):
+ map[string]interface {}{
+-	"bar": "result",
++	"bar": "something",
+ 	"foo": 1234,
+ }` {
+		t.Errorf("incorrect error response: %v", err)
+	}
+	// Options are passed through to the comparison.
+	expected["foo"] = 4321
+	err = checkers.DeepEquals.Check(obtained, expected, checkers.MaxMismatches(1))
+	if !strings.HasPrefix(err.Error(), `mismatch at ["bar"]: unequal; obtained "result"; expected "something"
+diff`) {
+		t.Errorf("incorrect error response: %v", err)
+	}
+	// Limits apply to the diff.
+	err = checkers.DeepEquals.Check([]int{1, 2, 3, 4}, []int{1, 5, 3, 4}, checkers.MaxElements(2))
+	if err.Error() != `mismatch at [1]: unequal; obtained 2; expected 5
+diff (-obtained +expected):
+ []int{
+ 	1,
+-	2,
++	5,
+ 	...(+2 more),
+ }` {
+		t.Errorf("incorrect error response: %v", err)
+	}
+	// Differences beyond the limits are not diffed.
+	err = checkers.DeepEquals.Check([]int{1, 2, 3, 4}, []int{1, 2, 3, 5}, checkers.MaxElements(2))
+	if err.Error() != `mismatch at [3]: unequal; obtained 4; expected 5` {
+		t.Errorf("incorrect error response: %v", err)
+	}
+	// Values that fit on a line are not diffed.
+	err = checkers.DeepEquals.Check(1, 2)
+	if err.Error() != `mismatch at top level: unequal; obtained 1; expected 2` {
+		t.Errorf("incorrect error response: %v", err)
+	}
+	// Reordered elements of slices of structs are paired up.
+	type person struct {
+		Name string
+		Age  int
+	}
+	err = checkers.DeepEquals.Check(
+		[]person{{"bob", 26}, {"alice", 30}},
+		[]person{{"alice", 30}, {"bob", 25}},
+		checkers.MaxMismatches(1))
+	if !strings.HasSuffix(err.Error(), `
+similar elements:
+obtained element 0 looks like expected element 1 except field Age
+obtained element 1 equals expected element 0`) {
 		t.Errorf("incorrect error response: %v", err)
 	}
 }
@@ -123,6 +543,70 @@ func (a aStringer) String() string {
 	return a.v
 }
 
+// halfBuilt is a Stringer whose String method panics until its name is
+// set.
+type halfBuilt struct {
+	name *string
+}
+
+func (h *halfBuilt) String() string {
+	return "built " + *h.name
+}
+
+func TestHasLen(t *testing.T) {
+	for _, test := range []struct {
+		description string
+		obtained    interface{}
+		expected    interface{}
+		err         string
+	}{
+		{
+			description: "slice",
+			obtained:    []int{1, 2},
+			expected:    2,
+		}, {
+			description: "nil map",
+			obtained:    map[string]int(nil),
+			expected:    0,
+		}, {
+			description: "wrong length",
+			obtained:    "foo",
+			expected:    2,
+			err:         "expected length 2, obtained 3",
+		}, {
+			description: "obtained nil",
+			obtained:    nil,
+			expected:    0,
+			err:         "HasLen checker expected array, channel, map, slice or string, obtained was nil",
+		}, {
+			description: "expected nil",
+			obtained:    "foo",
+			expected:    nil,
+			err:         "expected length must be an int, got nil",
+		}, {
+			description: "expected not an int",
+			obtained:    "foo",
+			expected:    "3",
+			err:         "expected length must be an int, got type string",
+		},
+	} {
+		err := checkers.HasLen.Check(test.obtained, test.expected)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+		} else {
+			if test.err == "" {
+				t.Errorf("%s: unexpected error: %v", test.description, err)
+			} else {
+				if err.Error() != test.err {
+					t.Errorf("%s: error mismatch: \n\tobtained: %q\n\texpected: %q", test.description, err.Error(), test.err)
+				}
+			}
+		}
+	}
+}
+
 func TestMatches(t *testing.T) {
 	for _, test := range []struct {
 		description string
@@ -148,6 +632,11 @@ func TestMatches(t *testing.T) {
 			description: "stringer matches",
 			obtained:    aStringer{"testing"},
 			expected:    "test.*",
+		}, {
+			description: "stringer panics",
+			obtained:    &halfBuilt{},
+			expected:    "built.*",
+			err:         "String method of *checkers_test.halfBuilt panicked: runtime error: invalid memory address or nil pointer dereference",
 		}, {
 			description: "pattern matches entire string",
 			obtained:    "testing",
@@ -157,6 +646,36 @@ func TestMatches(t *testing.T) {
 			description: "pattern handles full definition",
 			obtained:    "testing",
 			expected:    "^test.*$",
+		}, {
+			description: "obtained nil",
+			obtained:    nil,
+			expected:    "test.*",
+			err:         "nil is neither a string nor has a 'String() string' method",
+		}, {
+			description: "expected nil",
+			obtained:    "testing",
+			expected:    nil,
+			err:         "expected value must be a string containing a regexp pattern",
+		}, {
+			description: "invalid pattern",
+			obtained:    "testing",
+			expected:    "test[",
+			err:         `invalid regexp pattern "test[": missing closing ]: "[" at position 4`,
+		}, {
+			description: "invalid pattern reported before obtained",
+			obtained:    42,
+			expected:    "te**",
+			err:         `invalid regexp pattern "te**": invalid nested repetition operator: "**" at position 2`,
+		}, {
+			description: "invalid anchored pattern",
+			obtained:    "testing",
+			expected:    "^(test$",
+			err:         `invalid regexp pattern "^(test$": missing closing ): "^(test$" at position 0`,
+		}, {
+			description: "trailing backslash",
+			obtained:    "testing",
+			expected:    `test\`,
+			err:         `invalid regexp pattern "test\\": trailing backslash at end of expression at position 4`,
 		},
 	} {
 		err := checkers.Matches.Check(test.obtained, test.expected)
@@ -176,6 +695,60 @@ func TestMatches(t *testing.T) {
 	}
 }
 
+func TestMatchesRegexp(t *testing.T) {
+	for _, test := range []struct {
+		description string
+		obtained    interface{}
+		re          *regexp.Regexp
+		err         string
+	}{
+		{
+			description: "not a string or Stringer",
+			obtained:    42,
+			re:          regexp.MustCompile("something"),
+			err:         "int(42) is neither a string nor has a 'String() string' method",
+		}, {
+			description: "string matches",
+			obtained:    "testing",
+			re:          regexp.MustCompile("^test.*$"),
+		}, {
+			description: "stringer matches",
+			obtained:    aStringer{"testing"},
+			re:          regexp.MustCompile("^test.*$"),
+		}, {
+			description: "pattern is not anchored",
+			obtained:    "testing",
+			re:          regexp.MustCompile("est"),
+		}, {
+			description: "no match",
+			obtained:    "testing",
+			re:          regexp.MustCompile("^est"),
+			err:         `"testing" did not match pattern "^est"`,
+		},
+	} {
+		err := checkers.MatchesRegexp(test.re).Check(test.obtained)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+		} else {
+			if test.err == "" {
+				t.Errorf("%s: unexpected error: %v", test.description, err)
+			} else {
+				if err.Error() != test.err {
+					t.Errorf("%s: error mismatch: \n\tobtained: %q\n\texpected: %q", test.description, err.Error(), test.err)
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkMatches(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		checkers.Matches.Check("testing the pattern", "test.* the (pattern|regexp)")
+	}
+}
+
 func TestPanicMatches(t *testing.T) {
 	for _, test := range []struct {
 		description string
@@ -198,6 +771,11 @@ func TestPanicMatches(t *testing.T) {
 			obtained:    func() {},
 			expected:    42,
 			err:         "expected value must be a string containing a regexp pattern",
+		}, {
+			description: "invalid pattern reported before calling",
+			obtained:    func() { panic("should not be called") },
+			expected:    "oops(",
+			err:         `invalid regexp pattern "oops(": missing closing ): "oops(" at position 0`,
 		}, {
 			description: "no panic",
 			obtained:    func() {},
@@ -208,6 +786,16 @@ func TestPanicMatches(t *testing.T) {
 			obtained:    func() { panic(42) },
 			expected:    "oops",
 			err:         "recovered panic value int(42) is not a string nor an error",
+		}, {
+			description: "panic with nil",
+			obtained:    func() { panic(nil) },
+			expected:    ".*",
+			err:         "recovered panic value nil is not a string nor an error",
+		}, {
+			description: "nil function",
+			obtained:    nil,
+			expected:    ".*",
+			err:         "first arg must be a function that takes no args",
 		}, {
 			description: "panic with a string",
 			obtained:    func() { panic("oopsy") },
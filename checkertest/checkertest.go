@@ -0,0 +1,159 @@
+// Add a copyright
+// Add a licence
+
+// Package checkertest provides helpers for testing implementations of
+// checkers.Checker.
+//
+// A checker should keep to the same contract as the checkers in the
+// checkers package, so that checkers from different sources can be
+// used together:
+//
+//   - Check returns nil when the check passes, and an error describing
+//     the difference otherwise. As checkers are often run without their
+//     failures being reported, no formatting should be done on success.
+//   - The extra values start with those the checker requires, such as
+//     the expected value, followed by any options it understands. A
+//     missing required value, or any other extra value, is reported as
+//     an error.
+//   - Check never panics, whatever the values it is given, including
+//     untyped nil. Values of the wrong type are reported as an error.
+//   - Descriptions start with a lower case letter and have no trailing
+//     punctuation, and refer to the values as obtained and expected.
+//   - A checker holds no state between calls, so that it can be used
+//     from many goroutines at once.
+//
+// Run checks a table of cases against a checker, and CheckArguments
+// checks the handling of missing, unexpected and nil values.
+package checkertest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/howbazaar/checkers"
+)
+
+// Case is a single use of a checker and its expected result.
+type Case struct {
+	// Description identifies the case in failures.
+	Description string
+	// Obtained and Extras are passed to the checker.
+	Obtained interface{}
+	Extras   []interface{}
+	// Err is empty if the check is expected to pass. Otherwise it is a
+	// regular expression that the description of the failure must
+	// match in full.
+	Err string
+}
+
+// Run checks each case against the checker, reporting any that do not
+// have the expected result to tb.
+func Run(tb testing.TB, checker checkers.Checker, cases []Case) {
+	tb.Helper()
+	for _, c := range cases {
+		if err := checkCase(checker, c); err != nil {
+			tb.Errorf("%s: %v", c.Description, err)
+		}
+	}
+}
+
+// AssertPasses reports a failure to tb if the check does not pass.
+func AssertPasses(tb testing.TB, checker checkers.Checker, obtained interface{}, extras ...interface{}) {
+	tb.Helper()
+	if err := checkCase(checker, Case{Obtained: obtained, Extras: extras}); err != nil {
+		tb.Error(err)
+	}
+}
+
+// AssertFails reports a failure to tb unless the check fails with a
+// description that matches the pattern in full.
+func AssertFails(tb testing.TB, checker checkers.Checker, pattern string, obtained interface{}, extras ...interface{}) {
+	tb.Helper()
+	if err := checkCase(checker, Case{Obtained: obtained, Extras: extras, Err: pattern}); err != nil {
+		tb.Error(err)
+	}
+}
+
+// CheckArguments checks that the checker handles bad arguments as the
+// contract requires. The obtained and required values should be a use
+// of the checker that passes. The checker is then expected to fail,
+// without panicking, when each required value is left off, when an
+// unexpected extra value is added, and when the values are replaced
+// with untyped nil.
+func CheckArguments(tb testing.TB, checker checkers.Checker, obtained interface{}, required ...interface{}) {
+	tb.Helper()
+	if err := check(checker, obtained, required); err != nil {
+		tb.Errorf("valid arguments: unexpected error: %v", err)
+		return
+	}
+	for n := len(required) - 1; n >= 0; n-- {
+		if err := check(checker, obtained, required[:n]); err == nil {
+			tb.Errorf("%d of %d required values: unexpected success", n, len(required))
+		} else if _, ok := err.(panicError); ok {
+			tb.Errorf("%d of %d required values: %v", n, len(required), err)
+		}
+	}
+	extras := append(append([]interface{}(nil), required...), unexpected{})
+	if err := check(checker, obtained, extras); err == nil {
+		tb.Errorf("unexpected extra value: unexpected success")
+	} else if _, ok := err.(panicError); ok {
+		tb.Errorf("unexpected extra value: %v", err)
+	}
+	if err := check(checker, nil, required); err != nil {
+		if _, ok := err.(panicError); ok {
+			tb.Errorf("nil obtained value: %v", err)
+		}
+	}
+	for i := range required {
+		nils := append([]interface{}(nil), required...)
+		nils[i] = nil
+		if err := check(checker, obtained, nils); err != nil {
+			if _, ok := err.(panicError); ok {
+				tb.Errorf("nil required value %d: %v", i, err)
+			}
+		}
+	}
+}
+
+// unexpected is the type of the extra value that no checker expects.
+type unexpected struct{}
+
+// panicError describes a panic from a checker.
+type panicError struct {
+	value interface{}
+}
+
+func (err panicError) Error() string {
+	return fmt.Sprintf("checker panicked: %v", err.value)
+}
+
+// check runs the checker, returning any panic as a panicError.
+func check(checker checkers.Checker, obtained interface{}, extras []interface{}) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = panicError{v}
+		}
+	}()
+	return checker.Check(obtained, extras...)
+}
+
+// checkCase returns an error describing how the result of the check
+// differed from that expected by the case.
+func checkCase(checker checkers.Checker, c Case) error {
+	err := check(checker, c.Obtained, c.Extras)
+	if _, ok := err.(panicError); ok {
+		return err
+	}
+	switch {
+	case err == nil && c.Err == "":
+		return nil
+	case err == nil:
+		return fmt.Errorf("expected error matching %q, check passed", c.Err)
+	case c.Err == "":
+		return fmt.Errorf("unexpected error: %v", err)
+	}
+	if mismatch := checkers.Matches.Check(err.Error(), c.Err); mismatch != nil {
+		return fmt.Errorf("error mismatch: %v", mismatch)
+	}
+	return nil
+}
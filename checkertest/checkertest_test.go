@@ -0,0 +1,104 @@
+// Add a copyright
+// Add a licence
+
+package checkertest_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/howbazaar/checkers"
+	"github.com/howbazaar/checkers/checkertest"
+)
+
+// isEven is a checker that keeps to the contract.
+type isEven struct{}
+
+func (isEven) Check(obtained interface{}, extras ...interface{}) error {
+	if len(extras) != 0 {
+		return fmt.Errorf("unexpected extra value %T", extras[0])
+	}
+	i, ok := obtained.(int)
+	if !ok {
+		return fmt.Errorf("expected int, obtained %T", obtained)
+	}
+	if i%2 != 0 {
+		return fmt.Errorf("%d is odd", i)
+	}
+	return nil
+}
+
+// sloppy is a checker that doesn't keep to the contract.
+type sloppy struct{}
+
+func (sloppy) Check(obtained interface{}, extras ...interface{}) error {
+	if obtained.(int) != extras[0].(int) {
+		return errors.New("not equal")
+	}
+	return nil
+}
+
+func TestRun(t *testing.T) {
+	checkertest.Run(t, isEven{}, []checkertest.Case{
+		{Description: "even", Obtained: 2},
+		{Description: "odd", Obtained: 3, Err: "3 is odd"},
+		{Description: "string", Obtained: "2", Err: "expected int, obtained .*"},
+	})
+
+	r := checkers.NewRecordingTB()
+	r.Run(func(tb testing.TB) {
+		checkertest.Run(tb, isEven{}, []checkertest.Case{
+			{Description: "unexpected pass", Obtained: 2, Err: "2 is odd"},
+			{Description: "unexpected failure", Obtained: 3},
+			{Description: "wrong error", Obtained: 3, Err: "odd"},
+		})
+	})
+	if err := checkers.DeepEquals.Check(r.Errors(), []string{
+		`unexpected pass: expected error matching "2 is odd", check passed`,
+		`unexpected failure: unexpected error: 3 is odd`,
+		`wrong error: error mismatch: "3 is odd" did not match pattern "^odd$"`,
+	}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestAssert(t *testing.T) {
+	checkertest.AssertPasses(t, checkers.Equals, 1, 1)
+	checkertest.AssertFails(t, checkers.Equals, "expected int value 2, got 1", 1, 2)
+
+	r := checkers.NewRecordingTB()
+	r.Run(func(tb testing.TB) {
+		checkertest.AssertPasses(tb, checkers.Equals, 1, 2)
+		checkertest.AssertFails(tb, checkers.Equals, ".*", 1, 1)
+		checkertest.AssertPasses(tb, sloppy{}, 1)
+	})
+	if err := checkers.DeepEquals.Check(r.Errors(), []string{
+		"unexpected error: expected int value 2, got 1",
+		`expected error matching ".*", check passed`,
+		"checker panicked: runtime error: index out of range [0] with length 0",
+	}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCheckArguments(t *testing.T) {
+	checkertest.CheckArguments(t, isEven{}, 2)
+	checkertest.CheckArguments(t, checkers.Equals, 1, 1)
+	checkertest.CheckArguments(t, checkers.DeepEquals, []int{1}, []int{1})
+	checkertest.CheckArguments(t, checkers.HasLen, "foo", 3)
+	checkertest.CheckArguments(t, checkers.Matches, "foo", "f.*")
+
+	r := checkers.NewRecordingTB()
+	r.Run(func(tb testing.TB) {
+		checkertest.CheckArguments(tb, sloppy{}, 1, 1)
+	})
+	if err := checkers.DeepEquals.Check(r.Errors(), []string{
+		"0 of 1 required values: checker panicked: runtime error: index out of range [0] with length 0",
+		"unexpected extra value: unexpected success",
+		"nil obtained value: checker panicked: interface conversion: interface {} is nil, not int",
+		"nil required value 0: checker panicked: interface conversion: interface {} is nil, not int",
+	}); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,149 @@
+// Add a copyright
+// Add a licence
+
+package clock
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/howbazaar/checkers"
+)
+
+// fakeTimer returns the obtained value as a timer from a fake clock.
+func fakeTimer(checker string, obtained interface{}) (*Timer, error) {
+	t, ok := obtained.(*Timer)
+	if !ok || t == nil {
+		return nil, fmt.Errorf("%s checker expected *clock.Timer, obtained was %T", checker, obtained)
+	}
+	if t.fake == nil {
+		return nil, fmt.Errorf("%s checker expected a timer from a fake clock", checker)
+	}
+	return t, nil
+}
+
+// fakeClock returns the obtained value as a fake clock.
+func fakeClock(checker string, obtained interface{}) (*Fake, error) {
+	f, ok := obtained.(*Fake)
+	if !ok || f == nil {
+		return nil, fmt.Errorf("%s checker expected *clock.Fake, obtained was %T", checker, obtained)
+	}
+	return f, nil
+}
+
+type timerFired struct{}
+
+// TimerFired checker checks that the obtained timer, made from a fake
+// clock, has fired since it was made or last reset.
+//
+//	timer := clock.NewTimer(time.Second)
+//	clock.Advance(time.Second)
+//	t.Check(timer, clock.TimerFired)
+var TimerFired checkers.Checker = timerFired{}
+
+func (timerFired) Check(obtained interface{}, extras ...interface{}) error {
+	if len(extras) != 0 {
+		return fmt.Errorf("unexpected extra value %T(%#v)", extras[0], extras[0])
+	}
+	t, err := fakeTimer("TimerFired", obtained)
+	if err != nil {
+		return err
+	}
+	t.fake.mu.Lock()
+	defer t.fake.mu.Unlock()
+	switch {
+	case t.fired:
+		return nil
+	case t.stopped:
+		return errors.New("timer was stopped before it fired")
+	}
+	return fmt.Errorf("timer has not fired, it is due in %v", t.when.Sub(t.fake.now))
+}
+
+type tickersPending struct{}
+
+// TickersPending checker checks that the obtained fake clock has the
+// expected number of tickers that have not been stopped.
+var TickersPending checkers.Checker = tickersPending{}
+
+func (tickersPending) Check(obtained interface{}, extras ...interface{}) error {
+	if len(extras) == 0 {
+		return errors.New("missing 'expected' value")
+	}
+	expected, extras := extras[0], extras[1:]
+	if len(extras) != 0 {
+		return fmt.Errorf("unexpected extra value %T(%#v)", extras[0], extras[0])
+	}
+	n, ok := expected.(int)
+	if !ok {
+		return fmt.Errorf("expected count must be an int, got %T", expected)
+	}
+	f, err := fakeClock("TickersPending", obtained)
+	if err != nil {
+		return err
+	}
+	_, tickers := f.pending()
+	if len(tickers) == n {
+		return nil
+	}
+	return fmt.Errorf("expected %d tickers pending, got %d%s", n, len(tickers), describeTickers(tickers))
+}
+
+type noTimersPending struct{}
+
+// NoTimersPending checker checks that every timer made from the
+// obtained fake clock has either fired or been stopped, and that every
+// ticker has been stopped. A timer or ticker left running by retry or
+// backoff code is a leak when the code uses the real clock.
+var NoTimersPending checkers.Checker = noTimersPending{}
+
+func (noTimersPending) Check(obtained interface{}, extras ...interface{}) error {
+	if len(extras) != 0 {
+		return fmt.Errorf("unexpected extra value %T(%#v)", extras[0], extras[0])
+	}
+	f, err := fakeClock("NoTimersPending", obtained)
+	if err != nil {
+		return err
+	}
+	timers, tickers := f.pending()
+	if len(timers) == 0 && len(tickers) == 0 {
+		return nil
+	}
+	now := f.Now()
+	var lines []string
+	for _, when := range timers {
+		lines = append(lines, fmt.Sprintf("\ttimer due in %v", when.Sub(now)))
+	}
+	for _, period := range tickers {
+		lines = append(lines, fmt.Sprintf("\tticker every %v", period))
+	}
+	return fmt.Errorf("timers or tickers not stopped:\n%s", strings.Join(lines, "\n"))
+}
+
+// CheckStoppedAtEnd checks with NoTimersPending that all the timers and
+// tickers made from the fake clock have been stopped by the end of the
+// test, reporting any that have not to tb. The check is made after any
+// cleanup functions registered later have run.
+func CheckStoppedAtEnd(tb testing.TB, f *Fake) {
+	tb.Helper()
+	tb.Cleanup(func() {
+		if err := NoTimersPending.Check(f); err != nil {
+			tb.Error(err.Error())
+		}
+	})
+}
+
+// describeTickers lists the periods of the tickers, if there are any.
+func describeTickers(periods []time.Duration) string {
+	if len(periods) == 0 {
+		return ""
+	}
+	names := make([]string, len(periods))
+	for i, period := range periods {
+		names[i] = period.String()
+	}
+	return " with periods " + strings.Join(names, ", ")
+}
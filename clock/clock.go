@@ -0,0 +1,244 @@
+// Add a copyright
+// Add a licence
+
+// Package clock provides a fake clock whose time only moves when the
+// test advances it, and checkers for the timers and tickers made from
+// it. Code under test takes the clock as a Clock interface, so that
+// retry and backoff loops can be driven step by step, and the checkers
+// catch timers that never fire or are never stopped.
+package clock
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock is the interface to time used by code under test. Both Real
+// and the fake clocks returned by NewFake implement it.
+type Clock interface {
+	Now() time.Time
+	NewTimer(d time.Duration) *Timer
+	NewTicker(d time.Duration) *Ticker
+}
+
+// Timer is a single event, as with time.Timer.
+type Timer struct {
+	C <-chan time.Time
+
+	real *time.Timer
+	fake *Fake
+	c    chan time.Time
+	when time.Time
+	// fired is set once the timer has fired, and stopped once it has
+	// been stopped before firing. Reset clears both.
+	fired   bool
+	stopped bool
+}
+
+// Stop prevents the timer from firing. It reports whether the call
+// stopped the timer, being false if the timer had already fired or
+// been stopped.
+func (t *Timer) Stop() bool {
+	if t.real != nil {
+		return t.real.Stop()
+	}
+	t.fake.mu.Lock()
+	defer t.fake.mu.Unlock()
+	active := !t.fired && !t.stopped
+	t.stopped = true
+	return active
+}
+
+// Reset changes the timer to fire after d. It reports whether the timer
+// had been active.
+func (t *Timer) Reset(d time.Duration) bool {
+	if t.real != nil {
+		return t.real.Reset(d)
+	}
+	t.fake.mu.Lock()
+	defer t.fake.mu.Unlock()
+	active := !t.fired && !t.stopped
+	t.fired, t.stopped = false, false
+	t.when = t.fake.now.Add(d)
+	t.fake.fireDue(t)
+	return active
+}
+
+// Ticker delivers ticks at intervals, as with time.Ticker.
+type Ticker struct {
+	C <-chan time.Time
+
+	real    *time.Ticker
+	fake    *Fake
+	c       chan time.Time
+	period  time.Duration
+	next    time.Time
+	stopped bool
+}
+
+// Stop turns off the ticker. No more ticks are sent once it returns.
+func (t *Ticker) Stop() {
+	if t.real != nil {
+		t.real.Stop()
+		return
+	}
+	t.fake.mu.Lock()
+	defer t.fake.mu.Unlock()
+	t.stopped = true
+}
+
+type realClock struct{}
+
+// Real is the Clock that uses the time package.
+var Real Clock = realClock{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) NewTimer(d time.Duration) *Timer {
+	t := time.NewTimer(d)
+	return &Timer{C: t.C, real: t}
+}
+
+func (realClock) NewTicker(d time.Duration) *Ticker {
+	t := time.NewTicker(d)
+	return &Ticker{C: t.C, real: t}
+}
+
+// Fake is a Clock whose time only changes when Advance is called.
+// Timers and tickers made from it fire as Advance passes the times they
+// are due.
+type Fake struct {
+	mu      sync.Mutex
+	now     time.Time
+	timers  []*Timer
+	tickers []*Ticker
+}
+
+// NewFake returns a fake clock set to now.
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now}
+}
+
+// Now returns the time of the fake clock.
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// NewTimer returns a timer that fires once the clock is advanced by d.
+// A timer for a duration that is not positive fires straight away.
+func (f *Fake) NewTimer(d time.Duration) *Timer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	c := make(chan time.Time, 1)
+	t := &Timer{C: c, fake: f, c: c, when: f.now.Add(d)}
+	f.timers = append(f.timers, t)
+	f.fireDue(t)
+	return t
+}
+
+// fireDue fires the timer if it is already due.
+func (f *Fake) fireDue(t *Timer) {
+	if !t.when.After(f.now) {
+		t.fired = true
+		send(t.c, f.now)
+	}
+}
+
+// NewTicker returns a ticker that ticks each time the clock is advanced
+// past another period of d. It panics if d is not positive, as
+// time.NewTicker does.
+func (f *Fake) NewTicker(d time.Duration) *Ticker {
+	if d <= 0 {
+		panic("non-positive interval for NewTicker")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	c := make(chan time.Time, 1)
+	t := &Ticker{C: c, fake: f, c: c, period: d, next: f.now.Add(d)}
+	f.tickers = append(f.tickers, t)
+	return t
+}
+
+// Advance moves the clock on by d, firing the timers and ticking the
+// tickers that come due, in the order they are due. As with the time
+// package, a tick is dropped if the previous one has not been received.
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	end := f.now.Add(d)
+	for {
+		when, fire := f.nextEvent(end)
+		if fire == nil {
+			break
+		}
+		f.now = when
+		fire()
+	}
+	f.now = end
+}
+
+// nextEvent returns the time of the first timer or tick due by end,
+// and a function that fires it, or nil if there is none.
+func (f *Fake) nextEvent(end time.Time) (time.Time, func()) {
+	var when time.Time
+	var fire func()
+	due := func(t time.Time) bool {
+		return !t.After(end) && (fire == nil || t.Before(when))
+	}
+	for _, t := range f.timers {
+		t := t
+		if !t.fired && !t.stopped && due(t.when) {
+			when = t.when
+			fire = func() {
+				t.fired = true
+				send(t.c, when)
+			}
+		}
+	}
+	for _, t := range f.tickers {
+		t := t
+		if !t.stopped && due(t.next) {
+			when = t.next
+			fire = func() {
+				t.next = t.next.Add(t.period)
+				send(t.c, when)
+			}
+		}
+	}
+	return when, fire
+}
+
+// send sends the time without blocking, dropping it if the channel is
+// full.
+func send(c chan time.Time, t time.Time) {
+	select {
+	case c <- t:
+	default:
+	}
+}
+
+// pending returns the due times of the timers that have neither fired
+// nor been stopped, and the periods of the tickers that have not been
+// stopped, each in ascending order.
+func (f *Fake) pending() (timers []time.Time, tickers []time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, t := range f.timers {
+		if !t.fired && !t.stopped {
+			timers = append(timers, t.when)
+		}
+	}
+	for _, t := range f.tickers {
+		if !t.stopped {
+			tickers = append(tickers, t.period)
+		}
+	}
+	sort.Slice(timers, func(i, j int) bool { return timers[i].Before(timers[j]) })
+	sort.Slice(tickers, func(i, j int) bool { return tickers[i] < tickers[j] })
+	return timers, tickers
+}
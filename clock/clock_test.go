@@ -0,0 +1,138 @@
+// Add a copyright
+// Add a licence
+
+package clock_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/howbazaar/checkers"
+	"github.com/howbazaar/checkers/checkertest"
+	"github.com/howbazaar/checkers/clock"
+)
+
+var epoch = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// backoff waits on timers of doubling duration, as retry code does,
+// returning once attempt succeeds.
+func backoff(c clock.Clock, attempt func() bool, done chan<- int) {
+	delay := time.Second
+	for tries := 1; ; tries++ {
+		if attempt() {
+			done <- tries
+			return
+		}
+		timer := c.NewTimer(delay)
+		<-timer.C
+		delay *= 2
+	}
+}
+
+func TestFakeAdvance(t *testing.T) {
+	f := clock.NewFake(epoch)
+	timer := f.NewTimer(2 * time.Second)
+	ticker := f.NewTicker(time.Second)
+
+	f.Advance(time.Second)
+	if got := <-ticker.C; !got.Equal(epoch.Add(time.Second)) {
+		t.Errorf("unexpected tick at %v", got)
+	}
+	checkertest.AssertFails(t, clock.TimerFired, "timer has not fired, it is due in 1s", timer)
+
+	f.Advance(time.Second)
+	if got := <-timer.C; !got.Equal(epoch.Add(2 * time.Second)) {
+		t.Errorf("unexpected timer fire at %v", got)
+	}
+	checkertest.AssertPasses(t, clock.TimerFired, timer)
+	if timer.Stop() {
+		t.Errorf("stopping a fired timer reported it active")
+	}
+
+	// The tick at 2s was not received, so the one at 3s is dropped.
+	f.Advance(time.Second)
+	if got := <-ticker.C; !got.Equal(epoch.Add(2 * time.Second)) {
+		t.Errorf("unexpected tick at %v", got)
+	}
+	if got := f.Now(); !got.Equal(epoch.Add(3 * time.Second)) {
+		t.Errorf("unexpected time %v", got)
+	}
+	ticker.Stop()
+	checkertest.AssertPasses(t, clock.NoTimersPending, f)
+}
+
+func TestBackoff(t *testing.T) {
+	f := clock.NewFake(epoch)
+	clock.CheckStoppedAtEnd(t, f)
+	calls := make(chan bool)
+	done := make(chan int)
+	go backoff(f, func() bool { return <-calls }, done)
+	for i := 0; i < 3; i++ {
+		calls <- false
+		// Wait for the timer to be made before advancing.
+		for clock.NoTimersPending.Check(f) == nil {
+			time.Sleep(time.Millisecond)
+		}
+		f.Advance(time.Duration(1<<uint(i)) * time.Second)
+	}
+	calls <- true
+	if tries := <-done; tries != 4 {
+		t.Errorf("expected 4 tries, got %d", tries)
+	}
+	if got := f.Now(); !got.Equal(epoch.Add(7 * time.Second)) {
+		t.Errorf("unexpected time %v", got)
+	}
+}
+
+func TestTimerFired(t *testing.T) {
+	f := clock.NewFake(epoch)
+	stopped := f.NewTimer(time.Second)
+	stopped.Stop()
+	reset := f.NewTimer(time.Second)
+	f.Advance(time.Second)
+	reset.Reset(time.Minute)
+	checkertest.Run(t, clock.TimerFired, []checkertest.Case{
+		{Description: "stopped", Obtained: stopped, Err: "timer was stopped before it fired"},
+		{Description: "reset", Obtained: reset, Err: "timer has not fired, it is due in 1m0s"},
+		{Description: "real timer", Obtained: clock.Real.NewTimer(time.Hour), Err: "TimerFired checker expected a timer from a fake clock"},
+		{Description: "not a timer", Obtained: f, Err: `TimerFired checker expected \*clock.Timer, obtained was \*clock.Fake`},
+	})
+	checkertest.CheckArguments(t, clock.TimerFired, f.NewTimer(-time.Second))
+}
+
+func TestTickersPending(t *testing.T) {
+	f := clock.NewFake(epoch)
+	f.NewTicker(time.Second)
+	f.NewTicker(time.Minute)
+	f.NewTicker(time.Hour).Stop()
+	checkertest.CheckArguments(t, clock.TickersPending, f, 2)
+	checkertest.Run(t, clock.TickersPending, []checkertest.Case{
+		{Description: "pending", Obtained: f, Extras: []interface{}{2}},
+		{Description: "too few", Obtained: f, Extras: []interface{}{3}, Err: "expected 3 tickers pending, got 2 with periods 1s, 1m0s"},
+		{Description: "none", Obtained: clock.NewFake(epoch), Extras: []interface{}{1}, Err: "expected 1 tickers pending, got 0"},
+		{Description: "bad count", Obtained: f, Extras: []interface{}{"2"}, Err: "expected count must be an int, got string"},
+	})
+}
+
+func TestNoTimersPending(t *testing.T) {
+	f := clock.NewFake(epoch)
+	f.NewTimer(5 * time.Second)
+	f.NewTimer(time.Second).Stop()
+	f.NewTicker(time.Minute)
+	checkertest.AssertFails(t, clock.NoTimersPending,
+		"timers or tickers not stopped:\n\ttimer due in 5s\n\tticker every 1m0s", f)
+
+	r := checkers.NewRecordingTB()
+	r.Run(func(tb testing.TB) {
+		f := clock.NewFake(epoch)
+		clock.CheckStoppedAtEnd(tb, f)
+		timer := f.NewTimer(time.Second)
+		f.NewTimer(time.Minute)
+		tb.Cleanup(func() { timer.Stop() })
+	})
+	if err := checkers.DeepEquals.Check(r.Errors(), []string{
+		"timers or tickers not stopped:\n\ttimer due in 1m0s",
+	}); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,26 @@
+// Add a copyright
+// Add a licence
+
+//go:build go1.15
+// +build go1.15
+
+package clock
+
+import "time"
+
+// Reset stops the ticker and restarts it with the new period. It panics
+// if d is not positive, as time.Ticker does.
+func (t *Ticker) Reset(d time.Duration) {
+	if t.real != nil {
+		t.real.Reset(d)
+		return
+	}
+	if d <= 0 {
+		panic("non-positive interval for Ticker.Reset")
+	}
+	t.fake.mu.Lock()
+	defer t.fake.mu.Unlock()
+	t.stopped = false
+	t.period = d
+	t.next = t.fake.now.Add(d)
+}
@@ -0,0 +1,26 @@
+// Add a copyright
+// Add a licence
+
+//go:build go1.15
+// +build go1.15
+
+package clock_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/howbazaar/checkers/checkertest"
+	"github.com/howbazaar/checkers/clock"
+)
+
+func TestTickerReset(t *testing.T) {
+	f := clock.NewFake(epoch)
+	f.NewTicker(time.Second).Reset(time.Minute)
+	stopped := f.NewTicker(time.Hour)
+	stopped.Stop()
+	stopped.Reset(2 * time.Hour)
+	checkertest.Run(t, clock.TickersPending, []checkertest.Case{
+		{Description: "reset", Obtained: f, Extras: []interface{}{3}, Err: "expected 3 tickers pending, got 2 with periods 1m0s, 2h0m0s"},
+	})
+}
@@ -0,0 +1,53 @@
+// Add a copyright
+// Add a licence
+
+// Package cmpchecker provides a checker that compares values using
+// github.com/google/go-cmp. It is a separate module so that only code
+// using it depends on go-cmp.
+package cmpchecker
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/howbazaar/checkers"
+)
+
+type deepEqualsCmp struct{}
+
+// DeepEqualsCmp checker compares the obtained and expected values with
+// cmp.Equal. Any cmp.Option values following the expected value are
+// passed through to go-cmp, and a failure is described with cmp.Diff.
+// Comments made with checkers.Commentf may be given with the options.
+var DeepEqualsCmp checkers.Checker = deepEqualsCmp{}
+
+func (deepEqualsCmp) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := checkers.SplitComments(extras)
+	defer checkers.AddComments(&err, comments)
+	if len(extras) == 0 {
+		return errors.New("missing 'expected' value")
+	}
+	expected, extras := extras[0], extras[1:]
+	var options []cmp.Option
+	for _, extra := range extras {
+		option, ok := extra.(cmp.Option)
+		if !ok {
+			return fmt.Errorf("extra value %T(%#v) is not a cmp.Option", extra, extra)
+		}
+		options = append(options, option)
+	}
+
+	// go-cmp panics when the options given cannot handle the values,
+	// such as structs with unexported fields.
+	defer func() {
+		if v := recover(); v != nil {
+			err = fmt.Errorf("cmp unable to compare values: %v", v)
+		}
+	}()
+	if cmp.Equal(obtained, expected, options...) {
+		return nil
+	}
+	return fmt.Errorf("mismatch (-obtained +expected):\n%s", cmp.Diff(obtained, expected, options...))
+}
@@ -0,0 +1,82 @@
+// Add a copyright
+// Add a licence
+
+package cmpchecker_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/howbazaar/checkers"
+	"github.com/howbazaar/checkers/cmpchecker"
+)
+
+type record struct {
+	Name  string
+	Tags  []string
+	count int
+}
+
+func TestDeepEqualsCmp(t *testing.T) {
+	for _, test := range []struct {
+		description string
+		obtained    interface{}
+		extras      []interface{}
+		err         string
+	}{
+		{
+			description: "missing expected",
+			obtained:    1,
+			err:         `^missing 'expected' value$`,
+		}, {
+			description: "equal",
+			obtained:    record{Name: "a"},
+			extras:      []interface{}{record{Name: "a"}, cmpopts.IgnoreUnexported(record{})},
+		}, {
+			description: "options used",
+			obtained:    record{Name: "a", Tags: []string{}},
+			extras:      []interface{}{record{Name: "a"}, cmpopts.IgnoreUnexported(record{}), cmpopts.EquateEmpty()},
+		}, {
+			description: "unequal",
+			obtained:    record{Name: "a"},
+			extras:      []interface{}{record{Name: "b"}, cmpopts.IgnoreUnexported(record{})},
+			// go-cmp deliberately varies its output between regular and
+			// non-breaking spaces.
+			err: `(?s)^mismatch \(-obtained \+expected\):\n.*-[\s\x{a0}]+Name:[ \x{a0}]+"a",\n.*\+[\s\x{a0}]+Name:[ \x{a0}]+"b",.*`,
+		}, {
+			description: "unexported fields without option",
+			obtained:    record{Name: "a"},
+			extras:      []interface{}{record{Name: "a"}},
+			err:         `^cmp unable to compare values: .*`,
+		}, {
+			description: "extra not an option",
+			obtained:    1,
+			extras:      []interface{}{1, "foo"},
+			err:         `^extra value string\("foo"\) is not a cmp.Option$`,
+		}, {
+			description: "comment with options",
+			obtained:    record{Name: "a"},
+			extras:      []interface{}{record{Name: "a"}, checkers.Commentf("case %d", 1), cmpopts.IgnoreUnexported(record{})},
+		}, {
+			description: "comment on failure",
+			obtained:    1,
+			extras:      []interface{}{2, checkers.Commentf("case %d", 1)},
+			err:         `(?s)^mismatch \(-obtained \+expected\):\n.*\ncomment: case 1$`,
+		},
+	} {
+		err := cmpchecker.DeepEqualsCmp.Check(test.obtained, test.extras...)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+			continue
+		}
+		if test.err == "" {
+			t.Errorf("%s: unexpected error: %v", test.description, err)
+		} else if ok, _ := regexp.MatchString(test.err, err.Error()); !ok {
+			t.Errorf("%s: error mismatch: \n\tobtained: %q\n\texpected: %q", test.description, err.Error(), test.err)
+		}
+	}
+}
@@ -0,0 +1,10 @@
+module github.com/howbazaar/checkers/cmpchecker
+
+go 1.14
+
+replace github.com/howbazaar/checkers => ../
+
+require (
+	github.com/google/go-cmp v0.6.0
+	github.com/howbazaar/checkers v0.0.0-00010101000000-000000000000
+)
@@ -0,0 +1,296 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+type mapOfSlicesEquivalent struct{}
+
+// MapOfSlicesEquivalent checker checks that the obtained map of slices,
+// such as an http.Header or url.Values, has the same keys as the
+// expected map, of the same type, with each key holding the same
+// elements in any order. Any DeepEqualOption values following the
+// expected value alter how the elements are compared.
+var MapOfSlicesEquivalent Checker = mapOfSlicesEquivalent{}
+
+func (mapOfSlicesEquivalent) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if len(extras) == 0 {
+		return errors.New("missing 'expected' value")
+	}
+	expected, extras := extras[0], extras[1:]
+	options := make([]interface{}, 0, len(extras)+1)
+	for i, extra := range extras {
+		if _, ok := extra.(DeepEqualOption); !ok {
+			return unexpectedExtras("MapOfSlicesEquivalent", extras[i:])
+		}
+		options = append(options, extra)
+	}
+	t := reflect.TypeOf(obtained)
+	if t == nil || t.Kind() != reflect.Map || t.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("MapOfSlicesEquivalent checker expected a map of slices, obtained was %s", describeType(obtained))
+	}
+	if reflect.TypeOf(expected) != t {
+		return fmt.Errorf("expected value must be a %s, got %s", t, describeType(expected))
+	}
+	options = append(options, IgnoreOrder(reflect.Zero(t.Elem()).Interface()))
+	return DeepEquals.Check(obtained, append([]interface{}{expected}, options...)...)
+}
+
+type contains struct{}
+
+// Contains checker checks that the obtained value contains the expected
+// one. A string must contain the expected string as a substring, an
+// array or slice must have an element, and a map a value, that is deeply
+// equal to the expected value, as compared by DeepEqual. The expected
+// value must be of the obtained value's element type, unless that is an
+// interface type.
+//
+//	t.Check(names, checkers.Contains, "alice")
+var Contains Checker = contains{}
+
+func (contains) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if len(extras) == 0 {
+		return errors.New("missing 'expected' value")
+	}
+	expected, extras := extras[0], extras[1:]
+	if err := unexpectedExtras("Contains", extras); err != nil {
+		return err
+	}
+	v := reflect.ValueOf(obtained)
+	switch v.Kind() {
+	case reflect.String:
+		substring, ok := expected.(string)
+		if !ok {
+			return fmt.Errorf("expected value must be a string, got %s", describeType(expected))
+		}
+		if strings.Contains(v.String(), substring) {
+			return nil
+		}
+		return failf("%q does not contain %q", v.String(), substring)
+	case reflect.Array, reflect.Slice, reflect.Map:
+	default:
+		return fmt.Errorf("Contains checker expected a string, array, slice or map, obtained was %s", describeType(obtained))
+	}
+	elemType := v.Type().Elem()
+	if elemType.Kind() != reflect.Interface && reflect.TypeOf(expected) != elemType {
+		return fmt.Errorf("expected value must be a %s, got %s", elemType, describeType(expected))
+	}
+	if v.Kind() == reflect.Map {
+		iter := v.MapRange()
+		for iter.Next() {
+			if ok, _ := DeepEqual(iter.Value().Interface(), expected); ok {
+				return nil
+			}
+		}
+		return lazyFailure(func() string {
+			return fmt.Sprintf("%s has no value %s", describe(obtained), describe(expected))
+		})
+	}
+	for i := 0; i < v.Len(); i++ {
+		if ok, _ := DeepEqual(v.Index(i).Interface(), expected); ok {
+			return nil
+		}
+	}
+	return lazyFailure(func() string {
+		return fmt.Sprintf("%s has no element %s", describe(obtained), describe(expected))
+	})
+}
+
+// maxListedEntries is how many of the keys or values of a map a failure
+// of HasKey or HasValue lists.
+const maxListedEntries = 10
+
+type hasKey struct{}
+
+// HasKey checker checks that the obtained map holds the expected key,
+// which must be of the map's key type, unless that is an interface
+// type. A failure lists the keys the map does hold, in order, up to a
+// limit.
+//
+//	t.Check(config, checkers.HasKey, "listen-address")
+var HasKey Checker = hasKey{}
+
+func (hasKey) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if len(extras) == 0 {
+		return errors.New("missing 'expected' value")
+	}
+	expected, extras := extras[0], extras[1:]
+	if err := unexpectedExtras("HasKey", extras); err != nil {
+		return err
+	}
+	m := reflect.ValueOf(obtained)
+	if m.Kind() != reflect.Map {
+		return fmt.Errorf("HasKey checker expected a map, obtained was %s", describeType(obtained))
+	}
+	keyType := m.Type().Key()
+	var key reflect.Value
+	switch {
+	case expected == nil && keyType.Kind() == reflect.Interface:
+		key = reflect.Zero(keyType)
+	case expected == nil || (keyType.Kind() != reflect.Interface && reflect.TypeOf(expected) != keyType):
+		return fmt.Errorf("expected key must be a %s, got %s", keyType, describeType(expected))
+	case !reflect.TypeOf(expected).Comparable():
+		return fmt.Errorf("expected key must be comparable, got %s", describeType(expected))
+	default:
+		key = reflect.ValueOf(expected)
+	}
+	if m.MapIndex(key).IsValid() {
+		return nil
+	}
+	return lazyFailure(func() string {
+		return fmt.Sprintf("key %s not found in %s with keys %s",
+			prettyPrintLine(key), m.Type(), listEntries(sortedKeys(m)))
+	})
+}
+
+type hasValue struct{}
+
+// HasValue checker checks that the obtained map holds a value deeply
+// equal to the expected one, as compared by DeepEqual. The expected
+// value must be of the map's element type, unless that is an interface
+// type. A failure lists the values the map does hold, in the order of
+// their keys, up to a limit.
+//
+//	t.Check(owners, checkers.HasValue, "alice")
+var HasValue Checker = hasValue{}
+
+func (hasValue) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if len(extras) == 0 {
+		return errors.New("missing 'expected' value")
+	}
+	expected, extras := extras[0], extras[1:]
+	if err := unexpectedExtras("HasValue", extras); err != nil {
+		return err
+	}
+	m := reflect.ValueOf(obtained)
+	if m.Kind() != reflect.Map {
+		return fmt.Errorf("HasValue checker expected a map, obtained was %s", describeType(obtained))
+	}
+	elemType := m.Type().Elem()
+	if elemType.Kind() != reflect.Interface && reflect.TypeOf(expected) != elemType {
+		return fmt.Errorf("expected value must be a %s, got %s", elemType, describeType(expected))
+	}
+	iter := m.MapRange()
+	for iter.Next() {
+		if ok, _ := DeepEqual(iter.Value().Interface(), expected); ok {
+			return nil
+		}
+	}
+	return lazyFailure(func() string {
+		keys := sortedKeys(m)
+		values := make([]reflect.Value, len(keys))
+		for i, key := range keys {
+			values[i] = m.MapIndex(key)
+		}
+		return fmt.Sprintf("value %s not found in %s with values %s",
+			prettyPrintLine(reflect.ValueOf(expected)), m.Type(), listEntries(values))
+	})
+}
+
+// listEntries renders the first maxListedEntries of the values as a
+// list, saying how many more there are.
+func listEntries(values []reflect.Value) string {
+	return listRendered(len(values), func(i int) string {
+		return prettyPrintLine(values[i])
+	})
+}
+
+// listRendered lists the first maxListedEntries of n items, rendered by
+// render, saying how many more there are.
+func listRendered(n int, render func(i int) string) string {
+	var items []string
+	for i := 0; i < n; i++ {
+		if i == maxListedEntries {
+			items = append(items, fmt.Sprintf("... %d more", n-i))
+			break
+		}
+		items = append(items, render(i))
+	}
+	return "[" + strings.Join(items, ", ") + "]"
+}
+
+type emptiness struct {
+	name  string
+	empty bool
+}
+
+// IsEmpty checker checks that the obtained array, channel, map, slice
+// or string has a length of zero. Unlike HasLen with 0, a failure lists
+// what the value holds, up to a limit.
+//
+//	t.Check(errs, checkers.IsEmpty)
+var IsEmpty Checker = emptiness{"IsEmpty", true}
+
+// IsNotEmpty checker checks that the obtained array, channel, map,
+// slice or string has a length other than zero.
+var IsNotEmpty Checker = emptiness{"IsNotEmpty", false}
+
+func (c emptiness) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if err := unexpectedExtras(c.name, extras); err != nil {
+		return err
+	}
+	v := reflect.ValueOf(obtained)
+	switch v.Kind() {
+	case reflect.Array, reflect.Chan, reflect.Map, reflect.Slice, reflect.String:
+	default:
+		return fmt.Errorf("%s checker expected array, channel, map, slice or string, obtained was %s", c.name, describeType(obtained))
+	}
+	n := v.Len()
+	switch {
+	case (n == 0) == c.empty:
+		return nil
+	case !c.empty:
+		return failf("expected not empty, got empty %s", v.Type())
+	}
+	return lazyFailure(func() string {
+		return "expected empty, got " + describeContents(v)
+	})
+}
+
+// describeContents describes how many elements the value, which is not
+// empty, holds, and lists them.
+func describeContents(v reflect.Value) string {
+	n := v.Len()
+	switch v.Kind() {
+	case reflect.String:
+		return fmt.Sprintf("%s: %q", plural(n, "byte"), v.String())
+	case reflect.Chan:
+		return fmt.Sprintf("%s buffered in %s", plural(n, "element"), v.Type())
+	case reflect.Map:
+		keys := sortedKeys(v)
+		return plural(n, "entry") + ": " + listRendered(n, func(i int) string {
+			return prettyPrintLine(keys[i]) + ": " + prettyPrintLine(v.MapIndex(keys[i]))
+		})
+	}
+	return plural(n, "element") + ": " + listRendered(n, func(i int) string {
+		return prettyPrintLine(v.Index(i))
+	})
+}
+
+// plural returns the count of things, named by the singular noun.
+func plural(n int, noun string) string {
+	switch {
+	case n == 1:
+	case strings.HasSuffix(noun, "y"):
+		noun = strings.TrimSuffix(noun, "y") + "ies"
+	default:
+		noun += "s"
+	}
+	return fmt.Sprintf("%d %s", n, noun)
+}
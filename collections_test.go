@@ -0,0 +1,387 @@
+// Add a copyright
+// Add a licence
+
+package checkers_test
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/howbazaar/checkers"
+)
+
+func TestMapOfSlicesEquivalent(t *testing.T) {
+	for _, test := range []struct {
+		description string
+		obtained    interface{}
+		extras      []interface{}
+		err         string
+	}{
+		{
+			description: "reordered values",
+			obtained:    url.Values{"a": {"1", "2"}, "b": {"3"}},
+			extras:      []interface{}{url.Values{"b": {"3"}, "a": {"2", "1"}}},
+		}, {
+			description: "header",
+			obtained:    http.Header{"Accept": {"text/html", "application/json"}},
+			extras:      []interface{}{http.Header{"Accept": {"application/json", "text/html"}}},
+		}, {
+			description: "plain map",
+			obtained:    map[int][]int{1: {1, 2, 2}},
+			extras:      []interface{}{map[int][]int{1: {2, 1, 2}}},
+		}, {
+			description: "different values",
+			obtained:    map[int][]int{1: {1, 2, 2}},
+			extras:      []interface{}{map[int][]int{1: {2, 1, 1}}},
+			err: `2 mismatches:
+	mismatch at \[1\]\[2\]: unexpected element; obtained 2; expected <nil>
+	mismatch at \[1\]: missing element; obtained <nil>; expected 1
+`,
+		}, {
+			description: "missing value",
+			obtained:    url.Values{"a": {"1"}},
+			extras:      []interface{}{url.Values{"a": {"1", "2"}}},
+			err:         `mismatch at \["a"\]: length mismatch, 1 vs 2; obtained \[\]string{"1"}; expected \[\]string{"1", "2"}\n`,
+		}, {
+			description: "missing key",
+			obtained:    url.Values{"a": {"1"}},
+			extras:      []interface{}{url.Values{"a": {"1"}, "b": {"2"}}},
+			err:         `mismatch at top level: length mismatch, 1 vs 2; .*\n\+\t"b": \[\]string{\n`,
+		}, {
+			description: "options",
+			obtained:    map[string][]*int{"a": {nil}},
+			extras:      []interface{}{map[string][]*int{"a": {nil}}, checkers.MaxMismatches(1)},
+		}, {
+			description: "not a map of slices",
+			obtained:    map[string]string{},
+			extras:      []interface{}{map[string]string{}},
+			err:         `MapOfSlicesEquivalent checker expected a map of slices, obtained was type map\[string\]string`,
+		}, {
+			description: "different types",
+			obtained:    url.Values{},
+			extras:      []interface{}{http.Header{}},
+			err:         `expected value must be a url.Values, got type http.Header`,
+		}, {
+			description: "unexpected extra",
+			obtained:    url.Values{},
+			extras:      []interface{}{url.Values{}, "x"},
+			err:         `too many arguments to checker MapOfSlicesEquivalent, unexpected string\("x"\)`,
+		},
+	} {
+		err := checkers.MapOfSlicesEquivalent.Check(test.obtained, test.extras...)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+		} else {
+			if test.err == "" {
+				t.Errorf("%s: unexpected error: %v", test.description, err)
+			} else if matchErr := checkers.Matches.Check(err.Error(), "(?s)"+test.err+".*"); matchErr != nil {
+				t.Errorf("%s: error mismatch: %v", test.description, matchErr)
+			}
+		}
+	}
+}
+
+func TestContains(t *testing.T) {
+	type point struct{ X, Y int }
+	for _, test := range []struct {
+		description string
+		obtained    interface{}
+		extras      []interface{}
+		err         string
+	}{
+		{
+			description: "substring",
+			obtained:    "hello world",
+			extras:      []interface{}{"o w"},
+		}, {
+			description: "missing substring",
+			obtained:    "hello world",
+			extras:      []interface{}{"bye"},
+			err:         `"hello world" does not contain "bye"`,
+		}, {
+			description: "slice element",
+			obtained:    []point{{1, 2}, {3, 4}},
+			extras:      []interface{}{point{3, 4}},
+		}, {
+			description: "array element",
+			obtained:    [2]string{"a", "b"},
+			extras:      []interface{}{"b"},
+		}, {
+			description: "interface elements",
+			obtained:    []interface{}{1, "two", nil},
+			extras:      []interface{}{"two"},
+		}, {
+			description: "missing element",
+			obtained:    []point{{1, 2}},
+			extras:      []interface{}{point{2, 1}},
+			err:         `[]checkers_test.point value []checkers_test.point{checkers_test.point{X: 1, Y: 2}} has no element checkers_test.point value checkers_test.point{X: 2, Y: 1}`,
+		}, {
+			description: "map value",
+			obtained:    map[string][]int{"a": {1}, "b": {2, 3}},
+			extras:      []interface{}{[]int{2, 3}},
+		}, {
+			description: "missing map value",
+			obtained:    map[string]int{"b": 2, "a": 1},
+			extras:      []interface{}{3},
+			err:         `map[string]int value map[string]int{"a": 1, "b": 2} has no value int value 3`,
+		}, {
+			description: "with comment",
+			obtained:    []int{1},
+			extras:      []interface{}{2, checkers.Commentf("ids")},
+			err:         "[]int value [1] has no element int value 2\ncomment: ids",
+		}, {
+			description: "wrong element type",
+			obtained:    []int64{1},
+			extras:      []interface{}{1},
+			err:         `expected value must be a int64, got type int`,
+		}, {
+			description: "string with non-string",
+			obtained:    "abc",
+			extras:      []interface{}{'a'},
+			err:         `expected value must be a string, got type int32`,
+		}, {
+			description: "unsupported",
+			obtained:    42,
+			extras:      []interface{}{4},
+			err:         `Contains checker expected a string, array, slice or map, obtained was type int`,
+		}, {
+			description: "nil",
+			obtained:    nil,
+			extras:      []interface{}{4},
+			err:         `Contains checker expected a string, array, slice or map, obtained was nil`,
+		}, {
+			description: "missing expected",
+			obtained:    []int{},
+			err:         `missing 'expected' value`,
+		},
+	} {
+		err := checkers.Contains.Check(test.obtained, test.extras...)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+		} else {
+			if test.err == "" {
+				t.Errorf("%s: unexpected error: %v", test.description, err)
+			} else if err.Error() != test.err {
+				t.Errorf("%s: error mismatch: \n\tobtained: %q\n\texpected: %q", test.description, err.Error(), test.err)
+			}
+		}
+	}
+}
+
+func TestHasKey(t *testing.T) {
+	many := make(map[int]bool)
+	for i := 0; i < 12; i++ {
+		many[i] = true
+	}
+	for _, test := range []struct {
+		description string
+		checker     checkers.Checker
+		obtained    interface{}
+		extras      []interface{}
+		err         string
+	}{
+		{
+			description: "key",
+			checker:     checkers.HasKey,
+			obtained:    map[string]int{"a": 1},
+			extras:      []interface{}{"a"},
+		}, {
+			description: "interface key",
+			checker:     checkers.HasKey,
+			obtained:    map[interface{}]int{1: 1, nil: 2},
+			extras:      []interface{}{nil},
+		}, {
+			description: "missing key",
+			checker:     checkers.HasKey,
+			obtained:    map[string]int{"b": 2, "a": 1},
+			extras:      []interface{}{"c"},
+			err:         `key "c" not found in map[string]int with keys ["a", "b"]`,
+		}, {
+			description: "many keys",
+			checker:     checkers.HasKey,
+			obtained:    many,
+			extras:      []interface{}{20, checkers.Commentf("listing")},
+			err:         "key 20 not found in map[int]bool with keys [0, 1, 2, 3, 4, 5, 6, 7, 8, 9, ... 2 more]\ncomment: listing",
+		}, {
+			description: "wrong key type",
+			checker:     checkers.HasKey,
+			obtained:    map[string]int{},
+			extras:      []interface{}{1},
+			err:         `expected key must be a string, got type int`,
+		}, {
+			description: "uncomparable key",
+			checker:     checkers.HasKey,
+			obtained:    map[interface{}]int{},
+			extras:      []interface{}{[]int{1}},
+			err:         `expected key must be comparable, got type []int`,
+		}, {
+			description: "not a map",
+			checker:     checkers.HasKey,
+			obtained:    []string{"a"},
+			extras:      []interface{}{"a"},
+			err:         `HasKey checker expected a map, obtained was type []string`,
+		}, {
+			description: "value",
+			checker:     checkers.HasValue,
+			obtained:    map[string][]int{"a": {1, 2}},
+			extras:      []interface{}{[]int{1, 2}},
+		}, {
+			description: "nil value",
+			checker:     checkers.HasValue,
+			obtained:    map[string]interface{}{"a": nil},
+			extras:      []interface{}{nil},
+		}, {
+			description: "missing value",
+			checker:     checkers.HasValue,
+			obtained:    map[string]int{"b": 1, "a": 2},
+			extras:      []interface{}{3},
+			err:         `value 3 not found in map[string]int with values [2, 1]`,
+		}, {
+			description: "missing nil value",
+			checker:     checkers.HasValue,
+			obtained:    map[string]interface{}{"a": 1},
+			extras:      []interface{}{nil},
+			err:         `value <nil> not found in map[string]interface {} with values [1]`,
+		}, {
+			description: "wrong value type",
+			checker:     checkers.HasValue,
+			obtained:    map[string]int{},
+			extras:      []interface{}{"1"},
+			err:         `expected value must be a int, got type string`,
+		}, {
+			description: "HasValue not a map",
+			checker:     checkers.HasValue,
+			obtained:    nil,
+			extras:      []interface{}{"a"},
+			err:         `HasValue checker expected a map, obtained was nil`,
+		},
+	} {
+		err := test.checker.Check(test.obtained, test.extras...)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+		} else {
+			if test.err == "" {
+				t.Errorf("%s: unexpected error: %v", test.description, err)
+			} else if err.Error() != test.err {
+				t.Errorf("%s: error mismatch: \n\tobtained: %q\n\texpected: %q", test.description, err.Error(), test.err)
+			}
+		}
+	}
+}
+
+func TestIsEmpty(t *testing.T) {
+	buffered := make(chan int, 3)
+	buffered <- 1
+	buffered <- 2
+	many := make([]int, 12)
+	for i := range many {
+		many[i] = i
+	}
+	for _, test := range []struct {
+		description string
+		checker     checkers.Checker
+		obtained    interface{}
+		extras      []interface{}
+		err         string
+	}{
+		{
+			description: "empty string",
+			checker:     checkers.IsEmpty,
+			obtained:    "",
+		}, {
+			description: "nil slice",
+			checker:     checkers.IsEmpty,
+			obtained:    []string(nil),
+		}, {
+			description: "empty map",
+			checker:     checkers.IsEmpty,
+			obtained:    map[string]int{},
+		}, {
+			description: "empty channel",
+			checker:     checkers.IsEmpty,
+			obtained:    make(chan int, 1),
+		}, {
+			description: "string",
+			checker:     checkers.IsEmpty,
+			obtained:    "a",
+			err:         `expected empty, got 1 byte: "a"`,
+		}, {
+			description: "slice",
+			checker:     checkers.IsEmpty,
+			obtained:    []string{"a", "b", "c"},
+			extras:      []interface{}{checkers.Commentf("errors")},
+			err:         "expected empty, got 3 elements: [\"a\", \"b\", \"c\"]\ncomment: errors",
+		}, {
+			description: "long slice",
+			checker:     checkers.IsEmpty,
+			obtained:    many,
+			err:         "expected empty, got 12 elements: [0, 1, 2, 3, 4, 5, 6, 7, 8, 9, ... 2 more]",
+		}, {
+			description: "array",
+			checker:     checkers.IsEmpty,
+			obtained:    [1]bool{},
+			err:         "expected empty, got 1 element: [false]",
+		}, {
+			description: "map",
+			checker:     checkers.IsEmpty,
+			obtained:    map[string]int{"b": 2, "a": 1},
+			err:         `expected empty, got 2 entries: ["a": 1, "b": 2]`,
+		}, {
+			description: "channel",
+			checker:     checkers.IsEmpty,
+			obtained:    buffered,
+			err:         "expected empty, got 2 elements buffered in chan int",
+		}, {
+			description: "not a collection",
+			checker:     checkers.IsEmpty,
+			obtained:    0,
+			err:         "IsEmpty checker expected array, channel, map, slice or string, obtained was type int",
+		}, {
+			description: "not empty",
+			checker:     checkers.IsNotEmpty,
+			obtained:    map[int]int{1: 1},
+		}, {
+			description: "empty",
+			checker:     checkers.IsNotEmpty,
+			obtained:    []byte{},
+			err:         "expected not empty, got empty []uint8",
+		}, {
+			description: "empty string not empty",
+			checker:     checkers.IsNotEmpty,
+			obtained:    "",
+			extras:      []interface{}{checkers.Commentf("name")},
+			err:         "expected not empty, got empty string\ncomment: name",
+		}, {
+			description: "nil",
+			checker:     checkers.IsNotEmpty,
+			obtained:    nil,
+			err:         "IsNotEmpty checker expected array, channel, map, slice or string, obtained was nil",
+		}, {
+			description: "too many",
+			checker:     checkers.IsNotEmpty,
+			obtained:    "a",
+			extras:      []interface{}{1},
+			err:         "too many arguments to checker IsNotEmpty, unexpected int(1)",
+		},
+	} {
+		err := test.checker.Check(test.obtained, test.extras...)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+		} else {
+			if test.err == "" {
+				t.Errorf("%s: unexpected error: %v", test.description, err)
+			} else if err.Error() != test.err {
+				t.Errorf("%s: error mismatch: \n\tobtained: %q\n\texpected: %q", test.description, err.Error(), test.err)
+			}
+		}
+	}
+}
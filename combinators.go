@@ -0,0 +1,47 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"errors"
+	"strings"
+)
+
+type not struct {
+	checker Checker
+}
+
+// Not returns a checker that passes when the given checker fails, and
+// fails when it passes, with the same obtained and extra values.
+//
+//	t.Assert(name, checkers.Not(checkers.Matches), "tmp.*")
+//
+// Any error from the given checker counts as it failing, including
+// those for missing or unexpected extra values, so a negated check
+// should be written by first seeing the check pass.
+func Not(checker Checker) Checker {
+	return not{checker}
+}
+
+func (n not) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if n.checker == nil {
+		return errors.New("Not needs a checker to negate, got nil")
+	}
+	if n.checker.Check(obtained, extras...) != nil {
+		return nil
+	}
+	return lazyFailure(func() string {
+		var buf strings.Builder
+		buf.WriteString(checkerName(n.checker))
+		buf.WriteString(" checker unexpectedly succeeded; obtained ")
+		buf.WriteString(describe(obtained))
+		for _, extra := range extras {
+			buf.WriteString("; with ")
+			buf.WriteString(describe(extra))
+		}
+		return buf.String()
+	})
+}
@@ -0,0 +1,74 @@
+// Add a copyright
+// Add a licence
+
+package checkers_test
+
+import (
+	"testing"
+
+	"github.com/howbazaar/checkers"
+)
+
+func TestNot(t *testing.T) {
+	for _, test := range []struct {
+		description string
+		checker     checkers.Checker
+		obtained    interface{}
+		extras      []interface{}
+		err         string
+	}{
+		{
+			description: "inner fails",
+			checker:     checkers.Not(checkers.Matches),
+			obtained:    "bar",
+			extras:      []interface{}{"foo.*"},
+		}, {
+			description: "inner passes",
+			checker:     checkers.Not(checkers.Matches),
+			obtained:    "foobar",
+			extras:      []interface{}{"foo.*"},
+			err:         `Matches checker unexpectedly succeeded; obtained string value foobar; with string value foo.*`,
+		}, {
+			description: "no extras",
+			checker:     checkers.Not(checkers.IsNil),
+			obtained:    nil,
+			err:         "IsNil checker unexpectedly succeeded; obtained nil",
+		}, {
+			description: "with comment",
+			checker:     checkers.Not(checkers.Equals),
+			obtained:    1,
+			extras:      []interface{}{1, checkers.Commentf("counting")},
+			err:         "Equals checker unexpectedly succeeded; obtained int value 1; with int value 1\ncomment: counting",
+		}, {
+			description: "unregistered checker",
+			checker:     checkers.Not(checkers.PanicsWhenCalled()),
+			obtained:    func() { panic("boom") },
+			extras:      []interface{}{"boom"},
+			err:         `checkers.panicsWhenCalled checker unexpectedly succeeded; obtained func\(\) value .*; with string value boom`,
+		}, {
+			description: "double negation",
+			checker:     checkers.Not(checkers.Not(checkers.IsTrue)),
+			obtained:    true,
+		}, {
+			description: "nil checker",
+			checker:     checkers.Not(nil),
+			obtained:    1,
+			err:         "Not needs a checker to negate, got nil",
+		},
+	} {
+		err := test.checker.Check(test.obtained, test.extras...)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+		} else {
+			if test.err == "" {
+				t.Errorf("%s: unexpected error: %v", test.description, err)
+			} else {
+				if err := checkers.Matches.Check(err.Error(), test.err); err != nil {
+					t.Errorf("%s: error mismatch: %v", test.description, err)
+				}
+			}
+		}
+	}
+}
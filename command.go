@@ -0,0 +1,248 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// CommandResult holds what a command wrote and how it exited, as
+// returned by RunCommand and RunMain.
+type CommandResult struct {
+	Stdout   string
+	Stderr   string
+	ExitCode int
+}
+
+// RunCommand runs the named command with the arguments, returning its
+// output and exit code. A command that exits with a non-zero code is
+// not a failure of the test, so that it can be checked with
+// ExitCodeEquals, but one that can't be run at all fails the test
+// immediately.
+func (t *Test) RunCommand(name string, args ...string) *CommandResult {
+	tb := t.testingTB()
+	tb.Helper()
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(name, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	result := &CommandResult{
+		Stdout: stdout.String(),
+		Stderr: stderr.String(),
+	}
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+	case errors.As(err, &exitErr):
+		result.ExitCode = exitErr.ExitCode()
+	default:
+		tb.Fatalf("unable to run %s: %v", name, err)
+	}
+	return result
+}
+
+// RunMain runs the main function of a command in process, such as one
+// built with the flag package or cobra, returning what it wrote and the
+// exit code it returned. The function is given the arguments, without
+// the command name, and the writers to use for stdout and stderr.
+func (t *Test) RunMain(main func(args []string, stdout, stderr io.Writer) int, args ...string) *CommandResult {
+	t.testingTB().Helper()
+	var stdout, stderr bytes.Buffer
+	code := main(args, &stdout, &stderr)
+	return &CommandResult{
+		Stdout:   stdout.String(),
+		Stderr:   stderr.String(),
+		ExitCode: code,
+	}
+}
+
+// commandResult returns the obtained value as a *CommandResult.
+func commandResult(checker string, obtained interface{}) (*CommandResult, error) {
+	result, ok := obtained.(*CommandResult)
+	if !ok || result == nil {
+		return nil, fmt.Errorf("%s checker expected *CommandResult, obtained was %s", checker, describe(obtained))
+	}
+	return result, nil
+}
+
+type exitCodeEquals struct{}
+
+// ExitCodeEquals checker checks that the command of the obtained
+// *CommandResult exited with the expected code. A failure includes what
+// the command wrote to stderr.
+var ExitCodeEquals Checker = exitCodeEquals{}
+
+func (exitCodeEquals) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if len(extras) == 0 {
+		return errors.New("missing 'expected' value")
+	}
+	expected, extras := extras[0], extras[1:]
+	if err := unexpectedExtras("ExitCodeEquals", extras); err != nil {
+		return err
+	}
+	result, err := commandResult("ExitCodeEquals", obtained)
+	if err != nil {
+		return err
+	}
+	code, ok := expected.(int)
+	if !ok {
+		return fmt.Errorf("expected exit code must be an int, got %s", describeType(expected))
+	}
+	if result.ExitCode == code {
+		return nil
+	}
+	return failf("expected exit code %d, got %d; stderr: %q", code, result.ExitCode, result.Stderr)
+}
+
+type outputMatches struct {
+	stderr bool
+}
+
+// StdoutMatches checker checks that what the command of the obtained
+// *CommandResult wrote to stdout matches the regexp pattern in full,
+// with "." matching newlines too.
+var StdoutMatches Checker = outputMatches{}
+
+// StderrMatches checker checks that what the command of the obtained
+// *CommandResult wrote to stderr matches the regexp pattern in full,
+// with "." matching newlines too.
+var StderrMatches Checker = outputMatches{stderr: true}
+
+func (c outputMatches) name() string {
+	if c.stderr {
+		return "StderrMatches"
+	}
+	return "StdoutMatches"
+}
+
+func (c outputMatches) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if len(extras) == 0 {
+		return errors.New("missing 'expected' value")
+	}
+	expected, extras := extras[0], extras[1:]
+	if err := unexpectedExtras(c.name(), extras); err != nil {
+		return err
+	}
+	pattern, ok := expected.(string)
+	if !ok {
+		return errors.New("expected value must be a string containing a regexp pattern")
+	}
+	if _, err := compilePattern(pattern); err != nil {
+		return err
+	}
+	// Output usually spans lines, so "." matches newlines too.
+	re, err := compilePattern("(?s)" + pattern)
+	if err != nil {
+		return err
+	}
+	result, err := commandResult(c.name(), obtained)
+	if err != nil {
+		return err
+	}
+	stream, output := "stdout", result.Stdout
+	if c.stderr {
+		stream, output = "stderr", result.Stderr
+	}
+	if !re.MatchString(output) {
+		return failf("%s: %q did not match pattern %q", stream, output, pattern)
+	}
+	return nil
+}
+
+// ExpectedCmd describes how an *exec.Cmd is expected to be set up, for
+// the CmdEquals checker. Fields left as their zero value are not
+// checked.
+type ExpectedCmd struct {
+	// Path is the path of the command to run.
+	Path string
+	// Args holds the command line arguments, including the command
+	// name as Args[0].
+	Args []string
+	// Env holds environment variables that must be set for the command,
+	// with their values. Only the variables listed are compared, so the
+	// command may have others set too. When the command's Env is nil it
+	// runs with the environment of the current process, so that is
+	// where the variables are looked for. Where a variable is set more
+	// than once, the last value is used, as it is when the command is
+	// run.
+	Env map[string]string
+	// Dir is the working directory of the command.
+	Dir string
+}
+
+type cmdEquals struct{}
+
+// CmdEquals checker checks that the obtained *exec.Cmd is set up as
+// described by the expected ExpectedCmd, without running the command,
+// so that code that builds commands can be tested on its own.
+//
+//	t.Check(cmd, checkers.CmdEquals, checkers.ExpectedCmd{
+//		Args: []string{"git", "commit", "-m", "message"},
+//		Env:  map[string]string{"GIT_AUTHOR_NAME": "test"},
+//	})
+var CmdEquals Checker = cmdEquals{}
+
+func (cmdEquals) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if len(extras) == 0 {
+		return errors.New("missing 'expected' value")
+	}
+	expected, extras := extras[0], extras[1:]
+	if err := unexpectedExtras("CmdEquals", extras); err != nil {
+		return err
+	}
+	want, ok := expected.(ExpectedCmd)
+	if !ok {
+		return fmt.Errorf("expected value must be a checkers.ExpectedCmd, got %s", describeType(expected))
+	}
+	cmd, ok := obtained.(*exec.Cmd)
+	if !ok || cmd == nil {
+		return fmt.Errorf("CmdEquals checker expected *exec.Cmd, obtained was %s", describe(obtained))
+	}
+
+	// Only the fields that are expected are compared.
+	var got ExpectedCmd
+	if want.Path != "" {
+		got.Path = cmd.Path
+	}
+	if want.Args != nil {
+		got.Args = cmd.Args
+	}
+	if want.Env != nil {
+		env := cmd.Env
+		if env == nil {
+			env = os.Environ()
+		}
+		values := make(map[string]string)
+		for _, kv := range env {
+			if i := strings.Index(kv, "="); i >= 0 {
+				values[kv[:i]] = kv[i+1:]
+			} else {
+				values[kv] = ""
+			}
+		}
+		got.Env = make(map[string]string)
+		for key := range want.Env {
+			if value, ok := values[key]; ok {
+				got.Env[key] = value
+			}
+		}
+	}
+	if want.Dir != "" {
+		got.Dir = cmd.Dir
+	}
+	return DeepEquals.Check(got, want)
+}
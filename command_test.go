@@ -0,0 +1,244 @@
+// Add a copyright
+// Add a licence
+
+package checkers_test
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/howbazaar/checkers"
+)
+
+// greet is the main function of a small command line tool.
+func greet(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("greet", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	name := flags.String("name", "world", "who to greet")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	fmt.Fprintf(stdout, "hello %s\n", *name)
+	return 0
+}
+
+func TestRunMain(t *testing.T) {
+	ct := &checkers.Test{TB: t}
+	result := ct.RunMain(greet, "-name", "gopher")
+	ct.Check(result, checkers.ExitCodeEquals, 0)
+	ct.Check(result, checkers.StdoutMatches, "hello gopher\n")
+	ct.Check(result, checkers.StderrMatches, "")
+
+	result = ct.RunMain(greet, "-unknown")
+	ct.Check(result, checkers.ExitCodeEquals, 2)
+	ct.Check(result, checkers.StderrMatches, "flag provided but not defined: -unknown\n.*")
+}
+
+func TestRunCommand(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	ct := &checkers.Test{TB: t}
+	result := ct.RunCommand("sh", "-c", "echo out; echo err >&2; exit 3")
+	ct.Check(result, checkers.DeepEquals, &checkers.CommandResult{
+		Stdout:   "out\n",
+		Stderr:   "err\n",
+		ExitCode: 3,
+	})
+
+	r := checkers.NewRecordingTB()
+	r.Run(func(tb testing.TB) {
+		ct := &checkers.Test{TB: tb}
+		ct.RunCommand("checkers-no-such-command")
+		t.Errorf("test continued after command failed to run")
+	})
+	errs := r.Errors()
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 error, got %q", errs)
+	}
+	ct.Check(errs[0], checkers.Matches, "unable to run checkers-no-such-command: .*")
+}
+
+func TestCommandCheckers(t *testing.T) {
+	result := &checkers.CommandResult{
+		Stdout:   "hello\n",
+		Stderr:   "warning: deprecated\n",
+		ExitCode: 1,
+	}
+	for _, test := range []struct {
+		description string
+		checker     checkers.Checker
+		obtained    interface{}
+		extras      []interface{}
+		err         string
+	}{
+		{
+			description: "exit code",
+			checker:     checkers.ExitCodeEquals,
+			obtained:    result,
+			extras:      []interface{}{1},
+		}, {
+			description: "wrong exit code",
+			checker:     checkers.ExitCodeEquals,
+			obtained:    result,
+			extras:      []interface{}{0},
+			err:         `expected exit code 0, got 1; stderr: "warning: deprecated\n"`,
+		}, {
+			description: "exit code not an int",
+			checker:     checkers.ExitCodeEquals,
+			obtained:    result,
+			extras:      []interface{}{"1"},
+			err:         "expected exit code must be an int, got type string",
+		}, {
+			description: "exit code of nil result",
+			checker:     checkers.ExitCodeEquals,
+			obtained:    (*checkers.CommandResult)(nil),
+			extras:      []interface{}{0},
+			err:         "ExitCodeEquals checker expected *CommandResult, obtained was nil *checkers.CommandResult",
+		}, {
+			description: "stdout matches",
+			checker:     checkers.StdoutMatches,
+			obtained:    result,
+			extras:      []interface{}{"hel+o\n"},
+		}, {
+			description: "stdout mismatch",
+			checker:     checkers.StdoutMatches,
+			obtained:    result,
+			extras:      []interface{}{"goodbye\n"},
+			err:         `stdout: "hello\n" did not match pattern "goodbye\n"`,
+		}, {
+			description: "stderr matches",
+			checker:     checkers.StderrMatches,
+			obtained:    result,
+			extras:      []interface{}{"warning: .*\n"},
+		}, {
+			description: "stderr mismatch",
+			checker:     checkers.StderrMatches,
+			obtained:    result,
+			extras:      []interface{}{""},
+			err:         `stderr: "warning: deprecated\n" did not match pattern ""`,
+		}, {
+			description: "invalid pattern",
+			checker:     checkers.StdoutMatches,
+			obtained:    "not a result",
+			extras:      []interface{}{"("},
+			err:         `invalid regexp pattern "(": missing closing ): "(" at position 0`,
+		}, {
+			description: "output of wrong type",
+			checker:     checkers.StderrMatches,
+			obtained:    "not a result",
+			extras:      []interface{}{""},
+			err:         `StderrMatches checker expected *CommandResult, obtained was string value not a result`,
+		},
+	} {
+		err := test.checker.Check(test.obtained, test.extras...)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+		} else {
+			if test.err == "" {
+				t.Errorf("%s: unexpected error: %v", test.description, err)
+			} else {
+				if err.Error() != test.err {
+					t.Errorf("%s: error mismatch: \n\tobtained: %q\n\texpected: %q", test.description, err.Error(), test.err)
+				}
+			}
+		}
+	}
+}
+
+func TestCmdEquals(t *testing.T) {
+	cmd := &exec.Cmd{
+		Path: "/usr/bin/git",
+		Args: []string{"git", "commit", "-m", "message"},
+		Env:  []string{"HOME=/tmp", "GIT_AUTHOR_NAME=first", "GIT_AUTHOR_NAME=test", "EMPTY"},
+		Dir:  "/src",
+	}
+	inherited := &exec.Cmd{Path: "/bin/true"}
+	os.Setenv("CHECKERS_CMD_EQUALS", "inherited")
+	defer os.Unsetenv("CHECKERS_CMD_EQUALS")
+	for _, test := range []struct {
+		description string
+		obtained    interface{}
+		extras      []interface{}
+		err         string
+	}{
+		{
+			description: "all fields",
+			obtained:    cmd,
+			extras: []interface{}{checkers.ExpectedCmd{
+				Path: "/usr/bin/git",
+				Args: []string{"git", "commit", "-m", "message"},
+				Env:  map[string]string{"HOME": "/tmp", "GIT_AUTHOR_NAME": "test", "EMPTY": ""},
+				Dir:  "/src",
+			}},
+		}, {
+			description: "some fields",
+			obtained:    cmd,
+			extras:      []interface{}{checkers.ExpectedCmd{Dir: "/src"}},
+		}, {
+			description: "wrong args",
+			obtained:    cmd,
+			extras:      []interface{}{checkers.ExpectedCmd{Args: []string{"git", "commit", "-m", "other"}}},
+			err:         `mismatch at \.Args\[3\]: unequal; obtained "message"; expected "other"`,
+		}, {
+			description: "wrong env",
+			obtained:    cmd,
+			extras:      []interface{}{checkers.ExpectedCmd{Env: map[string]string{"HOME": "/tmp", "GIT_AUTHOR_NAME": "first", "EMPTY": ""}}},
+			err:         `mismatch at \.Env\["GIT_AUTHOR_NAME"\]: unequal; obtained "test"; expected "first"`,
+		}, {
+			description: "wrong path and dir",
+			obtained:    cmd,
+			extras:      []interface{}{checkers.ExpectedCmd{Path: "git", Dir: "/"}},
+			err: "2 mismatches:\n" +
+				"\tmismatch at \\.Path: unequal; obtained \"/usr/bin/git\"; expected \"git\"\n" +
+				"\tmismatch at \\.Dir: unequal; obtained \"/src\"; expected \"/\"",
+		}, {
+			description: "some env",
+			obtained:    cmd,
+			extras:      []interface{}{checkers.ExpectedCmd{Env: map[string]string{"GIT_AUTHOR_NAME": "test"}}},
+		}, {
+			description: "missing env",
+			obtained:    cmd,
+			extras:      []interface{}{checkers.ExpectedCmd{Env: map[string]string{"GIT_AUTHOR_EMAIL": "test@example.com"}}},
+			err:         `mismatch at \.Env: .*`,
+		}, {
+			description: "inherited env",
+			obtained:    inherited,
+			extras:      []interface{}{checkers.ExpectedCmd{Env: map[string]string{"CHECKERS_CMD_EQUALS": "inherited"}}},
+		}, {
+			description: "wrong inherited env",
+			obtained:    inherited,
+			extras:      []interface{}{checkers.ExpectedCmd{Env: map[string]string{"CHECKERS_CMD_EQUALS": "set"}}},
+			err:         `mismatch at \.Env\["CHECKERS_CMD_EQUALS"\]: unequal; obtained "inherited"; expected "set"`,
+		}, {
+			description: "not a command",
+			obtained:    "git",
+			extras:      []interface{}{checkers.ExpectedCmd{}},
+			err:         "CmdEquals checker expected \\*exec.Cmd, obtained was string value git",
+		}, {
+			description: "bad expected",
+			obtained:    cmd,
+			extras:      []interface{}{[]string{"git"}},
+			err:         "expected value must be a checkers.ExpectedCmd, got type \\[\\]string",
+		},
+	} {
+		err := checkers.CmdEquals.Check(test.obtained, test.extras...)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+		} else {
+			if test.err == "" {
+				t.Errorf("%s: unexpected error: %v", test.description, err)
+			} else if matchErr := checkers.Matches.Check(err.Error(), "(?s)"+test.err+"(\n.*)?"); matchErr != nil {
+				t.Errorf("%s: error mismatch: %v", test.description, matchErr)
+			}
+		}
+	}
+}
@@ -0,0 +1,59 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testContexts holds the context returned by Test.Context for each
+// test, from the first call until the test is cleaned up.
+var testContexts = struct {
+	sync.Mutex
+	byTB map[testing.TB]context.Context
+}{byTB: make(map[testing.TB]context.Context)}
+
+// Context returns a context that is cancelled when the test is cleaned
+// up, for passing to the code under test so that anything it starts is
+// stopped by the end of the test. As with the Context method of
+// testing.T, which it hides, each call during a test returns the same
+// context, including calls through other Tests wrapping the same
+// testing.TB.
+func (t *Test) Context() context.Context {
+	tb := t.testingTB()
+	testContexts.Lock()
+	defer testContexts.Unlock()
+	if ctx, ok := testContexts.byTB[tb]; ok {
+		return ctx
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	if !reflect.TypeOf(tb).Comparable() {
+		tb.Cleanup(cancel)
+		return ctx
+	}
+	testContexts.byTB[tb] = ctx
+	tb.Cleanup(func() {
+		cancel()
+		testContexts.Lock()
+		defer testContexts.Unlock()
+		delete(testContexts.byTB, tb)
+	})
+	return ctx
+}
+
+// ContextWithTimeout returns a new context, derived from the one
+// returned by Context, that is also cancelled once the timeout has
+// passed. It can be given to the checkers that wait, Eventually and
+// ChanReceives, to set how long they wait for.
+//
+//	t.Assert(results, checkers.ChanReceives, want, t.ContextWithTimeout(time.Second))
+func (t *Test) ContextWithTimeout(timeout time.Duration) context.Context {
+	ctx, cancel := context.WithTimeout(t.Context(), timeout)
+	t.testingTB().Cleanup(cancel)
+	return ctx
+}
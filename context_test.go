@@ -0,0 +1,66 @@
+// Add a copyright
+// Add a licence
+
+package checkers_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/howbazaar/checkers"
+)
+
+func TestContext(t *testing.T) {
+	var ctx, timeoutCtx context.Context
+	r := checkers.NewRecordingTB()
+	r.Run(func(tb testing.TB) {
+		ct := &checkers.Test{TB: tb}
+		ctx = ct.Context()
+		if ct.Context() != ctx {
+			t.Errorf("new context returned by second call")
+		}
+		if (&checkers.Test{TB: tb}).Context() != ctx {
+			t.Errorf("new context returned through another Test")
+		}
+		timeoutCtx = ct.ContextWithTimeout(time.Hour)
+		if ctx.Err() != nil || timeoutCtx.Err() != nil {
+			t.Errorf("context cancelled during the test")
+		}
+		if _, ok := timeoutCtx.Deadline(); !ok {
+			t.Errorf("no deadline on context with timeout")
+		}
+	})
+	if ctx.Err() != context.Canceled {
+		t.Errorf("context not cancelled at cleanup: %v", ctx.Err())
+	}
+	if timeoutCtx.Err() != context.Canceled {
+		t.Errorf("context with timeout not cancelled at cleanup: %v", timeoutCtx.Err())
+	}
+}
+
+func TestContextWithTimeout(t *testing.T) {
+	ct := &checkers.Test{TB: t}
+	ctx := ct.ContextWithTimeout(time.Millisecond)
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("context not cancelled at timeout")
+	}
+	if ctx.Err() != context.DeadlineExceeded {
+		t.Errorf("unexpected error: %v", ctx.Err())
+	}
+}
+
+func TestContextPerTest(t *testing.T) {
+	var contexts []context.Context
+	for i := 0; i < 2; i++ {
+		r := checkers.NewRecordingTB()
+		r.Run(func(tb testing.TB) {
+			contexts = append(contexts, (&checkers.Test{TB: tb}).Context())
+		})
+	}
+	if contexts[0] == contexts[1] {
+		t.Errorf("context shared between tests")
+	}
+}
@@ -0,0 +1,233 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// BeginTx starts a transaction on the database that is rolled back when
+// the test is cleaned up, so that a test can change the database freely
+// without affecting other tests. Started in a suite's SetUpTest method,
+// it gives each test of the suite its own transaction. The test fails
+// immediately if the transaction can't be started.
+func (t *Test) BeginTx(db *sql.DB, options *sql.TxOptions) *sql.Tx {
+	tb := t.testingTB()
+	tb.Helper()
+	tx, err := db.BeginTx(context.Background(), options)
+	if err != nil {
+		tb.Fatalf("unable to begin transaction: %v", err)
+	}
+	tb.Cleanup(func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			tb.Errorf("unable to roll back transaction: %v", err)
+		}
+	})
+	return tx
+}
+
+// Queryer is implemented by *sql.DB, *sql.Tx and *sql.Conn, any of
+// which may be checked by the database checkers.
+type Queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// SQLQuery is a query with arguments for its placeholders, as taken by
+// QueryReturns.
+type SQLQuery struct {
+	Query string
+	Args  []interface{}
+}
+
+// SQL returns an SQLQuery for the query and its arguments.
+func SQL(query string, args ...interface{}) SQLQuery {
+	return SQLQuery{Query: query, Args: args}
+}
+
+type rowCountEquals struct{}
+
+// RowCountEquals checker checks that the table named by the first extra
+// value of the obtained Queryer has the number of rows given by the
+// second. The table name is used in the query as it is given.
+//
+//	t.Check(tx, checkers.RowCountEquals, "users", 3)
+var RowCountEquals Checker = rowCountEquals{}
+
+func (rowCountEquals) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if len(extras) < 2 {
+		return errors.New("missing 'table' and 'expected' values")
+	}
+	tableArg, expectedArg, extras := extras[0], extras[1], extras[2:]
+	if err := unexpectedExtras("RowCountEquals", extras); err != nil {
+		return err
+	}
+	q, err := obtainedQueryer("RowCountEquals", obtained)
+	if err != nil {
+		return err
+	}
+	table, ok := tableArg.(string)
+	if !ok {
+		return errors.New("table must be a string")
+	}
+	expectedValue := reflect.ValueOf(expectedArg)
+	if kindFamily(expectedValue.Kind()) != reflect.Int {
+		return fmt.Errorf("expected row count must be an int, got %s", describeType(expectedArg))
+	}
+	expected := expectedValue.Int()
+
+	rows, err := queryRows(q, SQLQuery{Query: "SELECT COUNT(*) FROM " + table})
+	if err != nil {
+		return err
+	}
+	if len(rows) != 1 || len(rows[0]) != 1 {
+		return fmt.Errorf("unable to count rows in %s", table)
+	}
+	count, ok := rowCount(rows[0][0])
+	if !ok {
+		return fmt.Errorf("unable to count rows in %s: count is %T", table, rows[0][0])
+	}
+	if count == expected {
+		return nil
+	}
+	return failf("expected %d rows in %s, got %d", expected, table, count)
+}
+
+// rowCount returns the normalized value of a COUNT(*) query as an
+// int64. Drivers return the count as an integer, signed or not, which
+// is normalized to an int64, or as text, which is normalized to a
+// string.
+func rowCount(value interface{}) (int64, bool) {
+	switch v := value.(type) {
+	case int64:
+		return v, true
+	case string:
+		count, err := strconv.ParseInt(v, 10, 64)
+		return count, err == nil
+	}
+	return 0, false
+}
+
+type queryReturns struct{}
+
+// QueryReturns checker runs the query given by the first extra value on
+// the obtained Queryer, and checks that the rows it returns are those
+// given by the second, a [][]interface{}. The query is a string, or an
+// SQLQuery for a query with arguments. Values are compared after
+// converting integers to int64, floats to float64 and []byte to string,
+// so that expected values can be written naturally whatever the driver
+// returns.
+//
+//	t.Check(tx, checkers.QueryReturns, "SELECT name, age FROM users ORDER BY name",
+//		[][]interface{}{{"alice", 30}, {"bob", 25}})
+var QueryReturns Checker = queryReturns{}
+
+func (queryReturns) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if len(extras) < 2 {
+		return errors.New("missing 'query' and 'expected' values")
+	}
+	queryArg, expectedArg, extras := extras[0], extras[1], extras[2:]
+	if err := unexpectedExtras("QueryReturns", extras); err != nil {
+		return err
+	}
+	q, err := obtainedQueryer("QueryReturns", obtained)
+	if err != nil {
+		return err
+	}
+	var query SQLQuery
+	switch arg := queryArg.(type) {
+	case string:
+		query.Query = arg
+	case SQLQuery:
+		query = arg
+	default:
+		return fmt.Errorf("query must be a string or SQLQuery, got %s", describeType(queryArg))
+	}
+	expected, ok := expectedArg.([][]interface{})
+	if !ok {
+		return fmt.Errorf("expected rows must be a [][]interface{}, got %s", describeType(expectedArg))
+	}
+
+	rows, err := queryRows(q, query)
+	if err != nil {
+		return err
+	}
+	normalized := make([][]interface{}, len(expected))
+	for i, row := range expected {
+		normalized[i] = make([]interface{}, len(row))
+		for j, value := range row {
+			normalized[i][j] = normalizeSQLValue(value)
+		}
+	}
+	return DeepEquals.Check(rows, normalized)
+}
+
+// obtainedQueryer returns the obtained value as a Queryer.
+func obtainedQueryer(checker string, obtained interface{}) (Queryer, error) {
+	q, ok := obtained.(Queryer)
+	if !ok {
+		return nil, fmt.Errorf("%s checker expected *sql.DB, *sql.Tx or *sql.Conn, obtained was %s", checker, describeType(obtained))
+	}
+	return q, nil
+}
+
+// queryRows runs the query, returning all the rows with their values
+// normalized by normalizeSQLValue. A query that returns no rows gives
+// an empty, rather than nil, result.
+func queryRows(q Queryer, query SQLQuery) ([][]interface{}, error) {
+	rows, err := q.QueryContext(context.Background(), query.Query, query.Args...)
+	if err != nil {
+		return nil, fmt.Errorf("query %q failed: %v", query.Query, err)
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("query %q failed: %v", query.Query, err)
+	}
+	result := [][]interface{}{}
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, fmt.Errorf("query %q failed: %v", query.Query, err)
+		}
+		for i, value := range values {
+			values[i] = normalizeSQLValue(value)
+		}
+		result = append(result, values)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query %q failed: %v", query.Query, err)
+	}
+	return result, nil
+}
+
+// normalizeSQLValue converts the value to the type used to compare
+// values from the database.
+func normalizeSQLValue(value interface{}) interface{} {
+	if b, ok := value.([]byte); ok {
+		return string(b)
+	}
+	v := reflect.ValueOf(value)
+	switch kindFamily(v.Kind()) {
+	case reflect.Int:
+		return v.Int()
+	case reflect.Uint:
+		return int64(v.Uint())
+	case reflect.Float64:
+		return v.Float()
+	}
+	return value
+}
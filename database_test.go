@@ -0,0 +1,357 @@
+// Add a copyright
+// Add a licence
+
+package checkers_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/howbazaar/checkers"
+)
+
+// memDriver is a database/sql driver for an in-memory database that
+// understands just enough SQL to test the database checkers:
+//
+//	INSERT INTO table VALUES (?, ...)
+//	SELECT COUNT(*) FROM table
+//	SELECT * FROM table
+//
+// A transaction works on the tables directly and restores a copy of
+// them on rollback. Counts are returned as int64 values, or as text or
+// uint64 values when the data source name is "text-counts" or
+// "unsigned-counts", as some drivers return them.
+type memDriver struct {
+	mu     sync.Mutex
+	tables map[string][][]driver.Value
+}
+
+var memDB = &memDriver{tables: make(map[string][][]driver.Value)}
+
+func init() {
+	sql.Register("checkers-mem", memDB)
+}
+
+func (d *memDriver) Open(name string) (driver.Conn, error) {
+	return &memConn{d, name}, nil
+}
+
+type memConn struct {
+	d    *memDriver
+	name string
+}
+
+func (c *memConn) Prepare(query string) (driver.Stmt, error) {
+	return &memStmt{c.d, c.name, strings.Fields(query)}, nil
+}
+
+func (c *memConn) Close() error { return nil }
+
+func (c *memConn) Begin() (driver.Tx, error) {
+	c.d.mu.Lock()
+	defer c.d.mu.Unlock()
+	saved := make(map[string][][]driver.Value)
+	for name, rows := range c.d.tables {
+		saved[name] = append([][]driver.Value(nil), rows...)
+	}
+	return &memTx{c.d, saved}, nil
+}
+
+type memTx struct {
+	d     *memDriver
+	saved map[string][][]driver.Value
+}
+
+func (tx *memTx) Commit() error { return nil }
+
+func (tx *memTx) Rollback() error {
+	tx.d.mu.Lock()
+	defer tx.d.mu.Unlock()
+	tx.d.tables = tx.saved
+	return nil
+}
+
+type memStmt struct {
+	d      *memDriver
+	name   string
+	fields []string
+}
+
+func (s *memStmt) Close() error  { return nil }
+func (s *memStmt) NumInput() int { return -1 }
+
+func (s *memStmt) Exec(args []driver.Value) (driver.Result, error) {
+	if len(s.fields) < 4 || s.fields[0] != "INSERT" {
+		return nil, errors.New("unsupported statement")
+	}
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	table := s.fields[2]
+	s.d.tables[table] = append(s.d.tables[table], args)
+	return driver.RowsAffected(1), nil
+}
+
+func (s *memStmt) Query(args []driver.Value) (driver.Rows, error) {
+	if len(s.fields) != 4 || s.fields[0] != "SELECT" {
+		return nil, errors.New("unsupported query")
+	}
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	rows, ok := s.d.tables[s.fields[3]]
+	if !ok {
+		return nil, fmt.Errorf("no such table: %s", s.fields[3])
+	}
+	if s.fields[1] == "COUNT(*)" {
+		var count driver.Value = int64(len(rows))
+		switch s.name {
+		case "text-counts":
+			count = []byte(strconv.Itoa(len(rows)))
+		case "unsigned-counts":
+			count = uint64(len(rows))
+		}
+		return &memRows{columns: []string{"count"}, rows: [][]driver.Value{{count}}}, nil
+	}
+	var columns []string
+	if len(rows) > 0 {
+		for i := range rows[0] {
+			columns = append(columns, fmt.Sprintf("c%d", i))
+		}
+	}
+	return &memRows{columns: columns, rows: append([][]driver.Value(nil), rows...)}, nil
+}
+
+type memRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *memRows) Columns() []string { return r.columns }
+func (r *memRows) Close() error      { return nil }
+
+func (r *memRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+func openMemDB(t *testing.T) *sql.DB {
+	db, err := sql.Open("checkers-mem", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	memDB.mu.Lock()
+	memDB.tables = map[string][][]driver.Value{
+		"users": {{"alice", int64(30)}, {"bob", int64(25)}},
+		"empty": nil,
+	}
+	memDB.mu.Unlock()
+	return db
+}
+
+func TestBeginTx(t *testing.T) {
+	db := openMemDB(t)
+	r := checkers.NewRecordingTB()
+	r.Run(func(tb testing.TB) {
+		ct := &checkers.Test{TB: tb}
+		tx := ct.BeginTx(db, nil)
+		if _, err := tx.Exec("INSERT INTO users VALUES (?, ?)", "carol", 41); err != nil {
+			t.Fatal(err)
+		}
+		ct.Check(tx, checkers.RowCountEquals, "users", 3)
+	})
+	if errs := r.Errors(); len(errs) != 0 {
+		t.Fatalf("unexpected errors: %q", errs)
+	}
+	if err := checkers.RowCountEquals.Check(db, "users", 2); err != nil {
+		t.Errorf("transaction not rolled back: %v", err)
+	}
+}
+
+type dbSuite struct {
+	checkers.Test
+	db *sql.DB
+	tx *sql.Tx
+}
+
+func (s *dbSuite) SetUpTest() {
+	s.tx = s.BeginTx(s.db, nil)
+}
+
+func (s *dbSuite) TestInsert() {
+	_, err := s.tx.Exec("INSERT INTO empty VALUES (?)", 1)
+	s.Assert(err, checkers.IsNil)
+	s.Check(s.tx, checkers.RowCountEquals, "empty", 1)
+}
+
+func (s *dbSuite) TestInsertAgain() {
+	_, err := s.tx.Exec("INSERT INTO empty VALUES (?)", 2)
+	s.Assert(err, checkers.IsNil)
+	s.Check(s.tx, checkers.QueryReturns, "SELECT * FROM empty", [][]interface{}{{2}})
+}
+
+func TestBeginTxInSuite(t *testing.T) {
+	db := openMemDB(t)
+	checkers.RunSuite(t, &dbSuite{db: db})
+	if err := checkers.RowCountEquals.Check(db, "empty", 0); err != nil {
+		t.Errorf("transactions not rolled back: %v", err)
+	}
+}
+
+func TestDatabaseCheckers(t *testing.T) {
+	db := openMemDB(t)
+	textCounts, err := sql.Open("checkers-mem", "text-counts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer textCounts.Close()
+	unsignedCounts, err := sql.Open("checkers-mem", "unsigned-counts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unsignedCounts.Close()
+	for _, test := range []struct {
+		description string
+		checker     checkers.Checker
+		obtained    interface{}
+		extras      []interface{}
+		err         string
+	}{
+		{
+			description: "row count",
+			checker:     checkers.RowCountEquals,
+			obtained:    db,
+			extras:      []interface{}{"users", 2},
+		}, {
+			description: "row count of empty table",
+			checker:     checkers.RowCountEquals,
+			obtained:    db,
+			extras:      []interface{}{"empty", int64(0)},
+		}, {
+			description: "row count returned as text",
+			checker:     checkers.RowCountEquals,
+			obtained:    textCounts,
+			extras:      []interface{}{"users", 2},
+		}, {
+			description: "row count returned as text mismatch",
+			checker:     checkers.RowCountEquals,
+			obtained:    textCounts,
+			extras:      []interface{}{"users", 3},
+			err:         "expected 3 rows in users, got 2",
+		}, {
+			description: "row count returned as unsigned",
+			checker:     checkers.RowCountEquals,
+			obtained:    unsignedCounts,
+			extras:      []interface{}{"users", 2},
+		}, {
+			description: "row count mismatch",
+			checker:     checkers.RowCountEquals,
+			obtained:    db,
+			extras:      []interface{}{"users", 3},
+			err:         "expected 3 rows in users, got 2",
+		}, {
+			description: "row count of missing table",
+			checker:     checkers.RowCountEquals,
+			obtained:    db,
+			extras:      []interface{}{"missing", 0},
+			err:         `query "SELECT COUNT(*) FROM missing" failed: no such table: missing`,
+		}, {
+			description: "row count not an int",
+			checker:     checkers.RowCountEquals,
+			obtained:    db,
+			extras:      []interface{}{"users", "2"},
+			err:         "expected row count must be an int, got type string",
+		}, {
+			description: "row count missing arguments",
+			checker:     checkers.RowCountEquals,
+			obtained:    db,
+			extras:      []interface{}{"users"},
+			err:         "missing 'table' and 'expected' values",
+		}, {
+			description: "row count too many arguments",
+			checker:     checkers.RowCountEquals,
+			obtained:    db,
+			extras:      []interface{}{"users", 2, 3},
+			err:         "too many arguments to checker RowCountEquals, unexpected int(3)",
+		}, {
+			description: "row count not a database",
+			checker:     checkers.RowCountEquals,
+			obtained:    "users",
+			extras:      []interface{}{"users", 2},
+			err:         "RowCountEquals checker expected *sql.DB, *sql.Tx or *sql.Conn, obtained was type string",
+		}, {
+			description: "query returns",
+			checker:     checkers.QueryReturns,
+			obtained:    db,
+			extras:      []interface{}{"SELECT * FROM users", [][]interface{}{{"alice", 30}, {"bob", uint8(25)}}},
+		}, {
+			description: "query with arguments",
+			checker:     checkers.QueryReturns,
+			obtained:    db,
+			extras:      []interface{}{checkers.SQL("SELECT * FROM users", 1), [][]interface{}{{"alice", 30}, {"bob", 25}}},
+		}, {
+			description: "query returns nothing",
+			checker:     checkers.QueryReturns,
+			obtained:    db,
+			extras:      []interface{}{"SELECT * FROM empty", [][]interface{}{}},
+		}, {
+			description: "query returns different rows",
+			checker:     checkers.QueryReturns,
+			obtained:    db,
+			extras:      []interface{}{"SELECT * FROM users", [][]interface{}{{"alice", 30}, {"bob", 26}}},
+			err: "mismatch at [1][1]: unequal; obtained 25; expected 26\n" +
+				"diff (-obtained +expected):\n" +
+				" ...\n" +
+				" \t},\n" +
+				" \t[]interface {}{\n" +
+				" \t\t\"bob\",\n" +
+				"-\t\t25,\n" +
+				"+\t\t26,\n" +
+				" \t},\n" +
+				" }",
+		}, {
+			description: "query fails",
+			checker:     checkers.QueryReturns,
+			obtained:    db,
+			extras:      []interface{}{"DELETE FROM users", [][]interface{}{}},
+			err:         `query "DELETE FROM users" failed: unsupported query`,
+		}, {
+			description: "query not a string",
+			checker:     checkers.QueryReturns,
+			obtained:    db,
+			extras:      []interface{}{1, [][]interface{}{}},
+			err:         "query must be a string or SQLQuery, got type int",
+		}, {
+			description: "expected not rows",
+			checker:     checkers.QueryReturns,
+			obtained:    db,
+			extras:      []interface{}{"SELECT * FROM users", []interface{}{"alice"}},
+			err:         "expected rows must be a [][]interface{}, got type []interface {}",
+		},
+	} {
+		err := test.checker.Check(test.obtained, test.extras...)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+		} else {
+			if test.err == "" {
+				t.Errorf("%s: unexpected error: %v", test.description, err)
+			} else {
+				if err.Error() != test.err {
+					t.Errorf("%s: error mismatch: \n\tobtained: %q\n\texpected: %q", test.description, err.Error(), test.err)
+				}
+			}
+		}
+	}
+}
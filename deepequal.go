@@ -10,6 +10,8 @@ package checkers
 import (
 	"fmt"
 	"reflect"
+	"sort"
+	"strings"
 	"time"
 	"unsafe"
 )
@@ -24,23 +26,56 @@ type visit struct {
 	a1  uintptr
 	a2  uintptr
 	typ reflect.Type
+	len int
+}
+
+// ref identifies a pointer, map or slice on one side of the comparison.
+type ref struct {
+	addr uintptr
+	typ  reflect.Type
 }
 
 type mismatchError struct {
 	v1, v2 reflect.Value
 	path   string
 	how    string
+	cycle  string
+	// err, if set, is the error returned by a custom check, which
+	// is reported as is.
+	err error
+	// config, if set, limits how the values are rendered.
+	config *deepEqualConfig
+	// redacted is set for the values of fields tagged to be redacted,
+	// which are never shown.
+	redacted bool
 }
 
 func (err *mismatchError) Error() string {
+	if err.err != nil {
+		return err.err.Error()
+	}
 	path := err.path
 	if path == "" {
 		path = "top level"
 	}
-	return fmt.Sprintf("mismatch at %s: %s; obtained %#v; expected %#v", path, err.how, printable(err.v1), printable(err.v2))
+	how := err.how
+	if err.cycle != "" {
+		how += " (" + err.cycle + ")"
+	}
+	if err.redacted {
+		return fmt.Sprintf("mismatch at %s: %s; obtained %s; expected %s", path, how, redactedText, redactedText)
+	}
+	if err.config != nil && err.config.limited() {
+		return fmt.Sprintf("mismatch at %s: %s; obtained %s; expected %s", path, how,
+			limitedPrint(err.v1, err.config, true), limitedPrint(err.v2, err.config, true))
+	}
+	return fmt.Sprintf("mismatch at %s: %s; obtained %#v; expected %#v", path, how, printable(err.v1), printable(err.v2))
 }
 
 func printable(v reflect.Value) interface{} {
+	if v.IsValid() && (hasPrinter(v.Type()) || holdsComposite(v.Type())) {
+		return rendered(prettyPrintLine(v))
+	}
 	vi := interfaceOf(v)
 	switch vi := vi.(type) {
 	case time.Time:
@@ -50,65 +85,230 @@ func printable(v reflect.Value) interface{} {
 	}
 }
 
-// Tests for deep equality using reflected types. The map argument tracks
+// mismatchesError holds every mismatch found by a single comparison.
+type mismatchesError struct {
+	errs []error
+	// truncated is set when the comparison stopped at the limit
+	// on the number of mismatches.
+	truncated bool
+}
+
+func (err *mismatchesError) Error() string {
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "%d mismatches", len(err.errs))
+	if err.truncated {
+		buf.WriteString(" (stopped at limit, there may be more)")
+	}
+	buf.WriteString(":")
+	for _, e := range err.errs {
+		buf.WriteString("\n\t")
+		buf.WriteString(e.Error())
+	}
+	return buf.String()
+}
+
+// deepEqualer holds the state of a single deep comparison.
+type deepEqualer struct {
+	deepEqualConfig
+
+	// visited tracks comparisons that have already been seen, which
+	// allows short circuiting on recursive types.
+	visited map[visit]bool
+	// active1 and active2 record the path of each reference currently
+	// being followed on the obtained and expected sides respectively.
+	// A reference that is found again while still active closes a cycle.
+	active1 map[ref]string
+	active2 map[ref]string
+	// cycle describes the first cycle that was followed on one side
+	// only, and is attached to any mismatches found beneath it.
+	cycle string
+	// transformed holds the transformer that produced the values
+	// being compared, which must not be applied to them again.
+	transformed *transformer
+
+	mismatches []*mismatchError
+}
+
+func newDeepEqualer(options []DeepEqualOption) *deepEqualer {
+	return &deepEqualer{
+		deepEqualConfig: newDeepEqualConfig(options),
+		visited:         make(map[visit]bool),
+		active1:         make(map[ref]string),
+		active2:         make(map[ref]string),
+	}
+}
+
+// mismatch records a difference and returns false so callers can
+// return its result directly.
+func (d *deepEqualer) mismatch(err *mismatchError) bool {
+	err.config = &d.deepEqualConfig
+	d.mismatches = append(d.mismatches, err)
+	if d.reporter != nil {
+		d.reporter.Report(err.mismatch())
+	}
+	return false
+}
+
+// done reports whether enough mismatches have been found that the
+// comparison should stop.
+func (d *deepEqualer) done() bool {
+	return d.maxMismatches > 0 && len(d.mismatches) >= d.maxMismatches
+}
+
+// result converts the recorded mismatches into the values returned
+// from DeepEqual.
+func (d *deepEqualer) result() (bool, error) {
+	switch len(d.mismatches) {
+	case 0:
+		return true, nil
+	case 1:
+		return false, d.mismatches[0]
+	}
+	errs := make([]error, len(d.mismatches))
+	for i, m := range d.mismatches {
+		errs[i] = m
+	}
+	return false, &mismatchesError{
+		errs:      errs,
+		truncated: d.done(),
+	}
+}
+
+// hard reports whether the values are references that may be part
+// of a cycle. Nil references cannot be cyclic.
+func hard(v1, v2 reflect.Value) bool {
+	switch v1.Kind() {
+	case reflect.Map, reflect.Slice, reflect.Ptr:
+		return !v1.IsNil() && !v2.IsNil()
+	}
+	return false
+}
+
+func pathName(path string) string {
+	if path == "" {
+		return "top level"
+	}
+	return path
+}
+
+// Tests for deep equality using reflected types. The visited map tracks
 // comparisons that have already been seen, which allows short circuiting on
-// recursive types.
-func deepValueEqual(path string, v1, v2 reflect.Value, visited map[visit]bool, depth int, customCheckFunc CustomCheckFunc) (ok bool, err error) {
-	errorf := func(f string, a ...interface{}) error {
-		return &mismatchError{
-			v1:   v1,
-			v2:   v2,
-			path: path,
-			how:  fmt.Sprintf(f, a...),
-		}
+// recursive types. Any differences found are recorded in d.mismatches.
+func (d *deepEqualer) deepValueEqual(path string, v1, v2 reflect.Value, depth int) bool {
+	mismatchf := func(f string, a ...interface{}) bool {
+		return d.mismatch(&mismatchError{
+			v1:    v1,
+			v2:    v2,
+			path:  path,
+			how:   fmt.Sprintf(f, a...),
+			cycle: d.cycle,
+		})
 	}
 	if !v1.IsValid() || !v2.IsValid() {
 		if v1.IsValid() == v2.IsValid() {
-			return true, nil
+			return true
 		}
-		return false, errorf("validity mismatch")
+		return mismatchf("validity mismatch")
 	}
 	if v1.Type() != v2.Type() {
-		return false, errorf("type mismatch %s vs %s", v1.Type(), v2.Type())
+		return mismatchf("type mismatch %s vs %s", v1.Type(), v2.Type())
 	}
 
-	// if depth > 10 { panic("deepValueEqual") }	// for debugging
-	hard := func(k reflect.Kind) bool {
-		switch k {
+	if d.maxDepth > 0 && depth > d.maxDepth {
+		switch v1.Kind() {
 		case reflect.Array, reflect.Map, reflect.Slice, reflect.Struct:
-			return true
+			return mismatchf("maximum depth %d exceeded", d.maxDepth)
 		}
-		return false
 	}
 
-	if v1.CanAddr() && v2.CanAddr() && hard(v1.Kind()) {
-		addr1 := v1.UnsafeAddr()
-		addr2 := v2.UnsafeAddr()
+	skip := d.transformed
+	d.transformed = nil
+	if t := d.transformerFor(path, v1.Type()); t != nil && t != skip {
+		r1, r2 := t.apply(v1), t.apply(v2)
+		d.transformed = t
+		return d.deepValueEqual(t.name+"("+path+")", r1, r2, depth+1)
+	}
+
+	if d.drainIterators && isIterator(v1) && isIterator(v2) {
+		s1, ok1 := drain(v1)
+		s2, ok2 := drain(v2)
+		if !ok1 || !ok2 {
+			return mismatchf("iterator produced more than %d values", maxIteratorValues)
+		}
+		return d.deepValueEqual("drain("+path+")", s1, s2, depth+1)
+	}
+
+	// if depth > 10 { panic("deepValueEqual") }	// for debugging
+	if hard(v1, v2) {
+		typ := v1.Type()
+		r1 := ref{v1.Pointer(), typ}
+		r2 := ref{v2.Pointer(), typ}
+		n := 0
+		if v1.Kind() == reflect.Slice {
+			// Slices sharing a backing array are only the same
+			// reference if they are also the same length.
+			n = v1.Len()
+		}
+		addr1, addr2 := r1.addr, r2.addr
 		if addr1 > addr2 {
 			// Canonicalize order to reduce number of entries in visited.
 			addr1, addr2 = addr2, addr1
 		}
 
 		// Short circuit if references are identical ...
-		if addr1 == addr2 {
-			return true, nil
+		if addr1 == addr2 && (v1.Kind() != reflect.Slice || v1.Len() == v2.Len()) {
+			return true
 		}
 
 		// ... or already seen
-		typ := v1.Type()
-		v := visit{addr1, addr2, typ}
-		if visited[v] {
-			return true, nil
+		v := visit{addr1, addr2, typ, n}
+		if d.visited[v] {
+			return true
 		}
 
 		// Remember for later.
-		visited[v] = true
+		d.visited[v] = true
+
+		// A reference that is already being followed on only one
+		// side means that side has looped back on itself while the
+		// other has not (yet), so note it for any mismatch found below.
+		if d.cycle == "" {
+			if prev, ok := d.active1[r1]; ok {
+				d.cycle = fmt.Sprintf("obtained %s cycles back to %s", pathName(path), pathName(prev))
+			} else if prev, ok := d.active2[r2]; ok {
+				d.cycle = fmt.Sprintf("expected %s cycles back to %s", pathName(path), pathName(prev))
+			}
+			if d.cycle != "" {
+				defer func() { d.cycle = "" }()
+			}
+		}
+		if _, ok := d.active1[r1]; !ok {
+			d.active1[r1] = path
+			defer delete(d.active1, r1)
+		}
+		if _, ok := d.active2[r2]; !ok {
+			d.active2[r2] = path
+			defer delete(d.active2, r2)
+		}
 	}
 
-	if customCheckFunc != nil && v1.CanInterface() && v2.CanInterface() {
-		useDefault, equal, err := customCheckFunc(path, v1.Interface(), v2.Interface())
+	if d.customCheckFunc != nil && v1.CanInterface() && v2.CanInterface() {
+		useDefault, equal, err := d.customCheckFunc(path, v1.Interface(), v2.Interface())
 		if !useDefault {
-			return equal, err
+			if equal {
+				return true
+			}
+			if err == nil {
+				return mismatchf("custom check failed")
+			}
+			return d.mismatch(&mismatchError{
+				v1:    v1,
+				v2:    v2,
+				path:  path,
+				how:   err.Error(),
+				cycle: d.cycle,
+				err:   err,
+			})
 		}
 	}
 
@@ -116,201 +316,452 @@ func deepValueEqual(path string, v1, v2 reflect.Value, visited map[visit]bool, d
 	case reflect.Array:
 		if v1.Len() != v2.Len() {
 			// can't happen!
-			return false, errorf("length mismatch, %d vs %d", v1.Len(), v2.Len())
+			return mismatchf("length mismatch, %d vs %d", v1.Len(), v2.Len())
+		}
+		if d.unordered(path, v1.Type()) {
+			return d.unorderedElementsEqual(path, v1, v2, depth)
 		}
-		for i := 0; i < v1.Len(); i++ {
-			if ok, err := deepValueEqual(
-				fmt.Sprintf("%s[%d]", path, i),
-				v1.Index(i), v2.Index(i), visited, depth+1, customCheckFunc); !ok {
-				return false, err
+		if d.canFastCompare(v1.Type()) {
+			if equal, ok := fastEqual(v1, v2); ok && equal {
+				return true
 			}
 		}
-		return true, nil
+		return d.elementsEqual(path, v1, v2, depth)
 	case reflect.Slice:
 		// We treat a nil slice the same as an empty slice.
 		if v1.Len() != v2.Len() {
-			return false, errorf("length mismatch, %d vs %d", v1.Len(), v2.Len())
+			return mismatchf("length mismatch, %d vs %d", v1.Len(), v2.Len())
 		}
 		if v1.Pointer() == v2.Pointer() {
-			return true, nil
+			return true
 		}
-		for i := 0; i < v1.Len(); i++ {
-			if ok, err := deepValueEqual(
-				fmt.Sprintf("%s[%d]", path, i),
-				v1.Index(i), v2.Index(i), visited, depth+1, customCheckFunc); !ok {
-				return false, err
+		if d.unordered(path, v1.Type()) {
+			return d.unorderedElementsEqual(path, v1, v2, depth)
+		}
+		if d.canFastCompare(v1.Type()) {
+			if equal, ok := fastEqual(v1, v2); ok && equal {
+				return true
 			}
 		}
-		return true, nil
+		return d.elementsEqual(path, v1, v2, depth)
 	case reflect.Interface:
 		if v1.IsNil() || v2.IsNil() {
 			if v1.IsNil() != v2.IsNil() {
-				return false, errorf("nil vs non-nil interface mismatch")
+				return mismatchf("nil vs non-nil interface mismatch")
 			}
-			return true, nil
+			return true
 		}
-		return deepValueEqual(path, v1.Elem(), v2.Elem(), visited, depth+1, customCheckFunc)
+		return d.deepValueEqual(path, v1.Elem(), v2.Elem(), depth)
 	case reflect.Ptr:
-		return deepValueEqual("(*"+path+")", v1.Elem(), v2.Elem(), visited, depth+1, customCheckFunc)
+		// Pointers are followed implicitly, as they are by Go selectors,
+		// so do not add to the path or the depth.
+		return d.deepValueEqual(path, v1.Elem(), v2.Elem(), depth)
 	case reflect.Struct:
 		if v1.Type() == timeType {
 			// Special case for time - we ignore the time zone.
 			t1 := interfaceOf(v1).(time.Time)
 			t2 := interfaceOf(v2).(time.Time)
 			if t1.Equal(t2) {
-				return true, nil
+				return true
 			}
-			return false, errorf("unequal")
+			return mismatchf("unequal")
 		}
+		compareUnexported := true
+		if d.unexported != nil {
+			var known bool
+			compareUnexported, known = d.unexported[v1.Type()]
+			if !known {
+				if name, ok := firstUnexportedField(v1.Type()); ok {
+					return mismatchf("unexported field %s of %s is neither ignored nor allowed", name, v1.Type())
+				}
+			}
+		}
+		equal := true
 		for i, n := 0, v1.NumField(); i < n; i++ {
-			path := path + "." + v1.Type().Field(i).Name
-			if ok, err := deepValueEqual(path, v1.Field(i), v2.Field(i), visited, depth+1, customCheckFunc); !ok {
-				return false, err
+			field := v1.Type().Field(i)
+			if field.PkgPath != "" && !compareUnexported {
+				continue
+			}
+			if d.partial && v2.Field(i).IsZero() {
+				continue
+			}
+			path := path + "." + field.Name
+			if isRedacted(field) {
+				// Compare the field apart, so that nothing about
+				// how its values differ is reported.
+				if !d.worker().deepValueEqual(path, v1.Field(i), v2.Field(i), depth+1) {
+					equal = false
+					d.mismatch(&mismatchError{
+						v1:       v1.Field(i),
+						v2:       v2.Field(i),
+						path:     path,
+						how:      "unequal",
+						cycle:    d.cycle,
+						redacted: true,
+					})
+					if d.done() {
+						break
+					}
+				}
+				continue
+			}
+			if !d.deepValueEqual(path, v1.Field(i), v2.Field(i), depth+1) {
+				equal = false
+				if d.done() {
+					break
+				}
 			}
 		}
-		return true, nil
+		return equal
 	case reflect.Map:
 		if v1.IsNil() != v2.IsNil() {
-			return false, errorf("nil vs non-nil mismatch")
+			return mismatchf("nil vs non-nil mismatch")
+		}
+		if d.foldKeys && v1.Type().Key().Kind() == reflect.String {
+			return d.foldedMapEqual(path, v1, v2, depth)
 		}
 		if v1.Len() != v2.Len() {
-			return false, errorf("length mismatch, %d vs %d", v1.Len(), v2.Len())
+			return mismatchf("length mismatch, %d vs %d", v1.Len(), v2.Len())
 		}
 		if v1.Pointer() == v2.Pointer() {
-			return true, nil
+			return true
+		}
+		if d.canFastCompare(v1.Type()) {
+			if equal, ok := fastEqual(v1, v2); ok && equal {
+				return true
+			}
 		}
-		for _, k := range v1.MapKeys() {
-			var p string
+		keys := sortedKeys(v1)
+		keyPath := func(k reflect.Value) string {
 			if k.CanInterface() {
-				p = path + "[" + fmt.Sprintf("%#v", k.Interface()) + "]"
-			} else {
-				p = path + "[someKey]"
+				return path + "[" + fmt.Sprintf("%#v", k.Interface()) + "]"
 			}
-			if ok, err := deepValueEqual(p, v1.MapIndex(k), v2.MapIndex(k), visited, depth+1, customCheckFunc); !ok {
-				return false, err
+			return path + "[someKey]"
+		}
+		if d.parallel(len(keys)) {
+			return d.parallelEqual(len(keys), depth, func(i int) (string, reflect.Value, reflect.Value) {
+				return keyPath(keys[i]), v1.MapIndex(keys[i]), v2.MapIndex(keys[i])
+			})
+		}
+		equal := true
+		for _, k := range keys {
+			if !d.deepValueEqual(keyPath(k), v1.MapIndex(k), v2.MapIndex(k), depth+1) {
+				equal = false
+				if d.done() {
+					break
+				}
 			}
 		}
-		return true, nil
+		return equal
 	case reflect.Func:
-		if v1.IsNil() && v2.IsNil() {
-			return true, nil
+		switch {
+		case d.funcs == funcsIgnored:
+			return true
+		case v1.IsNil() && v2.IsNil():
+			return true
+		case d.funcs == funcsByPointer:
+			if v1.Pointer() == v2.Pointer() {
+				return true
+			}
+			return mismatchf("different functions")
 		}
 		// Can't do better than this:
-		return false, errorf("non-nil functions")
+		return mismatchf("non-nil functions")
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		if v1.Int() != v2.Int() {
-			return false, errorf("unequal")
+			return mismatchf("unequal")
 		}
-		return true, nil
+		return true
 	case reflect.Uint, reflect.Uintptr, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
 		if v1.Uint() != v2.Uint() {
-			return false, errorf("unequal")
+			return mismatchf("unequal")
 		}
-		return true, nil
+		return true
 	case reflect.Float32, reflect.Float64:
 		if v1.Float() != v2.Float() {
-			return false, errorf("unequal")
+			return mismatchf("unequal")
 		}
-		return true, nil
+		return true
 	case reflect.Complex64, reflect.Complex128:
 		if v1.Complex() != v2.Complex() {
-			return false, errorf("unequal")
+			return mismatchf("unequal")
 		}
-		return true, nil
+		return true
 	case reflect.Bool:
 		if v1.Bool() != v2.Bool() {
-			return false, errorf("unequal")
+			return mismatchf("unequal")
 		}
-		return true, nil
+		return true
 	case reflect.String:
 		if v1.String() != v2.String() {
-			return false, errorf("unequal")
+			return mismatchf("unequal")
 		}
-		return true, nil
+		return true
 	case reflect.Chan, reflect.UnsafePointer:
 		if v1.Pointer() != v2.Pointer() {
-			return false, errorf("unequal")
+			return mismatchf("unequal")
 		}
-		return true, nil
+		return true
 	default:
 		panic("unexpected type " + v1.Type().String())
 	}
 }
 
-// DeepEqual tests for deep equality. It uses normal == equality where
-// possible but will scan elements of arrays, slices, maps, and fields
-// of structs. In maps, keys are compared with == but elements use deep
-// equality. DeepEqual correctly handles recursive types. Functions are
-// equal only if they are both nil.
-//
-// DeepEqual differs from reflect.DeepEqual in two ways:
-// - an empty slice is considered equal to a nil slice.
-// - two time.Time values that represent the same instant
-// but with different time zones are considered equal.
-//
-// If the two values compare unequal, the resulting error holds the
-// first difference encountered.
-func DeepEqual(a1, a2 interface{}) (bool, error) {
-	errorf := func(f string, a ...interface{}) error {
-		return &mismatchError{
-			v1:   reflect.ValueOf(a1),
-			v2:   reflect.ValueOf(a2),
-			path: "",
-			how:  fmt.Sprintf(f, a...),
+// firstUnexportedField returns the name of the first unexported field
+// of the struct type, if it has one.
+func firstUnexportedField(t reflect.Type) (string, bool) {
+	for i, n := 0, t.NumField(); i < n; i++ {
+		if field := t.Field(i); field.PkgPath != "" {
+			return field.Name, true
 		}
 	}
-	if a1 == nil || a2 == nil {
-		if a1 == a2 {
-			return true, nil
+	return "", false
+}
+
+// elementsEqual compares the elements of two arrays or slices of
+// the same length.
+func (d *deepEqualer) elementsEqual(path string, v1, v2 reflect.Value, depth int) bool {
+	if d.parallel(v1.Len()) {
+		return d.parallelEqual(v1.Len(), depth, func(i int) (string, reflect.Value, reflect.Value) {
+			return fmt.Sprintf("%s[%d]", path, i), v1.Index(i), v2.Index(i)
+		})
+	}
+	equal := true
+	for i := 0; i < v1.Len(); i++ {
+		if !d.deepValueEqual(fmt.Sprintf("%s[%d]", path, i), v1.Index(i), v2.Index(i), depth+1) {
+			equal = false
+			if d.done() {
+				break
+			}
 		}
-		return false, errorf("nil vs non-nil mismatch")
 	}
-	v1 := reflect.ValueOf(a1)
-	v2 := reflect.ValueOf(a2)
-	if v1.Type() != v2.Type() {
-		return false, errorf("type mismatch %s vs %s", v1.Type(), v2.Type())
+	return equal
+}
+
+// unordered reports whether the slice or array at the path should be
+// compared without regard to the order of its elements.
+func (d *deepEqualer) unordered(path string, t reflect.Type) bool {
+	return d.unorderedPaths[path] || d.unorderedTypes[t]
+}
+
+// trial returns a comparer with the same configuration as d that can
+// be used to test whether two values are equal without recording or
+// reporting any mismatches in d.
+func (d *deepEqualer) trial() *deepEqualer {
+	t := newDeepEqualer(nil)
+	t.deepEqualConfig = d.deepEqualConfig
+	t.reporter = nil
+	t.maxMismatches = 1
+	return t
+}
+
+// unorderedElementsEqual compares the elements of two arrays or slices
+// of the same length, pairing each element of v1 with the first equal
+// element of v2 that has not already been paired.
+func (d *deepEqualer) unorderedElementsEqual(path string, v1, v2 reflect.Value, depth int) bool {
+	n := v1.Len()
+	paired := make([]bool, n)
+	var unpaired []int
+	for i := 0; i < n; i++ {
+		found := false
+		for j := 0; j < n; j++ {
+			if paired[j] {
+				continue
+			}
+			if d.trial().deepValueEqual(path, v1.Index(i), v2.Index(j), depth+1) {
+				paired[j] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			unpaired = append(unpaired, i)
+		}
+	}
+	if len(unpaired) == 0 {
+		return true
+	}
+	for _, i := range unpaired {
+		d.mismatch(&mismatchError{
+			v1:    v1.Index(i),
+			path:  fmt.Sprintf("%s[%d]", path, i),
+			how:   "unexpected element",
+			cycle: d.cycle,
+		})
+		if d.done() {
+			return false
+		}
 	}
-	return deepValueEqual("", v1, v2, make(map[visit]bool), 0, nil)
+	for j := 0; j < n; j++ {
+		if paired[j] {
+			continue
+		}
+		d.mismatch(&mismatchError{
+			v2:    v2.Index(j),
+			path:  path,
+			how:   "missing element",
+			cycle: d.cycle,
+		})
+		if d.done() {
+			return false
+		}
+	}
+	return false
 }
 
-// DeepEqualWithCustomCheck tests for deep equality. It uses normal == equality where
+// foldKeys returns the keys of a string keyed map indexed by their
+// lower case form. If two keys differ only by case, they are returned
+// instead.
+func foldKeys(m reflect.Value) (map[string]reflect.Value, []string) {
+	folded := make(map[string]reflect.Value)
+	for _, k := range sortedKeys(m) {
+		lower := strings.ToLower(k.String())
+		if other, found := folded[lower]; found {
+			return nil, []string{other.String(), k.String()}
+		}
+		folded[lower] = k
+	}
+	return folded, nil
+}
+
+// foldedMapEqual compares two maps with string keys, matching their
+// keys without regard to case.
+func (d *deepEqualer) foldedMapEqual(path string, v1, v2 reflect.Value, depth int) bool {
+	keys1, dup1 := foldKeys(v1)
+	keys2, dup2 := foldKeys(v2)
+	if dup1 != nil || dup2 != nil {
+		how := "obtained"
+		dup := dup1
+		if dup == nil {
+			how, dup = "expected", dup2
+		}
+		return d.mismatch(&mismatchError{
+			v1:    v1,
+			v2:    v2,
+			path:  path,
+			how:   fmt.Sprintf("%s keys %q and %q differ only by case", how, dup[0], dup[1]),
+			cycle: d.cycle,
+		})
+	}
+	if len(keys1) != len(keys2) {
+		return d.mismatch(&mismatchError{
+			v1:    v1,
+			v2:    v2,
+			path:  path,
+			how:   fmt.Sprintf("length mismatch, %d vs %d", len(keys1), len(keys2)),
+			cycle: d.cycle,
+		})
+	}
+	equal := true
+	for _, k1 := range sortedKeys(v1) {
+		var e2 reflect.Value
+		if k2, found := keys2[strings.ToLower(k1.String())]; found {
+			e2 = v2.MapIndex(k2)
+		}
+		p := path + "[" + fmt.Sprintf("%#v", interfaceOf(k1)) + "]"
+		if !d.deepValueEqual(p, v1.MapIndex(k1), e2, depth+1) {
+			equal = false
+			if d.done() {
+				break
+			}
+		}
+	}
+	return equal
+}
+
+// sortedKeys returns the keys of the map in a stable order so that
+// mismatches are reported deterministically.
+func sortedKeys(m reflect.Value) []reflect.Value {
+	keys := m.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return keyLess(keys[i], keys[j])
+	})
+	return keys
+}
+
+func keyLess(a, b reflect.Value) bool {
+	if a.Kind() == b.Kind() {
+		switch a.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return a.Int() < b.Int()
+		case reflect.Uint, reflect.Uintptr, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return a.Uint() < b.Uint()
+		case reflect.Float32, reflect.Float64:
+			return a.Float() < b.Float()
+		case reflect.String:
+			return a.String() < b.String()
+		}
+	}
+	return fmt.Sprintf("%#v", interfaceOf(a)) < fmt.Sprintf("%#v", interfaceOf(b))
+}
+
+// DeepEqual tests for deep equality. It uses normal == equality where
 // possible but will scan elements of arrays, slices, maps, and fields
 // of structs. In maps, keys are compared with == but elements use deep
 // equality. DeepEqual correctly handles recursive types. Functions are
-// equal only if they are both nil.
+// equal only if they are both nil, unless the CompareFuncsByPointer or
+// IgnoreFuncs options are used. Channels are equal only if they are
+// the same channel.
 //
 // DeepEqual differs from reflect.DeepEqual in two ways:
 // - an empty slice is considered equal to a nil slice.
 // - two time.Time values that represent the same instant
 // but with different time zones are considered equal.
 //
-// If the two values compare unequal, the resulting error holds the
-// first difference encountered.
+// If the two values compare unequal, the resulting error describes
+// every difference found, up to a limit of 10. When only one difference
+// is found the error holds just that difference. When a difference is
+// reached by following a reference that cycles back on only one side,
+// its description also says where that cycle was closed.
+func DeepEqual(a1, a2 interface{}) (bool, error) {
+	return DeepEqualWithOptions(a1, a2)
+}
+
+// DeepEqualWithCustomCheck tests for deep equality in the same way as
+// DeepEqual.
 //
 // If both values are interface-able and customCheckFunc is non nil,
 // customCheckFunc will be invoked. If it returns useDefault as true, the
 // DeepEqual continues, otherwise the result of the customCheckFunc is used.
 func DeepEqualWithCustomCheck(a1 interface{}, a2 interface{}, customCheckFunc CustomCheckFunc) (bool, error) {
-	errorf := func(f string, a ...interface{}) error {
-		return &mismatchError{
-			v1:   reflect.ValueOf(a1),
-			v2:   reflect.ValueOf(a2),
-			path: "",
-			how:  fmt.Sprintf(f, a...),
-		}
+	return DeepEqualWithOptions(a1, a2, CustomCheck(customCheckFunc))
+}
+
+// DeepEqualWithOptions tests for deep equality in the same way as
+// DeepEqual, with the behaviour of the comparison altered by the
+// options given.
+func DeepEqualWithOptions(a1, a2 interface{}, options ...DeepEqualOption) (bool, error) {
+	d := newDeepEqualer(options)
+	v1 := reflect.ValueOf(a1)
+	v2 := reflect.ValueOf(a2)
+	mismatchf := func(f string, a ...interface{}) (bool, error) {
+		d.mismatch(&mismatchError{
+			v1:  v1,
+			v2:  v2,
+			how: fmt.Sprintf(f, a...),
+		})
+		return d.result()
 	}
 	if a1 == nil || a2 == nil {
 		if a1 == a2 {
 			return true, nil
 		}
-		return false, errorf("nil vs non-nil mismatch")
+		other := a1
+		if other == nil {
+			other = a2
+		}
+		if isTypedNil(other) {
+			// Comparing untyped nil with the nil value of a type is
+			// a common surprise, so say so.
+			return mismatchf("untyped nil vs nil %T mismatch", other)
+		}
+		return mismatchf("nil vs non-nil mismatch")
 	}
-	v1 := reflect.ValueOf(a1)
-	v2 := reflect.ValueOf(a2)
 	if v1.Type() != v2.Type() {
-		return false, errorf("type mismatch %s vs %s", v1.Type(), v2.Type())
+		return mismatchf("type mismatch %s vs %s", v1.Type(), v2.Type())
 	}
-	return deepValueEqual("", v1, v2, make(map[visit]bool), 0, customCheckFunc)
+	d.deepValueEqual("", v1, v2, 0)
+	return d.result()
 }
 
 // CustomCheckFunc should return true for useDefault if DeepEqualWithCustomCheck should behave like DeepEqual.
@@ -0,0 +1,99 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"bytes"
+	"reflect"
+)
+
+// canFastCompare reports whether the elements of values of type t,
+// a slice, array or map, can be compared without visiting each one.
+// That is only possible when no option could alter how the elements
+// are compared.
+func (d *deepEqualer) canFastCompare(t reflect.Type) bool {
+	if d.customCheckFunc != nil {
+		return false
+	}
+	elem := t.Elem()
+	for _, tr := range d.transformers {
+		if tr.path != "" || tr.applies(elem) {
+			return false
+		}
+	}
+	switch elem.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uintptr, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return t.Kind() != reflect.Map || t.Key().Kind() == reflect.String
+	}
+	return false
+}
+
+// fastEqual compares two slices, arrays or maps of the same type with
+// elements of a basic kind, without using reflection on each element
+// where possible. It reports whether the values are equal, and whether
+// it was able to tell. Values it reports as unequal are compared again
+// element by element to describe the differences.
+func fastEqual(v1, v2 reflect.Value) (equal, ok bool) {
+	if v1.Kind() == reflect.Array {
+		// Arrays of basic kinds are comparable with ==.
+		return interfaceOf(v1) == interfaceOf(v2), true
+	}
+	if v1.Kind() == reflect.Slice && v1.Type().Elem().Kind() == reflect.Uint8 {
+		return bytes.Equal(bypassCanInterface(v1).Bytes(), bypassCanInterface(v2).Bytes()), true
+	}
+	switch a := interfaceOf(v1).(type) {
+	case []string:
+		b := interfaceOf(v2).([]string)
+		for i := range a {
+			if a[i] != b[i] {
+				return false, true
+			}
+		}
+		return true, true
+	case []int:
+		b := interfaceOf(v2).([]int)
+		for i := range a {
+			if a[i] != b[i] {
+				return false, true
+			}
+		}
+		return true, true
+	case []int64:
+		b := interfaceOf(v2).([]int64)
+		for i := range a {
+			if a[i] != b[i] {
+				return false, true
+			}
+		}
+		return true, true
+	case []float64:
+		b := interfaceOf(v2).([]float64)
+		for i := range a {
+			if a[i] != b[i] {
+				return false, true
+			}
+		}
+		return true, true
+	case map[string]string:
+		b := interfaceOf(v2).(map[string]string)
+		for k, av := range a {
+			if bv, found := b[k]; !found || av != bv {
+				return false, true
+			}
+		}
+		return true, true
+	case map[string]int:
+		b := interfaceOf(v2).(map[string]int)
+		for k, av := range a {
+			if bv, found := b[k]; !found || av != bv {
+				return false, true
+			}
+		}
+		return true, true
+	}
+	return false, false
+}
@@ -0,0 +1,103 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"reflect"
+)
+
+// maxIteratorValues limits how many values are drained from an
+// iterator, so an infinite iterator fails the comparison rather than
+// running forever.
+const maxIteratorValues = 1 << 20
+
+var boolType = reflect.TypeOf(true)
+
+// isSeq reports whether t has the shape of an iter.Seq or iter.Seq2,
+// that is func(yield func(V) bool) or func(yield func(K, V) bool).
+func isSeq(t reflect.Type) bool {
+	if t.Kind() != reflect.Func || t.NumIn() != 1 || t.NumOut() != 0 {
+		return false
+	}
+	yield := t.In(0)
+	return yield.Kind() == reflect.Func &&
+		(yield.NumIn() == 1 || yield.NumIn() == 2) &&
+		yield.NumOut() == 1 && yield.Out(0) == boolType
+}
+
+// nextMethod returns the Next method of the value, if it has one of the
+// form Next() (V, bool).
+func nextMethod(v reflect.Value) (reflect.Value, bool) {
+	if v.Kind() == reflect.Ptr && v.IsNil() {
+		return reflect.Value{}, false
+	}
+	method, ok := v.Type().MethodByName("Next")
+	if !ok {
+		return reflect.Value{}, false
+	}
+	t := method.Type
+	// The method type includes the receiver as its first argument.
+	if t.NumIn() != 1 || t.NumOut() != 2 || t.Out(1) != boolType {
+		return reflect.Value{}, false
+	}
+	return bypassCanInterface(v).Method(method.Index), true
+}
+
+// isIterator reports whether values of v's type are drained before they
+// are compared.
+func isIterator(v reflect.Value) bool {
+	if isSeq(v.Type()) {
+		return !v.IsNil()
+	}
+	_, ok := nextMethod(v)
+	return ok
+}
+
+// drain collects the values produced by the iterator into a slice. The
+// pairs produced by an iter.Seq2 are collected as structs with Key and
+// Value fields. It also reports whether the iterator stopped of its own
+// accord before producing more than maxIteratorValues values.
+func drain(v reflect.Value) (reflect.Value, bool) {
+	if next, ok := nextMethod(v); ok {
+		result := reflect.MakeSlice(reflect.SliceOf(next.Type().Out(0)), 0, 0)
+		for result.Len() < maxIteratorValues {
+			out := next.Call(nil)
+			if !out[1].Bool() {
+				return result, true
+			}
+			result = reflect.Append(result, out[0])
+		}
+		return result, false
+	}
+
+	yieldType := v.Type().In(0)
+	var elemType reflect.Type
+	if yieldType.NumIn() == 1 {
+		elemType = yieldType.In(0)
+	} else {
+		elemType = reflect.StructOf([]reflect.StructField{
+			{Name: "Key", Type: yieldType.In(0)},
+			{Name: "Value", Type: yieldType.In(1)},
+		})
+	}
+	result := reflect.MakeSlice(reflect.SliceOf(elemType), 0, 0)
+	finished := true
+	yield := reflect.MakeFunc(yieldType, func(args []reflect.Value) []reflect.Value {
+		if result.Len() >= maxIteratorValues {
+			finished = false
+			return []reflect.Value{reflect.ValueOf(false)}
+		}
+		if len(args) == 1 {
+			result = reflect.Append(result, args[0])
+		} else {
+			pair := reflect.New(elemType).Elem()
+			pair.Field(0).Set(args[0])
+			pair.Field(1).Set(args[1])
+			result = reflect.Append(result, pair)
+		}
+		return []reflect.Value{reflect.ValueOf(true)}
+	})
+	bypassCanInterface(v).Call([]reflect.Value{yield})
+	return result, finished
+}
@@ -0,0 +1,340 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// defaultMaxMismatches is the number of mismatches a deep comparison
+// collects before it stops looking for more.
+const defaultMaxMismatches = 10
+
+// deepEqualConfig holds the settings that DeepEqualOptions alter.
+type deepEqualConfig struct {
+	customCheckFunc CustomCheckFunc
+	maxMismatches   int
+	// unexported records, for struct types named in IgnoreUnexported
+	// or AllowUnexported options, whether their unexported fields are
+	// compared. Once either option is used, the unexported fields of
+	// any other struct type cause an error.
+	unexported map[reflect.Type]bool
+	// partial has struct fields that are zero in the expected value
+	// left out of the comparison.
+	partial bool
+	// unorderedPaths and unorderedTypes name the slices and arrays
+	// whose elements are compared without regard to order.
+	unorderedPaths map[string]bool
+	unorderedTypes map[reflect.Type]bool
+	// transformers are applied to values before they are compared.
+	transformers []*transformer
+	// reporter is told of each mismatch as it is found.
+	reporter Reporter
+	// funcs says how function values are compared.
+	funcs funcComparison
+	// maxDepth, if positive, limits how deeply the values are compared
+	// and rendered.
+	maxDepth int
+	// maxElements, if positive, limits how many elements of each
+	// value are rendered when describing mismatches.
+	maxElements int
+	// parallelThreshold, if positive, is the length at or above which
+	// the elements of slices, arrays and maps are compared concurrently.
+	parallelThreshold int
+	// drainIterators has iterators compared by the values they produce.
+	drainIterators bool
+	// foldKeys has string keyed maps compare their keys without
+	// regard to case.
+	foldKeys bool
+}
+
+// funcComparison says how function values are compared.
+type funcComparison int
+
+const (
+	// funcsNilOnly considers functions equal only if both are nil.
+	funcsNilOnly funcComparison = iota
+	// funcsByPointer considers functions equal if they share the
+	// same code pointer.
+	funcsByPointer
+	// funcsIgnored considers all functions of the same type equal.
+	funcsIgnored
+)
+
+func newDeepEqualConfig(options []DeepEqualOption) deepEqualConfig {
+	c := deepEqualConfig{
+		maxMismatches: defaultMaxMismatches,
+	}
+	for _, option := range options {
+		option(&c)
+	}
+	return c
+}
+
+// limited reports whether values should be rendered within limits.
+func (c *deepEqualConfig) limited() bool {
+	return c.maxDepth > 0 || c.maxElements > 0
+}
+
+// DeepEqualOption alters the behaviour of a deep comparison. Options
+// are passed to DeepEqualWithOptions, or as extra values after the
+// expected value to the DeepEquals checker.
+type DeepEqualOption func(*deepEqualConfig)
+
+// CustomCheck has the comparison call check for every pair of values
+// that can be interfaced. See DeepEqualWithCustomCheck.
+func CustomCheck(check CustomCheckFunc) DeepEqualOption {
+	return func(c *deepEqualConfig) {
+		c.customCheckFunc = check
+	}
+}
+
+// MaxMismatches sets the number of mismatches that are collected before
+// the comparison stops. A limit of one reports only the first mismatch,
+// and a limit of zero or less collects every mismatch.
+func MaxMismatches(n int) DeepEqualOption {
+	return func(c *deepEqualConfig) {
+		c.maxMismatches = n
+	}
+}
+
+// IgnoreUnexported has the comparison skip the unexported fields of
+// the struct types of the values given. Values may be structs or
+// pointers to structs.
+//
+// Once IgnoreUnexported or AllowUnexported has been used, comparing a
+// struct that has unexported fields and is not of a type named by one
+// of them is reported as an error rather than silently reading those
+// fields.
+func IgnoreUnexported(types ...interface{}) DeepEqualOption {
+	return unexportedOption(types, false)
+}
+
+// AllowUnexported has the comparison compare the unexported fields of
+// the struct types of the values given. Values may be structs or
+// pointers to structs. See IgnoreUnexported.
+func AllowUnexported(types ...interface{}) DeepEqualOption {
+	return unexportedOption(types, true)
+}
+
+func unexportedOption(types []interface{}, compare bool) DeepEqualOption {
+	return func(c *deepEqualConfig) {
+		if c.unexported == nil {
+			c.unexported = make(map[reflect.Type]bool)
+		}
+		for _, value := range types {
+			t := reflect.TypeOf(value)
+			for t != nil && t.Kind() == reflect.Ptr {
+				t = t.Elem()
+			}
+			if t != nil {
+				c.unexported[t] = compare
+			}
+		}
+	}
+}
+
+// PartialMatch has the comparison skip every struct field whose value
+// in the expected value is the zero value for its type, at any depth.
+// This allows tests to specify only the fields they care about, and
+// stay valid as new fields are added to the structs being compared.
+func PartialMatch() DeepEqualOption {
+	return func(c *deepEqualConfig) {
+		c.partial = true
+	}
+}
+
+// IgnoreOrderAt has the comparison treat the slices or arrays at the
+// given paths as unordered collections, so they are equal if every
+// element of one can be paired with an equal element of the other.
+// Paths use the syntax described by Mismatch.Path, for example
+// ".Items" or "[\"key\"].Tags".
+func IgnoreOrderAt(paths ...string) DeepEqualOption {
+	return func(c *deepEqualConfig) {
+		if c.unorderedPaths == nil {
+			c.unorderedPaths = make(map[string]bool)
+		}
+		for _, path := range paths {
+			c.unorderedPaths[path] = true
+		}
+	}
+}
+
+// IgnoreOrder has the comparison treat every slice or array with the
+// same type as one of the values given as an unordered collection. See
+// IgnoreOrderAt. For example, IgnoreOrder([]string(nil)) ignores the
+// order of all string slices.
+func IgnoreOrder(collections ...interface{}) DeepEqualOption {
+	return func(c *deepEqualConfig) {
+		if c.unorderedTypes == nil {
+			c.unorderedTypes = make(map[reflect.Type]bool)
+		}
+		for _, value := range collections {
+			if t := reflect.TypeOf(value); t != nil {
+				c.unorderedTypes[t] = true
+			}
+		}
+	}
+}
+
+// transformer converts values of one type into another before they are
+// compared.
+type transformer struct {
+	name string
+	fn   reflect.Value
+	in   reflect.Type
+	// path, if set, restricts the transformer to the value at that path.
+	path string
+}
+
+func newTransformer(name string, fn interface{}) *transformer {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func || v.Type().NumIn() != 1 || v.Type().NumOut() != 1 {
+		panic(fmt.Sprintf("transformer %q must be a function taking one argument and returning one value, not %T", name, fn))
+	}
+	return &transformer{
+		name: name,
+		fn:   v,
+		in:   v.Type().In(0),
+	}
+}
+
+// applies reports whether the transformer converts values of type t.
+func (t *transformer) applies(typ reflect.Type) bool {
+	if t.in.Kind() == reflect.Interface {
+		return typ.Implements(t.in)
+	}
+	return typ == t.in
+}
+
+func (t *transformer) apply(v reflect.Value) reflect.Value {
+	return t.fn.Call([]reflect.Value{bypassCanInterface(v)})[0]
+}
+
+// Transformer has the comparison pass both values through fn before
+// comparing them, wherever values of fn's argument type are found. The
+// function must take one argument and return one value, for example
+// strings.ToLower or func(t time.Time) time.Time { return t.Round(time.Second) }.
+// If the argument type is an interface, fn is used for every value that
+// implements it. Mismatches beneath a transformed value have paths such
+// as "name(.Field)". The result of a transformer is not passed to the
+// same transformer again.
+//
+// Transformer panics if fn is not a suitable function.
+func Transformer(name string, fn interface{}) DeepEqualOption {
+	t := newTransformer(name, fn)
+	return func(c *deepEqualConfig) {
+		c.transformers = append(c.transformers, t)
+	}
+}
+
+// TransformerAt is like Transformer, but only transforms the values at
+// the path given. Paths use the syntax described by Mismatch.Path.
+func TransformerAt(path, name string, fn interface{}) DeepEqualOption {
+	t := newTransformer(name, fn)
+	t.path = path
+	return func(c *deepEqualConfig) {
+		c.transformers = append(c.transformers, t)
+	}
+}
+
+// transformerFor returns the transformer to apply to the value of
+// type typ at path, preferring those specific to the path.
+func (c *deepEqualConfig) transformerFor(path string, typ reflect.Type) *transformer {
+	var found *transformer
+	for _, t := range c.transformers {
+		if !t.applies(typ) {
+			continue
+		}
+		if t.path == path {
+			return t
+		}
+		if t.path == "" && found == nil {
+			found = t
+		}
+	}
+	return found
+}
+
+// CompareFuncsByPointer has the comparison consider two functions equal
+// if both are nil, or if both have the same underlying code pointer.
+// Without it, non-nil functions are never equal. Note that closures
+// created by the same function literal share a code pointer, so are
+// considered equal even if they capture different variables.
+func CompareFuncsByPointer() DeepEqualOption {
+	return func(c *deepEqualConfig) {
+		c.funcs = funcsByPointer
+	}
+}
+
+// IgnoreFuncs has the comparison consider all functions of the same
+// type equal, allowing structs that hold callbacks to be compared on
+// their other fields.
+func IgnoreFuncs() DeepEqualOption {
+	return func(c *deepEqualConfig) {
+		c.funcs = funcsIgnored
+	}
+}
+
+// MaxDepth limits how deeply nested values are compared. Reaching a
+// struct, slice, array or map nested more deeply than n is reported as
+// a mismatch rather than descending further, so huge or pathological
+// trees cannot exhaust the stack. Following pointers and interfaces
+// does not count towards the depth. Values in mismatch descriptions and
+// diffs are also only rendered to that depth, with deeper values shown
+// as "{...}".
+func MaxDepth(n int) DeepEqualOption {
+	return func(c *deepEqualConfig) {
+		c.maxDepth = n
+	}
+}
+
+// MaxElements limits how many elements of each slice, array or map, and
+// how many fields of each struct, are rendered in mismatch descriptions
+// and diffs. Those left out are summarised with a "...(+N more)" marker.
+func MaxElements(n int) DeepEqualOption {
+	return func(c *deepEqualConfig) {
+		c.maxElements = n
+	}
+}
+
+// Parallel has the comparison split the elements of any slice, array or
+// map of at least threshold elements across multiple goroutines. The
+// mismatches found are reported in the same order as they would be by
+// a sequential comparison, although once the mismatch limit is reached
+// the remaining mismatches of other goroutines are discarded. Any
+// custom check or transformer functions used must be safe to call
+// concurrently, and a Reporter is only told of mismatches once all the
+// goroutines have finished.
+func Parallel(threshold int) DeepEqualOption {
+	return func(c *deepEqualConfig) {
+		c.parallelThreshold = threshold
+	}
+}
+
+// DrainIterators has the comparison compare iterators by the sequences
+// of values they produce. Iterators are non-nil functions of the form
+// func(yield func(V) bool), such as iter.Seq, or func(yield func(K, V) bool),
+// such as iter.Seq2, and values with a method of the form Next() (V, bool).
+// Each side is drained into a slice, with the pairs from an iter.Seq2
+// held in structs with Key and Value fields, and the slices compared.
+// Mismatches within them have paths such as "drain(.Items)[2]".
+// Draining consumes the iterators.
+func DrainIterators() DeepEqualOption {
+	return func(c *deepEqualConfig) {
+		c.drainIterators = true
+	}
+}
+
+// IgnoreMapKeyCase has the comparison match the keys of maps with
+// string keys without regard to case, as is needed for HTTP headers or
+// environment maps. A map with two keys that differ only by case, such
+// as "Accept" and "accept", is reported as a mismatch rather than one
+// of its entries being chosen arbitrarily.
+func IgnoreMapKeyCase() DeepEqualOption {
+	return func(c *deepEqualConfig) {
+		c.foldKeys = true
+	}
+}
@@ -0,0 +1,71 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"reflect"
+	"runtime"
+	"sync"
+)
+
+// parallel reports whether a collection of n elements should be
+// compared concurrently.
+func (d *deepEqualer) parallel(n int) bool {
+	return d.parallelThreshold > 0 && n >= d.parallelThreshold
+}
+
+// worker returns a comparer for use by one goroutine of a parallel
+// comparison. It shares d's configuration, but collects its own
+// mismatches and does not report them or start goroutines itself.
+func (d *deepEqualer) worker() *deepEqualer {
+	w := newDeepEqualer(nil)
+	w.deepEqualConfig = d.deepEqualConfig
+	w.reporter = nil
+	w.parallelThreshold = 0
+	w.cycle = d.cycle
+	return w
+}
+
+// parallelEqual compares n pairs of values, as returned by pair, across
+// a goroutine per processor. The mismatches found by each goroutine are
+// recorded in d in order, so the result is the same as comparing the
+// pairs sequentially.
+func (d *deepEqualer) parallelEqual(n, depth int, pair func(i int) (string, reflect.Value, reflect.Value)) bool {
+	workers := runtime.GOMAXPROCS(0)
+	chunk := (n + workers - 1) / workers
+	results := make([][]*mismatchError, workers)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		start, end := w*chunk, (w+1)*chunk
+		if end > n {
+			end = n
+		}
+		if start >= end {
+			break
+		}
+		wg.Add(1)
+		go func(w, start, end int) {
+			defer wg.Done()
+			worker := d.worker()
+			for i := start; i < end && !worker.done(); i++ {
+				path, v1, v2 := pair(i)
+				worker.deepValueEqual(path, v1, v2, depth+1)
+			}
+			results[w] = worker.mismatches
+		}(w, start, end)
+	}
+	wg.Wait()
+
+	equal := true
+	for _, mismatches := range results {
+		for _, m := range mismatches {
+			equal = false
+			d.mismatch(m)
+			if d.done() {
+				return false
+			}
+		}
+	}
+	return equal
+}
@@ -12,6 +12,7 @@ package checkers_test
 
 import (
 	"regexp"
+	"strings"
 	"testing"
 	"time"
 
@@ -68,15 +69,16 @@ var deepEqualTests = []DeepEqualTest{
 	{float32(0.5), float32(0.6), false, `mismatch at top level: unequal; obtained 0\.5; expected 0\.6`},
 	{"hello", "hey", false, `mismatch at top level: unequal; obtained "hello"; expected "hey"`},
 	{make([]int, 10), make([]int, 11), false, `mismatch at top level: length mismatch, 10 vs 11; obtained \[\]int\{0, 0, 0, 0, 0, 0, 0, 0, 0, 0\}; expected \[\]int\{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0\}`},
-	{&[3]int{1, 2, 3}, &[3]int{1, 2, 4}, false, `mismatch at \(\*\)\[2\]: unequal; obtained 3; expected 4`},
+	{&[3]int{1, 2, 3}, &[3]int{1, 2, 4}, false, `mismatch at \[2\]: unequal; obtained 3; expected 4`},
 	{Basic{1, 0.5}, Basic{1, 0.6}, false, `mismatch at \.y: unequal; obtained 0\.5; expected 0\.6`},
 	{Basic{1, 0}, Basic{2, 0}, false, `mismatch at \.x: unequal; obtained 1; expected 2`},
 	{map[int]string{1: "one", 3: "two"}, map[int]string{2: "two", 1: "one"}, false, `mismatch at \[3\]: validity mismatch; obtained "two"; expected <nil>`},
 	{map[int]string{1: "one", 2: "txo"}, map[int]string{2: "two", 1: "one"}, false, `mismatch at \[2\]: unequal; obtained "txo"; expected "two"`},
-	{map[int]string{1: "one"}, map[int]string{2: "two", 1: "one"}, false, `mismatch at top level: length mismatch, 1 vs 2; obtained map\[int\]string\{1:"one"\}; expected map\[int\]string\{.*\}`},
-	{map[int]string{2: "two", 1: "one"}, map[int]string{1: "one"}, false, `mismatch at top level: length mismatch, 2 vs 1; obtained map\[int\]string\{.*\}; expected map\[int\]string\{1:"one"\}`},
+	{map[int]string{1: "one"}, map[int]string{2: "two", 1: "one"}, false, `mismatch at top level: length mismatch, 1 vs 2; obtained map\[int\]string\{1: "one"\}; expected map\[int\]string\{1: "one", 2: "two"\}`},
+	{map[int]string{2: "two", 1: "one"}, map[int]string{1: "one"}, false, `mismatch at top level: length mismatch, 2 vs 1; obtained map\[int\]string\{1: "one", 2: "two"\}; expected map\[int\]string\{1: "one"\}`},
 	{nil, 1, false, `mismatch at top level: nil vs non-nil mismatch; obtained <nil>; expected 1`},
 	{1, nil, false, `mismatch at top level: nil vs non-nil mismatch; obtained 1; expected <nil>`},
+	{(*int)(nil), nil, false, `mismatch at top level: untyped nil vs nil \*int mismatch; obtained \(\*int\)\(nil\); expected <nil>`},
 	{fn1, fn3, false, `mismatch at top level: non-nil functions; obtained \(func\(\)\)\(nil\); expected \(func\(\)\)\(0x[0-9a-f]+\)`},
 	{fn3, fn3, false, `mismatch at top level: non-nil functions; obtained \(func\(\)\)\(0x[0-9a-f]+\); expected \(func\(\)\)\(0x[0-9a-f]+\)`},
 	{[]interface{}{nil}, []interface{}{"a"}, false, `mismatch at \[0\]: nil vs non-nil interface mismatch`},
@@ -91,8 +93,8 @@ var deepEqualTests = []DeepEqualTest{
 	{int32(1), int64(1), false, `mismatch at top level: type mismatch int32 vs int64; obtained 1; expected 1`},
 	{0.5, "hello", false, `mismatch at top level: type mismatch float64 vs string; obtained 0\.5; expected "hello"`},
 	{[]int{1, 2, 3}, [3]int{1, 2, 3}, false, `mismatch at top level: type mismatch \[\]int vs \[3\]int; obtained \[\]int\{1, 2, 3\}; expected \[3\]int\{1, 2, 3\}`},
-	{&[3]interface{}{1, 2, 4}, &[3]interface{}{1, 2, "s"}, false, `mismatch at \(\*\)\[2\]: type mismatch int vs string; obtained 4; expected "s"`},
-	{Basic{1, 0.5}, NotBasic{1, 0.5}, false, `mismatch at top level: type mismatch checkers_test\.Basic vs checkers_test\.NotBasic; obtained checkers_test\.Basic\{x:1, y:0\.5\}; expected checkers_test\.NotBasic\{x:1, y:0\.5\}`},
+	{&[3]interface{}{1, 2, 4}, &[3]interface{}{1, 2, "s"}, false, `mismatch at \[2\]: type mismatch int vs string; obtained 4; expected "s"`},
+	{Basic{1, 0.5}, NotBasic{1, 0.5}, false, `mismatch at top level: type mismatch checkers_test\.Basic vs checkers_test\.NotBasic; obtained checkers_test\.Basic\{x: 1, y: 0\.5\}; expected checkers_test\.NotBasic\{x: 1, y: 0\.5\}`},
 	{time.Unix(0, 0).UTC(), time.Unix(0, 0).In(time.FixedZone("FOO", 60*60)).Add(1), false, `mismatch at top level: unequal; obtained "1970-01-01T00:00:00Z"; expected "1970-01-01T00:00:00.000000001Z"`},
 	{time.Unix(0, 0).UTC(), time.Unix(0, 0).Add(1), false, `mismatch at top level: unequal; obtained "1970-01-01T00:00:00Z"; expected "1970-01-01T00:00:00.000000001Z"`},
 
@@ -140,6 +142,390 @@ func TestDeepEqualRecursiveStruct(t *testing.T) {
 	}
 }
 
+func TestDeepEqualCyclicMap(t *testing.T) {
+	a := map[string]interface{}{"x": 1}
+	a["self"] = a
+	b := map[string]interface{}{"x": 1}
+	b["self"] = b
+	if !deepEqual(a, b) {
+		t.Error("deepEqual(cyclic map same) = false, want true")
+	}
+}
+
+func TestDeepEqualCyclicSlice(t *testing.T) {
+	a := []interface{}{1, nil}
+	a[1] = a
+	b := []interface{}{1, nil}
+	b[1] = b
+	if !deepEqual(a, b) {
+		t.Error("deepEqual(cyclic slice same) = false, want true")
+	}
+}
+
+func TestDeepEqualCyclicPointer(t *testing.T) {
+	a, b := new(interface{}), new(interface{})
+	*a = a
+	*b = b
+	if !deepEqual(a, b) {
+		t.Error("deepEqual(cyclic pointer same) = false, want true")
+	}
+}
+
+func TestDeepEqualCycleReported(t *testing.T) {
+	a := new(Recursive)
+	*a = Recursive{12, a}
+	b := &Recursive{12, &Recursive{12, nil}}
+	ok, err := checkers.DeepEqual(a, b)
+	if ok {
+		t.Fatal("deepEqual(cyclic, acyclic) = true, want false")
+	}
+	want := `^mismatch at \.r\.r: validity mismatch \(obtained \.r cycles back to top level\); `
+	if ok, _ := regexp.MatchString(want, err.Error()); !ok {
+		t.Errorf("unexpected error %q, want %q", err.Error(), want)
+	}
+}
+
+type Wide struct {
+	A, B, C, D int
+}
+
+func TestDeepEqualReportsAllMismatches(t *testing.T) {
+	_, err := checkers.DeepEqual(Wide{1, 2, 3, 4}, Wide{1, 5, 3, 6})
+	want := "2 mismatches:\n" +
+		"\tmismatch at .B: unequal; obtained 2; expected 5\n" +
+		"\tmismatch at .D: unequal; obtained 4; expected 6"
+	if err == nil || err.Error() != want {
+		t.Errorf("unexpected error %v, want %q", err, want)
+	}
+}
+
+func TestDeepEqualMismatchesSortedMapKeys(t *testing.T) {
+	a := map[string]int{"c": 1, "a": 1, "b": 1}
+	b := map[string]int{"c": 2, "a": 2, "b": 2}
+	_, err := checkers.DeepEqual(a, b)
+	want := "3 mismatches:\n" +
+		"\tmismatch at [\"a\"]: unequal; obtained 1; expected 2\n" +
+		"\tmismatch at [\"b\"]: unequal; obtained 1; expected 2\n" +
+		"\tmismatch at [\"c\"]: unequal; obtained 1; expected 2"
+	if err == nil || err.Error() != want {
+		t.Errorf("unexpected error %v, want %q", err, want)
+	}
+}
+
+func TestDeepEqualMaxMismatches(t *testing.T) {
+	a := make([]int, 20)
+	b := make([]int, 20)
+	for i := range b {
+		b[i] = i + 1
+	}
+	_, err := checkers.DeepEqual(a, b)
+	if ok, _ := regexp.MatchString(`^10 mismatches \(stopped at limit, there may be more\):\n`, err.Error()); !ok {
+		t.Errorf("unexpected error %q", err.Error())
+	}
+
+	_, err = checkers.DeepEqualWithOptions(a, b, checkers.MaxMismatches(1))
+	want := "mismatch at [0]: unequal; obtained 0; expected 1"
+	if err.Error() != want {
+		t.Errorf("unexpected error %q, want %q", err.Error(), want)
+	}
+
+	_, err = checkers.DeepEqualWithOptions(a, b, checkers.MaxMismatches(0))
+	if ok, _ := regexp.MatchString(`^20 mismatches:\n`, err.Error()); !ok {
+		t.Errorf("unexpected error %q", err.Error())
+	}
+}
+
+type Mixed struct {
+	Exported   int
+	unexported int
+}
+
+type Outer struct {
+	Inner Mixed
+	other Basic
+}
+
+func TestDeepEqualIgnoreUnexported(t *testing.T) {
+	a := Outer{Mixed{1, 2}, Basic{1, 0.5}}
+	b := Outer{Mixed{1, 3}, Basic{2, 0.5}}
+	ok, err := checkers.DeepEqualWithOptions(a, b, checkers.IgnoreUnexported(Outer{}, &Mixed{}))
+	if !ok {
+		t.Errorf("unexpected mismatch: %v", err)
+	}
+	b.Inner.Exported = 4
+	_, err = checkers.DeepEqualWithOptions(a, b, checkers.IgnoreUnexported(Outer{}, Mixed{}))
+	if err == nil || err.Error() != "mismatch at .Inner.Exported: unequal; obtained 1; expected 4" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestDeepEqualAllowUnexported(t *testing.T) {
+	a := Outer{Mixed{1, 2}, Basic{1, 0.5}}
+	b := Outer{Mixed{1, 3}, Basic{1, 0.5}}
+	_, err := checkers.DeepEqualWithOptions(a, b,
+		checkers.IgnoreUnexported(Outer{}),
+		checkers.AllowUnexported(Mixed{}))
+	if err == nil || err.Error() != "mismatch at .Inner.unexported: unequal; obtained 2; expected 3" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestDeepEqualUnexportedNotSpecified(t *testing.T) {
+	a := Outer{Mixed{1, 2}, Basic{1, 0.5}}
+	_, err := checkers.DeepEqualWithOptions(a, a,
+		checkers.AllowUnexported(Outer{}),
+		checkers.MaxMismatches(1))
+	want := "mismatch at .Inner: unexported field unexported of checkers_test.Mixed is neither ignored nor allowed; " +
+		"obtained checkers_test.Mixed{Exported: 1, unexported: 2}; expected checkers_test.Mixed{Exported: 1, unexported: 2}"
+	if err == nil || err.Error() != want {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+type Person struct {
+	Name    string
+	Age     int
+	Tags    []string
+	Address *Address
+}
+
+type Address struct {
+	Street string
+	City   string
+}
+
+func TestDeepEqualPartialMatch(t *testing.T) {
+	obtained := Person{
+		Name:    "Alice",
+		Age:     42,
+		Tags:    []string{"admin"},
+		Address: &Address{Street: "Main St", City: "Dunedin"},
+	}
+	expected := Person{
+		Name:    "Alice",
+		Address: &Address{City: "Dunedin"},
+	}
+	ok, err := checkers.DeepEqualWithOptions(obtained, expected, checkers.PartialMatch())
+	if !ok {
+		t.Errorf("unexpected mismatch: %v", err)
+	}
+	expected.Age = 21
+	_, err = checkers.DeepEqualWithOptions(obtained, expected, checkers.PartialMatch())
+	if err == nil || err.Error() != "mismatch at .Age: unequal; obtained 42; expected 21" {
+		t.Errorf("unexpected error: %v", err)
+	}
+	// Without the option every field is compared.
+	if deepEqual(obtained, Person{Name: "Alice"}) {
+		t.Error("deepEqual(partial) = true, want false")
+	}
+}
+
+type Group struct {
+	Name    string
+	Members []string
+	Scores  []int
+}
+
+func TestDeepEqualIgnoreOrderAt(t *testing.T) {
+	a := Group{"g", []string{"a", "b", "c"}, []int{1, 2}}
+	b := Group{"g", []string{"c", "a", "b"}, []int{1, 2}}
+	ok, err := checkers.DeepEqualWithOptions(a, b, checkers.IgnoreOrderAt(".Members"))
+	if !ok {
+		t.Errorf("unexpected mismatch: %v", err)
+	}
+	b.Scores = []int{2, 1}
+	if ok, _ := checkers.DeepEqualWithOptions(a, b, checkers.IgnoreOrderAt(".Members")); ok {
+		t.Error("unordered comparison applied to the wrong path")
+	}
+}
+
+func TestDeepEqualIgnoreOrder(t *testing.T) {
+	a := []Group{{"g", []string{"a", "b"}, []int{1, 2}}}
+	b := []Group{{"g", []string{"b", "a"}, []int{2, 1}}}
+	ok, err := checkers.DeepEqualWithOptions(a, b, checkers.IgnoreOrder([]string(nil), []int(nil)))
+	if !ok {
+		t.Errorf("unexpected mismatch: %v", err)
+	}
+}
+
+func TestDeepEqualIgnoreOrderMismatch(t *testing.T) {
+	a := []string{"a", "b", "b"}
+	b := []string{"b", "c", "a"}
+	_, err := checkers.DeepEqualWithOptions(a, b, checkers.IgnoreOrder([]string(nil)))
+	want := "2 mismatches:\n" +
+		"\tmismatch at [2]: unexpected element; obtained \"b\"; expected <nil>\n" +
+		"\tmismatch at top level: missing element; obtained <nil>; expected \"c\""
+	if err == nil || err.Error() != want {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+type Event struct {
+	Name string
+	When time.Time
+}
+
+func TestDeepEqualTransformer(t *testing.T) {
+	base := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	a := map[string]Event{"A": {"start", base}}
+	b := map[string]Event{"A": {"START", base.Add(300 * time.Millisecond)}}
+	round := func(t time.Time) time.Time { return t.Round(time.Second) }
+	ok, err := checkers.DeepEqualWithOptions(a, b,
+		checkers.Transformer("lower", strings.ToLower),
+		checkers.Transformer("round", round))
+	if !ok {
+		t.Errorf("unexpected mismatch: %v", err)
+	}
+	b["A"] = Event{"stop", base}
+	_, err = checkers.DeepEqualWithOptions(a, b, checkers.Transformer("lower", strings.ToLower))
+	if err == nil || err.Error() != `mismatch at lower(["A"].Name): unequal; obtained "start"; expected "stop"` {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestDeepEqualTransformerAt(t *testing.T) {
+	a := Event{Name: "start"}
+	b := Event{Name: "START"}
+	ok, err := checkers.DeepEqualWithOptions(a, b, checkers.TransformerAt(".Name", "lower", strings.ToLower))
+	if !ok {
+		t.Errorf("unexpected mismatch: %v", err)
+	}
+	ok, _ = checkers.DeepEqualWithOptions(a, b, checkers.TransformerAt(".Other", "lower", strings.ToLower))
+	if ok {
+		t.Error("transformer applied to the wrong path")
+	}
+}
+
+func TestDeepEqualTransformerInvalid(t *testing.T) {
+	err := checkers.PanicMatches.Check(func() {
+		checkers.Transformer("bad", 42)
+	}, `transformer "bad" must be a function taking one argument and returning one value, not int`)
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+type recordingReporter struct {
+	mismatches []checkers.Mismatch
+}
+
+func (r *recordingReporter) Report(m checkers.Mismatch) {
+	r.mismatches = append(r.mismatches, m)
+}
+
+type Nested struct {
+	Items []map[string]*Basic
+}
+
+func TestDeepEqualReporter(t *testing.T) {
+	a := Nested{[]map[string]*Basic{{"key": {1, 0.5}}, {}}}
+	b := Nested{[]map[string]*Basic{{"key": {2, 0.5}}, {"extra": nil}}}
+	var r recordingReporter
+	_, err := checkers.DeepEqualWithOptions(a, b, checkers.WithReporter(&r))
+	expected := []checkers.Mismatch{{
+		Path:     `.Items[0]["key"].x`,
+		Reason:   "unequal",
+		Obtained: 1,
+		Expected: 2,
+	}, {
+		Path:     `.Items[1]`,
+		Reason:   "length mismatch, 0 vs 1",
+		Obtained: map[string]*Basic{},
+		Expected: map[string]*Basic{"extra": nil},
+	}}
+	if ok, diff := checkers.DeepEqual(r.mismatches, expected); !ok {
+		t.Errorf("reported mismatches differ: %v", diff)
+	}
+	if ok, diff := checkers.DeepEqual(checkers.Mismatches(err), expected); !ok {
+		t.Errorf("mismatches from error differ: %v", diff)
+	}
+}
+
+func TestDeepEqualReporterIgnoreOrder(t *testing.T) {
+	var r recordingReporter
+	ok, err := checkers.DeepEqualWithOptions([]int{1, 2, 3}, []int{3, 2, 1}, checkers.IgnoreOrder([]int(nil)), checkers.WithReporter(&r))
+	if !ok {
+		t.Fatalf("unexpected mismatch: %v", err)
+	}
+	if len(r.mismatches) != 0 {
+		t.Errorf("unexpected mismatches reported: %v", r.mismatches)
+	}
+}
+
+type Handler struct {
+	Name     string
+	Callback func()
+	Done     chan struct{}
+}
+
+func TestDeepEqualFuncs(t *testing.T) {
+	done := make(chan struct{})
+	a := Handler{"h", fn3, done}
+	b := Handler{"h", fn3, done}
+	if deepEqual(a, b) {
+		t.Error("deepEqual(non-nil funcs) = true, want false")
+	}
+	if ok, err := checkers.DeepEqualWithOptions(a, b, checkers.CompareFuncsByPointer()); !ok {
+		t.Errorf("unexpected mismatch: %v", err)
+	}
+	b.Callback = func() {}
+	_, err := checkers.DeepEqualWithOptions(a, b, checkers.CompareFuncsByPointer())
+	if err == nil || !strings.HasPrefix(err.Error(), "mismatch at .Callback: different functions;") {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if ok, err := checkers.DeepEqualWithOptions(a, b, checkers.IgnoreFuncs()); !ok {
+		t.Errorf("unexpected mismatch: %v", err)
+	}
+}
+
+func TestDeepEqualChannelIdentity(t *testing.T) {
+	a := Handler{Done: make(chan struct{})}
+	b := Handler{Done: make(chan struct{})}
+	_, err := checkers.DeepEqual(a, b)
+	if err == nil || !strings.HasPrefix(err.Error(), "mismatch at .Done: unequal;") {
+		t.Errorf("unexpected error: %v", err)
+	}
+	b.Done = a.Done
+	if !deepEqual(a, b) {
+		t.Error("deepEqual(same channel) = false, want true")
+	}
+}
+
+func TestDeepEqualMaxDepth(t *testing.T) {
+	build := func(n int) *Recursive {
+		var r *Recursive
+		for i := 0; i < n; i++ {
+			r = &Recursive{i, r}
+		}
+		return r
+	}
+	ok, err := checkers.DeepEqualWithOptions(build(100000), build(100000), checkers.MaxDepth(2))
+	if ok {
+		t.Fatal("comparison beyond the maximum depth succeeded")
+	}
+	want := "mismatch at .r.r.r: maximum depth 2 exceeded; " +
+		"obtained checkers_test.Recursive{x: 99996, r: &checkers_test.Recursive{x: 99995, r: &checkers_test.Recursive{...}}}; " +
+		"expected checkers_test.Recursive{x: 99996, r: &checkers_test.Recursive{x: 99995, r: &checkers_test.Recursive{...}}}"
+	if err.Error() != want {
+		t.Errorf("unexpected error:\n%s\nwant:\n%s", err, want)
+	}
+	if ok, err := checkers.DeepEqualWithOptions(build(3), build(3), checkers.MaxDepth(10)); !ok {
+		t.Errorf("unexpected mismatch: %v", err)
+	}
+}
+
+func TestDeepEqualMaxElements(t *testing.T) {
+	a := Nested{Items: make([]map[string]*Basic, 100)}
+	b := Nested{Items: make([]map[string]*Basic, 101)}
+	_, err := checkers.DeepEqualWithOptions(a, b, checkers.MaxElements(2))
+	want := "mismatch at .Items: length mismatch, 100 vs 101; " +
+		"obtained []map[string]*checkers_test.Basic{map[string]*checkers_test.Basic(nil), map[string]*checkers_test.Basic(nil), ...(+98 more)}; " +
+		"expected []map[string]*checkers_test.Basic{map[string]*checkers_test.Basic(nil), map[string]*checkers_test.Basic(nil), ...(+99 more)}"
+	if err == nil || err.Error() != want {
+		t.Errorf("unexpected error:\n%s\nwant:\n%s", err, want)
+	}
+}
+
 type _Complex struct {
 	a int
 	b [3]*_Complex
@@ -186,3 +572,192 @@ func TestDeepEqualUnexportedMap(t *testing.T) {
 		t.Error("deepEqual(x1, y1) = true, want false")
 	}
 }
+
+func TestDeepEqualFastPaths(t *testing.T) {
+	type named []byte
+	for _, test := range []DeepEqualTest{
+		{[]byte("hello"), []byte("hello"), true, ""},
+		{[]byte("hello"), []byte("hellO"), false, `mismatch at \[4\]: unequal; obtained 0x6f; expected 0x4f`},
+		{named("abc"), named("abc"), true, ""},
+		{[]string{"a", "b"}, []string{"a", "c"}, false, `mismatch at \[1\]: unequal; obtained "b"; expected "c"`},
+		{[]float64{1, 2}, []float64{1, 2}, true, ""},
+		{[2]int{1, 2}, [2]int{1, 3}, false, `mismatch at \[1\]: unequal; obtained 2; expected 3`},
+		{map[string]int{"a": 1}, map[string]int{"a": 2}, false, `mismatch at \["a"\]: unequal; obtained 1; expected 2`},
+		{map[string]string{"a": "x"}, map[string]string{"b": "x"}, false, `mismatch at \["a"\]: validity mismatch`},
+	} {
+		r, err := checkers.DeepEqual(test.a, test.b)
+		if r != test.eq {
+			t.Errorf("deepEqual(%v, %v) = %v, want %v", test.a, test.b, r, test.eq)
+			continue
+		}
+		if !test.eq {
+			if ok, _ := regexp.MatchString(test.msg, err.Error()); !ok {
+				t.Errorf("deepEqual(%v, %v); unexpected error %q, want %q", test.a, test.b, err.Error(), test.msg)
+			}
+		}
+	}
+	// Options that alter how elements are compared disable the fast paths.
+	ok, _ := checkers.DeepEqualWithOptions([]string{"A"}, []string{"a"}, checkers.Transformer("lower", strings.ToLower))
+	if !ok {
+		t.Error("transformer not applied to slice elements")
+	}
+}
+
+func TestDeepEqualParallel(t *testing.T) {
+	a := make([]Basic, 1000)
+	b := make([]Basic, 1000)
+	for _, i := range []int{999, 3, 500} {
+		b[i].x = i
+	}
+	sequential, _ := checkers.DeepEqual(a, b)
+	_, err := checkers.DeepEqualWithOptions(a, b, checkers.Parallel(10))
+	want := "3 mismatches:\n" +
+		"\tmismatch at [3].x: unequal; obtained 0; expected 3\n" +
+		"\tmismatch at [500].x: unequal; obtained 0; expected 500\n" +
+		"\tmismatch at [999].x: unequal; obtained 0; expected 999"
+	if sequential || err == nil || err.Error() != want {
+		t.Errorf("unexpected error %v, want %q", err, want)
+	}
+
+	m1 := make(map[int]Basic)
+	m2 := make(map[int]Basic)
+	for i := 0; i < 1000; i++ {
+		m1[i] = Basic{i, 0}
+		m2[i] = Basic{i, 0}
+	}
+	if ok, err := checkers.DeepEqualWithOptions(m1, m2, checkers.Parallel(10)); !ok {
+		t.Errorf("unexpected mismatch: %v", err)
+	}
+	m2[7] = Basic{}
+	_, err = checkers.DeepEqualWithOptions(m1, m2, checkers.Parallel(10), checkers.MaxMismatches(1))
+	if err == nil || err.Error() != "mismatch at [7].x: unequal; obtained 7; expected 0" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func BenchmarkDeepEqualStructsParallel(b *testing.B) {
+	x := make([]Basic, 1<<16)
+	y := make([]Basic, 1<<16)
+	for i := 0; i < b.N; i++ {
+		checkers.DeepEqualWithOptions(x, y, checkers.Parallel(1024))
+	}
+}
+
+func seqOf(values ...int) func(func(int) bool) {
+	return func(yield func(int) bool) {
+		for _, v := range values {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+func seq2Of(values ...string) func(func(int, string) bool) {
+	return func(yield func(int, string) bool) {
+		for i, v := range values {
+			if !yield(i, v) {
+				return
+			}
+		}
+	}
+}
+
+type counter struct {
+	n, max int
+}
+
+func (c *counter) Next() (int, bool) {
+	if c.n >= c.max {
+		return 0, false
+	}
+	c.n++
+	return c.n, true
+}
+
+type Results struct {
+	Values func(func(int) bool)
+}
+
+func TestDeepEqualDrainIterators(t *testing.T) {
+	drain := checkers.DrainIterators()
+	if ok, err := checkers.DeepEqualWithOptions(seqOf(1, 2, 3), seqOf(1, 2, 3), drain); !ok {
+		t.Errorf("unexpected mismatch: %v", err)
+	}
+	_, err := checkers.DeepEqualWithOptions(Results{seqOf(1, 2)}, Results{seqOf(1, 3)}, drain)
+	if err == nil || err.Error() != "mismatch at drain(.Values)[1]: unequal; obtained 2; expected 3" {
+		t.Errorf("unexpected error: %v", err)
+	}
+	_, err = checkers.DeepEqualWithOptions(seq2Of("a", "b"), seq2Of("a", "c"), drain)
+	if err == nil || err.Error() != `mismatch at drain()[1].Value: unequal; obtained "b"; expected "c"` {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if ok, err := checkers.DeepEqualWithOptions(&counter{max: 3}, &counter{max: 3}, drain); !ok {
+		t.Errorf("unexpected mismatch: %v", err)
+	}
+	_, err = checkers.DeepEqualWithOptions(&counter{max: 3}, &counter{max: 2}, drain)
+	if err == nil || !strings.HasPrefix(err.Error(), "mismatch at drain(): length mismatch, 3 vs 2;") {
+		t.Errorf("unexpected error: %v", err)
+	}
+	// Without the option iterators are compared as before.
+	if deepEqual(seqOf(1), seqOf(1)) {
+		t.Error("deepEqual(iterators) = true, want false")
+	}
+}
+
+func TestDeepEqualDrainInfiniteIterator(t *testing.T) {
+	forever := func(yield func(int) bool) {
+		for yield(1) {
+		}
+	}
+	_, err := checkers.DeepEqualWithOptions(forever, seqOf(1), checkers.DrainIterators())
+	if err == nil || !strings.HasPrefix(err.Error(), "mismatch at top level: iterator produced more than 1048576 values;") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestDeepEqualIgnoreMapKeyCase(t *testing.T) {
+	fold := checkers.IgnoreMapKeyCase()
+	a := map[string][]string{"Content-Type": {"text/plain"}, "X-Id": {"1"}}
+	b := map[string][]string{"content-type": {"text/plain"}, "X-ID": {"1"}}
+	if ok, err := checkers.DeepEqualWithOptions(a, b, fold); !ok {
+		t.Errorf("unexpected mismatch: %v", err)
+	}
+	b["X-ID"] = []string{"2"}
+	_, err := checkers.DeepEqualWithOptions(a, b, fold)
+	if err == nil || err.Error() != `mismatch at ["X-Id"][0]: unequal; obtained "1"; expected "2"` {
+		t.Errorf("unexpected error: %v", err)
+	}
+	b["x-id"] = []string{"1"}
+	_, err = checkers.DeepEqualWithOptions(a, b, fold)
+	if err == nil || !strings.HasPrefix(err.Error(), `mismatch at top level: expected keys "X-ID" and "x-id" differ only by case;`) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if deepEqual(map[string]int{"A": 1}, map[string]int{"a": 1}) {
+		t.Error("deepEqual(differently cased keys) = true, want false")
+	}
+}
+
+func BenchmarkDeepEqualBytes(b *testing.B) {
+	x := make([]byte, 1<<20)
+	y := make([]byte, 1<<20)
+	for i := 0; i < b.N; i++ {
+		checkers.DeepEqual(x, y)
+	}
+}
+
+func BenchmarkDeepEqualStrings(b *testing.B) {
+	x := make([]string, 1<<16)
+	y := make([]string, 1<<16)
+	for i := 0; i < b.N; i++ {
+		checkers.DeepEqual(x, y)
+	}
+}
+
+func BenchmarkDeepEqualStructs(b *testing.B) {
+	x := make([]Basic, 1<<16)
+	y := make([]Basic, 1<<16)
+	for i := 0; i < b.N; i++ {
+		checkers.DeepEqual(x, y)
+	}
+}
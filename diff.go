@@ -0,0 +1,167 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// diffContext is the number of unchanged lines shown around each
+// change in a diff.
+const diffContext = 3
+
+// maxDiffCells bounds the size of the table used to find the longest
+// common subsequence of lines. Beyond it the differing lines are shown
+// as wholly removed and added.
+const maxDiffCells = 4 << 20
+
+// diffError adds a diff of the rendered values, and any similar
+// elements found by similarElements, to the description of a failed
+// comparison. Rendering and diffing the values is expensive, so it is
+// only done when the description is first asked for.
+type diffError struct {
+	err                error
+	obtained, expected interface{}
+	config             deepEqualConfig
+
+	once    sync.Once
+	message string
+}
+
+func (err *diffError) Error() string {
+	err.once.Do(func() {
+		err.message = err.describe()
+	})
+	return err.message
+}
+
+func (err *diffError) describe() string {
+	message := err.err.Error()
+	obtained := limitedPrint(reflect.ValueOf(err.obtained), &err.config, false)
+	expected := limitedPrint(reflect.ValueOf(err.expected), &err.config, false)
+	// Values small enough to fit on a single line are already shown
+	// by the description, and values that render the same have their
+	// differences beyond the rendering limits.
+	if (isMultiline(obtained) || isMultiline(expected)) && obtained != expected {
+		message += "\ndiff (-obtained +expected):\n" + lineDiff(obtained, expected)
+	}
+	if similar := similarElements(err.obtained, err.expected, err.config); similar != "" {
+		message += "\nsimilar elements:\n" + similar
+	}
+	return message
+}
+
+// diffOp is a single line of a diff.
+type diffOp struct {
+	kind byte // ' ', '-' or '+'
+	line string
+}
+
+// lineDiff returns a line based diff of obtained and expected, where
+// lines only in obtained are prefixed with "-" and lines only in
+// expected are prefixed with "+". Runs of unchanged lines away from any
+// change are elided.
+func lineDiff(obtained, expected string) string {
+	ops := diffLines(strings.Split(obtained, "\n"), strings.Split(expected, "\n"))
+
+	// Work out which unchanged lines are close enough to a change
+	// to be shown.
+	show := make([]bool, len(ops))
+	for i, op := range ops {
+		if op.kind == ' ' {
+			continue
+		}
+		for j := i - diffContext; j <= i+diffContext; j++ {
+			if j >= 0 && j < len(ops) {
+				show[j] = true
+			}
+		}
+	}
+
+	var buf strings.Builder
+	elided := false
+	for i, op := range ops {
+		if !show[i] {
+			if !elided {
+				buf.WriteString(" ...\n")
+				elided = true
+			}
+			continue
+		}
+		elided = false
+		buf.WriteByte(op.kind)
+		buf.WriteString(op.line)
+		buf.WriteByte('\n')
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// diffLines finds the longest common subsequence of the lines and
+// returns the edits that turn a into b.
+func diffLines(a, b []string) []diffOp {
+	var ops []diffOp
+	// Common prefix and suffix lines are cheap to find, and keep
+	// the table below small for the usual case of a few changes.
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		ops = append(ops, diffOp{' ', a[prefix]})
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix &&
+		a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	ma := a[prefix : len(a)-suffix]
+	mb := b[prefix : len(b)-suffix]
+
+	if (len(ma)+1)*(len(mb)+1) > maxDiffCells {
+		for _, line := range ma {
+			ops = append(ops, diffOp{'-', line})
+		}
+		for _, line := range mb {
+			ops = append(ops, diffOp{'+', line})
+		}
+	} else {
+		// lcs[i][j] is the length of the longest common
+		// subsequence of ma[i:] and mb[j:].
+		lcs := make([][]int, len(ma)+1)
+		for i := range lcs {
+			lcs[i] = make([]int, len(mb)+1)
+		}
+		for i := len(ma) - 1; i >= 0; i-- {
+			for j := len(mb) - 1; j >= 0; j-- {
+				if ma[i] == mb[j] {
+					lcs[i][j] = lcs[i+1][j+1] + 1
+				} else if lcs[i+1][j] >= lcs[i][j+1] {
+					lcs[i][j] = lcs[i+1][j]
+				} else {
+					lcs[i][j] = lcs[i][j+1]
+				}
+			}
+		}
+		i, j := 0, 0
+		for i < len(ma) || j < len(mb) {
+			switch {
+			case i < len(ma) && j < len(mb) && ma[i] == mb[j]:
+				ops = append(ops, diffOp{' ', ma[i]})
+				i++
+				j++
+			case j == len(mb) || (i < len(ma) && lcs[i+1][j] >= lcs[i][j+1]):
+				ops = append(ops, diffOp{'-', ma[i]})
+				i++
+			default:
+				ops = append(ops, diffOp{'+', mb[j]})
+				j++
+			}
+		}
+	}
+
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, diffOp{' ', line})
+	}
+	return ops
+}
@@ -0,0 +1,79 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLineDiff(t *testing.T) {
+	for _, test := range []struct {
+		description string
+		obtained    string
+		expected    string
+		diff        string
+	}{
+		{
+			description: "changed line",
+			obtained:    "a\nb\nc",
+			expected:    "a\nx\nc",
+			diff:        " a\n-b\n+x\n c",
+		}, {
+			description: "added line",
+			obtained:    "a\nc",
+			expected:    "a\nb\nc",
+			diff:        " a\n+b\n c",
+		}, {
+			description: "removed line",
+			obtained:    "a\nb\nc",
+			expected:    "a\nc",
+			diff:        " a\n-b\n c",
+		}, {
+			description: "distant unchanged lines elided",
+			obtained:    "1\n2\n3\n4\n5\n6\n7\n8\n9",
+			expected:    "1\n2\n3\n4\n5\n6\n7\n8\nx",
+			diff:        " ...\n 6\n 7\n 8\n-9\n+x",
+		},
+	} {
+		diff := lineDiff(test.obtained, test.expected)
+		if diff != test.diff {
+			t.Errorf("%s: diff mismatch: \n\tobtained: %q\n\texpected: %q", test.description, diff, test.diff)
+		}
+	}
+}
+
+type prettyStruct struct {
+	Name  string
+	Tags  []string
+	Attrs map[string]int
+	Next  *prettyStruct
+}
+
+func TestPrettyPrint(t *testing.T) {
+	v := &prettyStruct{
+		Name:  "one",
+		Tags:  []string{"a", "b"},
+		Attrs: map[string]int{"z": 26, "a": 1},
+	}
+	v.Next = v
+	expected := strings.Join([]string{
+		`&checkers.prettyStruct{`,
+		`	Name: "one",`,
+		`	Tags: []string{`,
+		`		"a",`,
+		`		"b",`,
+		`	},`,
+		`	Attrs: map[string]int{`,
+		`		"a": 1,`,
+		`		"z": 26,`,
+		`	},`,
+		`	Next: <cycle>,`,
+		`}`,
+	}, "\n")
+	if obtained := prettyPrint(reflect.ValueOf(v)); obtained != expected {
+		t.Errorf("pretty print mismatch: \n\tobtained: %s\n\texpected: %s", obtained, expected)
+	}
+}
@@ -0,0 +1,42 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"time"
+)
+
+// FailIfLongerThan has the test fail when it is cleaned up if more than
+// d has passed since FailIfLongerThan was called, to keep the tests
+// that should be fast from slowly becoming slow. Call it at the start
+// of the test, so that the whole of it is timed.
+//
+//	t.FailIfLongerThan(100 * time.Millisecond)
+func (t *Test) FailIfLongerThan(d time.Duration) {
+	tb := t.testingTB()
+	tb.Helper()
+	t.onLongerThan(d, tb.Errorf)
+}
+
+// WarnIfLongerThan logs a warning when the test is cleaned up if more
+// than d has passed since WarnIfLongerThan was called, without failing
+// the test, for budgets that are not yet enforced.
+func (t *Test) WarnIfLongerThan(d time.Duration) {
+	tb := t.testingTB()
+	tb.Helper()
+	t.onLongerThan(d, tb.Logf)
+}
+
+func (t *Test) onLongerThan(d time.Duration, report func(format string, args ...interface{})) {
+	tb := t.testingTB()
+	start := time.Now()
+	tb.Cleanup(func() {
+		if tb.Skipped() {
+			return
+		}
+		if took := time.Since(start); took > d {
+			report("test took %v, longer than its budget of %v", took, d)
+		}
+	})
+}
@@ -0,0 +1,75 @@
+// Add a copyright
+// Add a licence
+
+package checkers_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/howbazaar/checkers"
+)
+
+func TestFailIfLongerThan(t *testing.T) {
+	for _, test := range []struct {
+		description string
+		test        func(ct *checkers.Test)
+		errors      string
+		logs        string
+	}{
+		{
+			description: "within budget",
+			test: func(ct *checkers.Test) {
+				ct.FailIfLongerThan(time.Hour)
+			},
+		}, {
+			description: "over budget",
+			test: func(ct *checkers.Test) {
+				ct.FailIfLongerThan(time.Nanosecond)
+				time.Sleep(time.Millisecond)
+			},
+			errors: `test took .*, longer than its budget of 1ns`,
+		}, {
+			description: "over budget warning",
+			test: func(ct *checkers.Test) {
+				ct.WarnIfLongerThan(time.Nanosecond)
+				time.Sleep(time.Millisecond)
+			},
+			logs: `test took .*, longer than its budget of 1ns`,
+		}, {
+			description: "skipped",
+			test: func(ct *checkers.Test) {
+				ct.FailIfLongerThan(time.Nanosecond)
+				time.Sleep(time.Millisecond)
+				ct.TB.Skip("not today")
+			},
+			logs: "not today",
+		},
+	} {
+		r := checkers.NewRecordingTB()
+		r.Run(func(tb testing.TB) {
+			test.test(&checkers.Test{TB: tb})
+		})
+		checkRecorded(t, test.description+" errors", r.Errors(), test.errors)
+		checkRecorded(t, test.description+" logs", r.Logs(), test.logs)
+	}
+}
+
+// checkRecorded checks that there is a single recorded message matching
+// the pattern, or none if the pattern is empty.
+func checkRecorded(t *testing.T, description string, recorded []string, pattern string) {
+	t.Helper()
+	if pattern == "" {
+		if len(recorded) != 0 {
+			t.Errorf("%s: unexpected messages: %q", description, recorded)
+		}
+		return
+	}
+	if len(recorded) != 1 {
+		t.Errorf("%s: expected one message, got %q", description, recorded)
+		return
+	}
+	if err := checkers.Matches.Check(recorded[0], pattern); err != nil {
+		t.Errorf("%s: %v", description, err)
+	}
+}
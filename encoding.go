@@ -0,0 +1,227 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type textRoundTrips struct{}
+
+// TextRoundTrips checker checks that the obtained value, which must
+// implement encoding.TextMarshaler, marshals to the expected text, and
+// that unmarshaling that text into a new value of the same type, with
+// encoding.TextUnmarshaler, gives a value deeply equal to the obtained
+// one. The obtained value may be a pointer to the value.
+//
+//	t.Check(Green, checkers.TextRoundTrips, "green")
+var TextRoundTrips Checker = textRoundTrips{}
+
+func (textRoundTrips) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if len(extras) == 0 {
+		return errors.New("missing 'expected' value")
+	}
+	expected, extras := extras[0], extras[1:]
+	if err := unexpectedExtras("TextRoundTrips", extras); err != nil {
+		return err
+	}
+	text, ok := expected.(string)
+	if !ok {
+		return fmt.Errorf("expected value must be a string, got %s", describeType(expected))
+	}
+	marshaler, ok := obtained.(encoding.TextMarshaler)
+	if !ok || isTypedNil(obtained) {
+		return fmt.Errorf("TextRoundTrips checker expected an encoding.TextMarshaler, obtained was %s", describe(obtained))
+	}
+	v := reflect.ValueOf(obtained)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	unmarshaled := reflect.New(v.Type())
+	unmarshaler, ok := unmarshaled.Interface().(encoding.TextUnmarshaler)
+	if !ok {
+		return fmt.Errorf("%s does not implement encoding.TextUnmarshaler", unmarshaled.Type())
+	}
+
+	marshaled, err := marshaler.MarshalText()
+	if err != nil {
+		return fmt.Errorf("MarshalText failed: %v", err)
+	}
+	if string(marshaled) != text {
+		return failf("marshaled to %q, expected %q", marshaled, text)
+	}
+	if err := unmarshaler.UnmarshalText(marshaled); err != nil {
+		return fmt.Errorf("UnmarshalText of %q failed: %v", marshaled, err)
+	}
+	if ok, err := DeepEqual(interfaceOf(unmarshaled.Elem()), interfaceOf(v)); !ok {
+		return lazyFailure(func() string {
+			return fmt.Sprintf("unmarshaling %q did not give the original value: %v", marshaled, err)
+		})
+	}
+	return nil
+}
+
+// wireRoundTrips checks that values survive a round trip through an
+// encoding, with the field names that the encoding gives them.
+type wireRoundTrips struct {
+	name      string
+	marshal   func(interface{}) ([]byte, error)
+	unmarshal func([]byte, interface{}) error
+	// fields returns the names of the top level fields of the data.
+	fields func([]byte) ([]string, error)
+}
+
+// JSONRoundTrips checker checks that the obtained value, such as a
+// model of an API, marshals with encoding/json to an object whose field
+// names are the expected []string, in any order, and that unmarshaling
+// that object into a new value of the same type gives a value deeply
+// equal to the obtained one. It catches fields renamed or dropped by
+// changes to the struct or its tags, so the obtained value should set
+// every field that is omitted when empty.
+//
+//	t.Check(User{ID: 1, Name: "alice"}, checkers.JSONRoundTrips, []string{"id", "full_name"})
+var JSONRoundTrips Checker = wireRoundTrips{"JSONRoundTrips", json.Marshal, json.Unmarshal, jsonFields}
+
+// XMLRoundTrips checker checks the obtained value as JSONRoundTrips
+// does, marshaling it with encoding/xml. The field names are those of
+// the elements directly within the root element, and those of the
+// attributes of the root element prefixed with "@". A struct with an
+// XMLName field must set it, as unmarshaling does.
+//
+//	t.Check(User{ID: 1, Name: "alice"}, checkers.XMLRoundTrips, []string{"@id", "full-name"})
+var XMLRoundTrips Checker = wireRoundTrips{"XMLRoundTrips", xml.Marshal, xml.Unmarshal, xmlFields}
+
+func (c wireRoundTrips) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if len(extras) == 0 {
+		return errors.New("missing 'expected' value")
+	}
+	expected, extras := extras[0], extras[1:]
+	if err := unexpectedExtras(c.name, extras); err != nil {
+		return err
+	}
+	names, ok := expected.([]string)
+	if !ok {
+		return fmt.Errorf("expected value must be a []string of field names, got %s", describeType(expected))
+	}
+	if obtained == nil || isTypedNil(obtained) {
+		return fmt.Errorf("%s checker expected a value to marshal, obtained was %s", c.name, describe(obtained))
+	}
+	marshaled, err := c.marshal(obtained)
+	if err != nil {
+		return fmt.Errorf("marshaling failed: %v", err)
+	}
+	fields, err := c.fields(marshaled)
+	if err != nil {
+		return fmt.Errorf("cannot find fields of %s: %v", marshaled, err)
+	}
+	if missing, unexpected := fieldsDiffer(fields, names); len(missing)+len(unexpected) > 0 {
+		var lines []string
+		if len(missing) > 0 {
+			lines = append(lines, "missing: "+strings.Join(missing, ", "))
+		}
+		if len(unexpected) > 0 {
+			lines = append(lines, "unexpected: "+strings.Join(unexpected, ", "))
+		}
+		return failf("marshaled field names do not match:\n\t%s\nmarshaled: %s", strings.Join(lines, "\n\t"), marshaled)
+	}
+	v := reflect.ValueOf(obtained)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	unmarshaled := reflect.New(v.Type())
+	if err := c.unmarshal(marshaled, unmarshaled.Interface()); err != nil {
+		return fmt.Errorf("unmarshaling %s failed: %v", marshaled, err)
+	}
+	if ok, err := DeepEqual(interfaceOf(unmarshaled.Elem()), interfaceOf(v)); !ok {
+		return lazyFailure(func() string {
+			return fmt.Sprintf("unmarshaling %s did not give the original value: %v", marshaled, err)
+		})
+	}
+	return nil
+}
+
+// fieldsDiffer returns the quoted names that are expected but not
+// obtained, and those obtained but not expected, each sorted.
+func fieldsDiffer(obtained, expected []string) (missing, unexpected []string) {
+	has := make(map[string]bool)
+	for _, name := range obtained {
+		has[name] = true
+	}
+	wanted := make(map[string]bool)
+	for _, name := range expected {
+		wanted[name] = true
+		if !has[name] {
+			missing = append(missing, strconv.Quote(name))
+		}
+	}
+	for name := range has {
+		if !wanted[name] {
+			unexpected = append(unexpected, strconv.Quote(name))
+		}
+	}
+	sort.Strings(missing)
+	sort.Strings(unexpected)
+	return missing, unexpected
+}
+
+// jsonFields returns the names of the fields of the JSON object.
+func jsonFields(data []byte) ([]string, error) {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, errors.New("not a JSON object")
+	}
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// xmlFields returns the names of the elements directly within the root
+// element of the XML document, and of the attributes of the root
+// element prefixed with "@".
+func xmlFields(data []byte) ([]string, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	var names []string
+	depth := 0
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return names, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch token := token.(type) {
+		case xml.StartElement:
+			switch depth {
+			case 0:
+				for _, attr := range token.Attr {
+					if attr.Name.Space != "xmlns" && attr.Name.Local != "xmlns" {
+						names = append(names, "@"+attr.Name.Local)
+					}
+				}
+			case 1:
+				names = append(names, token.Name.Local)
+			}
+			depth++
+		case xml.EndElement:
+			depth--
+		}
+	}
+}
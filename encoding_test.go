@@ -0,0 +1,262 @@
+// Add a copyright
+// Add a licence
+
+package checkers_test
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/howbazaar/checkers"
+)
+
+type colour int
+
+const (
+	red colour = iota
+	green
+	blue
+)
+
+var colourNames = []string{"red", "green", "blue"}
+
+func (c colour) MarshalText() ([]byte, error) {
+	if c < 0 || int(c) >= len(colourNames) {
+		return nil, fmt.Errorf("invalid colour %d", int(c))
+	}
+	return []byte(colourNames[c]), nil
+}
+
+func (c *colour) UnmarshalText(text []byte) error {
+	for i, name := range colourNames {
+		if name == string(text) {
+			*c = colour(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown colour %q", text)
+}
+
+// lossy loses its value when marshaled.
+type lossy int
+
+func (lossy) MarshalText() ([]byte, error) { return []byte("lossy"), nil }
+func (*lossy) UnmarshalText([]byte) error  { return nil }
+
+// marshalOnly can't be unmarshaled.
+type marshalOnly struct{}
+
+func (marshalOnly) MarshalText() ([]byte, error) { return []byte("x"), nil }
+
+// unmarshalFails can't unmarshal what it marshals.
+type unmarshalFails struct{}
+
+func (unmarshalFails) MarshalText() ([]byte, error) { return []byte("x"), nil }
+func (*unmarshalFails) UnmarshalText(text []byte) error {
+	return fmt.Errorf("cannot parse %q", text)
+}
+
+func TestTextRoundTrips(t *testing.T) {
+	for _, test := range []struct {
+		description string
+		obtained    interface{}
+		extras      []interface{}
+		err         string
+	}{
+		{
+			description: "enum",
+			obtained:    green,
+			extras:      []interface{}{"green"},
+		}, {
+			description: "pointer",
+			obtained:    func() *colour { c := blue; return &c }(),
+			extras:      []interface{}{"blue"},
+		}, {
+			description: "standard library type",
+			obtained:    net.ParseIP("192.168.0.1"),
+			extras:      []interface{}{"192.168.0.1"},
+		}, {
+			description: "wrong text",
+			obtained:    red,
+			extras:      []interface{}{"green"},
+			err:         `marshaled to "red", expected "green"`,
+		}, {
+			description: "marshal fails",
+			obtained:    colour(7),
+			extras:      []interface{}{"7"},
+			err:         "MarshalText failed: invalid colour 7",
+		}, {
+			description: "value lost",
+			obtained:    lossy(3),
+			extras:      []interface{}{"lossy"},
+			err:         `unmarshaling "lossy" did not give the original value: mismatch at top level: unequal; obtained 0; expected 3`,
+		}, {
+			description: "unmarshal fails",
+			obtained:    unmarshalFails{},
+			extras:      []interface{}{"x"},
+			err:         `UnmarshalText of "x" failed: cannot parse "x"`,
+		}, {
+			description: "not an unmarshaler",
+			obtained:    marshalOnly{},
+			extras:      []interface{}{"x"},
+			err:         "*checkers_test.marshalOnly does not implement encoding.TextUnmarshaler",
+		}, {
+			description: "not a marshaler",
+			obtained:    1,
+			extras:      []interface{}{"1"},
+			err:         "TextRoundTrips checker expected an encoding.TextMarshaler, obtained was int value 1",
+		}, {
+			description: "nil pointer",
+			obtained:    (*colour)(nil),
+			extras:      []interface{}{"red"},
+			err:         "TextRoundTrips checker expected an encoding.TextMarshaler, obtained was nil *checkers_test.colour",
+		}, {
+			description: "bad expected",
+			obtained:    red,
+			extras:      []interface{}{[]byte("red")},
+			err:         "expected value must be a string, got type []uint8",
+		},
+	} {
+		err := checkers.TextRoundTrips.Check(test.obtained, test.extras...)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+		} else {
+			if test.err == "" {
+				t.Errorf("%s: unexpected error: %v", test.description, err)
+			} else {
+				if err.Error() != test.err {
+					t.Errorf("%s: error mismatch: \n\tobtained: %q\n\texpected: %q", test.description, err.Error(), test.err)
+				}
+			}
+		}
+	}
+}
+
+type apiUser struct {
+	ID   int      `json:"id"`
+	Name string   `json:"full_name"`
+	Tags []string `json:"tags,omitempty"`
+}
+
+type xmlUser struct {
+	XMLName xml.Name `xml:"user"`
+	ID      int      `xml:"id,attr"`
+	Name    string   `xml:"full-name"`
+	Tags    []string `xml:"tag"`
+}
+
+type apiSession struct {
+	Token   string `json:"token"`
+	Expires int    `json:"-"`
+}
+
+func TestWireRoundTrips(t *testing.T) {
+	user := apiUser{ID: 1, Name: "alice", Tags: []string{"admin", "ops"}}
+	xUser := xmlUser{
+		XMLName: xml.Name{Local: "user"},
+		ID:      1,
+		Name:    "alice",
+		Tags:    []string{"admin", "ops"},
+	}
+	for _, test := range []struct {
+		description string
+		checker     checkers.Checker
+		obtained    interface{}
+		extras      []interface{}
+		err         string
+	}{
+		{
+			description: "json",
+			checker:     checkers.JSONRoundTrips,
+			obtained:    user,
+			extras:      []interface{}{[]string{"tags", "id", "full_name"}},
+		}, {
+			description: "json pointer",
+			checker:     checkers.JSONRoundTrips,
+			obtained:    &user,
+			extras:      []interface{}{[]string{"id", "full_name", "tags"}},
+		}, {
+			description: "json fields renamed",
+			checker:     checkers.JSONRoundTrips,
+			obtained:    user,
+			extras:      []interface{}{[]string{"id", "name", "tags", "email"}, checkers.Commentf("public API")},
+			err: "marshaled field names do not match:\n" +
+				"\tmissing: \"email\", \"name\"\n" +
+				"\tunexpected: \"full_name\"\n" +
+				`marshaled: {"id":1,"full_name":"alice","tags":["admin","ops"]}` + "\n" +
+				"comment: public API",
+		}, {
+			description: "json field lost",
+			checker:     checkers.JSONRoundTrips,
+			obtained:    apiSession{Token: "abc", Expires: 60},
+			extras:      []interface{}{[]string{"token"}},
+			err:         `unmarshaling {"token":"abc"} did not give the original value: mismatch at .Expires: unequal; obtained 0; expected 60`,
+		}, {
+			description: "json not an object",
+			checker:     checkers.JSONRoundTrips,
+			obtained:    []int{1},
+			extras:      []interface{}{[]string{}},
+			err:         "cannot find fields of [1]: not a JSON object",
+		}, {
+			description: "json marshaling fails",
+			checker:     checkers.JSONRoundTrips,
+			obtained:    make(chan int),
+			extras:      []interface{}{[]string{}},
+			err:         "marshaling failed: json: unsupported type: chan int",
+		}, {
+			description: "xml",
+			checker:     checkers.XMLRoundTrips,
+			obtained:    xUser,
+			extras:      []interface{}{[]string{"@id", "full-name", "tag"}},
+		}, {
+			description: "xml fields renamed",
+			checker:     checkers.XMLRoundTrips,
+			obtained:    xUser,
+			extras:      []interface{}{[]string{"id", "full-name", "tag"}},
+			err: "marshaled field names do not match:\n" +
+				"\tmissing: \"id\"\n" +
+				"\tunexpected: \"@id\"\n" +
+				`marshaled: <user id="1"><full-name>alice</full-name><tag>admin</tag><tag>ops</tag></user>`,
+		}, {
+			description: "xml name not set",
+			checker:     checkers.XMLRoundTrips,
+			obtained:    xmlUser{ID: 2},
+			extras:      []interface{}{[]string{"@id", "full-name"}},
+			err:         `unmarshaling <user id="2"><full-name></full-name></user> did not give the original value: mismatch at .XMLName.Local: unequal; obtained "user"; expected ""`,
+		}, {
+			description: "expected not field names",
+			checker:     checkers.JSONRoundTrips,
+			obtained:    user,
+			extras:      []interface{}{"id"},
+			err:         "expected value must be a []string of field names, got type string",
+		}, {
+			description: "nil",
+			checker:     checkers.XMLRoundTrips,
+			obtained:    (*xmlUser)(nil),
+			extras:      []interface{}{[]string{}},
+			err:         "XMLRoundTrips checker expected a value to marshal, obtained was nil *checkers_test.xmlUser",
+		}, {
+			description: "missing expected",
+			checker:     checkers.JSONRoundTrips,
+			obtained:    user,
+			err:         "missing 'expected' value",
+		},
+	} {
+		err := test.checker.Check(test.obtained, test.extras...)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+		} else {
+			if test.err == "" {
+				t.Errorf("%s: unexpected error: %v", test.description, err)
+			} else if err.Error() != test.err {
+				t.Errorf("%s: error mismatch: \n\tobtained: %q\n\texpected: %q", test.description, err.Error(), test.err)
+			}
+		}
+	}
+}
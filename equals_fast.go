@@ -0,0 +1,55 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"time"
+)
+
+// fastEquals compares values of the commonly used builtin types without
+// reflection. It reports whether the values were equal, and whether it
+// was able to compare them at all. Values of any other types, including
+// values of different types, are left to the reflection based
+// comparison so that the results are the same either way.
+func fastEquals(obtained, expected interface{}) (equal, handled bool) {
+	switch o := obtained.(type) {
+	case bool:
+		e, ok := expected.(bool)
+		return ok && o == e, ok
+	case string:
+		e, ok := expected.(string)
+		return ok && o == e, ok
+	case int:
+		e, ok := expected.(int)
+		return ok && o == e, ok
+	case int32:
+		e, ok := expected.(int32)
+		return ok && o == e, ok
+	case int64:
+		e, ok := expected.(int64)
+		return ok && o == e, ok
+	case uint:
+		e, ok := expected.(uint)
+		return ok && o == e, ok
+	case uint8:
+		e, ok := expected.(uint8)
+		return ok && o == e, ok
+	case uint32:
+		e, ok := expected.(uint32)
+		return ok && o == e, ok
+	case uint64:
+		e, ok := expected.(uint64)
+		return ok && o == e, ok
+	case float32:
+		e, ok := expected.(float32)
+		return ok && o == e, ok
+	case float64:
+		e, ok := expected.(float64)
+		return ok && o == e, ok
+	case time.Duration:
+		e, ok := expected.(time.Duration)
+		return ok && o == e, ok
+	}
+	return false, false
+}
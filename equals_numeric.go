@@ -0,0 +1,45 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+)
+
+// numericEquals compares two numbers of any kinds by their exact values.
+func numericEquals(obtained, expected interface{}) error {
+	want, ok := exactNumber(expected)
+	if !ok {
+		return fmt.Errorf("expected value must be a number, got %s", describeType(expected))
+	}
+	got, ok := exactNumber(obtained)
+	if !ok {
+		return fmt.Errorf("EqualsNumeric checker expected a number, obtained was %s", describeType(obtained))
+	}
+	if got != nil && want != nil && got.Cmp(want) == 0 {
+		return nil
+	}
+	return failf("expected %T value %v, got %T value %v", expected, display(expected), obtained, display(obtained))
+}
+
+// exactNumber returns the exact value of the number, or nil for NaN,
+// which has no value to compare.
+func exactNumber(value interface{}) (*big.Float, bool) {
+	v := reflect.ValueOf(value)
+	switch kindFamily(v.Kind()) {
+	case reflect.Int:
+		return new(big.Float).SetInt64(v.Int()), true
+	case reflect.Uint:
+		return new(big.Float).SetUint64(v.Uint()), true
+	case reflect.Float64:
+		if math.IsNaN(v.Float()) {
+			return nil, true
+		}
+		return new(big.Float).SetFloat64(v.Float()), true
+	}
+	return nil, false
+}
@@ -0,0 +1,299 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+type errorChainMatches struct{}
+
+// ErrorChainMatches checker checks every layer of the obtained error's
+// chain, as followed by errors.Unwrap. The expected value is a
+// []interface{} with one entry for each layer in turn, which is either
+// a string holding a regexp pattern that the layer's message must match
+// in full, or an error that the layer must be. The chain must have
+// exactly as many layers as there are entries.
+//
+//	t.Check(err, checkers.ErrorChainMatches, []interface{}{
+//		"loading config: .*",
+//		os.ErrNotExist,
+//	})
+var ErrorChainMatches Checker = errorChainMatches{}
+
+func (errorChainMatches) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if len(extras) == 0 {
+		return errors.New("missing 'expected' value")
+	}
+	expected, extras := extras[0], extras[1:]
+	if err := unexpectedExtras("ErrorChainMatches", extras); err != nil {
+		return err
+	}
+	layers, ok := expected.([]interface{})
+	if !ok {
+		return fmt.Errorf("expected value must be a []interface{} of patterns and errors, got %s", describeType(expected))
+	}
+	// Check the patterns first, so that mistakes in them are reported
+	// rather than whatever is wrong with the obtained value.
+	if err := checkErrorPatterns("layer", layers); err != nil {
+		return err
+	}
+	obtainedErr, ok := obtained.(error)
+	if !ok {
+		return fmt.Errorf("ErrorChainMatches checker expected an error, obtained was %s", describeType(obtained))
+	}
+
+	var chain []error
+	for e := obtainedErr; e != nil; e = errors.Unwrap(e) {
+		chain = append(chain, e)
+	}
+	for i, layer := range layers {
+		if i >= len(chain) {
+			return chainMismatch(chain, "chain has %d layers, expected %d", len(chain), len(layers))
+		}
+		switch layer := layer.(type) {
+		case string:
+			re, _ := compilePattern(layer)
+			if !re.MatchString(chain[i].Error()) {
+				return chainMismatch(chain, "layer %d: %q did not match pattern %q", i, chain[i].Error(), layer)
+			}
+		case error:
+			if !sameError(chain[i], layer) {
+				return chainMismatch(chain, "layer %d: %s is not %s", i, describeError(chain[i]), describeError(layer))
+			}
+		}
+	}
+	if len(chain) > len(layers) {
+		return chainMismatch(chain, "chain has %d layers, expected %d", len(chain), len(layers))
+	}
+	return nil
+}
+
+// checkErrorPatterns checks that each of the expectations is either an
+// error or a valid regexp pattern, naming them by kind in any error.
+func checkErrorPatterns(kind string, expectations []interface{}) error {
+	for i, expectation := range expectations {
+		switch expectation := expectation.(type) {
+		case string:
+			if _, err := compilePattern(expectation); err != nil {
+				return fmt.Errorf("%s %d: %v", kind, i, err)
+			}
+		case error:
+		default:
+			return fmt.Errorf("%s %d must be a pattern or an error, got %s", kind, i, describeType(expectation))
+		}
+	}
+	return nil
+}
+
+// sameError reports whether the errors are the same value, without
+// panicking on error types that can't be compared.
+func sameError(a, b error) bool {
+	if reflect.TypeOf(a) != reflect.TypeOf(b) || !reflect.TypeOf(a).Comparable() {
+		return false
+	}
+	return a == b
+}
+
+// chainMismatch describes why the error chain did not match, followed
+// by each layer of the chain.
+func chainMismatch(chain []error, format string, args ...interface{}) error {
+	return lazyFailure(func() string {
+		var buf strings.Builder
+		fmt.Fprintf(&buf, format, args...)
+		buf.WriteString("\nchain:")
+		for i, e := range chain {
+			fmt.Fprintf(&buf, "\n\t%d: %s", i, describeError(e))
+		}
+		return buf.String()
+	})
+}
+
+// describeError describes the error by its type and message.
+func describeError(err error) string {
+	return fmt.Sprintf("%T(%q)", err, err.Error())
+}
+
+type errorIs struct{}
+
+// ErrorIs checker checks that the obtained error is, or wraps, the
+// expected error, as reported by errors.Is. A failure lists each layer
+// of the obtained error's chain.
+//
+//	t.Check(err, checkers.ErrorIs, fs.ErrNotExist)
+var ErrorIs Checker = errorIs{}
+
+func (errorIs) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if len(extras) == 0 {
+		return errors.New("missing 'expected' value")
+	}
+	expected, extras := extras[0], extras[1:]
+	if err := unexpectedExtras("ErrorIs", extras); err != nil {
+		return err
+	}
+	target, ok := expected.(error)
+	if !ok {
+		return fmt.Errorf("expected value must be an error, got %s", describeType(expected))
+	}
+	if obtained == nil {
+		return failf("obtained error is nil, expected %s", describeError(target))
+	}
+	obtainedErr, ok := obtained.(error)
+	if !ok {
+		return fmt.Errorf("ErrorIs checker expected an error, obtained was %s", describeType(obtained))
+	}
+	if errors.Is(obtainedErr, target) {
+		return nil
+	}
+	var chain []error
+	for e := obtainedErr; e != nil; e = errors.Unwrap(e) {
+		chain = append(chain, e)
+	}
+	return chainMismatch(chain, "error is not %s", describeError(target))
+}
+
+type errorAs struct{}
+
+// ErrorAs checker checks that the obtained error is, or wraps, an error
+// that can be assigned to the value the expected pointer points to, as
+// reported by errors.As, which sets it to that error so that the test
+// can check it further. The expected value must be a non-nil pointer to
+// a type that implements error, or to an interface type. A failure
+// lists each layer of the obtained error's chain.
+//
+//	var pathErr *fs.PathError
+//	t.Assert(err, checkers.ErrorAs, &pathErr)
+//	t.Check(pathErr.Path, checkers.Equals, "config.yaml")
+var ErrorAs Checker = errorAs{}
+
+func (errorAs) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if len(extras) == 0 {
+		return errors.New("missing 'expected' value")
+	}
+	expected, extras := extras[0], extras[1:]
+	if err := unexpectedExtras("ErrorAs", extras); err != nil {
+		return err
+	}
+	// Check the target as errors.As does, as it panics on bad ones.
+	target := reflect.ValueOf(expected)
+	if target.Kind() != reflect.Ptr || target.IsNil() {
+		return fmt.Errorf("expected value must be a non-nil pointer, got %s", describeType(expected))
+	}
+	targetType := target.Type().Elem()
+	if targetType.Kind() != reflect.Interface && !targetType.Implements(errorType) {
+		return fmt.Errorf("expected value must point to an interface or a type that implements error, got %s", target.Type())
+	}
+	if obtained == nil {
+		return failf("obtained error is nil, expected one assignable to %s", targetType)
+	}
+	obtainedErr, ok := obtained.(error)
+	if !ok {
+		return fmt.Errorf("ErrorAs checker expected an error, obtained was %s", describeType(obtained))
+	}
+	if errors.As(obtainedErr, expected) {
+		return nil
+	}
+	var chain []error
+	for e := obtainedErr; e != nil; e = errors.Unwrap(e) {
+		chain = append(chain, e)
+	}
+	return chainMismatch(chain, "no error in chain is assignable to %s", targetType)
+}
+
+type joinedErrorsContain struct{}
+
+// JoinedErrorsContain checker checks that the obtained error joins
+// together errors, as those made by errors.Join do by implementing
+// Unwrap() []error, that include one for each of the expected entries.
+// The expected value is a []interface{} of entries, each either a
+// string holding a regexp pattern that a joined error's message must
+// match in full, or an error that a joined error must be, as reported by
+// errors.Is. Each joined error satisfies at most one entry, and joined
+// errors left over once every entry is satisfied are allowed.
+//
+//	t.Check(err, checkers.JoinedErrorsContain, []interface{}{
+//		"name: must not be empty",
+//		ErrInvalidAge,
+//	})
+var JoinedErrorsContain Checker = joinedErrorsContain{}
+
+func (joinedErrorsContain) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if len(extras) == 0 {
+		return errors.New("missing 'expected' value")
+	}
+	expected, extras := extras[0], extras[1:]
+	if err := unexpectedExtras("JoinedErrorsContain", extras); err != nil {
+		return err
+	}
+	entries, ok := expected.([]interface{})
+	if !ok {
+		return fmt.Errorf("expected value must be a []interface{} of patterns and errors, got %s", describeType(expected))
+	}
+	if err := checkErrorPatterns("entry", entries); err != nil {
+		return err
+	}
+	joined, ok := obtained.(interface{ Unwrap() []error })
+	if !ok {
+		return fmt.Errorf("JoinedErrorsContain checker expected an error with an Unwrap() []error method, obtained was %s", describeType(obtained))
+	}
+
+	errs := joined.Unwrap()
+	used := make([]bool, len(errs))
+	var unmatched []interface{}
+	for _, entry := range entries {
+		found := false
+		for i, e := range errs {
+			if used[i] || e == nil {
+				continue
+			}
+			switch entry := entry.(type) {
+			case string:
+				re, _ := compilePattern(entry)
+				found = re.MatchString(e.Error())
+			case error:
+				found = errors.Is(e, entry)
+			}
+			if found {
+				used[i] = true
+				break
+			}
+		}
+		if !found {
+			unmatched = append(unmatched, entry)
+		}
+	}
+	if len(unmatched) == 0 {
+		return nil
+	}
+	return lazyFailure(func() string {
+		var buf strings.Builder
+		fmt.Fprintf(&buf, "%d of %d entries not matched by a joined error:", len(unmatched), len(entries))
+		for _, entry := range unmatched {
+			if e, ok := entry.(error); ok {
+				fmt.Fprintf(&buf, "\n\t%s", describeError(e))
+			} else {
+				fmt.Fprintf(&buf, "\n\tpattern %q", entry)
+			}
+		}
+		heading := "\nleft over errors:"
+		for i, e := range errs {
+			if !used[i] && e != nil {
+				fmt.Fprintf(&buf, "%s\n\t%s", heading, describeError(e))
+				heading = ""
+			}
+		}
+		return buf.String()
+	})
+}
@@ -0,0 +1,345 @@
+// Add a copyright
+// Add a licence
+
+package checkers_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/howbazaar/checkers"
+)
+
+func TestErrorChainMatches(t *testing.T) {
+	wrapped := fmt.Errorf("loading config: %w", fmt.Errorf("reading file: %w", io.EOF))
+	for _, test := range []struct {
+		description string
+		obtained    interface{}
+		extras      []interface{}
+		err         string
+	}{
+		{
+			description: "full chain",
+			obtained:    wrapped,
+			extras:      []interface{}{[]interface{}{"loading config: .*", "reading file: EOF", io.EOF}},
+		}, {
+			description: "single layer",
+			obtained:    io.EOF,
+			extras:      []interface{}{[]interface{}{io.EOF}},
+		}, {
+			description: "pattern mismatch",
+			obtained:    wrapped,
+			extras:      []interface{}{[]interface{}{"loading config: .*", "parsing file: .*", io.EOF}},
+			err: `layer 1: "reading file: EOF" did not match pattern "parsing file: .*"
+chain:
+	0: *fmt.wrapError("loading config: reading file: EOF")
+	1: *fmt.wrapError("reading file: EOF")
+	2: *errors.errorString("EOF")`,
+		}, {
+			description: "sentinel mismatch",
+			obtained:    wrapped,
+			extras:      []interface{}{[]interface{}{".*", ".*", os.ErrNotExist}},
+			err: `layer 2: *errors.errorString("EOF") is not *errors.errorString("file does not exist")
+chain:
+	0: *fmt.wrapError("loading config: reading file: EOF")
+	1: *fmt.wrapError("reading file: EOF")
+	2: *errors.errorString("EOF")`,
+		}, {
+			description: "chain too short",
+			obtained:    io.EOF,
+			extras:      []interface{}{[]interface{}{"EOF", io.EOF}},
+			err: `chain has 1 layers, expected 2
+chain:
+	0: *errors.errorString("EOF")`,
+		}, {
+			description: "chain too long",
+			obtained:    wrapped,
+			extras:      []interface{}{[]interface{}{".*"}},
+			err: `chain has 3 layers, expected 1
+chain:
+	0: *fmt.wrapError("loading config: reading file: EOF")
+	1: *fmt.wrapError("reading file: EOF")
+	2: *errors.errorString("EOF")`,
+		}, {
+			description: "invalid pattern",
+			obtained:    nil,
+			extras:      []interface{}{[]interface{}{"("}},
+			err:         `layer 0: invalid regexp pattern "(": missing closing ): "(" at position 0`,
+		}, {
+			description: "invalid layer",
+			obtained:    wrapped,
+			extras:      []interface{}{[]interface{}{42}},
+			err:         "layer 0 must be a pattern or an error, got type int",
+		}, {
+			description: "not an error",
+			obtained:    nil,
+			extras:      []interface{}{[]interface{}{io.EOF}},
+			err:         "ErrorChainMatches checker expected an error, obtained was nil",
+		}, {
+			description: "bad expected",
+			obtained:    wrapped,
+			extras:      []interface{}{[]string{".*"}},
+			err:         "expected value must be a []interface{} of patterns and errors, got type []string",
+		}, {
+			description: "missing expected",
+			obtained:    errors.New("x"),
+			err:         "missing 'expected' value",
+		},
+	} {
+		err := checkers.ErrorChainMatches.Check(test.obtained, test.extras...)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+		} else {
+			if test.err == "" {
+				t.Errorf("%s: unexpected error: %v", test.description, err)
+			} else {
+				if err.Error() != test.err {
+					t.Errorf("%s: error mismatch: \n\tobtained: %q\n\texpected: %q", test.description, err.Error(), test.err)
+				}
+			}
+		}
+	}
+}
+
+// joinedErrors is a multiple error type, as made by errors.Join.
+type joinedErrors []error
+
+func (errs joinedErrors) Error() string {
+	return fmt.Sprintf("%d errors", len(errs))
+}
+
+func (errs joinedErrors) Unwrap() []error {
+	return errs
+}
+
+func TestJoinedErrorsContain(t *testing.T) {
+	joined := joinedErrors{
+		errors.New("name: must not be empty"),
+		fmt.Errorf("age: %w", os.ErrInvalid),
+		io.EOF,
+	}
+	for _, test := range []struct {
+		description string
+		obtained    interface{}
+		extras      []interface{}
+		err         string
+	}{
+		{
+			description: "all entries",
+			obtained:    joined,
+			extras:      []interface{}{[]interface{}{io.EOF, "name: .*", os.ErrInvalid}},
+		}, {
+			description: "some entries",
+			obtained:    joined,
+			extras:      []interface{}{[]interface{}{"age: .*"}},
+		}, {
+			description: "each error matches one entry",
+			obtained:    joined,
+			extras:      []interface{}{[]interface{}{"name: .*", ".*: .*", ".*: .*"}},
+			err: `1 of 3 entries not matched by a joined error:
+	pattern ".*: .*"
+left over errors:
+	*errors.errorString("EOF")`,
+		}, {
+			description: "unmatched entries",
+			obtained:    joined,
+			extras:      []interface{}{[]interface{}{os.ErrNotExist, "email: .*", io.EOF}},
+			err: `2 of 3 entries not matched by a joined error:
+	*errors.errorString("file does not exist")
+	pattern "email: .*"
+left over errors:
+	*errors.errorString("name: must not be empty")
+	*fmt.wrapError("age: invalid argument")`,
+		}, {
+			description: "no left over errors",
+			obtained:    joinedErrors{io.EOF},
+			extras:      []interface{}{[]interface{}{io.EOF, io.EOF}},
+			err: `1 of 2 entries not matched by a joined error:
+	*errors.errorString("EOF")`,
+		}, {
+			description: "not joined",
+			obtained:    io.EOF,
+			extras:      []interface{}{[]interface{}{io.EOF}},
+			err:         "JoinedErrorsContain checker expected an error with an Unwrap() []error method, obtained was type *errors.errorString",
+		}, {
+			description: "invalid entry",
+			obtained:    joined,
+			extras:      []interface{}{[]interface{}{"[", io.EOF}},
+			err:         `entry 0: invalid regexp pattern "[": missing closing ]: "[" at position 0`,
+		},
+	} {
+		err := checkers.JoinedErrorsContain.Check(test.obtained, test.extras...)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+		} else {
+			if test.err == "" {
+				t.Errorf("%s: unexpected error: %v", test.description, err)
+			} else {
+				if err.Error() != test.err {
+					t.Errorf("%s: error mismatch: \n\tobtained: %q\n\texpected: %q", test.description, err.Error(), test.err)
+				}
+			}
+		}
+	}
+}
+
+func TestErrorIs(t *testing.T) {
+	wrapped := fmt.Errorf("loading config: %w", fmt.Errorf("reading file: %w", io.EOF))
+	for _, test := range []struct {
+		description string
+		obtained    interface{}
+		extras      []interface{}
+		err         string
+	}{
+		{
+			description: "same error",
+			obtained:    io.EOF,
+			extras:      []interface{}{io.EOF},
+		}, {
+			description: "wrapped",
+			obtained:    wrapped,
+			extras:      []interface{}{io.EOF},
+		}, {
+			description: "not in chain",
+			obtained:    wrapped,
+			extras:      []interface{}{os.ErrNotExist},
+			err: "error is not *errors.errorString(\"file does not exist\")\n" +
+				"chain:\n" +
+				"\t0: *fmt.wrapError(\"loading config: reading file: EOF\")\n" +
+				"\t1: *fmt.wrapError(\"reading file: EOF\")\n" +
+				"\t2: *errors.errorString(\"EOF\")",
+		}, {
+			description: "with comment",
+			obtained:    io.EOF,
+			extras:      []interface{}{os.ErrClosed, checkers.Commentf("closing")},
+			err: "error is not *errors.errorString(\"file already closed\")\n" +
+				"chain:\n" +
+				"\t0: *errors.errorString(\"EOF\")\n" +
+				"comment: closing",
+		}, {
+			description: "nil error",
+			obtained:    nil,
+			extras:      []interface{}{io.EOF},
+			err:         "obtained error is nil, expected *errors.errorString(\"EOF\")",
+		}, {
+			description: "not an error",
+			obtained:    "EOF",
+			extras:      []interface{}{io.EOF},
+			err:         "ErrorIs checker expected an error, obtained was type string",
+		}, {
+			description: "expected not an error",
+			obtained:    io.EOF,
+			extras:      []interface{}{"EOF"},
+			err:         "expected value must be an error, got type string",
+		}, {
+			description: "missing expected",
+			obtained:    io.EOF,
+			err:         "missing 'expected' value",
+		},
+	} {
+		err := checkers.ErrorIs.Check(test.obtained, test.extras...)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+		} else {
+			if test.err == "" {
+				t.Errorf("%s: unexpected error: %v", test.description, err)
+			} else {
+				if err.Error() != test.err {
+					t.Errorf("%s: error mismatch: \n\tobtained: %q\n\texpected: %q", test.description, err.Error(), test.err)
+				}
+			}
+		}
+	}
+}
+
+func TestErrorAs(t *testing.T) {
+	pathErr := &os.PathError{Op: "open", Path: "config.yaml", Err: os.ErrNotExist}
+	wrapped := fmt.Errorf("loading config: %w", pathErr)
+	var target *os.PathError
+	var linkErr *os.LinkError
+	var timeout interface{ Timeout() bool }
+	for _, test := range []struct {
+		description string
+		obtained    interface{}
+		extras      []interface{}
+		err         string
+	}{
+		{
+			description: "wrapped",
+			obtained:    wrapped,
+			extras:      []interface{}{&target},
+		}, {
+			description: "interface",
+			obtained:    wrapped,
+			extras:      []interface{}{&timeout},
+		}, {
+			description: "not in chain",
+			obtained:    wrapped,
+			extras:      []interface{}{&linkErr},
+			err: "no error in chain is assignable to *os.LinkError\n" +
+				"chain:\n" +
+				"\t0: *fmt.wrapError(\"loading config: open config.yaml: file does not exist\")\n" +
+				"\t1: *fs.PathError(\"open config.yaml: file does not exist\")\n" +
+				"\t2: *errors.errorString(\"file does not exist\")",
+		}, {
+			description: "with comment",
+			obtained:    io.EOF,
+			extras:      []interface{}{&linkErr, checkers.Commentf("linking")},
+			err: "no error in chain is assignable to *os.LinkError\n" +
+				"chain:\n" +
+				"\t0: *errors.errorString(\"EOF\")\n" +
+				"comment: linking",
+		}, {
+			description: "nil error",
+			obtained:    nil,
+			extras:      []interface{}{&target},
+			err:         "obtained error is nil, expected one assignable to *fs.PathError",
+		}, {
+			description: "not an error",
+			obtained:    "EOF",
+			extras:      []interface{}{&target},
+			err:         "ErrorAs checker expected an error, obtained was type string",
+		}, {
+			description: "target not a pointer",
+			obtained:    wrapped,
+			extras:      []interface{}{target},
+			err:         "expected value must be a non-nil pointer, got type *fs.PathError",
+		}, {
+			description: "target not an error",
+			obtained:    wrapped,
+			extras:      []interface{}{new(string)},
+			err:         "expected value must point to an interface or a type that implements error, got *string",
+		}, {
+			description: "missing expected",
+			obtained:    wrapped,
+			err:         "missing 'expected' value",
+		},
+	} {
+		err := checkers.ErrorAs.Check(test.obtained, test.extras...)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+		} else {
+			if test.err == "" {
+				t.Errorf("%s: unexpected error: %v", test.description, err)
+			} else {
+				if err.Error() != test.err {
+					t.Errorf("%s: error mismatch: \n\tobtained: %q\n\texpected: %q", test.description, err.Error(), test.err)
+				}
+			}
+		}
+	}
+	if target != pathErr {
+		t.Errorf("target not set to the error in the chain, got %v", target)
+	}
+}
@@ -0,0 +1,198 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Event is an event emitted to an EventRecorder.
+type Event struct {
+	Time time.Time
+	Name string
+	// Data holds any value emitted with the event, or nil.
+	Data interface{}
+}
+
+// String returns the name of the event followed by its data, if any.
+func (e Event) String() string {
+	if e.Data == nil {
+		return e.Name
+	}
+	return fmt.Sprintf("%s %v", e.Name, display(e.Data))
+}
+
+// EventRecorder records the events that the code under test emits to
+// it, such as the steps of a protocol or the stages of a pipeline, so
+// that tests can check the flow of the code with EventsInOrder and
+// EventsInOrderWithin rather than with the text of its logs. It is safe
+// for concurrent use.
+//
+//	events := checkers.NewEventRecorder(nil)
+//	srv := NewServer(Config{OnEvent: events.Emit})
+//	...
+//	t.Check(events, checkers.EventsInOrder, []string{"accept", "handshake", "close"})
+type EventRecorder struct {
+	now func() time.Time
+
+	mu     sync.Mutex
+	events []Event
+}
+
+// NewEventRecorder returns an EventRecorder that has recorded nothing.
+// Events are recorded at the times that now returns, such as those of a
+// fake clock, or at the times from time.Now if now is nil.
+func NewEventRecorder(now func() time.Time) *EventRecorder {
+	if now == nil {
+		now = time.Now
+	}
+	return &EventRecorder{now: now}
+}
+
+// Emit records the event named name, with the data, which may be nil,
+// at the current time.
+func (r *EventRecorder) Emit(name string, data interface{}) {
+	event := Event{Time: r.now(), Name: name, Data: data}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+// Events returns the events recorded so far, in the order they were
+// emitted.
+func (r *EventRecorder) Events() []Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Event(nil), r.events...)
+}
+
+// obtainedEvents returns the events of the obtained value.
+func obtainedEvents(checker string, obtained interface{}) ([]Event, error) {
+	switch obtained := obtained.(type) {
+	case *EventRecorder:
+		if obtained != nil {
+			return obtained.Events(), nil
+		}
+	case []Event:
+		return obtained, nil
+	}
+	return nil, fmt.Errorf("%s checker expected *EventRecorder or []Event, obtained was %s", checker, describe(obtained))
+}
+
+// describeEvents lists the events for failure messages, each with its
+// time after the first.
+func describeEvents(events []Event) string {
+	if len(events) == 0 {
+		return "no events"
+	}
+	lines := make([]string, len(events))
+	for i, event := range events {
+		lines[i] = fmt.Sprintf("\t+%v %s", event.Time.Sub(events[0].Time), event)
+	}
+	return "events:\n" + strings.Join(lines, "\n")
+}
+
+type eventsInOrder struct {
+	name    string
+	bounded bool
+	within  time.Duration
+}
+
+// EventsInOrder checker checks that the obtained *EventRecorder or
+// []Event has events with the expected []string of names in that order,
+// with any other events before, between or after them.
+//
+//	t.Check(events, checkers.EventsInOrder, []string{"begin", "commit"})
+var EventsInOrder Checker = eventsInOrder{name: "EventsInOrder"}
+
+// EventsInOrderWithin returns a checker that checks the events as
+// EventsInOrder does, and that no more than d passes from the first of
+// the events to the last.
+//
+//	t.Check(events, checkers.EventsInOrderWithin(time.Second), []string{"request", "response"})
+func EventsInOrderWithin(d time.Duration) Checker {
+	return eventsInOrder{name: "EventsInOrderWithin", bounded: true, within: d}
+}
+
+func (c eventsInOrder) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if len(extras) == 0 {
+		return errors.New("missing 'expected' value")
+	}
+	expected, extras := extras[0], extras[1:]
+	if err := unexpectedExtras(c.name, extras); err != nil {
+		return err
+	}
+	names, ok := expected.([]string)
+	if !ok || len(names) == 0 {
+		return fmt.Errorf("expected value must be a non-empty []string of event names, got %s", describe(expected))
+	}
+	if c.within < 0 {
+		return fmt.Errorf("%s needs a non-negative duration, got %v", c.name, c.within)
+	}
+	events, err := obtainedEvents(c.name, obtained)
+	if err != nil {
+		return err
+	}
+	// Each occurrence of the first event may start a match, of which
+	// the shortest is the one that must be within the bound.
+	shortest := time.Duration(-1)
+	matched := 0
+	for start := range events {
+		if events[start].Name != names[0] {
+			continue
+		}
+		n, end := matchEvents(events[start:], names)
+		if n > matched {
+			matched = n
+		}
+		if n < len(names) {
+			break
+		}
+		if span := events[start+end].Time.Sub(events[start].Time); shortest < 0 || span < shortest {
+			shortest = span
+		}
+	}
+	if matched < len(names) {
+		return lazyFailure(func() string {
+			if matched == 0 {
+				return fmt.Sprintf("no event %q\n%s", names[0], describeEvents(events))
+			}
+			return fmt.Sprintf("no event %q after %s\n%s", names[matched], quoteNames(names[:matched]), describeEvents(events))
+		})
+	}
+	if c.bounded && shortest > c.within {
+		return lazyFailure(func() string {
+			return fmt.Sprintf("events from %q to %q took %v, more than %v\n%s",
+				names[0], names[len(names)-1], shortest, c.within, describeEvents(events))
+		})
+	}
+	return nil
+}
+
+// matchEvents returns how many of the names are found in order among
+// the events, and the index of the event that matched the last of them.
+func matchEvents(events []Event, names []string) (matched, last int) {
+	for i, event := range events {
+		if matched < len(names) && event.Name == names[matched] {
+			matched++
+			last = i
+		}
+	}
+	return matched, last
+}
+
+func quoteNames(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = strconv.Quote(name)
+	}
+	return strings.Join(quoted, ", ")
+}
@@ -0,0 +1,147 @@
+// Add a copyright
+// Add a licence
+
+package checkers_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/howbazaar/checkers"
+)
+
+func TestEventRecorder(t *testing.T) {
+	events := checkers.NewEventRecorder(nil)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			events.Emit("worker", i)
+		}(i)
+	}
+	wg.Wait()
+	recorded := events.Events()
+	if len(recorded) != 10 {
+		t.Fatalf("expected 10 events, got %d", len(recorded))
+	}
+	for _, event := range recorded {
+		if event.Name != "worker" || event.Time.IsZero() {
+			t.Errorf("unexpected event %#v", event)
+		}
+	}
+}
+
+func TestEventsInOrder(t *testing.T) {
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	now := start
+	events := checkers.NewEventRecorder(func() time.Time { return now })
+	emit := func(after time.Duration, name string, data interface{}) {
+		now = now.Add(after)
+		events.Emit(name, data)
+	}
+	emit(0, "connect", nil)
+	emit(time.Second, "query", 1)
+	emit(100*time.Millisecond, "connect", nil)
+	emit(200*time.Millisecond, "query", 2)
+	emit(time.Second, "close", nil)
+
+	for _, test := range []struct {
+		description string
+		checker     checkers.Checker
+		obtained    interface{}
+		extras      []interface{}
+		err         string
+	}{
+		{
+			description: "in order",
+			checker:     checkers.EventsInOrder,
+			obtained:    events,
+			extras:      []interface{}{[]string{"connect", "query", "close"}},
+		}, {
+			description: "with events between",
+			checker:     checkers.EventsInOrder,
+			obtained:    events.Events(),
+			extras:      []interface{}{[]string{"connect", "close"}},
+		}, {
+			description: "out of order",
+			checker:     checkers.EventsInOrder,
+			obtained:    events,
+			extras:      []interface{}{[]string{"connect", "close", "query"}, checkers.Commentf("pool")},
+			err: "no event \"query\" after \"connect\", \"close\"\n" +
+				"events:\n" +
+				"\t+0s connect\n" +
+				"\t+1s query 1\n" +
+				"\t+1.1s connect\n" +
+				"\t+1.3s query 2\n" +
+				"\t+2.3s close\n" +
+				"comment: pool",
+		}, {
+			description: "missing",
+			checker:     checkers.EventsInOrder,
+			obtained:    []checkers.Event{},
+			extras:      []interface{}{[]string{"connect"}},
+			err:         "no event \"connect\"\nno events",
+		}, {
+			description: "within",
+			checker:     checkers.EventsInOrderWithin(300 * time.Millisecond),
+			obtained:    events,
+			extras:      []interface{}{[]string{"connect", "query"}},
+		}, {
+			description: "not within",
+			checker:     checkers.EventsInOrderWithin(time.Second),
+			obtained:    events,
+			extras:      []interface{}{[]string{"connect", "query", "close"}},
+			err: "events from \"connect\" to \"close\" took 1.2s, more than 1s\n" +
+				"events:\n" +
+				"\t+0s connect\n" +
+				"\t+1s query 1\n" +
+				"\t+1.1s connect\n" +
+				"\t+1.3s query 2\n" +
+				"\t+2.3s close",
+		}, {
+			description: "within no time",
+			checker:     checkers.EventsInOrderWithin(0),
+			obtained:    []checkers.Event{{Time: start, Name: "a"}, {Time: start.Add(time.Nanosecond), Name: "b"}},
+			extras:      []interface{}{[]string{"a", "b"}},
+			err:         "events from \"a\" to \"b\" took 1ns, more than 0s\nevents:\n\t+0s a\n\t+1ns b",
+		}, {
+			description: "negative bound",
+			checker:     checkers.EventsInOrderWithin(-time.Second),
+			obtained:    events,
+			extras:      []interface{}{[]string{"connect"}},
+			err:         "EventsInOrderWithin needs a non-negative duration, got -1s",
+		}, {
+			description: "no names",
+			checker:     checkers.EventsInOrder,
+			obtained:    events,
+			extras:      []interface{}{[]string{}},
+			err:         "expected value must be a non-empty []string of event names, got []string value []",
+		}, {
+			description: "not events",
+			checker:     checkers.EventsInOrder,
+			obtained:    "connect",
+			extras:      []interface{}{[]string{"connect"}},
+			err:         "EventsInOrder checker expected *EventRecorder or []Event, obtained was string value connect",
+		}, {
+			description: "missing expected",
+			checker:     checkers.EventsInOrder,
+			obtained:    events,
+			err:         "missing 'expected' value",
+		},
+	} {
+		err := test.checker.Check(test.obtained, test.extras...)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+		} else {
+			if test.err == "" {
+				t.Errorf("%s: unexpected error: %v", test.description, err)
+			} else if err.Error() != test.err {
+				t.Errorf("%s: error mismatch: \n\tobtained: %q\n\texpected: %q", test.description, err.Error(), test.err)
+			}
+		}
+	}
+}
@@ -0,0 +1,127 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// subprocessEnv is the environment variable that identifies which call
+// a subprocess was started to run.
+const subprocessEnv = "CHECKERS_SUBPROCESS"
+
+// returnedMarker is written to stderr by a subprocess when the function
+// it was running returns rather than exiting.
+const returnedMarker = "\ncheckers: function returned without exiting\n"
+
+// subprocessCalls counts the calls to ExitsWith made by each test, so
+// that a subprocess can tell which of them it was started to run. The
+// count for a test is removed when it is cleaned up, so that a test run
+// again, such as with -count, numbers its calls from the start as the
+// subprocess does.
+var subprocessCalls = struct {
+	sync.Mutex
+	counts map[string]int
+}{counts: make(map[string]int)}
+
+func nextSubprocessID(tb testing.TB) string {
+	subprocessCalls.Lock()
+	defer subprocessCalls.Unlock()
+	name := tb.Name()
+	if _, ok := subprocessCalls.counts[name]; !ok {
+		tb.Cleanup(func() {
+			subprocessCalls.Lock()
+			defer subprocessCalls.Unlock()
+			delete(subprocessCalls.counts, name)
+		})
+	}
+	subprocessCalls.counts[name]++
+	return fmt.Sprintf("%s#%d", name, subprocessCalls.counts[name])
+}
+
+// ExitsWith checks that f ends the process with the exit code, writing
+// to stderr a message that matches the pattern in full, with "."
+// matching newlines too. The test is marked as failed if not.
+//
+// As exiting would end the test binary, f is run in a subprocess. The
+// test binary is run again with only the current test selected, and
+// with an environment variable set that has the call to ExitsWith run f
+// rather than starting another subprocess. Everything the test does
+// before the call is done again in the subprocess, and other calls to
+// ExitsWith there do nothing, so calls should come early in the test
+// and their results should not be relied on by the rest of the test.
+func (t *Test) ExitsWith(f func(), code int, stderrPattern string) bool {
+	tb := t.testingTB()
+	tb.Helper()
+	id := nextSubprocessID(tb)
+	switch os.Getenv(subprocessEnv) {
+	case id:
+		f()
+		fmt.Fprint(os.Stderr, returnedMarker)
+		os.Exit(0)
+	case "":
+		// Not in a subprocess, so start one.
+	default:
+		// In a subprocess started for a different call.
+		return true
+	}
+
+	// Check the pattern as given, so any mistakes in it are reported
+	// in its terms.
+	if _, err := compilePattern(stderrPattern); err != nil {
+		tb.Error(err.Error())
+		return false
+	}
+	re, err := compilePattern("(?s)" + stderrPattern)
+	if err != nil {
+		tb.Error(err.Error())
+		return false
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run="+runPattern(tb.Name()))
+	cmd.Env = append(os.Environ(), subprocessEnv+"="+id)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	exitCode := 0
+	if err := cmd.Run(); err != nil {
+		exitErr, ok := err.(*exec.ExitError)
+		if !ok {
+			tb.Errorf("unable to run subprocess: %v", err)
+			return false
+		}
+		exitCode = exitErr.ExitCode()
+	}
+
+	output := stderr.String()
+	if strings.Contains(output, returnedMarker) {
+		tb.Errorf("function returned without exiting\nstderr:\n%s", strings.Replace(output, returnedMarker, "", 1))
+		return false
+	}
+	if exitCode != code {
+		tb.Errorf("expected exit code %d, got %d\nstderr:\n%s", code, exitCode, output)
+		return false
+	}
+	if !re.MatchString(output) {
+		tb.Errorf("stderr did not match pattern %q\nstderr:\n%s", stderrPattern, output)
+		return false
+	}
+	return true
+}
+
+// runPattern returns the value for the -test.run flag that selects only
+// the named test or subtest.
+func runPattern(name string) string {
+	parts := strings.Split(name, "/")
+	for i, part := range parts {
+		parts[i] = "^" + regexp.QuoteMeta(part) + "$"
+	}
+	return strings.Join(parts, "/")
+}
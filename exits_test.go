@@ -0,0 +1,60 @@
+// Add a copyright
+// Add a licence
+
+package checkers_test
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/howbazaar/checkers"
+)
+
+func TestExitsWith(t *testing.T) {
+	ct := &checkers.Test{TB: t}
+	ct.ExitsWith(func() {
+		fmt.Fprintln(os.Stderr, "usage: bad flag")
+		os.Exit(2)
+	}, 2, "usage: .*\n")
+	ct.ExitsWith(func() {
+		os.Exit(0)
+	}, 0, "")
+}
+
+// namedTB is a RecordingTB that has the name of a real test, so that
+// ExitsWith can run it in a subprocess.
+type namedTB struct {
+	*checkers.RecordingTB
+	name string
+}
+
+func (tb namedTB) Name() string {
+	return tb.name
+}
+
+func TestExitsWithFailures(t *testing.T) {
+	r := checkers.NewRecordingTB()
+	r.Run(func(tb testing.TB) {
+		ct := &checkers.Test{TB: namedTB{r, t.Name()}}
+		ct.ExitsWith(func() {
+			os.Exit(3)
+		}, 2, "")
+		ct.ExitsWith(func() {
+			fmt.Fprint(os.Stderr, "oops")
+			os.Exit(2)
+		}, 2, "usage.*")
+		ct.ExitsWith(func() {
+			fmt.Fprint(os.Stderr, "returning")
+		}, 2, "")
+		ct.ExitsWith(func() {}, 2, "(")
+	})
+	if err := checkers.DeepEquals.Check(r.Errors(), []string{
+		"expected exit code 2, got 3\nstderr:\n",
+		"stderr did not match pattern \"usage.*\"\nstderr:\noops",
+		"function returned without exiting\nstderr:\nreturning",
+		`invalid regexp pattern "(": missing closing ): "(" at position 0`,
+	}); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,273 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Expectation is a check described as data, so that assertions about a
+// large value, such as an API response, can be kept in a file reviewed
+// alongside the rest of the test data.
+type Expectation struct {
+	// Path locates the value to check within the obtained value, using
+	// the syntax described by Mismatch.Path. The empty path checks the
+	// obtained value itself.
+	Path string `json:"path"`
+	// Checker is the name of a registered checker. See Register.
+	Checker string `json:"checker"`
+	// Args are the extra values given to the checker. The first is
+	// converted to the type of the value being checked where it can
+	// be, so that numbers and objects read from a file can be compared
+	// with Go values, and whole numbers are otherwise converted to int.
+	Args []interface{} `json:"args"`
+}
+
+// LoadExpectations reads a list of expectations from the file. The file
+// is decoded as JSON when unmarshal is nil; another format, such as
+// YAML, can be read by passing its unmarshal function, which should
+// decode objects with lower case "path", "checker" and "args" keys into
+// the fields of an Expectation.
+//
+//	[
+//		{"path": ".Name", "checker": "Equals", "args": ["alice"]},
+//		{"path": ".Tags", "checker": "HasLen", "args": [2]}
+//	]
+func LoadExpectations(filename string, unmarshal func([]byte, interface{}) error) ([]Expectation, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	if unmarshal == nil {
+		unmarshal = func(data []byte, v interface{}) error {
+			decoder := json.NewDecoder(bytes.NewReader(data))
+			decoder.UseNumber()
+			return decoder.Decode(v)
+		}
+	}
+	var expectations []Expectation
+	if err := unmarshal(data, &expectations); err != nil {
+		return nil, fmt.Errorf("unable to read expectations from %s: %v", filename, err)
+	}
+	return expectations, nil
+}
+
+// Check applies the expectation to the obtained value.
+func (e Expectation) Check(obtained interface{}) error {
+	checker, ok := Lookup(e.Checker)
+	if !ok {
+		return fmt.Errorf("no checker registered as %q", e.Checker)
+	}
+	v, err := valueAtPath(reflect.ValueOf(obtained), e.Path)
+	if err != nil {
+		return err
+	}
+	if v.Kind() == reflect.Interface && !v.IsNil() {
+		v = v.Elem()
+	}
+	value := interfaceOf(v)
+	args := make([]interface{}, len(e.Args))
+	for i, arg := range e.Args {
+		args[i] = normalizeNumber(arg)
+	}
+	if len(args) > 0 && v.IsValid() {
+		args[0] = convertArg(e.Args[0], args[0], v.Type())
+	}
+	return checker.Check(value, args...)
+}
+
+// CheckExpectations checks every expectation against the obtained value,
+// marking the test as a failure for each one that fails. The test
+// continues.
+func (t *Test) CheckExpectations(obtained interface{}, expectations []Expectation) bool {
+	tb := t.testingTB()
+	tb.Helper()
+	ok := true
+	for i, e := range expectations {
+		if err := e.Check(obtained); err != nil {
+			path := e.Path
+			if path == "" {
+				path = "top level"
+			}
+			tb.Errorf("expectation %d (%s at %s) failed: %v", i, e.Checker, path, err)
+			ok = false
+		}
+	}
+	return ok
+}
+
+// normalizeNumber converts a number decoded from a file to an int when
+// it is whole, and to a float64 otherwise, leaving other values as they
+// are.
+func normalizeNumber(arg interface{}) interface{} {
+	switch n := arg.(type) {
+	case json.Number:
+		if i, err := strconv.Atoi(string(n)); err == nil {
+			return i
+		}
+		if f, err := n.Float64(); err == nil {
+			return normalizeNumber(f)
+		}
+	case float64:
+		if n == math.Trunc(n) && math.Abs(n) < 1<<53 {
+			return int(n)
+		}
+	}
+	return arg
+}
+
+// convertArg returns the argument as the given type if it can be
+// decoded as one, and normalized otherwise.
+func convertArg(arg, normalized interface{}, typ reflect.Type) interface{} {
+	if reflect.TypeOf(normalized) == typ {
+		return normalized
+	}
+	data, err := json.Marshal(arg)
+	if err != nil {
+		return normalized
+	}
+	converted := reflect.New(typ)
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(converted.Interface()); err != nil {
+		return normalized
+	}
+	return converted.Elem().Interface()
+}
+
+// valueAtPath returns the value at the path within v, following
+// pointers and interfaces along the way.
+func valueAtPath(v reflect.Value, path string) (reflect.Value, error) {
+	rest := path
+	for rest != "" {
+		for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+			if v.IsNil() {
+				return reflect.Value{}, fmt.Errorf("path %q: nil %s before %q", path, v.Type(), rest)
+			}
+			v = v.Elem()
+		}
+		if !v.IsValid() {
+			return reflect.Value{}, fmt.Errorf("path %q: nil value before %q", path, rest)
+		}
+		switch rest[0] {
+		case '.':
+			end := strings.IndexAny(rest[1:], ".[")
+			if end < 0 {
+				end = len(rest) - 1
+			}
+			name := rest[1 : end+1]
+			rest = rest[end+1:]
+			if v.Kind() != reflect.Struct {
+				return reflect.Value{}, fmt.Errorf("path %q: field %s of non-struct %s", path, name, v.Type())
+			}
+			field := v.FieldByName(name)
+			if !field.IsValid() {
+				return reflect.Value{}, fmt.Errorf("path %q: no field %s in %s", path, name, v.Type())
+			}
+			v = field
+		case '[':
+			key, remaining, err := splitPathKey(rest)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("path %q: %v", path, err)
+			}
+			rest = remaining
+			v, err = indexValue(v, key)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("path %q: %v", path, err)
+			}
+		default:
+			return reflect.Value{}, fmt.Errorf("path %q: expected '.' or '[' at %q", path, rest)
+		}
+	}
+	return v, nil
+}
+
+// splitPathKey splits the bracketed key at the start of the path from
+// the rest of the path. A quoted key is returned still quoted.
+func splitPathKey(path string) (key, rest string, err error) {
+	end := -1
+	if strings.HasPrefix(path, `["`) {
+		for i := 2; i < len(path); i++ {
+			if path[i] == '\\' {
+				i++
+			} else if path[i] == '"' {
+				end = i + 1
+				break
+			}
+		}
+	} else {
+		end = strings.IndexByte(path, ']')
+	}
+	if end < 0 || end >= len(path) || path[end] != ']' {
+		return "", "", fmt.Errorf("unterminated key at %q", path)
+	}
+	return path[1:end], path[end+1:], nil
+}
+
+// indexValue returns the element of the slice, array or map v with the
+// key, written in Go syntax.
+func indexValue(v reflect.Value, key string) (reflect.Value, error) {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		i, err := strconv.Atoi(key)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid index [%s] of %s", key, v.Type())
+		}
+		if i < 0 || i >= v.Len() {
+			return reflect.Value{}, fmt.Errorf("index [%d] out of range for %s of length %d", i, v.Type(), v.Len())
+		}
+		return v.Index(i), nil
+	case reflect.Map:
+		k, err := parseMapKey(key, v.Type().Key())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		elem := v.MapIndex(k)
+		if !elem.IsValid() {
+			return reflect.Value{}, fmt.Errorf("no key [%s] in %s", key, v.Type())
+		}
+		return elem, nil
+	}
+	return reflect.Value{}, fmt.Errorf("key [%s] of %s", key, v.Type())
+}
+
+// parseMapKey converts the key, written in Go syntax, to a value of the
+// map key type.
+func parseMapKey(key string, typ reflect.Type) (reflect.Value, error) {
+	invalid := fmt.Errorf("invalid key [%s] for %s", key, typ)
+	switch kindFamily(typ.Kind()) {
+	case reflect.String:
+		s, err := strconv.Unquote(key)
+		if err != nil {
+			return reflect.Value{}, invalid
+		}
+		return reflect.ValueOf(s).Convert(typ), nil
+	case reflect.Int:
+		i, err := strconv.ParseInt(key, 10, 64)
+		if err != nil {
+			return reflect.Value{}, invalid
+		}
+		return reflect.ValueOf(i).Convert(typ), nil
+	case reflect.Uint:
+		u, err := strconv.ParseUint(key, 10, 64)
+		if err != nil {
+			return reflect.Value{}, invalid
+		}
+		return reflect.ValueOf(u).Convert(typ), nil
+	case reflect.Bool:
+		b, err := strconv.ParseBool(key)
+		if err != nil {
+			return reflect.Value{}, invalid
+		}
+		return reflect.ValueOf(b).Convert(typ), nil
+	}
+	return reflect.Value{}, fmt.Errorf("unsupported key type %s", typ)
+}
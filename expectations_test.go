@@ -0,0 +1,173 @@
+// Add a copyright
+// Add a licence
+
+package checkers_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/howbazaar/checkers"
+)
+
+type account struct {
+	Name    string
+	Age     int64
+	Tags    []string
+	Attrs   map[string]interface{}
+	Friends []*account
+	Manager *account
+	Counts  map[int]uint8
+}
+
+func newAccount() *account {
+	return &account{
+		Name:    "alice",
+		Age:     30,
+		Tags:    []string{"user", "admin"},
+		Attrs:   map[string]interface{}{"score": 1.5, "level": 3},
+		Friends: []*account{{Name: "bob", Age: 25}},
+		Counts:  map[int]uint8{7: 2},
+	}
+}
+
+func TestLoadExpectations(t *testing.T) {
+	for _, test := range []struct {
+		description string
+		unmarshal   func([]byte, interface{}) error
+	}{
+		{
+			description: "json",
+		}, {
+			description: "custom unmarshal",
+			unmarshal:   json.Unmarshal,
+		},
+	} {
+		expectations, err := checkers.LoadExpectations("testdata/expectations.json", test.unmarshal)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.description, err)
+		}
+		if len(expectations) != 7 {
+			t.Fatalf("%s: expected 7 expectations, got %d", test.description, len(expectations))
+		}
+		r := checkers.NewRecordingTB()
+		r.Run(func(tb testing.TB) {
+			ct := &checkers.Test{TB: tb}
+			ct.CheckExpectations(newAccount(), expectations)
+		})
+		if errs := r.Errors(); len(errs) != 0 {
+			t.Errorf("%s: unexpected failures: %q", test.description, errs)
+		}
+	}
+}
+
+func TestLoadExpectationsErrors(t *testing.T) {
+	_, err := checkers.LoadExpectations("testdata/expectations.json", func([]byte, interface{}) error {
+		return errors.New("bad format")
+	})
+	if err == nil || err.Error() != "unable to read expectations from testdata/expectations.json: bad format" {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := checkers.LoadExpectations("testdata/missing.json", nil); err == nil {
+		t.Errorf("expected error for missing file")
+	}
+}
+
+func TestExpectationCheck(t *testing.T) {
+	for _, test := range []struct {
+		description string
+		expectation checkers.Expectation
+		err         string
+	}{
+		{
+			description: "top level",
+			expectation: checkers.Expectation{Checker: "IsNil", Args: nil},
+			err:         "obtained value is non-nil",
+		}, {
+			description: "int map key",
+			expectation: checkers.Expectation{Path: ".Counts[7]", Checker: "Equals", Args: []interface{}{json.Number("2")}},
+		}, {
+			description: "interface map value",
+			expectation: checkers.Expectation{Path: `.Attrs["level"]`, Checker: "Equals", Args: []interface{}{json.Number("3")}},
+		}, {
+			description: "unequal",
+			expectation: checkers.Expectation{Path: ".Name", Checker: "Equals", Args: []interface{}{"bob"}},
+			err:         `expected string value bob, got alice\nstrings differ at byte 0 \(rune 0\):\n(?s).*`,
+		}, {
+			description: "unknown checker",
+			expectation: checkers.Expectation{Path: ".Name", Checker: "Unknown"},
+			err:         `no checker registered as "Unknown"`,
+		}, {
+			description: "missing field",
+			expectation: checkers.Expectation{Path: ".Email", Checker: "IsNil"},
+			err:         `path ".Email": no field Email in checkers_test.account`,
+		}, {
+			description: "field of non-struct",
+			expectation: checkers.Expectation{Path: ".Name.First", Checker: "IsNil"},
+			err:         `path ".Name.First": field First of non-struct string`,
+		}, {
+			description: "index out of range",
+			expectation: checkers.Expectation{Path: ".Tags[2]", Checker: "IsNil"},
+			err:         `path ".Tags\[2\]": index \[2\] out of range for \[\]string of length 2`,
+		}, {
+			description: "missing key",
+			expectation: checkers.Expectation{Path: `.Attrs["rank"]`, Checker: "IsNil"},
+			err:         `path ".Attrs\[\\"rank\\"\]": no key \["rank"\] in map\[string\]interface {}`,
+		}, {
+			description: "unquoted string key",
+			expectation: checkers.Expectation{Path: `.Attrs[rank]`, Checker: "IsNil"},
+			err:         `path ".Attrs\[rank\]": invalid key \[rank\] for string`,
+		}, {
+			description: "unterminated key",
+			expectation: checkers.Expectation{Path: `.Attrs["rank]`, Checker: "IsNil"},
+			err:         `path ".Attrs\[\\"rank\]": unterminated key at "\[\\"rank\]"`,
+		}, {
+			description: "through nil pointer",
+			expectation: checkers.Expectation{Path: ".Manager.Name", Checker: "IsNil"},
+			err:         `path ".Manager.Name": nil \*checkers_test.account before ".Name"`,
+		}, {
+			description: "bad syntax",
+			expectation: checkers.Expectation{Path: "Name", Checker: "IsNil"},
+			err:         `path "Name": expected '.' or '\[' at "Name"`,
+		},
+	} {
+		err := test.expectation.Check(newAccount())
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+		} else {
+			if test.err == "" {
+				t.Errorf("%s: unexpected error: %v", test.description, err)
+			} else if matchErr := checkers.Matches.Check(err.Error(), test.err); matchErr != nil {
+				t.Errorf("%s: error mismatch: %v", test.description, matchErr)
+			}
+		}
+	}
+}
+
+func TestCheckExpectationsFailures(t *testing.T) {
+	r := checkers.NewRecordingTB()
+	r.Run(func(tb testing.TB) {
+		ct := &checkers.Test{TB: tb}
+		ok := ct.CheckExpectations(newAccount(), []checkers.Expectation{
+			{Checker: "IsNil"},
+			{Path: ".Name", Checker: "Equals", Args: []interface{}{"alice"}},
+			{Path: ".Age", Checker: "Equals", Args: []interface{}{json.Number("31")}},
+		})
+		if ok {
+			t.Errorf("expected failure")
+		}
+	})
+	errs := r.Errors()
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 failures, got %q", errs)
+	}
+	if err := checkers.Matches.Check(errs[0], `expectation 0 \(IsNil at top level\) failed: obtained value is non-nil`); err != nil {
+		t.Error(err)
+	}
+	if expected := "expectation 2 (Equals at .Age) failed: expected int64 value 31, got 30"; errs[1] != expected {
+		t.Errorf("failure mismatch: \n\tobtained: %q\n\texpected: %q", errs[1], expected)
+	}
+}
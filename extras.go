@@ -0,0 +1,112 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The extra values passed to a checker start with any that the checker
+// requires, such as the expected value, followed by any options that
+// the checker understands. Comment values are understood by every
+// checker in this package. Any other extra values are reported as an
+// error, as they are most likely a mistake at the call site.
+
+// Comment is an extra value understood by all the checkers in this
+// package, that is added to the description of a failure.
+type Comment struct {
+	text string
+}
+
+// Commentf returns a Comment with the text formatted as by fmt.Sprintf.
+//
+//	t.Check(obtained, checkers.Equals, expected, checkers.Commentf("case %d", i))
+func Commentf(format string, args ...interface{}) Comment {
+	return Comment{fmt.Sprintf(format, args...)}
+}
+
+// String returns the text of the comment.
+func (c Comment) String() string {
+	return c.text
+}
+
+// splitComments separates any comments from the other extra values.
+func splitComments(extras []interface{}) ([]interface{}, []Comment) {
+	var comments []Comment
+	var rest []interface{}
+	for _, extra := range extras {
+		if comment, ok := extra.(Comment); ok {
+			comments = append(comments, comment)
+		} else {
+			rest = append(rest, extra)
+		}
+	}
+	if comments == nil {
+		return extras, nil
+	}
+	return rest, comments
+}
+
+// addComments adds the comments to the description of the failure, if
+// there is one. It is intended to be deferred by checkers.
+func addComments(err *error, comments []Comment) {
+	if *err != nil && len(comments) > 0 {
+		*err = &commentedError{
+			err:      *err,
+			comments: comments,
+		}
+	}
+}
+
+// SplitComments separates any comments from the other extra values, for
+// checkers outside this package to handle comments as those in it do.
+// The comments are added to any failure with AddComments.
+//
+//	extras, comments := checkers.SplitComments(extras)
+//	defer checkers.AddComments(&err, comments)
+func SplitComments(extras []interface{}) ([]interface{}, []Comment) {
+	return splitComments(extras)
+}
+
+// AddComments adds the comments to the description of the failure, if
+// there is one. It is intended to be deferred by checkers, as shown for
+// SplitComments.
+func AddComments(err *error, comments []Comment) {
+	addComments(err, comments)
+}
+
+// UnexpectedExtras returns an error if there are any extra values left
+// that the named checker has not used, for checkers outside this
+// package to report them as those in it do.
+func UnexpectedExtras(checker string, extras []interface{}) error {
+	return unexpectedExtras(checker, extras)
+}
+
+// unexpectedExtras returns an error if there are any extra values left
+// that the named checker has not used.
+func unexpectedExtras(checker string, extras []interface{}) error {
+	if len(extras) == 0 {
+		return nil
+	}
+	return fmt.Errorf("too many arguments to checker %s, unexpected %T(%#v)", checker, extras[0], extras[0])
+}
+
+// commentedError adds comments to a failure.
+type commentedError struct {
+	err      error
+	comments []Comment
+}
+
+func (err *commentedError) Error() string {
+	lines := []string{err.err.Error()}
+	for _, comment := range err.comments {
+		lines = append(lines, "comment: "+comment.text)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (err *commentedError) Unwrap() error {
+	return err.err
+}
@@ -0,0 +1,163 @@
+// Add a copyright
+// Add a licence
+
+package checkers_test
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/howbazaar/checkers"
+)
+
+func TestExtras(t *testing.T) {
+	for _, test := range []struct {
+		description string
+		checker     checkers.Checker
+		obtained    interface{}
+		extras      []interface{}
+		err         string
+	}{
+		{
+			description: "comment on success",
+			checker:     checkers.Equals,
+			obtained:    1,
+			extras:      []interface{}{1, checkers.Commentf("case %d", 1)},
+		}, {
+			description: "comment on failure",
+			checker:     checkers.Equals,
+			obtained:    1,
+			extras:      []interface{}{2, checkers.Commentf("case %d", 1)},
+			err:         "expected int value 2, got 1\ncomment: case 1",
+		}, {
+			description: "comments before and after",
+			checker:     checkers.IsTrue,
+			obtained:    false,
+			extras:      []interface{}{checkers.Commentf("first"), checkers.Commentf("second")},
+			err:         "obtained value is false\ncomment: first\ncomment: second",
+		}, {
+			description: "comment with expected value",
+			checker:     checkers.HasLen,
+			obtained:    "foo",
+			extras:      []interface{}{checkers.Commentf("first"), 2},
+			err:         "expected length 2, obtained 3\ncomment: first",
+		}, {
+			description: "IsNil too many",
+			checker:     checkers.IsNil,
+			obtained:    nil,
+			extras:      []interface{}{nil},
+			err:         "too many arguments to checker IsNil, unexpected <nil>(<nil>)",
+		}, {
+			description: "IsTrue too many",
+			checker:     checkers.IsTrue,
+			obtained:    true,
+			extras:      []interface{}{true},
+			err:         "too many arguments to checker IsTrue, unexpected bool(true)",
+		}, {
+			description: "IsFalse too many",
+			checker:     checkers.IsFalse,
+			obtained:    false,
+			extras:      []interface{}{"why"},
+			err:         `too many arguments to checker IsFalse, unexpected string("why")`,
+		}, {
+			description: "Equals too many",
+			checker:     checkers.Equals,
+			obtained:    1,
+			extras:      []interface{}{1, 2},
+			err:         "too many arguments to checker Equals, unexpected int(2)",
+		}, {
+			description: "EqualsConverted too many",
+			checker:     checkers.EqualsConverted,
+			obtained:    1,
+			extras:      []interface{}{1, 2},
+			err:         "too many arguments to checker EqualsConverted, unexpected int(2)",
+		}, {
+			description: "DeepEquals options",
+			checker:     checkers.DeepEquals,
+			obtained:    []int{1, 2},
+			extras:      []interface{}{[]int{2, 1}, checkers.IgnoreOrder([]int{})},
+		}, {
+			description: "DeepEquals too many",
+			checker:     checkers.DeepEquals,
+			obtained:    []int{1, 2},
+			extras:      []interface{}{[]int{1, 2}, checkers.MaxDepth(2), "extra"},
+			err:         `too many arguments to checker DeepEquals, unexpected string("extra")`,
+		}, {
+			description: "HasLen too many",
+			checker:     checkers.HasLen,
+			obtained:    "foo",
+			extras:      []interface{}{3, 4},
+			err:         "too many arguments to checker HasLen, unexpected int(4)",
+		}, {
+			description: "Matches too many",
+			checker:     checkers.Matches,
+			obtained:    "foo",
+			extras:      []interface{}{"f.*", "g.*"},
+			err:         `too many arguments to checker Matches, unexpected string("g.*")`,
+		}, {
+			description: "MatchesRegexp too many",
+			checker:     checkers.MatchesRegexp(regexp.MustCompile("f.*")),
+			obtained:    "foo",
+			extras:      []interface{}{"g.*"},
+			err:         `too many arguments to checker MatchesRegexp, unexpected string("g.*")`,
+		}, {
+			description: "PanicMatches too many",
+			checker:     checkers.PanicMatches,
+			obtained:    func() { panic("foo") },
+			extras:      []interface{}{"f.*", 1},
+			err:         "too many arguments to checker PanicMatches, unexpected int(1)",
+		}, {
+			description: "PanicMatches comment",
+			checker:     checkers.PanicMatches,
+			obtained:    func() { panic("foo") },
+			extras:      []interface{}{"g.*", checkers.Commentf("panics")},
+			err:         `"foo" did not match pattern "^g.*$"` + "\ncomment: panics",
+		},
+	} {
+		err := test.checker.Check(test.obtained, test.extras...)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+		} else {
+			if test.err == "" {
+				t.Errorf("%s: unexpected error: %v", test.description, err)
+			} else {
+				if err.Error() != test.err {
+					t.Errorf("%s: error mismatch: \n\tobtained: %q\n\texpected: %q", test.description, err.Error(), test.err)
+				}
+			}
+		}
+	}
+}
+
+func TestCommentedMismatches(t *testing.T) {
+	err := checkers.DeepEquals.Check([]int{1}, []int{2}, checkers.Commentf("why"))
+	mismatches := checkers.Mismatches(err)
+	if len(mismatches) != 1 || mismatches[0].Path != "[0]" {
+		t.Fatalf("unexpected mismatches: %#v", mismatches)
+	}
+}
+
+func TestSplitComments(t *testing.T) {
+	check := func(extras ...interface{}) (err error) {
+		extras, comments := checkers.SplitComments(extras)
+		defer checkers.AddComments(&err, comments)
+		return fmt.Errorf("extras %v", extras)
+	}
+	err := check(1, checkers.Commentf("first"), 2, checkers.Commentf("second"))
+	if expected := "extras [1 2]\ncomment: first\ncomment: second"; err.Error() != expected {
+		t.Fatalf("error mismatch: \n\tobtained: %q\n\texpected: %q", err.Error(), expected)
+	}
+}
+
+func TestUnexpectedExtras(t *testing.T) {
+	if err := checkers.UnexpectedExtras("Custom", nil); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	err := checkers.UnexpectedExtras("Custom", []interface{}{"more"})
+	if expected := `too many arguments to checker Custom, unexpected string("more")`; err == nil || err.Error() != expected {
+		t.Errorf("error mismatch: \n\tobtained: %v\n\texpected: %q", err, expected)
+	}
+}
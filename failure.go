@@ -0,0 +1,43 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"fmt"
+	"sync"
+)
+
+// failure is an error describing why a check failed, where the
+// description is only built when it is first asked for. Checkers are
+// often run without their failures being reported, such as when they
+// are negated or retried, and describing large values is expensive, so
+// no formatting is done until the description is needed.
+//
+// As the description is built later, the values it is built from must
+// not be changed after the failure is returned.
+type failure struct {
+	once    sync.Once
+	build   func() string
+	message string
+}
+
+// lazyFailure returns a failure described by the result of build.
+func lazyFailure(build func() string) error {
+	return &failure{build: build}
+}
+
+// failf returns a failure described as by fmt.Sprintf.
+func failf(format string, args ...interface{}) error {
+	return lazyFailure(func() string {
+		return fmt.Sprintf(format, args...)
+	})
+}
+
+func (f *failure) Error() string {
+	f.once.Do(func() {
+		f.message = f.build()
+		f.build = nil
+	})
+	return f.message
+}
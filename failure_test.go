@@ -0,0 +1,50 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"testing"
+)
+
+func TestLazyFailure(t *testing.T) {
+	built := 0
+	err := lazyFailure(func() string {
+		built++
+		return "failed"
+	})
+	if built != 0 {
+		t.Fatalf("description built before it was needed")
+	}
+	for i := 0; i < 2; i++ {
+		if err.Error() != "failed" {
+			t.Fatalf("unexpected description: %q", err.Error())
+		}
+	}
+	if built != 1 {
+		t.Fatalf("description built %d times", built)
+	}
+}
+
+func TestFailf(t *testing.T) {
+	err := failf("expected %d, got %q", 1, "one")
+	if err.Error() != `expected 1, got "one"` {
+		t.Fatalf("unexpected description: %q", err.Error())
+	}
+}
+
+func BenchmarkDeepEqualsFailure(b *testing.B) {
+	obtained := make([]int, 1000)
+	expected := make([]int, 1000)
+	expected[500] = 1
+	b.Run("unreported", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			DeepEquals.Check(obtained, expected)
+		}
+	})
+	b.Run("reported", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = DeepEquals.Check(obtained, expected).Error()
+		}
+	})
+}
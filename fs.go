@@ -0,0 +1,230 @@
+// Add a copyright
+// Add a licence
+
+//go:build go1.16
+// +build go1.16
+
+package checkers
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+	"testing/fstest"
+)
+
+// NewMapFS returns an in-memory filesystem holding the files, keyed by
+// their slash separated paths, with the content given. Directories are
+// implied by the paths of the files. The test fails immediately if any
+// path is not valid for an fs.FS.
+func (t *Test) NewMapFS(files map[string]string) fstest.MapFS {
+	tb := t.testingTB()
+	tb.Helper()
+	fsys := make(fstest.MapFS)
+	for name, content := range files {
+		if !fs.ValidPath(name) {
+			tb.Fatalf("invalid path %q", name)
+		}
+		fsys[name] = &fstest.MapFile{Data: []byte(content), Mode: 0644}
+	}
+	return fsys
+}
+
+func init() {
+	Register("FSContainsFile", FSContainsFile)
+	Register("FSFileMatches", FSFileMatches)
+	Register("FSTreeEquals", FSTreeEquals)
+}
+
+type fsContainsFile struct{}
+
+// FSContainsFile checker checks that the obtained fs.FS holds a regular
+// file with the expected path.
+var FSContainsFile Checker = fsContainsFile{}
+
+func (fsContainsFile) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if len(extras) == 0 {
+		return errors.New("missing 'expected' value")
+	}
+	expected, extras := extras[0], extras[1:]
+	if err := unexpectedExtras("FSContainsFile", extras); err != nil {
+		return err
+	}
+	fsys, err := obtainedFS("FSContainsFile", obtained)
+	if err != nil {
+		return err
+	}
+	name, ok := expected.(string)
+	if !ok {
+		return errors.New("expected value must be a string containing a path")
+	}
+	return checkRegularFile(fsys, name)
+}
+
+type fsFileMatches struct{}
+
+// FSFileMatches checker checks that the obtained fs.FS holds a file
+// with the path given by the first extra value, whose content matches
+// the pattern given by the second, in full, as with Matches. Patterns
+// can use the (?s) flag to have "." match newlines.
+var FSFileMatches Checker = fsFileMatches{}
+
+func (fsFileMatches) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if len(extras) < 2 {
+		return errors.New("missing 'path' and 'pattern' values")
+	}
+	expectedName, expectedPattern, extras := extras[0], extras[1], extras[2:]
+	if err := unexpectedExtras("FSFileMatches", extras); err != nil {
+		return err
+	}
+	name, ok := expectedName.(string)
+	if !ok {
+		return errors.New("path must be a string")
+	}
+	pattern, ok := expectedPattern.(string)
+	if !ok {
+		return errors.New("pattern must be a string containing a regexp pattern")
+	}
+	re, err := compilePattern(pattern)
+	if err != nil {
+		return err
+	}
+	fsys, err := obtainedFS("FSFileMatches", obtained)
+	if err != nil {
+		return err
+	}
+	if err := checkRegularFile(fsys, name); err != nil {
+		return err
+	}
+	content, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return fmt.Errorf("unable to read %q: %v", name, err)
+	}
+	if re.MatchString(string(content)) {
+		return nil
+	}
+	return failf("content of %q did not match pattern %q\ncontent: %q", name, re.String(), content)
+}
+
+type fsTreeEquals struct{}
+
+// FSTreeEquals checker checks that the obtained fs.FS holds the same
+// regular files, with the same content, as the expected value. The
+// expected value may be an fs.FS, or a map[string]string of paths to
+// content as taken by NewMapFS. Directories are only compared by the
+// files within them.
+var FSTreeEquals Checker = fsTreeEquals{}
+
+func (fsTreeEquals) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if len(extras) == 0 {
+		return errors.New("missing 'expected' value")
+	}
+	expected, extras := extras[0], extras[1:]
+	if err := unexpectedExtras("FSTreeEquals", extras); err != nil {
+		return err
+	}
+	fsys, err := obtainedFS("FSTreeEquals", obtained)
+	if err != nil {
+		return err
+	}
+	obtainedFiles, err := readTree(fsys)
+	if err != nil {
+		return fmt.Errorf("unable to read obtained fs: %v", err)
+	}
+	var expectedFiles map[string]string
+	switch expected := expected.(type) {
+	case map[string]string:
+		expectedFiles = expected
+	case fs.FS:
+		expectedFiles, err = readTree(expected)
+		if err != nil {
+			return fmt.Errorf("unable to read expected fs: %v", err)
+		}
+	default:
+		return fmt.Errorf("expected value must be an fs.FS or map[string]string, got %s", describeType(expected))
+	}
+
+	var names []string
+	for name := range obtainedFiles {
+		names = append(names, name)
+	}
+	for name := range expectedFiles {
+		if _, ok := obtainedFiles[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	var differences []string
+	for _, name := range names {
+		obtainedContent, inObtained := obtainedFiles[name]
+		expectedContent, inExpected := expectedFiles[name]
+		switch {
+		case !inObtained:
+			differences = append(differences, fmt.Sprintf("missing file %q", name))
+		case !inExpected:
+			differences = append(differences, fmt.Sprintf("unexpected file %q", name))
+		case obtainedContent != expectedContent:
+			differences = append(differences, fmt.Sprintf("content of %q differs; obtained %q; expected %q", name, obtainedContent, expectedContent))
+		}
+	}
+	if len(differences) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d differences:\n\t%s", len(differences), strings.Join(differences, "\n\t"))
+}
+
+// obtainedFS returns the obtained value as an fs.FS.
+func obtainedFS(checker string, obtained interface{}) (fs.FS, error) {
+	fsys, ok := obtained.(fs.FS)
+	if !ok {
+		return nil, fmt.Errorf("%s checker expected fs.FS, obtained was %s", checker, describeType(obtained))
+	}
+	return fsys, nil
+}
+
+// checkRegularFile returns an error unless the named file exists and
+// is a regular file.
+func checkRegularFile(fsys fs.FS, name string) error {
+	info, err := fs.Stat(fsys, name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return failf("file %q not found", name)
+	} else if err != nil {
+		return fmt.Errorf("unable to stat %q: %v", name, err)
+	}
+	if info.IsDir() {
+		return failf("%q is a directory, not a file", name)
+	}
+	if !info.Mode().IsRegular() {
+		return failf("%q is not a regular file", name)
+	}
+	return nil
+}
+
+// readTree returns the content of all the regular files in the fs.FS,
+// keyed by their path.
+func readTree(fsys fs.FS) (map[string]string, error) {
+	files := make(map[string]string)
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		content, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		files[name] = string(content)
+		return nil
+	})
+	return files, err
+}
@@ -0,0 +1,142 @@
+// Add a copyright
+// Add a licence
+
+//go:build go1.16
+// +build go1.16
+
+package checkers_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/howbazaar/checkers"
+)
+
+func TestNewMapFS(t *testing.T) {
+	ct := &checkers.Test{TB: t}
+	fsys := ct.NewMapFS(map[string]string{
+		"a.txt":     "hello",
+		"dir/b.txt": "world",
+	})
+	if err := fstest.TestFS(fsys, "a.txt", "dir/b.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	r := checkers.NewRecordingTB()
+	r.Run(func(tb testing.TB) {
+		ct := &checkers.Test{TB: tb}
+		ct.NewMapFS(map[string]string{"../a.txt": ""})
+	})
+	if err := checkers.DeepEquals.Check(r.Errors(), []string{`invalid path "../a.txt"`}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestFSCheckers(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.txt":     {Data: []byte("hello\nthere")},
+		"dir/b.txt": {Data: []byte("world")},
+	}
+	for _, test := range []struct {
+		description string
+		checker     checkers.Checker
+		obtained    interface{}
+		extras      []interface{}
+		err         string
+	}{
+		{
+			description: "contains file",
+			checker:     checkers.FSContainsFile,
+			obtained:    fsys,
+			extras:      []interface{}{"dir/b.txt"},
+		}, {
+			description: "file missing",
+			checker:     checkers.FSContainsFile,
+			obtained:    fsys,
+			extras:      []interface{}{"dir/c.txt"},
+			err:         `file "dir/c.txt" not found`,
+		}, {
+			description: "directory is not a file",
+			checker:     checkers.FSContainsFile,
+			obtained:    fsys,
+			extras:      []interface{}{"dir"},
+			err:         `"dir" is a directory, not a file`,
+		}, {
+			description: "not an fs",
+			checker:     checkers.FSContainsFile,
+			obtained:    "dir",
+			extras:      []interface{}{"dir"},
+			err:         "FSContainsFile checker expected fs.FS, obtained was type string",
+		}, {
+			description: "file matches",
+			checker:     checkers.FSFileMatches,
+			obtained:    fsys,
+			extras:      []interface{}{"a.txt", "(?s)hello.*"},
+		}, {
+			description: "file does not match",
+			checker:     checkers.FSFileMatches,
+			obtained:    fsys,
+			extras:      []interface{}{"a.txt", "hello.*"},
+			err:         "content of \"a.txt\" did not match pattern \"^hello.*$\"\ncontent: \"hello\\nthere\"",
+		}, {
+			description: "file to match missing",
+			checker:     checkers.FSFileMatches,
+			obtained:    fsys,
+			extras:      []interface{}{"b.txt", ".*"},
+			err:         `file "b.txt" not found`,
+		}, {
+			description: "missing pattern",
+			checker:     checkers.FSFileMatches,
+			obtained:    fsys,
+			extras:      []interface{}{"a.txt"},
+			err:         "missing 'path' and 'pattern' values",
+		}, {
+			description: "trees equal",
+			checker:     checkers.FSTreeEquals,
+			obtained:    fsys,
+			extras: []interface{}{map[string]string{
+				"a.txt":     "hello\nthere",
+				"dir/b.txt": "world",
+			}},
+		}, {
+			description: "trees equal, expected fs",
+			checker:     checkers.FSTreeEquals,
+			obtained:    fsys,
+			extras:      []interface{}{fstest.MapFS{"a.txt": {Data: []byte("hello\nthere")}, "dir/b.txt": {Data: []byte("world")}}},
+		}, {
+			description: "trees differ",
+			checker:     checkers.FSTreeEquals,
+			obtained:    fsys,
+			extras: []interface{}{map[string]string{
+				"a.txt":     "hello",
+				"dir/c.txt": "world",
+			}},
+			err: "3 differences:\n" +
+				"\tcontent of \"a.txt\" differs; obtained \"hello\\nthere\"; expected \"hello\"\n" +
+				"\tunexpected file \"dir/b.txt\"\n" +
+				"\tmissing file \"dir/c.txt\"",
+		}, {
+			description: "bad expected",
+			checker:     checkers.FSTreeEquals,
+			obtained:    fsys,
+			extras:      []interface{}{[]string{"a.txt"}},
+			err:         "expected value must be an fs.FS or map[string]string, got type []string",
+		},
+	} {
+		err := test.checker.Check(test.obtained, test.extras...)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+		} else {
+			if test.err == "" {
+				t.Errorf("%s: unexpected error: %v", test.description, err)
+			} else {
+				if err.Error() != test.err {
+					t.Errorf("%s: error mismatch: \n\tobtained: %q\n\texpected: %q", test.description, err.Error(), test.err)
+				}
+			}
+		}
+	}
+}
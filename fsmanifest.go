@@ -0,0 +1,186 @@
+// Add a copyright
+// Add a licence
+
+//go:build go1.16
+// +build go1.16
+
+package checkers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"io/ioutil"
+	"sort"
+	"strings"
+)
+
+func init() {
+	Register("FSMatchesManifest", FSMatchesManifest)
+}
+
+// Manifest describes the regular files expected in an fs.FS, keyed by
+// their slash separated paths, so that a whole tree, such as embedded
+// assets or the output of a generator, can be checked against a file
+// kept in testdata without keeping copies of the files themselves.
+type Manifest map[string]ManifestEntry
+
+// ManifestEntry describes a file in a Manifest. Any field left empty is
+// not checked, so a manifest can, for example, give only the sizes of
+// files whose content changes from build to build.
+type ManifestEntry struct {
+	// SHA256 is the hex encoded SHA-256 hash of the file's content.
+	SHA256 string `json:"sha256,omitempty"`
+	// Size is the size of the file in bytes.
+	Size *int64 `json:"size,omitempty"`
+	// Mode is the file's mode, as formatted by fs.FileMode.String,
+	// such as "-rw-r--r--".
+	Mode string `json:"mode,omitempty"`
+}
+
+// NewManifest returns a Manifest describing every regular file in the
+// fs.FS by its hash, size and mode.
+func NewManifest(fsys fs.FS) (Manifest, error) {
+	manifest := make(Manifest)
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		entry, err := manifestEntry(fsys, name)
+		if err != nil {
+			return err
+		}
+		manifest[name] = entry
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// manifestEntry describes the named file fully.
+func manifestEntry(fsys fs.FS, name string) (ManifestEntry, error) {
+	info, err := fs.Stat(fsys, name)
+	if err != nil {
+		return ManifestEntry{}, err
+	}
+	content, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return ManifestEntry{}, err
+	}
+	sum := sha256.Sum256(content)
+	size := info.Size()
+	return ManifestEntry{
+		SHA256: hex.EncodeToString(sum[:]),
+		Size:   &size,
+		Mode:   info.Mode().String(),
+	}, nil
+}
+
+// LoadManifest reads a Manifest from the JSON file, such as one written
+// by WriteManifest.
+//
+//	{
+//		"index.html": {"sha256": "9f86d0...", "size": 4, "mode": "-rw-r--r--"},
+//		"version.txt": {"size": 6}
+//	}
+func LoadManifest(filename string) (Manifest, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("unable to read manifest from %s: %v", filename, err)
+	}
+	return manifest, nil
+}
+
+// WriteManifest writes the Manifest to the file as JSON, to be read by
+// LoadManifest, so that the manifest in testdata can be regenerated
+// from a tree known to be right.
+func WriteManifest(filename string, manifest Manifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, append(data, '\n'), 0644)
+}
+
+type fsMatchesManifest struct{}
+
+// FSMatchesManifest checker checks that the obtained fs.FS holds exactly
+// the regular files listed in the expected Manifest, each with the
+// hash, size and mode its entry gives. A failure lists every difference.
+//
+//	manifest, err := checkers.LoadManifest("testdata/assets.json")
+//	t.Assert(err, checkers.IsNil)
+//	t.Check(assets, checkers.FSMatchesManifest, manifest)
+var FSMatchesManifest Checker = fsMatchesManifest{}
+
+func (fsMatchesManifest) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if len(extras) == 0 {
+		return errors.New("missing 'expected' value")
+	}
+	expected, extras := extras[0], extras[1:]
+	if err := unexpectedExtras("FSMatchesManifest", extras); err != nil {
+		return err
+	}
+	manifest, ok := expected.(Manifest)
+	if !ok {
+		return fmt.Errorf("expected value must be a Manifest, got %s", describeType(expected))
+	}
+	fsys, err := obtainedFS("FSMatchesManifest", obtained)
+	if err != nil {
+		return err
+	}
+	found, err := NewManifest(fsys)
+	if err != nil {
+		return fmt.Errorf("unable to read obtained fs: %v", err)
+	}
+
+	var names []string
+	for name := range found {
+		names = append(names, name)
+	}
+	for name := range manifest {
+		if _, ok := found[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	var differences []string
+	for _, name := range names {
+		got, inObtained := found[name]
+		want, inExpected := manifest[name]
+		switch {
+		case !inObtained:
+			differences = append(differences, fmt.Sprintf("missing file %q", name))
+		case !inExpected:
+			differences = append(differences, fmt.Sprintf("unexpected file %q", name))
+		default:
+			if want.Size != nil && *got.Size != *want.Size {
+				differences = append(differences, fmt.Sprintf("size of %q differs; obtained %d; expected %d", name, *got.Size, *want.Size))
+			}
+			if want.SHA256 != "" && !strings.EqualFold(got.SHA256, want.SHA256) {
+				differences = append(differences, fmt.Sprintf("sha256 of %q differs; obtained %s; expected %s", name, got.SHA256, want.SHA256))
+			}
+			if want.Mode != "" && got.Mode != want.Mode {
+				differences = append(differences, fmt.Sprintf("mode of %q differs; obtained %s; expected %s", name, got.Mode, want.Mode))
+			}
+		}
+	}
+	if len(differences) == 0 {
+		return nil
+	}
+	return failf("%d differences:\n\t%s", len(differences), strings.Join(differences, "\n\t"))
+}
@@ -0,0 +1,129 @@
+// Add a copyright
+// Add a licence
+
+//go:build go1.16
+// +build go1.16
+
+package checkers_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/howbazaar/checkers"
+)
+
+func TestFSMatchesManifest(t *testing.T) {
+	manifest, err := checkers.LoadManifest("testdata/manifest.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		description string
+		obtained    interface{}
+		extras      []interface{}
+		err         string
+	}{
+		{
+			description: "matches",
+			obtained: fstest.MapFS{
+				"a.txt":     {Data: []byte("hello"), Mode: 0644},
+				"dir/b.txt": {Data: []byte("world"), Mode: 0600},
+			},
+			extras: []interface{}{manifest},
+		}, {
+			description: "differences",
+			obtained: fstest.MapFS{
+				"a.txt":     {Data: []byte("hullo"), Mode: 0600},
+				"dir/b.txt": {Data: []byte("world!")},
+				"extra.txt": {Data: []byte("")},
+			},
+			extras: []interface{}{manifest, checkers.Commentf("assets")},
+			err: "4 differences:\n" +
+				"\tsha256 of \"a.txt\" differs; obtained 7835066a1457504217688c8f5d06909c6591e0ca78c254ccf17450d0d999cab0; expected 2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824\n" +
+				"\tmode of \"a.txt\" differs; obtained -rw-------; expected -rw-r--r--\n" +
+				"\tsize of \"dir/b.txt\" differs; obtained 6; expected 5\n" +
+				"\tunexpected file \"extra.txt\"\n" +
+				"comment: assets",
+		}, {
+			description: "missing file",
+			obtained: fstest.MapFS{
+				"a.txt": {Data: []byte("hello"), Mode: 0644},
+			},
+			extras: []interface{}{manifest},
+			err:    "1 differences:\n\tmissing file \"dir/b.txt\"",
+		}, {
+			description: "not a manifest",
+			obtained:    fstest.MapFS{},
+			extras:      []interface{}{map[string]string{}},
+			err:         "expected value must be a Manifest, got type map[string]string",
+		}, {
+			description: "not an fs",
+			obtained:    "testdata",
+			extras:      []interface{}{manifest},
+			err:         "FSMatchesManifest checker expected fs.FS, obtained was type string",
+		},
+	} {
+		err := checkers.FSMatchesManifest.Check(test.obtained, test.extras...)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+		} else {
+			if test.err == "" {
+				t.Errorf("%s: unexpected error: %v", test.description, err)
+			} else if err.Error() != test.err {
+				t.Errorf("%s: error mismatch: \n\tobtained: %q\n\texpected: %q", test.description, err.Error(), test.err)
+			}
+		}
+	}
+}
+
+func TestWriteManifest(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.txt":     {Data: []byte("hello"), Mode: 0644},
+		"dir/b.txt": {Data: []byte("world"), Mode: 0600},
+	}
+	manifest, err := checkers.NewManifest(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, err := ioutil.TempDir("", "checkers")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "manifest.json")
+	if err := checkers.WriteManifest(filename, manifest); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := checkers.LoadManifest(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := checkers.DeepEquals.Check(loaded, manifest); err != nil {
+		t.Error(err)
+	}
+	if err := checkers.FSMatchesManifest.Check(fsys, loaded); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestFSCheckersRegistered(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		expected checkers.Checker
+	}{
+		{"FSContainsFile", checkers.FSContainsFile},
+		{"FSFileMatches", checkers.FSFileMatches},
+		{"FSTreeEquals", checkers.FSTreeEquals},
+		{"FSMatchesManifest", checkers.FSMatchesManifest},
+	} {
+		if checker, ok := checkers.Lookup(test.name); !ok || checker != test.expected {
+			t.Errorf("%s: registered as %#v", test.name, checker)
+		}
+	}
+}
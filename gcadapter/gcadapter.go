@@ -0,0 +1,98 @@
+// Add a copyright
+// Add a licence
+
+// Package gcadapter adapts checkers written for gopkg.in/check.v1
+// (gocheck) so they can be used with the checkers package, and the
+// reverse, so that suites can be migrated gradually. It is a separate
+// module so that only code using it depends on gocheck.
+package gcadapter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/howbazaar/checkers"
+)
+
+type fromGocheck struct {
+	checker gc.Checker
+}
+
+// FromGocheck returns a Checker that runs the gocheck checker. The
+// obtained value and any extra values are passed to the gocheck checker
+// as its parameters, in the order given by its Info().Params, so
+//
+//	t.Assert(obtained, gcadapter.FromGocheck(jc.SameContents), expected)
+//
+// behaves as c.Assert(obtained, jc.SameContents, expected) would. Any
+// comments made with checkers.Commentf are added to the failure rather
+// than passed to the gocheck checker.
+func FromGocheck(checker gc.Checker) checkers.Checker {
+	return fromGocheck{checker}
+}
+
+func (c fromGocheck) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := checkers.SplitComments(extras)
+	defer checkers.AddComments(&err, comments)
+	info := c.checker.Info()
+	params := append([]interface{}{obtained}, extras...)
+	if len(params) != len(info.Params) {
+		return fmt.Errorf("%s checker expects %d arguments (%s), got %d",
+			info.Name, len(info.Params), strings.Join(info.Params, ", "), len(params))
+	}
+	// gocheck checkers may alter the names, so give them a copy.
+	names := append([]string(nil), info.Params...)
+	ok, message := c.checker.Check(params, names)
+	if ok {
+		return nil
+	}
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "%s check failed", info.Name)
+	if message != "" {
+		buf.WriteString(": " + message)
+	}
+	for i, name := range names {
+		fmt.Fprintf(&buf, "\n%s %T = %#v", name, params[i], params[i])
+	}
+	return errors.New(buf.String())
+}
+
+type toGocheck struct {
+	info    *gc.CheckerInfo
+	checker checkers.Checker
+}
+
+// ToGocheck returns a gocheck checker that runs the checker, so checkers
+// written for this package can be used from gocheck suites. The name
+// and parameter names are used by gocheck when reporting failures. If
+// no parameter names are given, the checker is taken to accept an
+// obtained and an expected value.
+//
+//	var SameDomain = gcadapter.ToGocheck(domain.SameDomain, "SameDomain")
+//	c.Assert(obtained, SameDomain, expected)
+func ToGocheck(checker checkers.Checker, name string, params ...string) gc.Checker {
+	if len(params) == 0 {
+		params = []string{"obtained", "expected"}
+	}
+	return &toGocheck{
+		info: &gc.CheckerInfo{
+			Name:   name,
+			Params: params,
+		},
+		checker: checker,
+	}
+}
+
+func (c *toGocheck) Info() *gc.CheckerInfo {
+	return c.info
+}
+
+func (c *toGocheck) Check(params []interface{}, names []string) (bool, string) {
+	if err := c.checker.Check(params[0], params[1:]...); err != nil {
+		return false, err.Error()
+	}
+	return true, ""
+}
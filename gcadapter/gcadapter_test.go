@@ -0,0 +1,122 @@
+// Add a copyright
+// Add a licence
+
+package gcadapter_test
+
+import (
+	"errors"
+	"testing"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/howbazaar/checkers"
+	"github.com/howbazaar/checkers/gcadapter"
+)
+
+func TestFromGocheck(t *testing.T) {
+	for _, test := range []struct {
+		description string
+		checker     gc.Checker
+		obtained    interface{}
+		extras      []interface{}
+		err         string
+	}{
+		{
+			description: "equals passes",
+			checker:     gc.Equals,
+			obtained:    42,
+			extras:      []interface{}{42},
+		}, {
+			description: "equals fails",
+			checker:     gc.Equals,
+			obtained:    42,
+			extras:      []interface{}{43},
+			err:         "Equals check failed\nobtained int = 42\nexpected int = 43",
+		}, {
+			description: "single parameter checker",
+			checker:     gc.IsNil,
+			obtained:    nil,
+		}, {
+			description: "failure with message",
+			checker:     gc.ErrorMatches,
+			obtained:    "not an error",
+			extras:      []interface{}{"oops"},
+			err:         "ErrorMatches check failed: Value is not an error\nvalue string = \"not an error\"\nregex string = \"oops\"",
+		}, {
+			description: "error matches",
+			checker:     gc.ErrorMatches,
+			obtained:    errors.New("oops: bad"),
+			extras:      []interface{}{"oops.*"},
+		}, {
+			description: "wrong number of arguments",
+			checker:     gc.DeepEquals,
+			obtained:    42,
+			err:         "DeepEquals checker expects 2 arguments (obtained, expected), got 1",
+		}, {
+			description: "comment passes",
+			checker:     gc.Equals,
+			obtained:    42,
+			extras:      []interface{}{42, checkers.Commentf("answer")},
+		}, {
+			description: "comment on failure",
+			checker:     gc.Equals,
+			obtained:    42,
+			extras:      []interface{}{43, checkers.Commentf("answer")},
+			err:         "Equals check failed\nobtained int = 42\nexpected int = 43\ncomment: answer",
+		},
+	} {
+		err := gcadapter.FromGocheck(test.checker).Check(test.obtained, test.extras...)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+			continue
+		}
+		if test.err == "" {
+			t.Errorf("%s: unexpected error: %v", test.description, err)
+		} else if err.Error() != test.err {
+			t.Errorf("%s: error mismatch: \n\tobtained: %q\n\texpected: %q", test.description, err.Error(), test.err)
+		}
+	}
+}
+
+type gocheckSuite struct{}
+
+var _ = gc.Suite(&gocheckSuite{})
+
+func TestGocheck(t *testing.T) {
+	gc.TestingT(t)
+}
+
+var (
+	equals  = gcadapter.ToGocheck(checkers.Equals, "Equals")
+	isNil   = gcadapter.ToGocheck(checkers.IsNil, "IsNil", "value")
+	matches = gcadapter.ToGocheck(checkers.Matches, "Matches", "value", "regex")
+)
+
+func (*gocheckSuite) TestToGocheckInfo(c *gc.C) {
+	c.Assert(equals.Info(), gc.DeepEquals, &gc.CheckerInfo{
+		Name:   "Equals",
+		Params: []string{"obtained", "expected"},
+	})
+	c.Assert(isNil.Info().Params, gc.DeepEquals, []string{"value"})
+}
+
+func (*gocheckSuite) TestToGocheckPasses(c *gc.C) {
+	c.Assert(42, equals, 42)
+	c.Assert(nil, isNil)
+	c.Assert("testing", matches, "test.*")
+	c.Assert(42, gc.Not(equals), 43)
+}
+
+func (*gocheckSuite) TestToGocheckFails(c *gc.C) {
+	ok, message := equals.Check([]interface{}{42, 43}, []string{"obtained", "expected"})
+	c.Assert(ok, gc.Equals, false)
+	c.Assert(message, gc.Equals, "expected int value 43, got 42")
+}
+
+func (*gocheckSuite) TestRoundTrip(c *gc.C) {
+	checker := gcadapter.FromGocheck(gcadapter.ToGocheck(checkers.Equals, "Equals"))
+	c.Assert(checker.Check(42, 42), gc.IsNil)
+	c.Assert(checker.Check(42, 43), gc.ErrorMatches, "(?s)Equals check failed: expected int value 43, got 42\n.*")
+}
@@ -0,0 +1,10 @@
+module github.com/howbazaar/checkers/gcadapter
+
+go 1.14
+
+replace github.com/howbazaar/checkers => ../
+
+require (
+	github.com/howbazaar/checkers v0.0.0-00010101000000-000000000000
+	gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c
+)
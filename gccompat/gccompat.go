@@ -0,0 +1,262 @@
+// Add a copyright
+// Add a licence
+
+// Package gccompat provides the parts of gopkg.in/check.v1 (gocheck)
+// that test files use most, built on the checkers package and run as
+// testing.T subtests, so that gocheck suites can be moved over by
+// changing their imports:
+//
+//	import gc "github.com/howbazaar/checkers/gccompat"
+//
+//	func Test(t *testing.T) { gc.TestingT(t) }
+//
+//	type fooSuite struct{}
+//
+//	var _ = gc.Suite(&fooSuite{})
+//
+//	func (s *fooSuite) TestBar(c *gc.C) {
+//		c.Assert(bar(), gc.Equals, 42)
+//	}
+//
+// Suites are registered with Suite and run by TestingT, each as a
+// subtest named after the suite type, with a subtest for each method
+// whose name starts with "Test" and that takes a *C. The fixture
+// methods SetUpSuite, TearDownSuite, SetUpTest and TearDownTest are
+// called around them as gocheck calls them. Benchmark methods are not
+// run.
+//
+// The checkers given to Assert and Check are checkers.Checker values,
+// so gocheck checkers with no counterpart here need to be wrapped with
+// gcadapter.FromGocheck, or replaced.
+package gccompat
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/howbazaar/checkers"
+)
+
+// The checkers with the same names in gocheck.
+var (
+	Equals       = checkers.Equals
+	DeepEquals   = checkers.DeepEquals
+	HasLen       = checkers.HasLen
+	Matches      = checkers.Matches
+	PanicMatches = checkers.PanicMatches
+)
+
+// IsNil checks that the obtained value is nil, as gocheck's IsNil does.
+// As with checkers.IsNil, a nil pointer, map, slice, channel or function
+// held in an interface is taken to be nil.
+var IsNil = checkers.IsNil
+
+// NotNil checks that the obtained value is not nil, as gocheck's NotNil
+// does.
+var NotNil = checkers.IsNotNil
+
+// Commentf returns a comment to add to the description of a failure,
+// as gocheck's Commentf does.
+func Commentf(format string, args ...interface{}) checkers.Comment {
+	return checkers.Commentf(format, args...)
+}
+
+// C is passed to the test and fixture methods of a suite, as gocheck's
+// C is. It reports to the testing.TB of the test, or of the suite for
+// SetUpSuite and TearDownSuite.
+type C struct {
+	testing.TB
+	name string
+}
+
+// Check checks the obtained value with the checker, marking the test as
+// failed if it fails. The test continues.
+func (c *C) Check(obtained interface{}, checker checkers.Checker, args ...interface{}) bool {
+	c.Helper()
+	return (&checkers.Test{TB: c.TB}).Check(obtained, checker, args...)
+}
+
+// Assert checks the obtained value with the checker, stopping the test
+// if it fails.
+func (c *C) Assert(obtained interface{}, checker checkers.Checker, args ...interface{}) {
+	c.Helper()
+	if !c.Check(obtained, checker, args...) {
+		c.FailNow()
+	}
+}
+
+// MkDir returns a new directory that is removed when the test ends.
+func (c *C) MkDir() string {
+	c.Helper()
+	dir, err := ioutil.TempDir("", "gccompat")
+	if err != nil {
+		c.Fatalf("cannot make directory: %v", err)
+	}
+	c.Cleanup(func() {
+		os.RemoveAll(dir)
+	})
+	return dir
+}
+
+// TestName returns the name of the test as gocheck gives it, such as
+// "fooSuite.TestBar".
+func (c *C) TestName() string {
+	return c.name
+}
+
+// Succeed does nothing, as a test that has not failed has succeeded.
+// It is kept for the tests that call it.
+func (c *C) Succeed() {}
+
+// SucceedNow stops the test, which passes unless it has already
+// failed, as gocheck's SucceedNow does. Like FailNow, it must be called
+// from the goroutine running the test method.
+func (c *C) SucceedNow() {
+	c.Succeed()
+	runtime.Goexit()
+}
+
+// suites holds the suites registered with Suite.
+var suites struct {
+	sync.Mutex
+	list []interface{}
+}
+
+// Suite registers the suite, a pointer to a value whose methods are
+// its tests, to be run by TestingT. It returns the suite so that it can
+// be called in a package level variable declaration.
+func Suite(suite interface{}) interface{} {
+	suites.Lock()
+	defer suites.Unlock()
+	suites.list = append(suites.list, suite)
+	return suite
+}
+
+// TestingT runs all the registered suites as subtests of t.
+func TestingT(t *testing.T) {
+	suites.Lock()
+	list := append([]interface{}(nil), suites.list...)
+	suites.Unlock()
+	for _, suite := range list {
+		runSuite(t, suite)
+	}
+}
+
+var cType = reflect.TypeOf((*C)(nil))
+
+func runSuite(t *testing.T, suite interface{}) {
+	v := reflect.ValueOf(suite)
+	name := reflect.Indirect(v).Type().Name()
+	t.Run(name, func(t *testing.T) {
+		tests, err := testMethods(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c := &C{TB: t, name: name}
+		if !callFixture(c, v, "SetUpSuite") {
+			return
+		}
+		defer callFixture(c, v, "TearDownSuite")
+		for _, method := range tests {
+			method := method
+			t.Run(method, func(t *testing.T) {
+				c := &C{TB: t, name: name + "." + method}
+				if !callFixture(c, v, "SetUpTest") {
+					return
+				}
+				defer callFixture(c, v, "TearDownTest")
+				runTest(v.MethodByName(method), c)
+			})
+		}
+	})
+}
+
+// runTest calls the test method in a goroutine of its own, as gocheck
+// does, so that SucceedNow can stop it with runtime.Goexit without the
+// test being failed for exiting early. A panic in the method is raised
+// again in the goroutine of the test.
+func runTest(method reflect.Value, c *C) {
+	done := make(chan interface{}, 1)
+	go func() {
+		defer func() {
+			done <- recover()
+		}()
+		method.Call([]reflect.Value{reflect.ValueOf(c)})
+	}()
+	if v := <-done; v != nil {
+		panic(v)
+	}
+}
+
+// testMethods returns the sorted names of the test methods of the
+// suite.
+func testMethods(v reflect.Value) ([]string, error) {
+	var names []string
+	var bad []string
+	for i := 0; i < v.NumMethod(); i++ {
+		method := v.Type().Method(i)
+		if !strings.HasPrefix(method.Name, "Test") {
+			continue
+		}
+		t := method.Type
+		if t.NumIn() != 2 || t.In(1) != cType || t.NumOut() != 0 {
+			bad = append(bad, method.Name)
+			continue
+		}
+		names = append(names, method.Name)
+	}
+	if len(bad) > 0 {
+		return nil, fmt.Errorf("test methods %s must take a *gccompat.C and return nothing", strings.Join(bad, ", "))
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// callFixture calls the named fixture method of the suite, if it has
+// one, reporting whether it ran without failing.
+func callFixture(c *C, v reflect.Value, name string) (ok bool) {
+	method := v.MethodByName(name)
+	if !method.IsValid() {
+		return true
+	}
+	if method.Type() != reflect.TypeOf(func(*C) {}) {
+		c.Errorf("%s must take a *gccompat.C and return nothing", name)
+		return false
+	}
+	method.Call([]reflect.Value{reflect.ValueOf(c)})
+	return !c.Failed()
+}
+
+// ErrorMatches checks that the obtained value is an error whose message
+// matches the pattern in full, as gocheck's ErrorMatches does.
+var ErrorMatches checkers.Checker = errorMatches{}
+
+type errorMatches struct{}
+
+func (errorMatches) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := checkers.SplitComments(extras)
+	defer checkers.AddComments(&err, comments)
+	if len(extras) == 0 {
+		return errors.New("missing 'expected' value")
+	}
+	pattern, extras := extras[0], extras[1:]
+	if err := checkers.UnexpectedExtras("ErrorMatches", extras); err != nil {
+		return err
+	}
+	if obtained == nil {
+		return errors.New("error value is nil")
+	}
+	obtainedErr, ok := obtained.(error)
+	if !ok {
+		return fmt.Errorf("ErrorMatches checker expected an error, obtained was %T", obtained)
+	}
+	return checkers.Matches.Check(obtainedErr.Error(), pattern)
+}
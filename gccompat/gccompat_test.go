@@ -0,0 +1,167 @@
+// Add a copyright
+// Add a licence
+
+package gccompat_test
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/howbazaar/checkers"
+	gc "github.com/howbazaar/checkers/gccompat"
+)
+
+type fixtureSuite struct {
+	calls []string
+	dir   string
+}
+
+var fixtures = &fixtureSuite{}
+
+var _ = gc.Suite(fixtures)
+
+func (s *fixtureSuite) SetUpSuite(c *gc.C)    { s.calls = append(s.calls, "SetUpSuite") }
+func (s *fixtureSuite) TearDownSuite(c *gc.C) { s.calls = append(s.calls, "TearDownSuite") }
+func (s *fixtureSuite) SetUpTest(c *gc.C)     { s.calls = append(s.calls, "SetUpTest") }
+func (s *fixtureSuite) TearDownTest(c *gc.C)  { s.calls = append(s.calls, "TearDownTest") }
+
+func (s *fixtureSuite) TestB(c *gc.C) {
+	s.calls = append(s.calls, c.TestName())
+	s.dir = c.MkDir()
+	c.Assert(s.dir, gc.Matches, ".+")
+}
+
+func (s *fixtureSuite) TestA(c *gc.C) {
+	s.calls = append(s.calls, c.TestName())
+	var p *int
+	c.Check(p, gc.IsNil)
+	c.Check(errors.New("no such file"), gc.ErrorMatches, "no such .*", gc.Commentf("reading"))
+}
+
+type succeedSuite struct {
+	tb      testing.TB
+	reached bool
+}
+
+var succeeding = &succeedSuite{}
+
+var _ = gc.Suite(succeeding)
+
+func (s *succeedSuite) TestSucceedNow(c *gc.C) {
+	s.tb = c.TB
+	c.SucceedNow()
+	s.reached = true
+	c.Error("test continued after SucceedNow")
+}
+
+// Helper is not a test method.
+func (s *fixtureSuite) Helper(c *gc.C) {
+	s.calls = append(s.calls, "Helper")
+}
+
+func TestSuite(t *testing.T) {
+	fixtures.calls = nil
+	gc.TestingT(t)
+	if err := checkers.DeepEquals.Check(fixtures.calls, []string{
+		"SetUpSuite",
+		"SetUpTest", "fixtureSuite.TestA", "TearDownTest",
+		"SetUpTest", "fixtureSuite.TestB", "TearDownTest",
+		"TearDownSuite",
+	}); err != nil {
+		t.Error(err)
+	}
+	if _, err := os.Stat(fixtures.dir); !os.IsNotExist(err) {
+		t.Errorf("directory %q not removed: %v", fixtures.dir, err)
+	}
+	switch {
+	case succeeding.tb == nil:
+		t.Errorf("SucceedNow test not run")
+	case succeeding.reached:
+		t.Errorf("SucceedNow did not stop the test")
+	case succeeding.tb.Skipped():
+		t.Errorf("SucceedNow skipped the test")
+	case succeeding.tb.Failed():
+		t.Errorf("SucceedNow failed the test")
+	}
+}
+
+func TestCheckers(t *testing.T) {
+	var nilMap map[string]int
+	for _, test := range []struct {
+		description string
+		checker     checkers.Checker
+		obtained    interface{}
+		extras      []interface{}
+		err         string
+	}{
+		{
+			description: "nil",
+			checker:     gc.IsNil,
+			obtained:    nil,
+		}, {
+			description: "typed nil",
+			checker:     gc.IsNil,
+			obtained:    nilMap,
+		}, {
+			description: "not nil",
+			checker:     gc.IsNil,
+			obtained:    1,
+			extras:      []interface{}{gc.Commentf("count")},
+			err:         "obtained value is non-nil\ncomment: count",
+		}, {
+			description: "not nil passes",
+			checker:     gc.NotNil,
+			obtained:    map[string]int{},
+		}, {
+			description: "not nil fails",
+			checker:     gc.NotNil,
+			obtained:    nilMap,
+			err:         "expected a non-nil value, obtained nil map[string]int",
+		}, {
+			description: "not nil unexpected extra",
+			checker:     gc.NotNil,
+			obtained:    1,
+			extras:      []interface{}{2},
+			err:         "too many arguments to checker IsNotNil, unexpected int(2)",
+		}, {
+			description: "error matches",
+			checker:     gc.ErrorMatches,
+			obtained:    errors.New("boom"),
+			extras:      []interface{}{"bo+m"},
+		}, {
+			description: "error does not match",
+			checker:     gc.ErrorMatches,
+			obtained:    errors.New("boom"),
+			extras:      []interface{}{"bang", gc.Commentf("closing")},
+			err:         "\"boom\" did not match pattern \"^bang$\"\ncomment: closing",
+		}, {
+			description: "nil error",
+			checker:     gc.ErrorMatches,
+			obtained:    nil,
+			extras:      []interface{}{"bang"},
+			err:         "error value is nil",
+		}, {
+			description: "not an error",
+			checker:     gc.ErrorMatches,
+			obtained:    "boom",
+			extras:      []interface{}{"boom"},
+			err:         "ErrorMatches checker expected an error, obtained was string",
+		},
+	} {
+		err := test.checker.Check(test.obtained, test.extras...)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+		} else {
+			if test.err == "" {
+				t.Errorf("%s: unexpected error: %v", test.description, err)
+			} else {
+				if err.Error() != test.err {
+					t.Errorf("%s: error mismatch: \n\tobtained: %q\n\texpected: %q", test.description, err.Error(), test.err)
+				}
+			}
+		}
+	}
+}
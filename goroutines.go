@@ -0,0 +1,76 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"time"
+)
+
+// goroutineGracePeriod is how long NumGoroutineAtMost waits for
+// goroutines that are exiting to finish before it fails.
+const goroutineGracePeriod = 100 * time.Millisecond
+
+type numGoroutineAtMost struct{}
+
+// NumGoroutineAtMost checker calls the obtained function, which takes no
+// arguments and returns nothing, and checks that afterwards no more than
+// the expected number of goroutines, an int, are running, as counted by
+// runtime.NumGoroutine. The count includes the goroutines of the test
+// framework and of any tests running in parallel, so the bound is
+// usually found by counting before the call. As goroutines told to stop
+// may take a moment to exit, the count is taken again for a short time
+// before the check fails. A failure includes the stacks of all the
+// goroutines.
+//
+//	before := runtime.NumGoroutine()
+//	t.Check(func() { pool.Run(jobs) }, checkers.NumGoroutineAtMost, before)
+var NumGoroutineAtMost Checker = numGoroutineAtMost{}
+
+func (numGoroutineAtMost) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if len(extras) == 0 {
+		return errors.New("missing 'expected' value")
+	}
+	expected, extras := extras[0], extras[1:]
+	if err := unexpectedExtras("NumGoroutineAtMost", extras); err != nil {
+		return err
+	}
+	limit, ok := expected.(int)
+	if !ok {
+		return fmt.Errorf("expected value must be an int, got %s", describeType(expected))
+	}
+	f, ok := obtained.(func())
+	if !ok || f == nil {
+		return fmt.Errorf("NumGoroutineAtMost checker expected a func(), obtained was %s", describeType(obtained))
+	}
+	f()
+	deadline := time.Now().Add(goroutineGracePeriod)
+	n := runtime.NumGoroutine()
+	for n > limit && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+		n = runtime.NumGoroutine()
+	}
+	if n <= limit {
+		return nil
+	}
+	stacks := allStacks()
+	return failf("%d goroutines running, expected at most %d\ngoroutines:\n%s", n, limit, stacks)
+}
+
+// allStacks returns the stacks of all goroutines, as formatted by
+// runtime.Stack.
+func allStacks() string {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return string(buf[:n])
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
@@ -0,0 +1,78 @@
+// Add a copyright
+// Add a licence
+
+package checkers_test
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/howbazaar/checkers"
+)
+
+func TestNumGoroutineAtMost(t *testing.T) {
+	stop := make(chan struct{})
+	stopped := make(chan struct{})
+	defer func() {
+		close(stop)
+		<-stopped
+	}()
+	before := runtime.NumGoroutine()
+	for _, test := range []struct {
+		description string
+		obtained    interface{}
+		extras      []interface{}
+		err         string
+	}{
+		{
+			description: "no goroutines left",
+			obtained:    func() {},
+			extras:      []interface{}{before},
+		}, {
+			description: "goroutine exiting",
+			obtained: func() {
+				go time.Sleep(10 * time.Millisecond)
+			},
+			extras: []interface{}{before},
+		}, {
+			description: "goroutine left running",
+			obtained: func() {
+				go func() {
+					defer close(stopped)
+					<-stop
+				}()
+			},
+			extras: []interface{}{before},
+			err:    `\d+ goroutines running, expected at most \d+\ngoroutines:\ngoroutine .*TestNumGoroutineAtMost.*`,
+		}, {
+			description: "with comment",
+			obtained:    func() {},
+			extras:      []interface{}{0, checkers.Commentf("pool")},
+			err:         `\d+ goroutines running, expected at most 0\n.*\ncomment: pool`,
+		}, {
+			description: "not a function",
+			obtained:    42,
+			extras:      []interface{}{1},
+			err:         `NumGoroutineAtMost checker expected a func\(\), obtained was type int`,
+		}, {
+			description: "bound not an int",
+			obtained:    func() {},
+			extras:      []interface{}{"1"},
+			err:         `expected value must be an int, got type string`,
+		},
+	} {
+		err := checkers.NumGoroutineAtMost.Check(test.obtained, test.extras...)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+		} else {
+			if test.err == "" {
+				t.Errorf("%s: unexpected error: %v", test.description, err)
+			} else if matchErr := checkers.Matches.Check(err.Error(), "(?s)"+test.err); matchErr != nil {
+				t.Errorf("%s: error mismatch: %v", test.description, matchErr)
+			}
+		}
+	}
+}
@@ -0,0 +1,138 @@
+// Add a copyright
+// Add a licence
+
+// Package grpctest provides an in-process gRPC server fixture, and
+// checkers for gRPC responses, so that gRPC services can be tested in
+// the same style as other code using the checkers package. It is a
+// separate module so that only code using it depends on gRPC.
+package grpctest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/howbazaar/checkers"
+)
+
+// bufferSize is the size of the in-memory connection buffers.
+const bufferSize = 1 << 20
+
+// Server is a gRPC server listening on an in-memory connection.
+type Server struct {
+	*grpc.Server
+	listener *bufconn.Listener
+}
+
+// NewServer starts a gRPC server that listens on an in-memory
+// connection, with the services added by register. The server is
+// stopped when the test is cleaned up, so it can be started in a suite's
+// SetUpTest method.
+//
+//	server := grpctest.NewServer(t, func(s *grpc.Server) {
+//		pb.RegisterGreeterServer(s, &greeter{})
+//	})
+//	client := pb.NewGreeterClient(server.Dial(t))
+func NewServer(tb testing.TB, register func(*grpc.Server), options ...grpc.ServerOption) *Server {
+	tb.Helper()
+	s := &Server{
+		Server:   grpc.NewServer(options...),
+		listener: bufconn.Listen(bufferSize),
+	}
+	register(s.Server)
+	go s.Serve(s.listener)
+	tb.Cleanup(s.Stop)
+	return s
+}
+
+// Dial returns a client connection to the server, which is closed when
+// the test is cleaned up.
+func (s *Server) Dial(tb testing.TB, options ...grpc.DialOption) *grpc.ClientConn {
+	tb.Helper()
+	options = append([]grpc.DialOption{
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return s.listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	}, options...)
+	conn, err := grpc.Dial("bufnet", options...)
+	if err != nil {
+		tb.Fatalf("unable to dial server: %v", err)
+	}
+	tb.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+type protoEquals struct{}
+
+// ProtoEquals checker checks that the obtained proto.Message is equal to
+// the expected one, as determined by proto.Equal.
+var ProtoEquals checkers.Checker = protoEquals{}
+
+func (protoEquals) Check(obtained interface{}, extras ...interface{}) error {
+	if len(extras) == 0 {
+		return errors.New("missing 'expected' value")
+	}
+	expected, extras := extras[0], extras[1:]
+	if len(extras) != 0 {
+		return fmt.Errorf("too many arguments to checker ProtoEquals, unexpected %T(%#v)", extras[0], extras[0])
+	}
+	obtainedMessage, ok := obtained.(proto.Message)
+	if !ok {
+		return fmt.Errorf("ProtoEquals checker expected proto.Message, obtained was %T", obtained)
+	}
+	expectedMessage, ok := expected.(proto.Message)
+	if !ok {
+		return fmt.Errorf("expected value must be a proto.Message, got %T", expected)
+	}
+	if proto.Equal(obtainedMessage, expectedMessage) {
+		return nil
+	}
+	return fmt.Errorf("mismatch:\n\tobtained: %T{%s}\n\texpected: %T{%s}",
+		obtained, prototext.MarshalOptions{}.Format(obtainedMessage), expected, prototext.MarshalOptions{}.Format(expectedMessage))
+}
+
+type hasStatusCode struct{}
+
+// HasStatusCode checker checks that the obtained error has the expected
+// gRPC status code. A nil error has the code OK.
+var HasStatusCode checkers.Checker = hasStatusCode{}
+
+func (hasStatusCode) Check(obtained interface{}, extras ...interface{}) error {
+	if len(extras) == 0 {
+		return errors.New("missing 'expected' value")
+	}
+	expected, extras := extras[0], extras[1:]
+	if len(extras) != 0 {
+		return fmt.Errorf("too many arguments to checker HasStatusCode, unexpected %T(%#v)", extras[0], extras[0])
+	}
+	var err error
+	if obtained != nil {
+		var ok bool
+		if err, ok = obtained.(error); !ok {
+			return fmt.Errorf("HasStatusCode checker expected error, obtained was %T", obtained)
+		}
+	}
+	code, ok := expected.(codes.Code)
+	if !ok {
+		return fmt.Errorf("expected value must be a codes.Code, got %T", expected)
+	}
+	s := status.Convert(err)
+	if s.Code() == code {
+		return nil
+	}
+	if s.Message() == "" {
+		return fmt.Errorf("expected status code %v, got %v", code, s.Code())
+	}
+	return fmt.Errorf("expected status code %v, got %v: %s", code, s.Code(), s.Message())
+}
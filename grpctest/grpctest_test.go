@@ -0,0 +1,150 @@
+// Add a copyright
+// Add a licence
+
+package grpctest_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+
+	"github.com/howbazaar/checkers"
+	"github.com/howbazaar/checkers/grpctest"
+)
+
+type healthSuite struct {
+	checkers.Test
+	client healthpb.HealthClient
+}
+
+func (s *healthSuite) SetUpTest() {
+	server := grpctest.NewServer(s.TB, func(gs *grpc.Server) {
+		h := health.NewServer()
+		h.SetServingStatus("ready", healthpb.HealthCheckResponse_SERVING)
+		healthpb.RegisterHealthServer(gs, h)
+	})
+	s.client = healthpb.NewHealthClient(server.Dial(s.TB))
+}
+
+func (s *healthSuite) TestServing() {
+	resp, err := s.client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "ready"})
+	s.Assert(err, grpctest.HasStatusCode, codes.OK)
+	s.Assert(resp, grpctest.ProtoEquals, &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING})
+}
+
+func (s *healthSuite) TestUnknownService() {
+	_, err := s.client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "unknown"})
+	s.Assert(err, grpctest.HasStatusCode, codes.NotFound)
+}
+
+func TestHealthSuite(t *testing.T) {
+	checkers.RunSuite(t, &healthSuite{})
+}
+
+func TestProtoEquals(t *testing.T) {
+	serving := &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING}
+	for _, test := range []struct {
+		description string
+		obtained    interface{}
+		extras      []interface{}
+		err         string
+	}{
+		{
+			description: "equal",
+			obtained:    serving,
+			extras:      []interface{}{&healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING}},
+		}, {
+			description: "unequal",
+			obtained:    serving,
+			extras:      []interface{}{&healthpb.HealthCheckResponse{}},
+			// prototext deliberately varies its spacing.
+			err: `(?s)^mismatch:\n\tobtained: \*grpc_health_v1.HealthCheckResponse\{status:[\s\x{a0}]*SERVING\}\n\texpected: \*grpc_health_v1.HealthCheckResponse\{\}$`,
+		}, {
+			description: "not a message",
+			obtained:    "status",
+			extras:      []interface{}{serving},
+			err:         `^ProtoEquals checker expected proto.Message, obtained was string$`,
+		}, {
+			description: "too many",
+			obtained:    serving,
+			extras:      []interface{}{serving, serving},
+			err:         `^too many arguments to checker ProtoEquals, .*`,
+		},
+	} {
+		err := grpctest.ProtoEquals.Check(test.obtained, test.extras...)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+			continue
+		}
+		if test.err == "" {
+			t.Errorf("%s: unexpected error: %v", test.description, err)
+		} else if ok, _ := regexp.MatchString(test.err, err.Error()); !ok {
+			t.Errorf("%s: error mismatch: \n\tobtained: %q\n\texpected: %q", test.description, err.Error(), test.err)
+		}
+	}
+}
+
+func TestHasStatusCode(t *testing.T) {
+	for _, test := range []struct {
+		description string
+		obtained    interface{}
+		extras      []interface{}
+		err         string
+	}{
+		{
+			description: "nil is OK",
+			obtained:    nil,
+			extras:      []interface{}{codes.OK},
+		}, {
+			description: "matching code",
+			obtained:    status.Error(codes.NotFound, "no such thing"),
+			extras:      []interface{}{codes.NotFound},
+		}, {
+			description: "different code",
+			obtained:    status.Error(codes.NotFound, "no such thing"),
+			extras:      []interface{}{codes.OK},
+			err:         "expected status code OK, got NotFound: no such thing",
+		}, {
+			description: "nil is not an error code",
+			obtained:    nil,
+			extras:      []interface{}{codes.Internal},
+			err:         "expected status code Internal, got OK",
+		}, {
+			description: "plain error",
+			obtained:    errors.New("oops"),
+			extras:      []interface{}{codes.Unknown},
+		}, {
+			description: "not an error",
+			obtained:    42,
+			extras:      []interface{}{codes.OK},
+			err:         "HasStatusCode checker expected error, obtained was int",
+		}, {
+			description: "bad expected",
+			obtained:    nil,
+			extras:      []interface{}{0},
+			err:         "expected value must be a codes.Code, got int",
+		},
+	} {
+		err := grpctest.HasStatusCode.Check(test.obtained, test.extras...)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+			continue
+		}
+		if test.err == "" {
+			t.Errorf("%s: unexpected error: %v", test.description, err)
+		} else if err.Error() != test.err {
+			t.Errorf("%s: error mismatch: \n\tobtained: %q\n\texpected: %q", test.description, err.Error(), test.err)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/textproto"
+)
+
+// HeaderOption is an extra value understood by the HeaderEquals
+// checker.
+type HeaderOption struct {
+	subset bool
+}
+
+// HeaderSubset returns a HeaderOption that has only the keys of the
+// expected header checked, so that the obtained header may hold others.
+func HeaderSubset() HeaderOption {
+	return HeaderOption{subset: true}
+}
+
+type headerEquals struct{}
+
+// HeaderEquals checker checks that the obtained header has the same
+// keys as the expected header, without regard to case, with each key
+// holding the same values in any order. Either header may be an
+// http.Header, a textproto.MIMEHeader or a map[string][]string. A
+// HeaderSubset option may follow the expected value.
+//
+//	t.Check(resp.Header, checkers.HeaderEquals, http.Header{
+//		"content-type": {"application/json"},
+//	}, checkers.HeaderSubset())
+var HeaderEquals Checker = headerEquals{}
+
+func (headerEquals) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if len(extras) == 0 {
+		return errors.New("missing 'expected' value")
+	}
+	expected, extras := extras[0], extras[1:]
+	var option HeaderOption
+	for i, extra := range extras {
+		o, ok := extra.(HeaderOption)
+		if !ok {
+			return unexpectedExtras("HeaderEquals", extras[i:])
+		}
+		option.subset = option.subset || o.subset
+	}
+	want, ok := canonicalHeader(expected)
+	if !ok {
+		return fmt.Errorf("expected value must be a header, got %s", describeType(expected))
+	}
+	got, ok := canonicalHeader(obtained)
+	if !ok {
+		return fmt.Errorf("HeaderEquals checker expected a header, obtained was %s", describeType(obtained))
+	}
+	if option.subset {
+		for key := range got {
+			if _, ok := want[key]; !ok {
+				delete(got, key)
+			}
+		}
+	}
+	return MapOfSlicesEquivalent.Check(got, want)
+}
+
+// canonicalHeader returns a copy of the header with its keys in
+// canonical form, merging the values of keys that differ only in case.
+func canonicalHeader(value interface{}) (http.Header, bool) {
+	var header map[string][]string
+	switch value := value.(type) {
+	case http.Header:
+		header = value
+	case textproto.MIMEHeader:
+		header = value
+	case map[string][]string:
+		header = value
+	default:
+		return nil, false
+	}
+	canonical := make(http.Header, len(header))
+	for key, values := range header {
+		key = textproto.CanonicalMIMEHeaderKey(key)
+		canonical[key] = append(canonical[key], values...)
+	}
+	return canonical, true
+}
@@ -0,0 +1,91 @@
+// Add a copyright
+// Add a licence
+
+package checkers_test
+
+import (
+	"net/http"
+	"net/textproto"
+	"testing"
+
+	"github.com/howbazaar/checkers"
+)
+
+func TestHeaderEquals(t *testing.T) {
+	header := http.Header{
+		"Content-Type": {"application/json"},
+		"Accept":       {"text/html", "application/json"},
+		"X-Request-Id": {"42"},
+	}
+	for _, test := range []struct {
+		description string
+		obtained    interface{}
+		extras      []interface{}
+		err         string
+	}{
+		{
+			description: "equal",
+			obtained:    header,
+			extras: []interface{}{http.Header{
+				"content-type": {"application/json"},
+				"ACCEPT":       {"application/json", "text/html"},
+				"x-request-id": {"42"},
+			}},
+		}, {
+			description: "mime header",
+			obtained:    textproto.MIMEHeader{"Accept": {"a", "b"}},
+			extras:      []interface{}{map[string][]string{"accept": {"b", "a"}}},
+		}, {
+			description: "keys differing in case are merged",
+			obtained:    map[string][]string{"accept": {"a"}, "Accept": {"b"}},
+			extras:      []interface{}{http.Header{"Accept": {"b", "a"}}},
+		}, {
+			description: "subset",
+			obtained:    header,
+			extras:      []interface{}{http.Header{"content-type": {"application/json"}}, checkers.HeaderSubset()},
+		}, {
+			description: "extra key",
+			obtained:    header,
+			extras:      []interface{}{http.Header{"content-type": {"application/json"}}},
+			err:         `mismatch at top level: length mismatch, 3 vs 1; .*`,
+		}, {
+			description: "subset missing key",
+			obtained:    header,
+			extras:      []interface{}{http.Header{"Etag": {"abc"}}, checkers.HeaderSubset()},
+			err:         `mismatch at top level: length mismatch, 0 vs 1; obtained http.Header{}; expected http.Header{"Etag": \[\]string{"abc"}}\ndiff.*`,
+		}, {
+			description: "subset different values",
+			obtained:    header,
+			extras:      []interface{}{http.Header{"accept": {"text/html"}}, checkers.HeaderSubset()},
+			err:         `mismatch at \["Accept"\]: length mismatch, 2 vs 1; .*`,
+		}, {
+			description: "not a header",
+			obtained:    map[string]string{"Accept": "a"},
+			extras:      []interface{}{http.Header{}},
+			err:         `HeaderEquals checker expected a header, obtained was type map\[string\]string`,
+		}, {
+			description: "expected not a header",
+			obtained:    header,
+			extras:      []interface{}{"Accept: a"},
+			err:         `expected value must be a header, got type string`,
+		}, {
+			description: "unexpected extra",
+			obtained:    header,
+			extras:      []interface{}{header, checkers.CollapseSpace()},
+			err:         `too many arguments to checker HeaderEquals, unexpected checkers.TextOption.*`,
+		},
+	} {
+		err := checkers.HeaderEquals.Check(test.obtained, test.extras...)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+		} else {
+			if test.err == "" {
+				t.Errorf("%s: unexpected error: %v", test.description, err)
+			} else if matchErr := checkers.Matches.Check(err.Error(), "(?s)"+test.err); matchErr != nil {
+				t.Errorf("%s: error mismatch: %v", test.description, matchErr)
+			}
+		}
+	}
+}
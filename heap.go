@@ -0,0 +1,37 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"runtime"
+)
+
+// CheckHeapGrowthBelow runs f and checks that the heap has grown by
+// less than limit bytes once it has returned, marking the test as a
+// failure if not. Garbage is collected before and after f runs, so only
+// the memory that f leaves reachable counts, and not what it allocates
+// and drops. Other goroutines allocating at the same time are counted
+// too, so the check is a coarse guard against gross regressions, such
+// as caches that are never emptied, rather than a measurement, and
+// tests using it should not run in parallel.
+//
+//	t.CheckHeapGrowthBelow(1<<20, func() {
+//		index.Load(records)
+//		index.Reset()
+//	})
+func (t *Test) CheckHeapGrowthBelow(limit int64, f func()) bool {
+	tb := t.testingTB()
+	tb.Helper()
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	f()
+	runtime.GC()
+	runtime.ReadMemStats(&after)
+	if growth := int64(after.HeapAlloc) - int64(before.HeapAlloc); growth >= limit {
+		tb.Errorf("heap grew by %d bytes, expected less than %d", growth, limit)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,46 @@
+// Add a copyright
+// Add a licence
+
+package checkers_test
+
+import (
+	"testing"
+
+	"github.com/howbazaar/checkers"
+)
+
+var retained [][]byte
+
+func TestCheckHeapGrowthBelow(t *testing.T) {
+	defer func() { retained = nil }()
+	for _, test := range []struct {
+		description string
+		f           func()
+		errors      string
+	}{
+		{
+			description: "garbage",
+			f: func() {
+				for i := 0; i < 100; i++ {
+					_ = make([]byte, 1<<20)
+				}
+			},
+		}, {
+			description: "retained",
+			f: func() {
+				retained = append(retained, make([]byte, 16<<20))
+			},
+			errors: `heap grew by \d+ bytes, expected less than 4194304`,
+		},
+	} {
+		r := checkers.NewRecordingTB()
+		var ok bool
+		r.Run(func(tb testing.TB) {
+			ok = (&checkers.Test{TB: tb}).CheckHeapGrowthBelow(4<<20, test.f)
+		})
+		if ok != (test.errors == "") {
+			t.Errorf("%s: unexpected result %v", test.description, ok)
+		}
+		checkRecorded(t, test.description, r.Errors(), test.errors)
+	}
+}
@@ -0,0 +1,293 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// updateHTTPEnv is the environment variable that, when set to a non
+// empty value, has HTTPRecorders send requests to the real servers and
+// save the responses as the new golden file.
+const updateHTTPEnv = "CHECKERS_UPDATE_HTTP"
+
+// RecordedRequest is a request sent through an HTTPRecorder.
+type RecordedRequest struct {
+	Method string
+	URL    string
+	Header http.Header
+	Body   []byte
+}
+
+// httpInteraction is a request and its response, as saved in golden
+// files.
+type httpInteraction struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body"`
+
+	// used records that the response has been replayed.
+	used bool
+}
+
+// HTTPRecorder is an http.RoundTripper that records the requests sent
+// through it, and responds to them with canned responses rather than
+// sending them on. Use it as the Transport of the http.Client used by
+// the code under test.
+type HTTPRecorder struct {
+	t         *Test
+	golden    string
+	update    bool
+	transport http.RoundTripper
+
+	mu           sync.Mutex
+	requests     []RecordedRequest
+	interactions []*httpInteraction
+}
+
+// RecordHTTP returns a new HTTPRecorder. If golden is not empty, it is
+// the path of a file of canned responses, such as one in testdata,
+// that are replayed for requests with the same method and URL. Repeated
+// requests are given the responses in the order they are in the file.
+//
+// When the CHECKERS_UPDATE_HTTP environment variable is set, requests
+// are instead sent to the real servers, and the responses are saved to
+// the golden file when the test is cleaned up.
+func (t *Test) RecordHTTP(golden string) *HTTPRecorder {
+	tb := t.testingTB()
+	tb.Helper()
+	r := &HTTPRecorder{
+		t:         t,
+		golden:    golden,
+		update:    golden != "" && os.Getenv(updateHTTPEnv) != "",
+		transport: http.DefaultTransport,
+	}
+	switch {
+	case r.update:
+		tb.Cleanup(r.save)
+	case golden != "":
+		data, err := ioutil.ReadFile(golden)
+		if err != nil {
+			tb.Fatalf("unable to read HTTP golden file: %v", err)
+		}
+		if err := json.Unmarshal(data, &r.interactions); err != nil {
+			tb.Fatalf("unable to parse HTTP golden file %q: %v", golden, err)
+		}
+	}
+	return r
+}
+
+// Client returns an http.Client that uses the recorder.
+func (r *HTTPRecorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Respond adds a canned response for requests with the method and URL,
+// after any already added for them.
+func (r *HTTPRecorder) Respond(method, url string, status int, body string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.interactions = append(r.interactions, &httpInteraction{
+		Method: method,
+		URL:    url,
+		Status: status,
+		Body:   body,
+	})
+}
+
+// Requests returns the requests sent through the recorder, in the order
+// they were sent.
+func (r *HTTPRecorder) Requests() []RecordedRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]RecordedRequest(nil), r.requests...)
+}
+
+// RoundTrip records the request and returns the canned response for it.
+func (r *HTTPRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	url := req.URL.String()
+	r.mu.Lock()
+	r.requests = append(r.requests, RecordedRequest{
+		Method: req.Method,
+		URL:    url,
+		Header: req.Header.Clone(),
+		Body:   body,
+	})
+	r.mu.Unlock()
+
+	if r.update {
+		return r.forward(req, body)
+	}
+	interaction := r.cannedResponse(req.Method, url)
+	if interaction == nil {
+		return nil, fmt.Errorf("no canned response for %s %s", req.Method, url)
+	}
+	header := interaction.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", interaction.Status, http.StatusText(interaction.Status)),
+		StatusCode:    interaction.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(strings.NewReader(interaction.Body)),
+		ContentLength: int64(len(interaction.Body)),
+		Request:       req,
+	}, nil
+}
+
+// cannedResponse returns the first unused response for the request, or
+// the last used one if they have all been used.
+func (r *HTTPRecorder) cannedResponse(method, url string) *httpInteraction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var last *httpInteraction
+	for _, interaction := range r.interactions {
+		if interaction.Method != method || interaction.URL != url {
+			continue
+		}
+		if !interaction.used {
+			interaction.used = true
+			return interaction
+		}
+		last = interaction
+	}
+	return last
+}
+
+// forward sends the request on to the real server, keeping the response
+// to save in the golden file.
+func (r *HTTPRecorder) forward(req *http.Request, body []byte) (*http.Response, error) {
+	out := req.Clone(req.Context())
+	out.Body = ioutil.NopCloser(bytes.NewReader(body))
+	resp, err := r.transport.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.interactions = append(r.interactions, &httpInteraction{
+		Method: req.Method,
+		URL:    req.URL.String(),
+		Status: resp.StatusCode,
+		Header: resp.Header.Clone(),
+		Body:   string(respBody),
+	})
+	return resp, nil
+}
+
+// save writes the interactions to the golden file.
+func (r *HTTPRecorder) save() {
+	r.mu.Lock()
+	data, err := json.MarshalIndent(r.interactions, "", "  ")
+	r.mu.Unlock()
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(r.golden), 0755)
+	}
+	if err == nil {
+		err = ioutil.WriteFile(r.golden, append(data, '\n'), 0644)
+	}
+	if err != nil {
+		r.t.testingTB().Errorf("unable to save HTTP golden file: %v", err)
+	}
+}
+
+type httpRequested struct{}
+
+// HTTPRequested checker checks that the obtained *HTTPRecorder was sent
+// a request with the method and URL given by the first two extra
+// values. If there is a third, the body of the request must be equal
+// to it if it is a string or []byte, or pass it if it is a Checker.
+//
+//	t.Check(recorder, checkers.HTTPRequested, "POST", "https://example.com/items", checkers.MatchesRegexp(itemJSON))
+var HTTPRequested Checker = httpRequested{}
+
+func (httpRequested) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if len(extras) < 2 {
+		return errors.New("missing 'method' and 'url' values")
+	}
+	methodArg, urlArg, extras := extras[0], extras[1], extras[2:]
+	var body interface{}
+	if len(extras) > 0 {
+		body, extras = extras[0], extras[1:]
+	}
+	if err := unexpectedExtras("HTTPRequested", extras); err != nil {
+		return err
+	}
+	recorder, ok := obtained.(*HTTPRecorder)
+	if !ok {
+		return fmt.Errorf("HTTPRequested checker expected *HTTPRecorder, obtained was %s", describeType(obtained))
+	}
+	method, ok := methodArg.(string)
+	if !ok {
+		return errors.New("method must be a string")
+	}
+	url, ok := urlArg.(string)
+	if !ok {
+		return errors.New("url must be a string")
+	}
+	var bodyMatches func([]byte) bool
+	switch body := body.(type) {
+	case nil:
+		bodyMatches = func([]byte) bool { return true }
+	case string:
+		bodyMatches = func(b []byte) bool { return string(b) == body }
+	case []byte:
+		bodyMatches = func(b []byte) bool { return bytes.Equal(b, body) }
+	case Checker:
+		bodyMatches = func(b []byte) bool { return body.Check(string(b)) == nil }
+	default:
+		return fmt.Errorf("body must be a string, []byte or Checker, got %s", describeType(body))
+	}
+
+	requests := recorder.Requests()
+	for _, request := range requests {
+		if request.Method == method && request.URL == url && bodyMatches(request.Body) {
+			return nil
+		}
+	}
+	return lazyFailure(func() string {
+		lines := []string{fmt.Sprintf("no matching %s %s request", method, url)}
+		if len(requests) == 0 {
+			lines = append(lines, "no requests")
+		} else {
+			lines = append(lines, "requests:")
+		}
+		for _, request := range requests {
+			lines = append(lines, fmt.Sprintf("\t%s %s %q", request.Method, request.URL, request.Body))
+		}
+		return strings.Join(lines, "\n")
+	})
+}
@@ -0,0 +1,153 @@
+// Add a copyright
+// Add a licence
+
+package checkers_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/howbazaar/checkers"
+)
+
+func get(t *testing.T, client *http.Client, url string) (int, string) {
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, string(body)
+}
+
+func TestRecordHTTP(t *testing.T) {
+	ct := &checkers.Test{TB: t}
+	recorder := ct.RecordHTTP("")
+	recorder.Respond("GET", "https://example.com/a", 200, "a")
+	recorder.Respond("POST", "https://example.com/b", 201, "")
+	client := recorder.Client()
+
+	status, body := get(t, client, "https://example.com/a")
+	if status != 200 || body != "a" {
+		t.Errorf("unexpected response: %d %q", status, body)
+	}
+	resp, err := client.Post("https://example.com/b", "application/json", strings.NewReader(`{"id":1}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != 201 {
+		t.Errorf("unexpected status: %d", resp.StatusCode)
+	}
+	_, err = client.Get("https://example.com/c")
+	if err == nil || !strings.Contains(err.Error(), "no canned response for GET https://example.com/c") {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	requests := recorder.Requests()
+	if len(requests) != 3 || requests[1].Header.Get("Content-Type") != "application/json" {
+		t.Errorf("unexpected requests: %#v", requests)
+	}
+
+	for _, test := range []struct {
+		description string
+		extras      []interface{}
+		err         string
+	}{
+		{
+			description: "requested",
+			extras:      []interface{}{"GET", "https://example.com/a"},
+		}, {
+			description: "requested with body",
+			extras:      []interface{}{"POST", "https://example.com/b", `{"id":1}`},
+		}, {
+			description: "requested with body checker",
+			extras:      []interface{}{"POST", "https://example.com/b", checkers.MatchesRegexp(regexp.MustCompile(`"id":\d+`))},
+		}, {
+			description: "body differs",
+			extras:      []interface{}{"POST", "https://example.com/b", []byte(`{"id":2}`)},
+			err: "no matching POST https://example.com/b request\nrequests:\n" +
+				"\tGET https://example.com/a \"\"\n" +
+				"\tPOST https://example.com/b \"{\\\"id\\\":1}\"\n" +
+				"\tGET https://example.com/c \"\"",
+		}, {
+			description: "bad body",
+			extras:      []interface{}{"POST", "https://example.com/b", 1},
+			err:         "body must be a string, []byte or Checker, got type int",
+		}, {
+			description: "too many",
+			extras:      []interface{}{"POST", "https://example.com/b", "", ""},
+			err:         `too many arguments to checker HTTPRequested, unexpected string("")`,
+		},
+	} {
+		err := checkers.HTTPRequested.Check(recorder, test.extras...)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+		} else {
+			if test.err == "" {
+				t.Errorf("%s: unexpected error: %v", test.description, err)
+			} else {
+				if err.Error() != test.err {
+					t.Errorf("%s: error mismatch: \n\tobtained: %q\n\texpected: %q", test.description, err.Error(), test.err)
+				}
+			}
+		}
+	}
+}
+
+func TestRecordHTTPReplay(t *testing.T) {
+	ct := &checkers.Test{TB: t}
+	client := ct.RecordHTTP(filepath.Join("testdata", "http_golden.json")).Client()
+	for _, expected := range []string{"200 [1, 2]", "503 busy", "503 busy"} {
+		status, body := get(t, client, "https://example.com/items")
+		if obtained := fmt.Sprintf("%d %s", status, body); obtained != expected {
+			t.Errorf("expected %q, got %q", expected, obtained)
+		}
+	}
+}
+
+func TestRecordHTTPUpdate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "hello %s", r.URL.Path)
+	}))
+	defer server.Close()
+	dir, err := ioutil.TempDir("", "checkers")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	golden := filepath.Join(dir, "testdata", "golden.json")
+
+	os.Setenv("CHECKERS_UPDATE_HTTP", "1")
+	r := checkers.NewRecordingTB()
+	r.Run(func(tb testing.TB) {
+		ct := &checkers.Test{TB: tb}
+		client := ct.RecordHTTP(golden).Client()
+		if _, body := get(t, client, server.URL+"/world"); body != "hello /world" {
+			t.Errorf("unexpected body: %q", body)
+		}
+	})
+	os.Unsetenv("CHECKERS_UPDATE_HTTP")
+	if r.Failed() {
+		t.Fatalf("recording failed: %v", r.Errors())
+	}
+
+	server.Close()
+	ct := &checkers.Test{TB: t}
+	client := ct.RecordHTTP(golden).Client()
+	if status, body := get(t, client, server.URL+"/world"); status != 200 || body != "hello /world" {
+		t.Errorf("unexpected replay: %d %q", status, body)
+	}
+}
@@ -0,0 +1,140 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+type requestHasQuery struct {
+	key     string
+	checker Checker
+}
+
+// RequestHasQuery returns a checker that checks that the URL of the
+// obtained *http.Request has the query parameter key. If checker is not
+// nil, the parameter's first value, a string, must also pass it, with
+// any extra values given to the returned checker passed on to it.
+//
+//	t.Check(req, checkers.RequestHasQuery("page", checkers.Equals), "2")
+//	t.Check(req, checkers.RequestHasQuery("q", checkers.Matches), "go.*")
+//	t.Check(req, checkers.RequestHasQuery("debug", nil))
+func RequestHasQuery(key string, checker Checker) Checker {
+	return requestHasQuery{key: key, checker: checker}
+}
+
+func (c requestHasQuery) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if c.checker == nil {
+		if err := unexpectedExtras("RequestHasQuery", extras); err != nil {
+			return err
+		}
+	}
+	req, ok := obtained.(*http.Request)
+	if !ok || req == nil || req.URL == nil {
+		return fmt.Errorf("RequestHasQuery checker expected an *http.Request with a URL, obtained was %s", describe(obtained))
+	}
+	query := req.URL.Query()
+	values, ok := query[c.key]
+	if !ok {
+		return lazyFailure(func() string {
+			keys := make([]string, 0, len(query))
+			for key := range query {
+				keys = append(keys, fmt.Sprintf("%q", key))
+			}
+			sort.Strings(keys)
+			return fmt.Sprintf("query parameter %q not found in %q with parameters [%s]",
+				c.key, req.URL.RawQuery, strings.Join(keys, ", "))
+		})
+	}
+	if c.checker == nil {
+		return nil
+	}
+	if err := c.checker.Check(values[0], extras...); err != nil {
+		return lazyFailure(func() string {
+			return fmt.Sprintf("query parameter %q: %v", c.key, err)
+		})
+	}
+	return nil
+}
+
+type requestFormEquals struct{}
+
+// RequestFormEquals checker checks that the form values of the obtained
+// *http.Request, from both its URL's query and a body of type
+// application/x-www-form-urlencoded, are the expected values, which may
+// be a url.Values, a map[string][]string or, for keys with a single
+// value, a map[string]string. The request's body is read and replaced,
+// so that the code under test can still read it.
+//
+//	t.Check(req, checkers.RequestFormEquals, map[string]string{
+//		"grant_type": "client_credentials",
+//		"scope":      "read",
+//	})
+var RequestFormEquals Checker = requestFormEquals{}
+
+func (requestFormEquals) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if len(extras) == 0 {
+		return errors.New("missing 'expected' value")
+	}
+	expected, extras := extras[0], extras[1:]
+	if err := unexpectedExtras("RequestFormEquals", extras); err != nil {
+		return err
+	}
+	var want url.Values
+	switch expected := expected.(type) {
+	case url.Values:
+		want = expected
+	case map[string][]string:
+		want = expected
+	case map[string]string:
+		want = make(url.Values)
+		for key, value := range expected {
+			want[key] = []string{value}
+		}
+	default:
+		return fmt.Errorf("expected value must be a url.Values, map[string][]string or map[string]string, got %s", describeType(expected))
+	}
+	req, ok := obtained.(*http.Request)
+	if !ok || req == nil || req.URL == nil {
+		return fmt.Errorf("RequestFormEquals checker expected an *http.Request with a URL, obtained was %s", describe(obtained))
+	}
+	got, err := requestForm(req)
+	if err != nil {
+		return err
+	}
+	return DeepEquals.Check(got, want)
+}
+
+// requestForm parses the form of a copy of the request, replacing the
+// body of the request so that it can be read again.
+func requestForm(req *http.Request) (url.Values, error) {
+	parsed := *req
+	parsed.Form = nil
+	parsed.PostForm = nil
+	parsed.Body = http.NoBody
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("unable to read request body: %v", err)
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+		parsed.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+	if err := parsed.ParseForm(); err != nil {
+		return nil, failf("cannot parse form: %v", err)
+	}
+	return parsed.Form, nil
+}
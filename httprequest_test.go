@@ -0,0 +1,125 @@
+// Add a copyright
+// Add a licence
+
+package checkers_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/howbazaar/checkers"
+)
+
+func TestRequestCheckers(t *testing.T) {
+	get, err := http.NewRequest("GET", "https://example.com/search?q=gophers&page=2&page=3", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	post, err := http.NewRequest("POST", "https://example.com/token?client=cli", strings.NewReader("grant_type=client_credentials&scope=read"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	post.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	for _, test := range []struct {
+		description string
+		checker     checkers.Checker
+		obtained    interface{}
+		extras      []interface{}
+		err         string
+	}{
+		{
+			description: "has query",
+			checker:     checkers.RequestHasQuery("q", nil),
+			obtained:    get,
+		}, {
+			description: "query value",
+			checker:     checkers.RequestHasQuery("page", checkers.Equals),
+			obtained:    get,
+			extras:      []interface{}{"2"},
+		}, {
+			description: "query value mismatch",
+			checker:     checkers.RequestHasQuery("q", checkers.Matches),
+			obtained:    get,
+			extras:      []interface{}{"rust.*", checkers.Commentf("search")},
+			err:         `query parameter "q": "gophers" did not match pattern "^rust.*$"` + "\ncomment: search",
+		}, {
+			description: "missing query",
+			checker:     checkers.RequestHasQuery("sort", nil),
+			obtained:    get,
+			err:         `query parameter "sort" not found in "q=gophers&page=2&page=3" with parameters ["page", "q"]`,
+		}, {
+			description: "unexpected extra without checker",
+			checker:     checkers.RequestHasQuery("q", nil),
+			obtained:    get,
+			extras:      []interface{}{"gophers"},
+			err:         `too many arguments to checker RequestHasQuery, unexpected string("gophers")`,
+		}, {
+			description: "query of non-request",
+			checker:     checkers.RequestHasQuery("q", nil),
+			obtained:    get.URL,
+			err:         `RequestHasQuery checker expected an *http.Request with a URL, obtained was *url.URL value https://example.com/search?q=gophers&page=2&page=3`,
+		}, {
+			description: "form",
+			checker:     checkers.RequestFormEquals,
+			obtained:    post,
+			extras: []interface{}{map[string]string{
+				"client":     "cli",
+				"grant_type": "client_credentials",
+				"scope":      "read",
+			}},
+		}, {
+			description: "query form",
+			checker:     checkers.RequestFormEquals,
+			obtained:    get,
+			extras:      []interface{}{url.Values{"q": {"gophers"}, "page": {"2", "3"}}},
+		}, {
+			description: "form mismatch",
+			checker:     checkers.RequestFormEquals,
+			obtained:    post,
+			extras: []interface{}{map[string][]string{
+				"client":     {"cli"},
+				"grant_type": {"password"},
+				"scope":      {"read"},
+			}},
+			err: `mismatch at ["grant_type"][0]: unequal; obtained "client_credentials"; expected "password"` + "\n" +
+				"diff (-obtained +expected):\n" +
+				" ...\n" +
+				" \t\t\"cli\",\n" +
+				" \t},\n" +
+				" \t\"grant_type\": []string{\n" +
+				"-\t\t\"client_credentials\",\n" +
+				"+\t\t\"password\",\n" +
+				" \t},\n" +
+				" \t\"scope\": []string{\n" +
+				" \t\t\"read\",\n" +
+				" ...",
+		}, {
+			description: "form expected of wrong type",
+			checker:     checkers.RequestFormEquals,
+			obtained:    post,
+			extras:      []interface{}{"scope=read"},
+			err:         `expected value must be a url.Values, map[string][]string or map[string]string, got type string`,
+		},
+	} {
+		err := test.checker.Check(test.obtained, test.extras...)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+		} else {
+			if test.err == "" {
+				t.Errorf("%s: unexpected error: %v", test.description, err)
+			} else if err.Error() != test.err {
+				t.Errorf("%s: error mismatch: \n\tobtained: %q\n\texpected: %q", test.description, err.Error(), test.err)
+			}
+		}
+	}
+	// The body is left to be read again.
+	body, err := ioutil.ReadAll(post.Body)
+	if err != nil || string(body) != "grant_type=client_credentials&scope=read" {
+		t.Errorf("body not restored, got %q, %v", body, err)
+	}
+}
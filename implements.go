@@ -0,0 +1,117 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+type interfaceFullyImplemented struct{}
+
+// InterfaceFullyImplemented checker checks that the obtained value's
+// type, or the type itself when given a reflect.Type, implements the
+// expected interface, given as a nil pointer to it such as
+// (*io.ReadCloser)(nil) or as a reflect.Type, with every method of the
+// interface declared by the type itself rather than promoted from an
+// embedded field. This catches mocks that embed the interface they
+// mirror, and so satisfy it with methods that panic. A failure lists
+// which methods are missing, declared and promoted.
+//
+// Methods are taken to be promoted when the Go runtime reports that
+// their code was generated by the compiler rather than written.
+var InterfaceFullyImplemented Checker = interfaceFullyImplemented{}
+
+func (interfaceFullyImplemented) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if len(extras) == 0 {
+		return errors.New("missing 'expected' value")
+	}
+	expected, extras := extras[0], extras[1:]
+	if err := unexpectedExtras("InterfaceFullyImplemented", extras); err != nil {
+		return err
+	}
+	iface, ok := expected.(reflect.Type)
+	if !ok {
+		if t := reflect.TypeOf(expected); t != nil && t.Kind() == reflect.Ptr {
+			iface = t.Elem()
+		}
+	}
+	if iface == nil || iface.Kind() != reflect.Interface {
+		return fmt.Errorf("expected value must be a nil pointer to an interface or an interface type, got %s", describeType(expected))
+	}
+	t, ok := obtained.(reflect.Type)
+	if !ok {
+		t = reflect.TypeOf(obtained)
+	}
+	if t == nil || t.Kind() == reflect.Interface {
+		return fmt.Errorf("InterfaceFullyImplemented checker expected a concrete type, obtained was %s", describeType(obtained))
+	}
+
+	base := t
+	if base.Kind() == reflect.Ptr {
+		base = base.Elem()
+	}
+	var missing, declared, promoted []string
+	for i := 0; i < iface.NumMethod(); i++ {
+		name := iface.Method(i).Name
+		method, ok := t.MethodByName(name)
+		if !ok {
+			missing = append(missing, name)
+			continue
+		}
+		// Look the method up on the type it is declared for, as calling
+		// a value method through a pointer is also generated code.
+		if m, ok := base.MethodByName(name); ok {
+			method = m
+		} else if m, ok := reflect.PtrTo(base).MethodByName(name); ok {
+			method = m
+		}
+		if generatedMethod(method) {
+			promoted = append(promoted, name)
+		} else {
+			declared = append(declared, name)
+		}
+	}
+	if len(missing) == 0 && len(promoted) == 0 {
+		return nil
+	}
+	return lazyFailure(func() string {
+		var buf strings.Builder
+		if len(missing) > 0 {
+			fmt.Fprintf(&buf, "%s does not implement %s", t, iface)
+		} else {
+			fmt.Fprintf(&buf, "%s does not declare all the methods of %s", t, iface)
+		}
+		for _, methods := range []struct {
+			kind  string
+			names []string
+		}{
+			{"missing", missing},
+			{"declared", declared},
+			{"promoted from embedded fields", promoted},
+		} {
+			if len(methods.names) > 0 {
+				fmt.Fprintf(&buf, "\n\t%s: %s", methods.kind, strings.Join(methods.names, ", "))
+			}
+		}
+		return buf.String()
+	})
+}
+
+// generatedMethod reports whether the code of the method was generated
+// by the compiler, as it is for methods promoted from embedded fields.
+func generatedMethod(method reflect.Method) bool {
+	pc := method.Func.Pointer()
+	f := runtime.FuncForPC(pc)
+	if f == nil {
+		return false
+	}
+	file, _ := f.FileLine(pc)
+	return file == "<autogenerated>"
+}
@@ -0,0 +1,99 @@
+// Add a copyright
+// Add a licence
+
+package checkers_test
+
+import (
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/howbazaar/checkers"
+)
+
+// fullMock declares every method of io.ReadWriteCloser.
+type fullMock struct{}
+
+func (fullMock) Read([]byte) (int, error)   { return 0, nil }
+func (*fullMock) Write([]byte) (int, error) { return 0, nil }
+func (fullMock) Close() error               { return nil }
+
+// stubMock embeds the interface it mirrors, leaving methods undeclared.
+type stubMock struct {
+	io.ReadWriteCloser
+}
+
+func (*stubMock) Write([]byte) (int, error) { return 0, nil }
+
+type closeOnly struct{}
+
+func (closeOnly) Close() error { return nil }
+
+// embeddingMock has its Close method promoted from an embedded struct.
+type embeddingMock struct {
+	closeOnly
+}
+
+func TestInterfaceFullyImplemented(t *testing.T) {
+	for _, test := range []struct {
+		description string
+		obtained    interface{}
+		extras      []interface{}
+		err         string
+	}{
+		{
+			description: "declared",
+			obtained:    &fullMock{},
+			extras:      []interface{}{(*io.ReadWriteCloser)(nil)},
+		}, {
+			description: "type",
+			obtained:    reflect.TypeOf(&fullMock{}),
+			extras:      []interface{}{reflect.TypeOf((*io.Closer)(nil)).Elem()},
+		}, {
+			description: "value without pointer methods",
+			obtained:    fullMock{},
+			extras:      []interface{}{(*io.ReadWriteCloser)(nil)},
+			err: "checkers_test.fullMock does not implement io.ReadWriteCloser\n" +
+				"\tmissing: Write\n" +
+				"\tdeclared: Close, Read",
+		}, {
+			description: "embedded interface",
+			obtained:    &stubMock{},
+			extras:      []interface{}{(*io.ReadWriteCloser)(nil)},
+			err: "*checkers_test.stubMock does not declare all the methods of io.ReadWriteCloser\n" +
+				"\tdeclared: Write\n" +
+				"\tpromoted from embedded fields: Close, Read",
+		}, {
+			description: "embedded struct",
+			obtained:    embeddingMock{},
+			extras:      []interface{}{(*io.Closer)(nil)},
+			err: "checkers_test.embeddingMock does not declare all the methods of io.Closer\n" +
+				"\tpromoted from embedded fields: Close",
+		}, {
+			description: "not an interface",
+			obtained:    fullMock{},
+			extras:      []interface{}{fullMock{}},
+			err:         "expected value must be a nil pointer to an interface or an interface type, got type checkers_test.fullMock",
+		}, {
+			description: "nil obtained",
+			obtained:    nil,
+			extras:      []interface{}{(*io.Closer)(nil)},
+			err:         "InterfaceFullyImplemented checker expected a concrete type, obtained was nil",
+		},
+	} {
+		err := checkers.InterfaceFullyImplemented.Check(test.obtained, test.extras...)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+		} else {
+			if test.err == "" {
+				t.Errorf("%s: unexpected error: %v", test.description, err)
+			} else {
+				if err.Error() != test.err {
+					t.Errorf("%s: error mismatch: \n\tobtained: %q\n\texpected: %q", test.description, err.Error(), test.err)
+				}
+			}
+		}
+	}
+}
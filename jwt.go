@@ -0,0 +1,260 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rsa"
+	_ "crypto/sha256" // for crypto.SHA256
+	_ "crypto/sha512" // for crypto.SHA384 and crypto.SHA512
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// JWTOption is an extra value understood by the JWTHasClaims checker.
+type JWTOption struct {
+	key interface{}
+}
+
+// VerifyJWT returns a JWTOption that has the signature of the token
+// verified with the key: a []byte secret for the HS256, HS384 and HS512
+// algorithms, an *rsa.PublicKey for RS256, RS384, RS512, PS256, PS384
+// and PS512, an *ecdsa.PublicKey for ES256, ES384 and ES512, or an
+// ed25519.PublicKey for EdDSA.
+func VerifyJWT(key interface{}) JWTOption {
+	return JWTOption{key: key}
+}
+
+type jwtHasClaims struct{}
+
+// JWTHasClaims checker checks the claims of the obtained JSON Web Token,
+// which may be a string or []byte holding the token, or an *http.Request
+// with the token in its Authorization header as a bearer token. The
+// expected value is a map[string]interface{} of the claims to check,
+// keyed by name. A claim whose expected value is a Checker must pass
+// it, with numbers given to it as int where they are whole and float64
+// otherwise. A claim expected to be nil must be absent. Any other value
+// is compared with DeepEquals after the claim is decoded as a value of
+// its type. Claims that are not named are not checked.
+//
+// The signature of the token is not verified unless a VerifyJWT option
+// follows the expected value.
+//
+//	t.Check(req, checkers.JWTHasClaims, map[string]interface{}{
+//		"sub":   "alice",
+//		"scope": []string{"read", "write"},
+//		"exp":   checkers.GreaterThan,
+//	}, checkers.VerifyJWT(secret))
+var JWTHasClaims Checker = jwtHasClaims{}
+
+func (jwtHasClaims) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if len(extras) == 0 {
+		return errors.New("missing 'expected' value")
+	}
+	expected, extras := extras[0], extras[1:]
+	var key interface{}
+	for i, extra := range extras {
+		o, ok := extra.(JWTOption)
+		if !ok {
+			return unexpectedExtras("JWTHasClaims", extras[i:])
+		}
+		key = o.key
+	}
+	claims, ok := expected.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("expected value must be a map[string]interface{} of claims, got %s", describeType(expected))
+	}
+	token, err := obtainedToken(obtained)
+	if err != nil {
+		return err
+	}
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return failf("token has %d parts, expected 3", len(parts))
+	}
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := decodeJWTPart(parts[0], &header); err != nil {
+		return failf("cannot decode token header: %v", err)
+	}
+	var payload map[string]json.RawMessage
+	if err := decodeJWTPart(parts[1], &payload); err != nil {
+		return failf("cannot decode token claims: %v", err)
+	}
+	if key != nil {
+		if err := verifyJWT(header.Alg, parts, key); err != nil {
+			return err
+		}
+	}
+
+	names := make([]string, 0, len(claims))
+	for name := range claims {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var mismatches []string
+	for _, name := range names {
+		if err := checkClaim(payload, name, claims[name]); err != nil {
+			mismatches = append(mismatches, fmt.Sprintf("claim %q: %v", name, err))
+		}
+	}
+	switch len(mismatches) {
+	case 0:
+		return nil
+	case 1:
+		return failf("%s", mismatches[0])
+	}
+	return failf("%d claims do not match:\n\t%s", len(mismatches), strings.Join(mismatches, "\n\t"))
+}
+
+// obtainedToken returns the token held by the obtained value.
+func obtainedToken(obtained interface{}) (string, error) {
+	switch obtained := obtained.(type) {
+	case string:
+		return obtained, nil
+	case []byte:
+		return string(obtained), nil
+	case *http.Request:
+		if obtained != nil {
+			auth := obtained.Header.Get("Authorization")
+			if len(auth) < 7 || !strings.EqualFold(auth[:7], "Bearer ") {
+				return "", failf("request has no bearer token in its Authorization header %q", auth)
+			}
+			return strings.TrimSpace(auth[7:]), nil
+		}
+	}
+	return "", fmt.Errorf("JWTHasClaims checker expected a token or *http.Request, obtained was %s", describeType(obtained))
+}
+
+// decodeJWTPart decodes a base64url encoded JSON part of a token.
+func decodeJWTPart(part string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// checkClaim checks the named claim in the payload against the
+// expected value.
+func checkClaim(payload map[string]json.RawMessage, name string, expected interface{}) error {
+	raw, present := payload[name]
+	if expected == nil {
+		if present {
+			return fmt.Errorf("expected to be absent, got %s", raw)
+		}
+		return nil
+	}
+	if !present {
+		return errors.New("not found")
+	}
+	if checker, ok := expected.(Checker); ok {
+		var value interface{}
+		decoder := json.NewDecoder(bytes.NewReader(raw))
+		decoder.UseNumber()
+		if err := decoder.Decode(&value); err != nil {
+			return err
+		}
+		return checker.Check(normalizeNumber(value))
+	}
+	value := reflect.New(reflect.TypeOf(expected))
+	if err := json.Unmarshal(raw, value.Interface()); err != nil {
+		return fmt.Errorf("cannot decode %s as %T", raw, expected)
+	}
+	return DeepEquals.Check(value.Elem().Interface(), expected)
+}
+
+// jwtHashes holds the hash used by the algorithms of each size.
+var jwtHashes = map[string]crypto.Hash{
+	"256": crypto.SHA256,
+	"384": crypto.SHA384,
+	"512": crypto.SHA512,
+}
+
+// verifyJWT checks the signature of the token, split into its parts,
+// with the key.
+func verifyJWT(alg string, parts []string, key interface{}) error {
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return failf("cannot decode token signature: %v", err)
+	}
+	signed := []byte(parts[0] + "." + parts[1])
+	if alg == "none" {
+		return failf("token is not signed")
+	}
+	// Apart from EdDSA, the algorithms are named by their family and
+	// the size of the SHA-2 hash they use.
+	family, h := alg, crypto.Hash(0)
+	if len(alg) == 5 {
+		family, h = alg[:2], jwtHashes[alg[2:]]
+	}
+	var ok bool
+	switch {
+	case family == "HS" && h != 0:
+		secret, isSecret := key.([]byte)
+		if !isSecret {
+			return jwtKeyMismatch(alg, key)
+		}
+		mac := hmac.New(h.New, secret)
+		mac.Write(signed)
+		ok = hmac.Equal(mac.Sum(nil), signature)
+	case (family == "RS" || family == "PS") && h != 0:
+		public, isRSA := key.(*rsa.PublicKey)
+		if !isRSA {
+			return jwtKeyMismatch(alg, key)
+		}
+		digest := h.New()
+		digest.Write(signed)
+		if family == "RS" {
+			ok = rsa.VerifyPKCS1v15(public, h, digest.Sum(nil), signature) == nil
+		} else {
+			ok = rsa.VerifyPSS(public, h, digest.Sum(nil), signature, nil) == nil
+		}
+	case family == "ES" && h != 0:
+		public, isECDSA := key.(*ecdsa.PublicKey)
+		if !isECDSA {
+			return jwtKeyMismatch(alg, key)
+		}
+		digest := h.New()
+		digest.Write(signed)
+		half := len(signature) / 2
+		if half > 0 && len(signature) == 2*half {
+			r := new(big.Int).SetBytes(signature[:half])
+			s := new(big.Int).SetBytes(signature[half:])
+			ok = ecdsa.Verify(public, digest.Sum(nil), r, s)
+		}
+	case alg == "EdDSA":
+		public, isEd25519 := key.(ed25519.PublicKey)
+		if !isEd25519 {
+			return jwtKeyMismatch(alg, key)
+		}
+		ok = ed25519.Verify(public, signed, signature)
+	default:
+		return failf("token has unsupported algorithm %q", alg)
+	}
+	if !ok {
+		return failf("token signature does not verify with the %s key", alg)
+	}
+	return nil
+}
+
+// jwtKeyMismatch reports that the key cannot verify tokens signed with
+// the algorithm.
+func jwtKeyMismatch(alg string, key interface{}) error {
+	return fmt.Errorf("key of type %T cannot verify a token signed with %s", key, alg)
+}
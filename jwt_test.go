@@ -0,0 +1,197 @@
+// Add a copyright
+// Add a licence
+
+package checkers_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/howbazaar/checkers"
+)
+
+// makeJWT returns a token with the algorithm and claims, signed by sign.
+func makeJWT(t *testing.T, alg string, claims map[string]interface{}, sign func([]byte) []byte) string {
+	header, _ := json.Marshal(map[string]string{"alg": alg, "typ": "JWT"})
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sign([]byte(signed)))
+}
+
+func TestJWTHasClaims(t *testing.T) {
+	claims := map[string]interface{}{
+		"sub":   "alice",
+		"exp":   1700000000,
+		"scope": []string{"read", "write"},
+		"admin": false,
+	}
+	secret := []byte("s3cr3t")
+	hs256 := makeJWT(t, "HS256", claims, func(signed []byte) []byte {
+		mac := hmac.New(sha256.New, secret)
+		mac.Write(signed)
+		return mac.Sum(nil)
+	})
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	es256 := makeJWT(t, "ES256", claims, func(signed []byte) []byte {
+		digest := sha256.Sum256(signed)
+		r, s, err := ecdsa.Sign(rand.Reader, ecKey, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig := make([]byte, 64)
+		r.FillBytes(sig[:32])
+		s.FillBytes(sig[32:])
+		return sig
+	})
+	edPublic, edPrivate, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	eddsa := makeJWT(t, "EdDSA", claims, func(signed []byte) []byte {
+		return ed25519.Sign(edPrivate, signed)
+	})
+	unsigned := makeJWT(t, "none", claims, func([]byte) []byte { return nil })
+	req, err := http.NewRequest("GET", "https://example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer "+hs256)
+
+	for _, test := range []struct {
+		description string
+		obtained    interface{}
+		extras      []interface{}
+		err         string
+	}{
+		{
+			description: "claims",
+			obtained:    hs256,
+			extras: []interface{}{map[string]interface{}{
+				"sub":   "alice",
+				"scope": []string{"read", "write"},
+				"admin": false,
+			}},
+		}, {
+			description: "claim checker missing its expected value",
+			obtained:    hs256,
+			extras:      []interface{}{map[string]interface{}{"exp": checkers.GreaterThan}},
+			err:         `claim "exp": missing 'expected' value`,
+		}, {
+			description: "claims with checkers",
+			obtained:    hs256,
+			extras: []interface{}{map[string]interface{}{
+				"sub":   checkers.Not(checkers.IsNil),
+				"admin": checkers.IsFalse,
+				"exp":   checkers.Not(checkers.Equals),
+				"iss":   nil,
+			}},
+		}, {
+			description: "verified HS256",
+			obtained:    []byte(hs256),
+			extras:      []interface{}{map[string]interface{}{"sub": "alice", "exp": 1700000000}, checkers.VerifyJWT(secret)},
+		}, {
+			description: "verified ES256",
+			obtained:    es256,
+			extras:      []interface{}{map[string]interface{}{"sub": "alice"}, checkers.VerifyJWT(&ecKey.PublicKey)},
+		}, {
+			description: "verified EdDSA",
+			obtained:    eddsa,
+			extras:      []interface{}{map[string]interface{}{"sub": "alice"}, checkers.VerifyJWT(edPublic)},
+		}, {
+			description: "request",
+			obtained:    req,
+			extras:      []interface{}{map[string]interface{}{"sub": "alice"}, checkers.VerifyJWT(secret)},
+		}, {
+			description: "wrong secret",
+			obtained:    hs256,
+			extras:      []interface{}{map[string]interface{}{}, checkers.VerifyJWT([]byte("guess"))},
+			err:         "token signature does not verify with the HS256 key",
+		}, {
+			description: "unsigned",
+			obtained:    unsigned,
+			extras:      []interface{}{map[string]interface{}{}, checkers.VerifyJWT(secret)},
+			err:         "token is not signed",
+		}, {
+			description: "unsigned unverified",
+			obtained:    unsigned,
+			extras:      []interface{}{map[string]interface{}{"sub": "alice"}},
+		}, {
+			description: "wrong key type",
+			obtained:    es256,
+			extras:      []interface{}{map[string]interface{}{}, checkers.VerifyJWT(secret)},
+			err:         "key of type []uint8 cannot verify a token signed with ES256",
+		}, {
+			description: "mismatched claims",
+			obtained:    hs256,
+			extras: []interface{}{map[string]interface{}{
+				"sub":   "bob",
+				"scope": []string{"read"},
+				"aud":   "api",
+				"admin": nil,
+			}, checkers.Commentf("login")},
+			err: "4 claims do not match:\n" +
+				"\tclaim \"admin\": expected to be absent, got false\n" +
+				"\tclaim \"aud\": not found\n" +
+				"\tclaim \"scope\": mismatch at top level: length mismatch, 2 vs 1; obtained []string{\"read\", \"write\"}; expected []string{\"read\"}\n" +
+				"diff (-obtained +expected):\n" +
+				" []string{\n" +
+				" \t\"read\",\n" +
+				"-\t\"write\",\n" +
+				" }\n" +
+				"\tclaim \"sub\": mismatch at top level: unequal; obtained \"alice\"; expected \"bob\"\n" +
+				"comment: login",
+		}, {
+			description: "claim of another type",
+			obtained:    hs256,
+			extras:      []interface{}{map[string]interface{}{"exp": "soon"}},
+			err:         `claim "exp": cannot decode 1700000000 as string`,
+		}, {
+			description: "malformed",
+			obtained:    "not.a-token",
+			extras:      []interface{}{map[string]interface{}{}},
+			err:         "token has 2 parts, expected 3",
+		}, {
+			description: "no bearer token",
+			obtained:    &http.Request{Header: http.Header{"Authorization": {"Basic YWxpY2U6"}}},
+			extras:      []interface{}{map[string]interface{}{}},
+			err:         `request has no bearer token in its Authorization header "Basic YWxpY2U6"`,
+		}, {
+			description: "not a token",
+			obtained:    42,
+			extras:      []interface{}{map[string]interface{}{}},
+			err:         "JWTHasClaims checker expected a token or *http.Request, obtained was type int",
+		}, {
+			description: "claims not a map",
+			obtained:    hs256,
+			extras:      []interface{}{map[string]string{"sub": "alice"}},
+			err:         "expected value must be a map[string]interface{} of claims, got type map[string]string",
+		},
+	} {
+		err := checkers.JWTHasClaims.Check(test.obtained, test.extras...)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+		} else {
+			if test.err == "" {
+				t.Errorf("%s: unexpected error: %v", test.description, err)
+			} else if err.Error() != test.err {
+				t.Errorf("%s: error mismatch: \n\tobtained: %q\n\texpected: %q", test.description, err.Error(), test.err)
+			}
+		}
+	}
+}
@@ -0,0 +1,79 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+type noExportedFieldsOmitted struct{}
+
+// NoExportedFieldsOmitted checker checks that the obtained struct, the
+// result of converting the expected source struct, has every exported
+// field of the source that it has a field of the same name for copied
+// across. Fields of the same type must be deeply equal. Fields of
+// different types, which the conversion must have translated, must not
+// be zero where the source field is not. Either struct may be given by
+// pointer.
+//
+//	t.Check(toUserDTO(user), checkers.NoExportedFieldsOmitted, user)
+var NoExportedFieldsOmitted Checker = noExportedFieldsOmitted{}
+
+func (noExportedFieldsOmitted) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if len(extras) == 0 {
+		return errors.New("missing 'expected' value")
+	}
+	expected, extras := extras[0], extras[1:]
+	if err := unexpectedExtras("NoExportedFieldsOmitted", extras); err != nil {
+		return err
+	}
+	source, ok := structValue(expected)
+	if !ok {
+		return fmt.Errorf("expected value must be a struct, got %s", describe(expected))
+	}
+	destination, ok := structValue(obtained)
+	if !ok {
+		return fmt.Errorf("NoExportedFieldsOmitted checker expected a struct, obtained was %s", describe(obtained))
+	}
+
+	var omitted []string
+	for i := 0; i < source.NumField(); i++ {
+		field := source.Type().Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		destField, ok := destination.Type().FieldByName(field.Name)
+		if !ok || destField.PkgPath != "" || len(destField.Index) != 1 {
+			continue
+		}
+		from, to := source.Field(i), destination.Field(destField.Index[0])
+		if field.Type == destField.Type {
+			if equal, _ := DeepEqual(interfaceOf(to), interfaceOf(from)); !equal {
+				omitted = append(omitted, fmt.Sprintf("%s: obtained %#v, source %#v", field.Name, printable(to), printable(from)))
+			}
+		} else if to.IsZero() && !from.IsZero() {
+			omitted = append(omitted, fmt.Sprintf("%s: obtained zero %s, source %#v", field.Name, destField.Type, printable(from)))
+		}
+	}
+	if len(omitted) == 0 {
+		return nil
+	}
+	return failf("%d fields of %s not copied to %s:\n\t%s",
+		len(omitted), source.Type(), destination.Type(), strings.Join(omitted, "\n\t"))
+}
+
+// structValue returns the struct held by the value, following a
+// pointer to it.
+func structValue(value interface{}) (reflect.Value, bool) {
+	v := reflect.ValueOf(value)
+	if v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	return v, v.Kind() == reflect.Struct
+}
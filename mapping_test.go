@@ -0,0 +1,93 @@
+// Add a copyright
+// Add a licence
+
+package checkers_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/howbazaar/checkers"
+)
+
+type domainUser struct {
+	ID       int
+	Name     string
+	Tags     []string
+	Created  time.Time
+	Password string
+	internal string
+}
+
+type userDTO struct {
+	ID       int
+	Name     string
+	Tags     []string
+	Created  string
+	Location string
+	internal string
+}
+
+func TestNoExportedFieldsOmitted(t *testing.T) {
+	user := domainUser{
+		ID:       7,
+		Name:     "alice",
+		Tags:     []string{"admin"},
+		Created:  time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		Password: "secret",
+		internal: "x",
+	}
+	for _, test := range []struct {
+		description string
+		obtained    interface{}
+		extras      []interface{}
+		err         string
+	}{
+		{
+			description: "all copied",
+			obtained:    userDTO{ID: 7, Name: "alice", Tags: []string{"admin"}, Created: "2024-03-01", Location: "here"},
+			extras:      []interface{}{user},
+		}, {
+			description: "pointers",
+			obtained:    &userDTO{ID: 7, Name: "alice", Tags: []string{"admin"}, Created: "2024-03-01"},
+			extras:      []interface{}{&user},
+		}, {
+			description: "zero source fields",
+			obtained:    userDTO{},
+			extras:      []interface{}{domainUser{}},
+		}, {
+			description: "fields omitted",
+			obtained:    userDTO{ID: 7, Name: "bob"},
+			extras:      []interface{}{user},
+			err: "3 fields of checkers_test.domainUser not copied to checkers_test.userDTO:\n" +
+				"\tName: obtained \"bob\", source \"alice\"\n" +
+				"\tTags: obtained []string(nil), source []string{\"admin\"}\n" +
+				"\tCreated: obtained zero string, source \"2024-03-01T00:00:00Z\"",
+		}, {
+			description: "not a struct",
+			obtained:    map[string]int{},
+			extras:      []interface{}{user},
+			err:         "NoExportedFieldsOmitted checker expected a struct, obtained was map[string]int value map[string]int{}",
+		}, {
+			description: "source not a struct",
+			obtained:    userDTO{},
+			extras:      []interface{}{(*domainUser)(nil)},
+			err:         "expected value must be a struct, got nil *checkers_test.domainUser",
+		},
+	} {
+		err := checkers.NoExportedFieldsOmitted.Check(test.obtained, test.extras...)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+		} else {
+			if test.err == "" {
+				t.Errorf("%s: unexpected error: %v", test.description, err)
+			} else {
+				if err.Error() != test.err {
+					t.Errorf("%s: error mismatch: \n\tobtained: %q\n\texpected: %q", test.description, err.Error(), test.err)
+				}
+			}
+		}
+	}
+}
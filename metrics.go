@@ -0,0 +1,191 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"errors"
+	"expvar"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// MetricGatherer is implemented by metrics registries that can report
+// the current value of a counter or gauge by name, so that they can be
+// used with MetricEquals and MetricDelta. Registries such as
+// Prometheus can be adapted with a small wrapper.
+type MetricGatherer interface {
+	// MetricValue returns the value of the named metric, and whether
+	// the metric exists.
+	MetricValue(name string) (float64, bool)
+}
+
+// ExpvarGatherer returns a MetricGatherer for the variables in the
+// expvar map. Variables in nested maps are named by the keys of the
+// maps and the variable joined by dots, such as "http.requests".
+// Variables must render as a number, as expvar.Int and expvar.Float
+// do.
+func ExpvarGatherer(m *expvar.Map) MetricGatherer {
+	return expvarGatherer{m.Get}
+}
+
+// PublishedExpvars is a MetricGatherer for the variables published by
+// expvar.Publish, named as with ExpvarGatherer.
+var PublishedExpvars MetricGatherer = expvarGatherer{expvar.Get}
+
+type expvarGatherer struct {
+	get func(string) expvar.Var
+}
+
+func (g expvarGatherer) MetricValue(name string) (float64, bool) {
+	v := g.get(name)
+	if v == nil {
+		// The name may be in a nested map. Keys may hold dots too,
+		// so try each split in turn.
+		for i := strings.Index(name, "."); i >= 0; i = nextDot(name, i) {
+			if m, ok := g.get(name[:i]).(*expvar.Map); ok {
+				if value, ok := (expvarGatherer{m.Get}).MetricValue(name[i+1:]); ok {
+					return value, true
+				}
+			}
+		}
+		return 0, false
+	}
+	value, err := strconv.ParseFloat(v.String(), 64)
+	if err != nil {
+		return 0, false
+	}
+	return value, true
+}
+
+// nextDot returns the index of the next dot in name after index i, or
+// -1 if there are no more.
+func nextDot(name string, i int) int {
+	next := strings.Index(name[i+1:], ".")
+	if next < 0 {
+		return -1
+	}
+	return i + 1 + next
+}
+
+// metricSource returns the value as a MetricGatherer.
+func metricSource(checker string, source interface{}) (MetricGatherer, error) {
+	switch source := source.(type) {
+	case *expvar.Map:
+		return ExpvarGatherer(source), nil
+	case MetricGatherer:
+		return source, nil
+	}
+	return nil, fmt.Errorf("%s checker expected *expvar.Map or MetricGatherer, got %s", checker, describeType(source))
+}
+
+// metricArgs returns the metric name and expected number from the extra
+// values.
+func metricArgs(nameArg, numberArg interface{}) (string, float64, error) {
+	name, ok := nameArg.(string)
+	if !ok {
+		return "", 0, errors.New("metric name must be a string")
+	}
+	value := reflect.ValueOf(numberArg)
+	switch kindFamily(value.Kind()) {
+	case reflect.Int:
+		return name, float64(value.Int()), nil
+	case reflect.Uint:
+		return name, float64(value.Uint()), nil
+	case reflect.Float64:
+		return name, value.Float(), nil
+	}
+	return "", 0, fmt.Errorf("expected metric value must be a number, got %s", describeType(numberArg))
+}
+
+// formatMetric formats a metric value without needless decimals.
+func formatMetric(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+type metricEquals struct{}
+
+// MetricEquals checker checks that the metric in the obtained
+// *expvar.Map or MetricGatherer, named by the first extra value, has
+// the value of the second.
+//
+//	t.Check(vars, checkers.MetricEquals, "requests", 3)
+var MetricEquals Checker = metricEquals{}
+
+func (metricEquals) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if len(extras) < 2 {
+		return errors.New("missing 'name' and 'expected' values")
+	}
+	nameArg, expectedArg, extras := extras[0], extras[1], extras[2:]
+	if err := unexpectedExtras("MetricEquals", extras); err != nil {
+		return err
+	}
+	source, err := metricSource("MetricEquals", obtained)
+	if err != nil {
+		return err
+	}
+	name, expected, err := metricArgs(nameArg, expectedArg)
+	if err != nil {
+		return err
+	}
+	value, ok := source.MetricValue(name)
+	if !ok {
+		return failf("metric %q not found", name)
+	}
+	if value == expected {
+		return nil
+	}
+	return failf("metric %q is %s, expected %s", name, formatMetric(value), formatMetric(expected))
+}
+
+type metricDelta struct{}
+
+// MetricDelta checker calls the obtained function, such as the body of
+// a test, and checks that it changes the metric by the expected amount.
+// The extra values are the *expvar.Map or MetricGatherer, the name of
+// the metric and the expected change. A metric that doesn't exist
+// before the function is called is taken to start at zero.
+//
+//	t.Check(func() {
+//		handler.ServeHTTP(recorder, request)
+//	}, checkers.MetricDelta, vars, "requests", 1)
+var MetricDelta Checker = metricDelta{}
+
+func (metricDelta) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if len(extras) < 3 {
+		return errors.New("missing 'source', 'name' and 'delta' values")
+	}
+	sourceArg, nameArg, deltaArg, extras := extras[0], extras[1], extras[2], extras[3:]
+	if err := unexpectedExtras("MetricDelta", extras); err != nil {
+		return err
+	}
+	f, ok := obtained.(func())
+	if !ok {
+		return fmt.Errorf("MetricDelta checker expected func(), obtained was %s", describeType(obtained))
+	}
+	source, err := metricSource("MetricDelta", sourceArg)
+	if err != nil {
+		return err
+	}
+	name, delta, err := metricArgs(nameArg, deltaArg)
+	if err != nil {
+		return err
+	}
+	before, _ := source.MetricValue(name)
+	f()
+	after, ok := source.MetricValue(name)
+	if !ok {
+		return failf("metric %q not found", name)
+	}
+	if after-before == delta {
+		return nil
+	}
+	return failf("metric %q changed by %s, from %s to %s, expected change of %s",
+		name, formatMetric(after-before), formatMetric(before), formatMetric(after), formatMetric(delta))
+}
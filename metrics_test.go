@@ -0,0 +1,143 @@
+// Add a copyright
+// Add a licence
+
+package checkers_test
+
+import (
+	"expvar"
+	"testing"
+
+	"github.com/howbazaar/checkers"
+)
+
+type gatherer map[string]float64
+
+func (g gatherer) MetricValue(name string) (float64, bool) {
+	v, ok := g[name]
+	return v, ok
+}
+
+// published is published once, as expvar panics if a name is reused.
+var published = expvar.NewInt("checkers_test.published")
+
+func TestMetricCheckers(t *testing.T) {
+	vars := new(expvar.Map).Init()
+	vars.Add("requests", 3)
+	vars.AddFloat("load", 0.5)
+	http := new(expvar.Map).Init()
+	http.Add("errors", 2)
+	vars.Set("http", http)
+	vars.Set("name", new(expvar.String))
+
+	published.Set(7)
+
+	increment := func() { vars.Add("requests", 2) }
+
+	for _, test := range []struct {
+		description string
+		checker     checkers.Checker
+		obtained    interface{}
+		extras      []interface{}
+		err         string
+	}{
+		{
+			description: "int equals",
+			checker:     checkers.MetricEquals,
+			obtained:    vars,
+			extras:      []interface{}{"requests", 3},
+		}, {
+			description: "float equals",
+			checker:     checkers.MetricEquals,
+			obtained:    vars,
+			extras:      []interface{}{"load", 0.5},
+		}, {
+			description: "nested equals",
+			checker:     checkers.MetricEquals,
+			obtained:    vars,
+			extras:      []interface{}{"http.errors", uint8(2)},
+		}, {
+			description: "published equals",
+			checker:     checkers.MetricEquals,
+			obtained:    checkers.PublishedExpvars,
+			extras:      []interface{}{"checkers_test.published", 7},
+		}, {
+			description: "gatherer equals",
+			checker:     checkers.MetricEquals,
+			obtained:    gatherer{"up": 1},
+			extras:      []interface{}{"up", 1},
+		}, {
+			description: "unequal",
+			checker:     checkers.MetricEquals,
+			obtained:    vars,
+			extras:      []interface{}{"load", 1},
+			err:         `metric "load" is 0.5, expected 1`,
+		}, {
+			description: "missing",
+			checker:     checkers.MetricEquals,
+			obtained:    vars,
+			extras:      []interface{}{"http.latency", 1},
+			err:         `metric "http.latency" not found`,
+		}, {
+			description: "not a number",
+			checker:     checkers.MetricEquals,
+			obtained:    vars,
+			extras:      []interface{}{"name", 1},
+			err:         `metric "name" not found`,
+		}, {
+			description: "bad source",
+			checker:     checkers.MetricEquals,
+			obtained:    map[string]int{},
+			extras:      []interface{}{"requests", 1},
+			err:         "MetricEquals checker expected *expvar.Map or MetricGatherer, got type map[string]int",
+		}, {
+			description: "bad expected",
+			checker:     checkers.MetricEquals,
+			obtained:    vars,
+			extras:      []interface{}{"requests", "3"},
+			err:         "expected metric value must be a number, got type string",
+		}, {
+			description: "delta",
+			checker:     checkers.MetricDelta,
+			obtained:    increment,
+			extras:      []interface{}{vars, "requests", 2},
+		}, {
+			description: "wrong delta",
+			checker:     checkers.MetricDelta,
+			obtained:    increment,
+			extras:      []interface{}{vars, "requests", 1},
+			err:         `metric "requests" changed by 2, from 5 to 7, expected change of 1`,
+		}, {
+			description: "delta of new metric",
+			checker:     checkers.MetricDelta,
+			obtained:    func() { vars.Add("new", 1) },
+			extras:      []interface{}{vars, "new", 1},
+		}, {
+			description: "delta missing arguments",
+			checker:     checkers.MetricDelta,
+			obtained:    increment,
+			extras:      []interface{}{vars, "requests"},
+			err:         "missing 'source', 'name' and 'delta' values",
+		}, {
+			description: "delta not a function",
+			checker:     checkers.MetricDelta,
+			obtained:    vars,
+			extras:      []interface{}{vars, "requests", 1},
+			err:         "MetricDelta checker expected func(), obtained was type *expvar.Map",
+		},
+	} {
+		err := test.checker.Check(test.obtained, test.extras...)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+		} else {
+			if test.err == "" {
+				t.Errorf("%s: unexpected error: %v", test.description, err)
+			} else {
+				if err.Error() != test.err {
+					t.Errorf("%s: error mismatch: \n\tobtained: %q\n\texpected: %q", test.description, err.Error(), test.err)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,253 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/mail"
+	"strings"
+)
+
+// Part describes a part of a multipart body expected by
+// MultipartHasPart. Fields left empty are not checked.
+type Part struct {
+	// Filename is the filename parameter of the part's
+	// Content-Disposition header.
+	Filename string
+	// ContentType is the media type of the part's Content-Type header,
+	// without any parameters, such as "image/png".
+	ContentType string
+	// Body is the content of the part, after any quoted-printable
+	// transfer encoding is decoded. It is a string or []byte that must
+	// be equal to the content, or a Checker that the content, as a
+	// string, must pass.
+	Body interface{}
+}
+
+// parsedPart is a part read from a multipart body.
+type parsedPart struct {
+	name        string
+	filename    string
+	contentType string
+	body        []byte
+}
+
+type multipartHasPart struct{}
+
+// MultipartHasPart checker checks that the obtained multipart body has
+// a part with the name given by the first extra value, as described by
+// the Part given by the second. A part's name is the name parameter of
+// its Content-Disposition header, as for multipart/form-data, or its
+// filename if it has no name, as for the attachments of a MIME message.
+//
+// The obtained value may be an *http.Request, such as one sent by the
+// client under test, whose body is read and replaced so that it can be
+// read again, or a *mail.Message. It may also be an io.Reader, string
+// or []byte holding a whole MIME message, headers and body. The media
+// type of the body's Content-Type must be multipart.
+//
+//	t.Check(req, checkers.MultipartHasPart, "avatar", checkers.Part{
+//		Filename:    "me.png",
+//		ContentType: "image/png",
+//		Body:        checkers.HasLen,
+//	})
+var MultipartHasPart Checker = multipartHasPart{}
+
+func (multipartHasPart) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if len(extras) < 2 {
+		return errors.New("missing 'name' and 'part' values")
+	}
+	nameArg, partArg, extras := extras[0], extras[1], extras[2:]
+	if err := unexpectedExtras("MultipartHasPart", extras); err != nil {
+		return err
+	}
+	name, ok := nameArg.(string)
+	if !ok {
+		return errors.New("name must be a string")
+	}
+	expected, ok := partArg.(Part)
+	if !ok {
+		return fmt.Errorf("part must be a Part, got %s", describeType(partArg))
+	}
+	switch expected.Body.(type) {
+	case nil, string, []byte, Checker:
+	default:
+		return fmt.Errorf("part body must be a string, []byte or Checker, got %s", describeType(expected.Body))
+	}
+	parts, err := readParts("MultipartHasPart", obtained)
+	if err != nil {
+		return err
+	}
+	var found *parsedPart
+	for i := range parts {
+		if parts[i].name == name {
+			found = &parts[i]
+			break
+		}
+	}
+	if found == nil {
+		return lazyFailure(func() string {
+			return fmt.Sprintf("no part named %q; parts are %s", name, partNames(parts))
+		})
+	}
+	if expected.Filename != "" && found.filename != expected.Filename {
+		return failf("part %q has filename %q, expected %q", name, found.filename, expected.Filename)
+	}
+	if expected.ContentType != "" {
+		mediaType, _, _ := mime.ParseMediaType(found.contentType)
+		if !strings.EqualFold(mediaType, expected.ContentType) {
+			return failf("part %q has content type %q, expected %q", name, found.contentType, expected.ContentType)
+		}
+	}
+	switch body := expected.Body.(type) {
+	case string:
+		if string(found.body) != body {
+			return failf("part %q has body %q, expected %q", name, found.body, body)
+		}
+	case []byte:
+		if !bytes.Equal(found.body, body) {
+			return failf("part %q has body %q, expected %q", name, found.body, body)
+		}
+	case Checker:
+		if err := body.Check(string(found.body)); err != nil {
+			return failf("part %q body: %v", name, err)
+		}
+	}
+	return nil
+}
+
+type multipartPartNames struct{}
+
+// MultipartPartNames checker checks that the parts of the obtained
+// multipart body, which may be any value that MultipartHasPart takes,
+// have the expected names, a []string, in order.
+//
+//	t.Check(req, checkers.MultipartPartNames, []string{"title", "avatar"})
+var MultipartPartNames Checker = multipartPartNames{}
+
+func (multipartPartNames) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if len(extras) == 0 {
+		return errors.New("missing 'expected' value")
+	}
+	expected, extras := extras[0], extras[1:]
+	if err := unexpectedExtras("MultipartPartNames", extras); err != nil {
+		return err
+	}
+	names, ok := expected.([]string)
+	if !ok {
+		return fmt.Errorf("expected value must be a []string, got %s", describeType(expected))
+	}
+	parts, err := readParts("MultipartPartNames", obtained)
+	if err != nil {
+		return err
+	}
+	obtainedNames := make([]string, len(parts))
+	for i, part := range parts {
+		obtainedNames[i] = part.name
+	}
+	return DeepEquals.Check(obtainedNames, names)
+}
+
+// readParts reads the parts of the obtained multipart body.
+func readParts(checker string, obtained interface{}) ([]parsedPart, error) {
+	var contentType string
+	var body []byte
+	switch obtained := obtained.(type) {
+	case *http.Request:
+		if obtained == nil {
+			return nil, fmt.Errorf("%s checker expected a request or MIME message, obtained was nil *http.Request", checker)
+		}
+		contentType = obtained.Header.Get("Content-Type")
+		if obtained.Body != nil {
+			data, err := ioutil.ReadAll(obtained.Body)
+			obtained.Body.Close()
+			if err != nil {
+				return nil, fmt.Errorf("unable to read request body: %v", err)
+			}
+			obtained.Body = ioutil.NopCloser(bytes.NewReader(data))
+			body = data
+		}
+	case *mail.Message:
+		if obtained == nil {
+			return nil, fmt.Errorf("%s checker expected a request or MIME message, obtained was nil *mail.Message", checker)
+		}
+		contentType = obtained.Header.Get("Content-Type")
+		data, err := ioutil.ReadAll(obtained.Body)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read message body: %v", err)
+		}
+		obtained.Body = bytes.NewReader(data)
+		body = data
+	case string, []byte, io.Reader:
+		var r io.Reader
+		switch obtained := obtained.(type) {
+		case string:
+			r = strings.NewReader(obtained)
+		case []byte:
+			r = bytes.NewReader(obtained)
+		case io.Reader:
+			r = obtained
+		}
+		message, err := mail.ReadMessage(r)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read MIME message: %v", err)
+		}
+		return readParts(checker, message)
+	default:
+		return nil, fmt.Errorf("%s checker expected a request or MIME message, obtained was %s", checker, describeType(obtained))
+	}
+
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, failf("cannot parse content type %q: %v", contentType, err)
+	}
+	if !strings.HasPrefix(mediaType, "multipart/") || params["boundary"] == "" {
+		return nil, failf("content type %q is not multipart with a boundary", contentType)
+	}
+	reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+	var parts []parsedPart
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return parts, nil
+		}
+		if err != nil {
+			return nil, failf("cannot read part %d: %v", len(parts), err)
+		}
+		content, err := ioutil.ReadAll(part)
+		if err != nil {
+			return nil, failf("cannot read part %d: %v", len(parts), err)
+		}
+		name := part.FormName()
+		if name == "" {
+			name = part.FileName()
+		}
+		parts = append(parts, parsedPart{
+			name:        name,
+			filename:    part.FileName(),
+			contentType: part.Header.Get("Content-Type"),
+			body:        content,
+		})
+	}
+}
+
+// partNames lists the names of the parts.
+func partNames(parts []parsedPart) string {
+	names := make([]string, len(parts))
+	for i, part := range parts {
+		names[i] = fmt.Sprintf("%q", part.name)
+	}
+	return "[" + strings.Join(names, ", ") + "]"
+}
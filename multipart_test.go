@@ -0,0 +1,172 @@
+// Add a copyright
+// Add a licence
+
+package checkers_test
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/howbazaar/checkers"
+)
+
+func newUploadRequest(t *testing.T) *http.Request {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	if err := w.WriteField("title", "holiday"); err != nil {
+		t.Fatal(err)
+	}
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", `form-data; name="photo"; filename="beach.png"`)
+	header.Set("Content-Type", "image/png")
+	part, err := w.CreatePart(header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	part.Write([]byte("\x89PNG"))
+	w.Close()
+	req, err := http.NewRequest("POST", "https://example.com/upload", &body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	return req
+}
+
+const mimeMessage = "From: alice@example.com\r\n" +
+	"Content-Type: multipart/mixed; boundary=XYZ\r\n" +
+	"\r\n" +
+	"--XYZ\r\n" +
+	"Content-Type: text/plain; charset=utf-8\r\n" +
+	"Content-Transfer-Encoding: quoted-printable\r\n" +
+	"\r\n" +
+	"caf=C3=A9\r\n" +
+	"--XYZ\r\n" +
+	"Content-Type: text/csv\r\n" +
+	"Content-Disposition: attachment; filename=\"report.csv\"\r\n" +
+	"\r\n" +
+	"a,b\r\n" +
+	"--XYZ--\r\n"
+
+func TestMultipartCheckers(t *testing.T) {
+	req := newUploadRequest(t)
+	for _, test := range []struct {
+		description string
+		checker     checkers.Checker
+		obtained    interface{}
+		extras      []interface{}
+		err         string
+	}{
+		{
+			description: "form field",
+			checker:     checkers.MultipartHasPart,
+			obtained:    req,
+			extras:      []interface{}{"title", checkers.Part{Body: "holiday"}},
+		}, {
+			description: "file",
+			checker:     checkers.MultipartHasPart,
+			obtained:    req,
+			extras: []interface{}{"photo", checkers.Part{
+				Filename:    "beach.png",
+				ContentType: "image/png",
+				Body:        []byte("\x89PNG"),
+			}},
+		}, {
+			description: "body checker",
+			checker:     checkers.MultipartHasPart,
+			obtained:    req,
+			extras:      []interface{}{"photo", checkers.Part{Body: checkers.Not(checkers.IsNil)}},
+		}, {
+			description: "body checker fails",
+			checker:     checkers.MultipartHasPart,
+			obtained:    req,
+			extras:      []interface{}{"photo", checkers.Part{Body: checkers.IsNil}},
+			err:         `part "photo" body: obtained value is non-nil`,
+		}, {
+			description: "wrong filename",
+			checker:     checkers.MultipartHasPart,
+			obtained:    req,
+			extras:      []interface{}{"photo", checkers.Part{Filename: "beach.jpg"}},
+			err:         `part "photo" has filename "beach.png", expected "beach.jpg"`,
+		}, {
+			description: "wrong content type",
+			checker:     checkers.MultipartHasPart,
+			obtained:    req,
+			extras:      []interface{}{"photo", checkers.Part{ContentType: "image/jpeg"}, checkers.Commentf("upload")},
+			err:         "part \"photo\" has content type \"image/png\", expected \"image/jpeg\"\ncomment: upload",
+		}, {
+			description: "wrong body",
+			checker:     checkers.MultipartHasPart,
+			obtained:    req,
+			extras:      []interface{}{"title", checkers.Part{Body: "work"}},
+			err:         `part "title" has body "holiday", expected "work"`,
+		}, {
+			description: "missing part",
+			checker:     checkers.MultipartHasPart,
+			obtained:    req,
+			extras:      []interface{}{"caption", checkers.Part{}},
+			err:         `no part named "caption"; parts are \["title", "photo"\]`,
+		}, {
+			description: "names",
+			checker:     checkers.MultipartPartNames,
+			obtained:    req,
+			extras:      []interface{}{[]string{"title", "photo"}},
+		}, {
+			description: "MIME message",
+			checker:     checkers.MultipartHasPart,
+			obtained:    mimeMessage,
+			extras:      []interface{}{"report.csv", checkers.Part{ContentType: "text/csv", Body: "a,b"}},
+		}, {
+			description: "MIME message names",
+			checker:     checkers.MultipartPartNames,
+			obtained:    strings.NewReader(mimeMessage),
+			extras:      []interface{}{[]string{"", "report.csv"}},
+		}, {
+			description: "quoted-printable decoded",
+			checker:     checkers.MultipartHasPart,
+			obtained:    []byte(mimeMessage),
+			extras:      []interface{}{"", checkers.Part{Body: "café"}},
+		}, {
+			description: "not multipart",
+			checker:     checkers.MultipartPartNames,
+			obtained:    "Content-Type: text/plain\r\n\r\nhello",
+			extras:      []interface{}{[]string{}},
+			err:         `content type "text/plain" is not multipart with a boundary`,
+		}, {
+			description: "unsupported",
+			checker:     checkers.MultipartHasPart,
+			obtained:    42,
+			extras:      []interface{}{"title", checkers.Part{}},
+			err:         `MultipartHasPart checker expected a request or MIME message, obtained was type int`,
+		}, {
+			description: "part not a Part",
+			checker:     checkers.MultipartHasPart,
+			obtained:    req,
+			extras:      []interface{}{"title", "holiday"},
+			err:         `part must be a Part, got type string`,
+		}, {
+			description: "bad body",
+			checker:     checkers.MultipartHasPart,
+			obtained:    req,
+			extras:      []interface{}{"title", checkers.Part{Body: 1}},
+			err:         `part body must be a string, \[\]byte or Checker, got type int`,
+		},
+	} {
+		err := test.checker.Check(test.obtained, test.extras...)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+		} else {
+			if test.err == "" {
+				t.Errorf("%s: unexpected error: %v", test.description, err)
+			} else if matchErr := checkers.Matches.Check(err.Error(), test.err); matchErr != nil {
+				t.Errorf("%s: error mismatch: %v", test.description, matchErr)
+			}
+		}
+	}
+}
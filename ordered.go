@@ -0,0 +1,126 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+)
+
+type ordered struct {
+	name     string
+	relation string
+	holds    func(cmp int) bool
+}
+
+// GreaterThan checker checks that the obtained number is greater than
+// the expected one. The numbers may be of any sizes of the same sort,
+// signed integers, unsigned integers or floats, including named types
+// such as time.Duration, and are compared without being truncated.
+//
+//	t.Assert(count, checkers.GreaterThan, 0)
+var GreaterThan Checker = ordered{"GreaterThan", "greater than", func(cmp int) bool { return cmp > 0 }}
+
+// LessThan checker checks that the obtained number is less than the
+// expected one, compared as by GreaterThan.
+var LessThan Checker = ordered{"LessThan", "less than", func(cmp int) bool { return cmp < 0 }}
+
+// AtLeast checker checks that the obtained number is greater than or
+// equal to the expected one, compared as by GreaterThan.
+var AtLeast Checker = ordered{"AtLeast", "at least", func(cmp int) bool { return cmp >= 0 }}
+
+// AtMost checker checks that the obtained number is less than or equal
+// to the expected one, compared as by GreaterThan.
+var AtMost Checker = ordered{"AtMost", "at most", func(cmp int) bool { return cmp <= 0 }}
+
+func (o ordered) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if len(extras) == 0 {
+		return errors.New("missing 'expected' value")
+	}
+	expected, extras := extras[0], extras[1:]
+	if err := unexpectedExtras(o.name, extras); err != nil {
+		return err
+	}
+	value := reflect.ValueOf(obtained)
+	exValue := reflect.ValueOf(expected)
+	family := kindFamily(value.Kind())
+	switch family {
+	case reflect.Int, reflect.Uint, reflect.Float64:
+	default:
+		return fmt.Errorf("%s checker expected a number, obtained was %s", o.name, describeType(obtained))
+	}
+	if kindFamily(exValue.Kind()) != family {
+		return fmt.Errorf("obtained type %T cannot be compared with expected type %T", obtained, expected)
+	}
+	if family == reflect.Float64 && (math.IsNaN(value.Float()) || math.IsNaN(exValue.Float())) {
+		return failf("obtained %v is not %s %v, as NaN is not ordered", display(obtained), o.relation, display(expected))
+	}
+	// The values are compared as the elements of a series are, which
+	// works across the sizes of each sort of number.
+	compare, _ := seriesComparison(value.Type())
+	if o.holds(compare(value, exValue)) {
+		return nil
+	}
+	return failf("obtained %v is not %s %v", display(obtained), o.relation, display(expected))
+}
+
+type between struct{}
+
+// Between checker checks that the obtained value is at least the first
+// extra value and at most the second. The values may be numbers,
+// compared as by GreaterThan, or time.Time values.
+//
+//	t.Check(latency, checkers.Between, 10*time.Millisecond, 50*time.Millisecond)
+//	t.Check(created, checkers.Between, start, time.Now())
+var Between Checker = between{}
+
+func (between) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if len(extras) < 2 {
+		return errors.New("missing 'low' and 'high' values")
+	}
+	low, high, extras := extras[0], extras[1], extras[2:]
+	if err := unexpectedExtras("Between", extras); err != nil {
+		return err
+	}
+	value := reflect.ValueOf(obtained)
+	lowValue, highValue := reflect.ValueOf(low), reflect.ValueOf(high)
+	isTime := reflect.TypeOf(obtained) == timeType
+	family := kindFamily(value.Kind())
+	switch {
+	case isTime:
+		if reflect.TypeOf(low) != timeType || reflect.TypeOf(high) != timeType {
+			return fmt.Errorf("bounds must be time.Time values, got %s and %s", describeType(low), describeType(high))
+		}
+	case family == reflect.Int || family == reflect.Uint || family == reflect.Float64:
+		if kindFamily(lowValue.Kind()) != family || kindFamily(highValue.Kind()) != family {
+			return fmt.Errorf("obtained type %T cannot be compared with bounds of types %T and %T", obtained, low, high)
+		}
+	default:
+		return fmt.Errorf("Between checker expected a number or time.Time, obtained was %s", describeType(obtained))
+	}
+	if family == reflect.Float64 && !isTime {
+		for _, v := range []reflect.Value{value, lowValue, highValue} {
+			if math.IsNaN(v.Float()) {
+				return failf("obtained %v is not between %v and %v, as NaN is not ordered", display(obtained), display(low), display(high))
+			}
+		}
+	}
+	compare, _ := seriesComparison(value.Type())
+	if compare(lowValue, highValue) > 0 {
+		return fmt.Errorf("low bound %v is greater than high bound %v", display(low), display(high))
+	}
+	switch {
+	case compare(value, lowValue) < 0:
+		return failf("obtained %v is not between %v and %v, being below the low bound", display(obtained), display(low), display(high))
+	case compare(value, highValue) > 0:
+		return failf("obtained %v is not between %v and %v, being above the high bound", display(obtained), display(low), display(high))
+	}
+	return nil
+}
@@ -0,0 +1,202 @@
+// Add a copyright
+// Add a licence
+
+package checkers_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/howbazaar/checkers"
+)
+
+func TestOrdered(t *testing.T) {
+	for _, test := range []struct {
+		description string
+		checker     checkers.Checker
+		obtained    interface{}
+		extras      []interface{}
+		err         string
+	}{
+		{
+			description: "greater than",
+			checker:     checkers.GreaterThan,
+			obtained:    1,
+			extras:      []interface{}{0},
+		}, {
+			description: "not greater than",
+			checker:     checkers.GreaterThan,
+			obtained:    0,
+			extras:      []interface{}{0},
+			err:         "obtained 0 is not greater than 0",
+		}, {
+			description: "less than",
+			checker:     checkers.LessThan,
+			obtained:    uint8(3),
+			extras:      []interface{}{uint64(4)},
+		}, {
+			description: "not less than",
+			checker:     checkers.LessThan,
+			obtained:    uint64(math.MaxUint64),
+			extras:      []interface{}{uint(1)},
+			err:         "obtained 18446744073709551615 is not less than 1",
+		}, {
+			description: "at least equal",
+			checker:     checkers.AtLeast,
+			obtained:    int8(-5),
+			extras:      []interface{}{int64(-5)},
+		}, {
+			description: "not at least",
+			checker:     checkers.AtLeast,
+			obtained:    int64(math.MinInt64),
+			extras:      []interface{}{int8(-1), checkers.Commentf("offset")},
+			err:         "obtained -9223372036854775808 is not at least -1\ncomment: offset",
+		}, {
+			description: "at most",
+			checker:     checkers.AtMost,
+			obtained:    float32(0.5),
+			extras:      []interface{}{0.5},
+		}, {
+			description: "not at most",
+			checker:     checkers.AtMost,
+			obtained:    1.5,
+			extras:      []interface{}{float32(1.25)},
+			err:         "obtained 1.5 is not at most 1.25",
+		}, {
+			description: "durations",
+			checker:     checkers.LessThan,
+			obtained:    time.Second,
+			extras:      []interface{}{2 * time.Second},
+		}, {
+			description: "NaN",
+			checker:     checkers.AtMost,
+			obtained:    math.NaN(),
+			extras:      []interface{}{1.0},
+			err:         "obtained NaN is not at most 1, as NaN is not ordered",
+		}, {
+			description: "mixed sorts",
+			checker:     checkers.GreaterThan,
+			obtained:    uint(1),
+			extras:      []interface{}{0},
+			err:         "obtained type uint cannot be compared with expected type int",
+		}, {
+			description: "not a number",
+			checker:     checkers.AtLeast,
+			obtained:    "10",
+			extras:      []interface{}{1},
+			err:         "AtLeast checker expected a number, obtained was type string",
+		}, {
+			description: "missing expected",
+			checker:     checkers.LessThan,
+			obtained:    1,
+			err:         "missing 'expected' value",
+		}, {
+			description: "too many",
+			checker:     checkers.AtMost,
+			obtained:    1,
+			extras:      []interface{}{2, 3},
+			err:         "too many arguments to checker AtMost, unexpected int(3)",
+		},
+	} {
+		err := test.checker.Check(test.obtained, test.extras...)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+		} else {
+			if test.err == "" {
+				t.Errorf("%s: unexpected error: %v", test.description, err)
+			} else if err.Error() != test.err {
+				t.Errorf("%s: error mismatch: \n\tobtained: %q\n\texpected: %q", test.description, err.Error(), test.err)
+			}
+		}
+	}
+}
+
+func TestBetween(t *testing.T) {
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	for _, test := range []struct {
+		description string
+		obtained    interface{}
+		extras      []interface{}
+		err         string
+	}{
+		{
+			description: "within",
+			obtained:    5,
+			extras:      []interface{}{1, 10},
+		}, {
+			description: "inclusive bounds",
+			obtained:    uint16(10),
+			extras:      []interface{}{uint8(10), uint64(10)},
+		}, {
+			description: "below",
+			obtained:    0.5,
+			extras:      []interface{}{1.0, float32(2)},
+			err:         "obtained 0.5 is not between 1 and 2, being below the low bound",
+		}, {
+			description: "above",
+			obtained:    11,
+			extras:      []interface{}{1, 10, checkers.Commentf("retries")},
+			err:         "obtained 11 is not between 1 and 10, being above the high bound\ncomment: retries",
+		}, {
+			description: "durations",
+			obtained:    30 * time.Millisecond,
+			extras:      []interface{}{10 * time.Millisecond, 50 * time.Millisecond},
+		}, {
+			description: "time within",
+			obtained:    start.Add(time.Minute),
+			extras:      []interface{}{start, end},
+		}, {
+			description: "time above",
+			obtained:    end.Add(time.Second),
+			extras:      []interface{}{start, end},
+			err:         "obtained 2024-03-01 13:00:01 +0000 UTC is not between 2024-03-01 12:00:00 +0000 UTC and 2024-03-01 13:00:00 +0000 UTC, being above the high bound",
+		}, {
+			description: "NaN",
+			obtained:    math.NaN(),
+			extras:      []interface{}{0.0, 1.0},
+			err:         "obtained NaN is not between 0 and 1, as NaN is not ordered",
+		}, {
+			description: "bounds reversed",
+			obtained:    5,
+			extras:      []interface{}{10, 1},
+			err:         "low bound 10 is greater than high bound 1",
+		}, {
+			description: "mixed sorts",
+			obtained:    5,
+			extras:      []interface{}{uint(1), 10},
+			err:         "obtained type int cannot be compared with bounds of types uint and int",
+		}, {
+			description: "time with number bounds",
+			obtained:    start,
+			extras:      []interface{}{nil, 10},
+			err:         "bounds must be time.Time values, got nil and type int",
+		}, {
+			description: "not ordered",
+			obtained:    "b",
+			extras:      []interface{}{"a", "c"},
+			err:         "Between checker expected a number or time.Time, obtained was type string",
+		}, {
+			description: "missing bounds",
+			obtained:    5,
+			extras:      []interface{}{1},
+			err:         "missing 'low' and 'high' values",
+		},
+	} {
+		err := checkers.Between.Check(test.obtained, test.extras...)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+		} else {
+			if test.err == "" {
+				t.Errorf("%s: unexpected error: %v", test.description, err)
+			} else if err.Error() != test.err {
+				t.Errorf("%s: error mismatch: \n\tobtained: %q\n\texpected: %q", test.description, err.Error(), test.err)
+			}
+		}
+	}
+}
@@ -0,0 +1,84 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"os/user"
+	"reflect"
+)
+
+// Patch sets the variable that dest points to to value, and restores
+// its original value when the test is cleaned up. It is for the
+// package level variables that code under test exposes so that it can
+// be tested, such as a function used in place of os.Hostname. A nil
+// value sets the variable to its zero value. The test fails immediately
+// if dest is not a non-nil pointer, or value cannot be assigned to the
+// variable.
+//
+//	// In the code under test:
+//	var hostname = os.Hostname
+//
+//	// In its tests:
+//	t.Patch(&hostname, func() (string, error) { return "db-1", nil })
+func (t *Test) Patch(dest, value interface{}) {
+	tb := t.testingTB()
+	tb.Helper()
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		tb.Fatalf("cannot patch %s, expected a non-nil pointer", describe(dest))
+		return
+	}
+	variable := v.Elem()
+	var newValue reflect.Value
+	if value == nil {
+		switch variable.Kind() {
+		case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Ptr, reflect.Slice, reflect.UnsafePointer:
+			newValue = reflect.Zero(variable.Type())
+		default:
+			tb.Fatalf("cannot patch variable of type %s with nil", variable.Type())
+			return
+		}
+	} else {
+		newValue = reflect.ValueOf(value)
+		if !newValue.Type().AssignableTo(variable.Type()) {
+			tb.Fatalf("cannot patch variable of type %s with %s", variable.Type(), describe(value))
+			return
+		}
+	}
+	original := reflect.New(variable.Type()).Elem()
+	original.Set(variable)
+	variable.Set(newValue)
+	tb.Cleanup(func() {
+		variable.Set(original)
+	})
+}
+
+// PatchHostname sets the function that dest points to, used by the
+// code under test in place of os.Hostname, to one that returns name,
+// restoring it when the test is cleaned up.
+func (t *Test) PatchHostname(dest *func() (string, error), name string) {
+	t.testingTB().Helper()
+	t.Patch(dest, func() (string, error) {
+		return name, nil
+	})
+}
+
+// PatchCurrentUser sets the function that dest points to, used by the
+// code under test in place of user.Current, to one that returns u,
+// restoring it when the test is cleaned up.
+func (t *Test) PatchCurrentUser(dest *func() (*user.User, error), u *user.User) {
+	t.testingTB().Helper()
+	t.Patch(dest, func() (*user.User, error) {
+		return u, nil
+	})
+}
+
+// PatchGOOS sets the variable that dest points to, used by the code
+// under test in place of runtime.GOOS so that its operating system
+// dependent branches can all be tested, to goos, restoring it when the
+// test is cleaned up.
+func (t *Test) PatchGOOS(dest *string, goos string) {
+	t.testingTB().Helper()
+	t.Patch(dest, goos)
+}
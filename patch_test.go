@@ -0,0 +1,115 @@
+// Add a copyright
+// Add a licence
+
+package checkers_test
+
+import (
+	"os"
+	"os/user"
+	"reflect"
+	"runtime"
+	"testing"
+
+	"github.com/howbazaar/checkers"
+)
+
+// The injectable functions and variables of a package under test.
+var (
+	hostname    = os.Hostname
+	currentUser = user.Current
+	goos        = runtime.GOOS
+)
+
+// machineID is the code under test, formatting a machine specific
+// identifier.
+func machineID() string {
+	host, _ := hostname()
+	u, _ := currentUser()
+	separator := "/"
+	if goos == "windows" {
+		separator = `\`
+	}
+	return host + separator + u.Username
+}
+
+func TestPatchFixtures(t *testing.T) {
+	r := checkers.NewRecordingTB()
+	r.Run(func(tb testing.TB) {
+		ct := &checkers.Test{TB: tb}
+		ct.PatchHostname(&hostname, "db-1")
+		ct.PatchCurrentUser(&currentUser, &user.User{Username: "alice"})
+		ct.PatchGOOS(&goos, "linux")
+		ct.Check(machineID(), checkers.Equals, "db-1/alice")
+		ct.PatchGOOS(&goos, "windows")
+		ct.Check(machineID(), checkers.Equals, `db-1\alice`)
+	})
+	if len(r.Errors()) != 0 {
+		t.Errorf("unexpected errors: %v", r.Errors())
+	}
+	if goos != runtime.GOOS {
+		t.Errorf("GOOS not restored, got %q", goos)
+	}
+	if reflect.ValueOf(hostname).Pointer() != reflect.ValueOf(os.Hostname).Pointer() {
+		t.Errorf("hostname not restored")
+	}
+	if reflect.ValueOf(currentUser).Pointer() != reflect.ValueOf(user.Current).Pointer() {
+		t.Errorf("current user not restored")
+	}
+}
+
+func TestPatch(t *testing.T) {
+	count := 1
+	var names []string
+	r := checkers.NewRecordingTB()
+	r.Run(func(tb testing.TB) {
+		ct := &checkers.Test{TB: tb}
+		ct.Patch(&count, 2)
+		ct.Patch(&names, nil)
+		ct.Patch(&names, []string{"a"})
+		ct.Check(count, checkers.Equals, 2)
+		ct.Check(names, checkers.DeepEquals, []string{"a"})
+	})
+	if len(r.Errors()) != 0 || count != 1 || names != nil {
+		t.Errorf("unexpected result: %v, %d, %v", r.Errors(), count, names)
+	}
+
+	for _, test := range []struct {
+		description string
+		dest        interface{}
+		value       interface{}
+		err         string
+	}{
+		{
+			description: "not a pointer",
+			dest:        count,
+			value:       2,
+			err:         "cannot patch int value 1, expected a non-nil pointer",
+		}, {
+			description: "nil pointer",
+			dest:        (*int)(nil),
+			value:       2,
+			err:         "cannot patch nil *int, expected a non-nil pointer",
+		}, {
+			description: "wrong type",
+			dest:        &count,
+			value:       "2",
+			err:         `cannot patch variable of type int with string value 2`,
+		}, {
+			description: "nil for non-nillable",
+			dest:        &count,
+			err:         "cannot patch variable of type int with nil",
+		},
+	} {
+		r := checkers.NewRecordingTB()
+		r.Run(func(tb testing.TB) {
+			ct := &checkers.Test{TB: tb}
+			ct.Patch(test.dest, test.value)
+		})
+		if err := checkers.DeepEquals.Check(r.Errors(), []string{test.err}); err != nil {
+			t.Errorf("%s: %v", test.description, err)
+		}
+	}
+	if count != 1 {
+		t.Errorf("count changed to %d", count)
+	}
+}
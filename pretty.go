@@ -0,0 +1,230 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// prettyPrinter renders values in a Go-like syntax spread over
+// multiple lines, with one field, element or map entry per line and
+// map keys sorted, so that the rendering of a value is stable and two
+// renderings can be usefully diffed.
+type prettyPrinter struct {
+	buf strings.Builder
+	// active holds the references currently being printed, so that
+	// cycles are printed as a marker instead of recursing forever.
+	active map[ref]bool
+
+	// oneLine has the value rendered on a single line.
+	oneLine bool
+	// maxDepth, if positive, limits how deeply nested values are
+	// rendered. Values beneath that depth are shown as "...".
+	maxDepth int
+	// maxElements, if positive, limits how many elements, entries or
+	// fields of each value are rendered, with the remainder
+	// summarised by a "...(+N more)" marker.
+	maxElements int
+}
+
+// prettyPrint returns the multiple line rendering of the value.
+func prettyPrint(v reflect.Value) string {
+	p := &prettyPrinter{active: make(map[ref]bool)}
+	p.print(v, 0)
+	return p.buf.String()
+}
+
+// limitedPrint returns the rendering of the value within the limits of
+// the configuration.
+func limitedPrint(v reflect.Value, c *deepEqualConfig, oneLine bool) string {
+	p := &prettyPrinter{
+		active:      make(map[ref]bool),
+		oneLine:     oneLine,
+		maxDepth:    c.maxDepth,
+		maxElements: c.maxElements,
+	}
+	p.print(v, 0)
+	return p.buf.String()
+}
+
+// open starts the rendering of a composite value.
+func (p *prettyPrinter) open(typ reflect.Type) {
+	p.buf.WriteString(typ.String() + "{")
+	if !p.oneLine {
+		p.buf.WriteString("\n")
+	}
+}
+
+// item starts the rendering of the i'th element of a composite value.
+func (p *prettyPrinter) item(i, depth int) {
+	if p.oneLine {
+		if i > 0 {
+			p.buf.WriteString(", ")
+		}
+		return
+	}
+	p.buf.WriteString(strings.Repeat("\t", depth+1))
+}
+
+// endItem finishes the rendering of an element of a composite value.
+func (p *prettyPrinter) endItem() {
+	if !p.oneLine {
+		p.buf.WriteString(",\n")
+	}
+}
+
+// close finishes the rendering of a composite value of n elements,
+// adding a marker for any elements that were left out.
+func (p *prettyPrinter) close(n, depth int) {
+	if p.maxElements > 0 && n > p.maxElements {
+		p.item(p.maxElements, depth)
+		fmt.Fprintf(&p.buf, "...(+%d more)", n-p.maxElements)
+		p.endItem()
+	}
+	if !p.oneLine {
+		p.buf.WriteString(strings.Repeat("\t", depth))
+	}
+	p.buf.WriteString("}")
+}
+
+// shown returns how many of the n elements of a composite value are
+// rendered.
+func (p *prettyPrinter) shown(n int) int {
+	if p.maxElements > 0 && n > p.maxElements {
+		return p.maxElements
+	}
+	return n
+}
+
+func (p *prettyPrinter) print(v reflect.Value, depth int) {
+	if !v.IsValid() {
+		p.buf.WriteString("<nil>")
+		return
+	}
+	if print := printerFor(v.Type()); print != nil {
+		p.buf.WriteString(print(interfaceOf(v)))
+		return
+	}
+	switch v.Kind() {
+	case reflect.Map, reflect.Slice, reflect.Ptr:
+		if !v.IsNil() {
+			r := ref{v.Pointer(), v.Type()}
+			if p.active[r] {
+				p.buf.WriteString("<cycle>")
+				return
+			}
+			p.active[r] = true
+			defer delete(p.active, r)
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			fmt.Fprintf(&p.buf, "%s(nil)", v.Type())
+			return
+		}
+		p.print(v.Elem(), depth)
+		return
+	case reflect.Ptr:
+		if v.IsNil() {
+			fmt.Fprintf(&p.buf, "(%s)(nil)", v.Type())
+			return
+		}
+		p.buf.WriteString("&")
+		p.print(v.Elem(), depth)
+		return
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+		if p.maxDepth > 0 && depth >= p.maxDepth {
+			fmt.Fprintf(&p.buf, "%s{...}", v.Type())
+			return
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		if v.Type() == timeType {
+			fmt.Fprintf(&p.buf, "%#v", printable(v))
+			return
+		}
+		n := v.NumField()
+		if n == 0 {
+			fmt.Fprintf(&p.buf, "%s{}", v.Type())
+			return
+		}
+		p.open(v.Type())
+		for i := 0; i < p.shown(n); i++ {
+			p.item(i, depth)
+			p.buf.WriteString(v.Type().Field(i).Name + ": ")
+			if isRedacted(v.Type().Field(i)) {
+				p.buf.WriteString(redactedText)
+			} else {
+				p.print(v.Field(i), depth+1)
+			}
+			p.endItem()
+		}
+		p.close(n, depth)
+	case reflect.Map:
+		if v.IsNil() {
+			fmt.Fprintf(&p.buf, "%s(nil)", v.Type())
+			return
+		}
+		n := v.Len()
+		if n == 0 {
+			fmt.Fprintf(&p.buf, "%s{}", v.Type())
+			return
+		}
+		p.open(v.Type())
+		for i, k := range sortedKeys(v)[:p.shown(n)] {
+			p.item(i, depth)
+			fmt.Fprintf(&p.buf, "%#v: ", interfaceOf(k))
+			p.print(v.MapIndex(k), depth+1)
+			p.endItem()
+		}
+		p.close(n, depth)
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			fmt.Fprintf(&p.buf, "%s(nil)", v.Type())
+			return
+		}
+		n := v.Len()
+		if n == 0 || (v.Type().Elem().Kind() == reflect.Uint8 && p.shown(n) == n) {
+			// Byte slices are more readable on a single line.
+			fmt.Fprintf(&p.buf, "%#v", interfaceOf(v))
+			return
+		}
+		p.open(v.Type())
+		for i := 0; i < p.shown(n); i++ {
+			p.item(i, depth)
+			p.print(v.Index(i), depth+1)
+			p.endItem()
+		}
+		p.close(n, depth)
+	default:
+		fmt.Fprintf(&p.buf, "%#v", interfaceOf(v))
+	}
+}
+
+// holdsComposite reports whether values of the type are, or directly
+// hold, maps or structs, which are rendered by the pretty printer
+// wherever they are shown so that map entries are always in the same
+// order and struct fields are named.
+func holdsComposite(typ reflect.Type) bool {
+	switch typ.Kind() {
+	case reflect.Map:
+		return true
+	case reflect.Struct:
+		return typ != timeType
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return holdsComposite(typ.Elem())
+	}
+	return false
+}
+
+// isMultiline reports whether the string spans more than one line.
+func isMultiline(s string) bool {
+	return strings.Contains(s, "\n")
+}
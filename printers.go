@@ -0,0 +1,155 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// valuesPrinters holds the printers registered with WithValuesPrinter,
+// by the type of value they render. Each type has a stack of printers,
+// the last of which is used, so that removing a printer restores the
+// one it replaced.
+var valuesPrinters = struct {
+	sync.RWMutex
+	byType map[reflect.Type][]*valuesPrinter
+}{byType: make(map[reflect.Type][]*valuesPrinter)}
+
+type valuesPrinter struct {
+	print func(interface{}) string
+}
+
+// WithValuesPrinter registers a function that renders values of the
+// same type as example wherever the checkers render a value in the
+// description of a failure, such as to hide secrets, show protocol
+// buffers as text or shorten long byte slices. It is used by Equals,
+// DeepEquals and the checkers built on them, for values of the type
+// itself and for values of the type held within other values, which are
+// then rendered field by field. The printer replaces any registered
+// before it for the type until the returned function is called to
+// remove it.
+//
+//	t.Cleanup(checkers.WithValuesPrinter(Password(""), func(interface{}) string {
+//		return "<password>"
+//	}))
+func WithValuesPrinter(example interface{}, print func(v interface{}) string) (remove func()) {
+	if example == nil || print == nil {
+		panic("checkers: WithValuesPrinter needs a typed example and a printer")
+	}
+	typ := reflect.TypeOf(example)
+	printer := &valuesPrinter{print}
+	valuesPrinters.Lock()
+	defer valuesPrinters.Unlock()
+	valuesPrinters.byType[typ] = append(valuesPrinters.byType[typ], printer)
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			valuesPrinters.Lock()
+			defer valuesPrinters.Unlock()
+			stack := valuesPrinters.byType[typ]
+			for i, p := range stack {
+				if p == printer {
+					stack = append(stack[:i:i], stack[i+1:]...)
+					break
+				}
+			}
+			if len(stack) == 0 {
+				delete(valuesPrinters.byType, typ)
+			} else {
+				valuesPrinters.byType[typ] = stack
+			}
+		})
+	}
+}
+
+// printerFor returns the printer registered for the type, if any.
+func printerFor(typ reflect.Type) func(interface{}) string {
+	valuesPrinters.RLock()
+	defer valuesPrinters.RUnlock()
+	stack := valuesPrinters.byType[typ]
+	if len(stack) == 0 {
+		return nil
+	}
+	return stack[len(stack)-1].print
+}
+
+// hasPrinter reports whether values of the type are, or can hold,
+// values rendered by a registered printer or struct fields that are to
+// be redacted, so that they must be rendered by the pretty printer.
+func hasPrinter(typ reflect.Type) bool {
+	valuesPrinters.RLock()
+	printers := len(valuesPrinters.byType) > 0
+	valuesPrinters.RUnlock()
+	return holdsPrinted(typ, printers, make(map[reflect.Type]bool))
+}
+
+func holdsPrinted(typ reflect.Type, anyPrinters bool, seen map[reflect.Type]bool) bool {
+	if seen[typ] {
+		return false
+	}
+	seen[typ] = true
+	if anyPrinters && printerFor(typ) != nil {
+		return true
+	}
+	switch typ.Kind() {
+	case reflect.Interface:
+		// Any value may be held in an interface.
+		return anyPrinters
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Chan:
+		return holdsPrinted(typ.Elem(), anyPrinters, seen)
+	case reflect.Map:
+		return holdsPrinted(typ.Key(), anyPrinters, seen) || holdsPrinted(typ.Elem(), anyPrinters, seen)
+	case reflect.Struct:
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			if isRedacted(field) || holdsPrinted(field.Type, anyPrinters, seen) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// rendered is a value already rendered for a failure description, which
+// formats as its rendering with both %v and %#v.
+type rendered string
+
+func (r rendered) String() string {
+	return string(r)
+}
+
+func (r rendered) GoString() string {
+	return string(r)
+}
+
+// display returns the value to format with %v in a failure
+// description. Values that are, or hold, values with a registered
+// printer, maps or structs are rendered by the pretty printer, unless
+// they describe themselves with an Error or String method.
+func display(v interface{}) interface{} {
+	if v == nil {
+		return v
+	}
+	typ := reflect.TypeOf(v)
+	if hasPrinter(typ) {
+		return rendered(prettyPrintLine(reflect.ValueOf(v)))
+	}
+	switch v.(type) {
+	case error, fmt.Stringer:
+		return v
+	}
+	if holdsComposite(typ) {
+		return rendered(prettyPrintLine(reflect.ValueOf(v)))
+	}
+	return v
+}
+
+// prettyPrintLine returns the single line rendering of the value.
+func prettyPrintLine(v reflect.Value) string {
+	p := &prettyPrinter{active: make(map[ref]bool), oneLine: true}
+	p.print(v, 0)
+	return p.buf.String()
+}
@@ -0,0 +1,185 @@
+// Add a copyright
+// Add a licence
+
+package checkers_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/howbazaar/checkers"
+)
+
+type password string
+
+type credentials struct {
+	User     string
+	Password password
+}
+
+func TestWithValuesPrinter(t *testing.T) {
+	remove := checkers.WithValuesPrinter(password(""), func(interface{}) string {
+		return "<password>"
+	})
+	defer remove()
+	for _, test := range []struct {
+		description string
+		checker     checkers.Checker
+		obtained    interface{}
+		extras      []interface{}
+		err         string
+	}{
+		{
+			description: "equals",
+			checker:     checkers.Equals,
+			obtained:    password("hunter2"),
+			extras:      []interface{}{password("swordfish")},
+			err:         "expected checkers_test.password value <password>, got <password>",
+		}, {
+			description: "deep equals field",
+			checker:     checkers.DeepEquals,
+			obtained:    credentials{"bob", "hunter2"},
+			extras:      []interface{}{credentials{"bob", "swordfish"}},
+			err:         "mismatch at .Password: unequal; obtained <password>; expected <password>",
+		}, {
+			description: "deep equals struct",
+			checker:     checkers.DeepEquals,
+			obtained:    []credentials{{"bob", "hunter2"}},
+			extras:      []interface{}{[]credentials{{"alice", "swordfish"}}},
+			err: "2 mismatches:\n" +
+				"\tmismatch at [0].User: unequal; obtained \"bob\"; expected \"alice\"\n" +
+				"\tmismatch at [0].Password: unequal; obtained <password>; expected <password>\n" +
+				"diff (-obtained +expected):\n" +
+				" []checkers_test.credentials{\n" +
+				" \tcheckers_test.credentials{\n" +
+				"-\t\tUser: \"bob\",\n" +
+				"+\t\tUser: \"alice\",\n" +
+				" \t\tPassword: <password>,\n" +
+				" \t},\n" +
+				" }",
+		}, {
+			description: "deep equals diff",
+			checker:     checkers.DeepEquals,
+			obtained:    []credentials{{"bob", "hunter2"}},
+			extras:      []interface{}{[]*credentials{{"bob", "hunter2"}}},
+			err: "mismatch at top level: type mismatch []checkers_test.credentials vs []*checkers_test.credentials; " +
+				"obtained []checkers_test.credentials{checkers_test.credentials{User: \"bob\", Password: <password>}}; " +
+				"expected []*checkers_test.credentials{&checkers_test.credentials{User: \"bob\", Password: <password>}}\n" +
+				"diff (-obtained +expected):\n" +
+				"-[]checkers_test.credentials{\n" +
+				"-\tcheckers_test.credentials{\n" +
+				"+[]*checkers_test.credentials{\n" +
+				"+\t&checkers_test.credentials{\n" +
+				" \t\tUser: \"bob\",\n" +
+				" \t\tPassword: <password>,\n" +
+				" \t},\n" +
+				" ...",
+		}, {
+			description: "described value",
+			checker:     checkers.PanicsWhenCalled(password("hunter2")),
+			obtained:    func(int) {},
+			extras:      []interface{}{".*"},
+			err:         "argument 0 must be int, got checkers_test.password value <password>",
+		},
+	} {
+		err := test.checker.Check(test.obtained, test.extras...)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+		} else {
+			if test.err == "" {
+				t.Errorf("%s: unexpected error: %v", test.description, err)
+			} else {
+				if err.Error() != test.err {
+					t.Errorf("%s: error mismatch: \n\tobtained: %q\n\texpected: %q", test.description, err.Error(), test.err)
+				}
+			}
+		}
+	}
+}
+
+func TestWithValuesPrinterRemove(t *testing.T) {
+	outer := checkers.WithValuesPrinter(password(""), func(v interface{}) string {
+		return "outer"
+	})
+	inner := checkers.WithValuesPrinter(password(""), func(v interface{}) string {
+		return fmt.Sprintf("%d characters", len(v.(password)))
+	})
+	check := func(expected string) {
+		t.Helper()
+		err := checkers.Equals.Check(password("hunter2"), password("x"))
+		if err == nil || err.Error() != expected {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+	check("expected checkers_test.password value 1 characters, got 7 characters")
+	outer()
+	check("expected checkers_test.password value 1 characters, got 7 characters")
+	inner()
+	inner()
+	check("expected checkers_test.password value x, got hunter2\n" +
+		"strings differ at byte 0 (rune 0):\n" +
+		"\tobtained: \"hunter2\"\n" +
+		"\texpected: \"x\"\n" +
+		"\t           ^")
+}
+
+type point struct {
+	X, Y int
+}
+
+func TestPrettyPrintedValues(t *testing.T) {
+	for _, test := range []struct {
+		description string
+		checker     checkers.Checker
+		obtained    interface{}
+		extras      []interface{}
+		err         string
+	}{
+		{
+			description: "struct fields named",
+			checker:     checkers.PanicsWhenCalled(point{1, 2}),
+			obtained:    func(int) {},
+			extras:      []interface{}{".*"},
+			err:         "argument 0 must be int, got checkers_test.point value checkers_test.point{X: 1, Y: 2}",
+		}, {
+			description: "map keys sorted",
+			checker:     checkers.PanicsWhenCalled(map[string]int{"c": 3, "a": 1, "b": 2}),
+			obtained:    func(int) {},
+			extras:      []interface{}{".*"},
+			err:         `argument 0 must be int, got map[string]int value map[string]int{"a": 1, "b": 2, "c": 3}`,
+		}, {
+			description: "map values sorted in mismatch",
+			checker:     checkers.DeepEquals,
+			obtained:    map[string]point{"b": {2, 2}, "a": {1, 1}},
+			extras:      []interface{}{map[string]point{"a": {1, 1}}},
+			err: "mismatch at top level: length mismatch, 2 vs 1; " +
+				`obtained map[string]checkers_test.point{"a": checkers_test.point{X: 1, Y: 1}, "b": checkers_test.point{X: 2, Y: 2}}; ` +
+				`expected map[string]checkers_test.point{"a": checkers_test.point{X: 1, Y: 1}}` +
+				"\ndiff (-obtained +expected):\n" +
+				" ...\n" +
+				" \t\tX: 1,\n" +
+				" \t\tY: 1,\n" +
+				" \t},\n" +
+				"-\t\"b\": checkers_test.point{\n" +
+				"-\t\tX: 2,\n" +
+				"-\t\tY: 2,\n" +
+				"-\t},\n" +
+				" }",
+		}, {
+			description: "errors keep their message",
+			checker:     checkers.PanicsWhenCalled(fmt.Errorf("boom")),
+			obtained:    func(int) {},
+			extras:      []interface{}{".*"},
+			err:         "argument 0 must be int, got *errors.errorString value boom",
+		},
+	} {
+		err := test.checker.Check(test.obtained, test.extras...)
+		if err == nil {
+			t.Errorf("%s: expected error: %q", test.description, test.err)
+		} else if err.Error() != test.err {
+			t.Errorf("%s: error mismatch: \n\tobtained: %q\n\texpected: %q", test.description, err.Error(), test.err)
+		}
+	}
+}
@@ -0,0 +1,10 @@
+module github.com/howbazaar/checkers/qtadapter
+
+go 1.14
+
+replace github.com/howbazaar/checkers => ../
+
+require (
+	github.com/frankban/quicktest v1.14.6
+	github.com/howbazaar/checkers v0.0.0-00010101000000-000000000000
+)
@@ -0,0 +1,89 @@
+// Add a copyright
+// Add a licence
+
+// Package qtadapter adapts checkers written for
+// github.com/frankban/quicktest so they can be used with the checkers
+// package, and the reverse. It is a separate module so that only code
+// using it depends on quicktest.
+package qtadapter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/howbazaar/checkers"
+)
+
+type fromQuicktest struct {
+	checker qt.Checker
+}
+
+// FromQuicktest returns a Checker that runs the quicktest checker. The
+// obtained value is passed as the quicktest got argument, and the extra
+// values as its remaining arguments. Any comments made with
+// checkers.Commentf are added to the failure rather than passed to the
+// quicktest checker.
+func FromQuicktest(checker qt.Checker) checkers.Checker {
+	return fromQuicktest{checker}
+}
+
+func (c fromQuicktest) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := checkers.SplitComments(extras)
+	defer checkers.AddComments(&err, comments)
+	names := c.checker.ArgNames()
+	if len(extras) != len(names)-1 {
+		return fmt.Errorf("checker expects %d arguments (%s), got %d",
+			len(names), strings.Join(names, ", "), len(extras)+1)
+	}
+	var notes []string
+	note := func(key string, value interface{}) {
+		notes = append(notes, fmt.Sprintf("%s: %#v", key, value))
+	}
+	err = c.checker.Check(obtained, extras, note)
+	if err == nil {
+		return nil
+	}
+	var lines []string
+	if err != qt.ErrSilent {
+		lines = append(lines, err.Error())
+	} else {
+		lines = append(lines, "check failed")
+	}
+	lines = append(lines, notes...)
+	if err != qt.ErrSilent && !qt.IsBadCheck(err) {
+		args := append([]interface{}{obtained}, extras...)
+		for i, name := range names {
+			lines = append(lines, fmt.Sprintf("%s: %#v", name, args[i]))
+		}
+	}
+	return errors.New(strings.Join(lines, "\n"))
+}
+
+type toQuicktest struct {
+	checker checkers.Checker
+	names   []string
+}
+
+// ToQuicktest returns a quicktest checker that runs the checker. The
+// argument names are used by quicktest when reporting failures. If none
+// are given, the checker is taken to accept a got and a want value.
+func ToQuicktest(checker checkers.Checker, argNames ...string) qt.Checker {
+	if len(argNames) == 0 {
+		argNames = []string{"got", "want"}
+	}
+	return toQuicktest{
+		checker: checker,
+		names:   argNames,
+	}
+}
+
+func (c toQuicktest) ArgNames() []string {
+	return c.names
+}
+
+func (c toQuicktest) Check(got interface{}, args []interface{}, note func(key string, value interface{})) error {
+	return c.checker.Check(got, args...)
+}
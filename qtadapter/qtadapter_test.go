@@ -0,0 +1,91 @@
+// Add a copyright
+// Add a licence
+
+package qtadapter_test
+
+import (
+	"errors"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/howbazaar/checkers"
+	"github.com/howbazaar/checkers/qtadapter"
+)
+
+func TestFromQuicktest(t *testing.T) {
+	for _, test := range []struct {
+		description string
+		checker     qt.Checker
+		obtained    interface{}
+		extras      []interface{}
+		err         string
+	}{
+		{
+			description: "equals passes",
+			checker:     qt.Equals,
+			obtained:    42,
+			extras:      []interface{}{42},
+		}, {
+			description: "equals fails",
+			checker:     qt.Equals,
+			obtained:    42,
+			extras:      []interface{}{43},
+			err:         "values are not equal\ngot: 42\nwant: 43",
+		}, {
+			description: "single argument checker",
+			checker:     qt.IsNil,
+			obtained:    errors.New("oops"),
+			err:         "got non-nil error\ngot: &errors.errorString{s:\"oops\"}",
+		}, {
+			description: "bad check omits arguments",
+			checker:     qt.ErrorMatches,
+			obtained:    errors.New("oops"),
+			extras:      []interface{}{42},
+			err:         "bad check: regexp is not a string\nregexp: 42",
+		}, {
+			description: "wrong number of arguments",
+			checker:     qt.Equals,
+			obtained:    42,
+			err:         "checker expects 2 arguments (got, want), got 1",
+		}, {
+			description: "comment passes",
+			checker:     qt.Equals,
+			obtained:    42,
+			extras:      []interface{}{42, checkers.Commentf("answer")},
+		}, {
+			description: "comment on failure",
+			checker:     qt.Equals,
+			obtained:    42,
+			extras:      []interface{}{43, checkers.Commentf("answer")},
+			err:         "values are not equal\ngot: 42\nwant: 43\ncomment: answer",
+		},
+	} {
+		err := qtadapter.FromQuicktest(test.checker).Check(test.obtained, test.extras...)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+			continue
+		}
+		if test.err == "" {
+			t.Errorf("%s: unexpected error: %v", test.description, err)
+		} else if err.Error() != test.err {
+			t.Errorf("%s: error mismatch: \n\tobtained: %q\n\texpected: %q", test.description, err.Error(), test.err)
+		}
+	}
+}
+
+func TestToQuicktest(t *testing.T) {
+	c := qt.New(t)
+	equals := qtadapter.ToQuicktest(checkers.Equals)
+	c.Assert(equals.ArgNames(), qt.DeepEquals, []string{"got", "want"})
+	c.Assert(42, equals, 42)
+	c.Assert(42, qt.Not(equals), 43)
+
+	matches := qtadapter.ToQuicktest(checkers.Matches, "got", "pattern")
+	c.Assert(matches.ArgNames(), qt.DeepEquals, []string{"got", "pattern"})
+	c.Assert("testing", matches, "test.*")
+	err := matches.Check("testing", []interface{}{"foo"}, nil)
+	c.Assert(err, qt.ErrorMatches, `"testing" did not match pattern "\^foo\$"`)
+}
@@ -0,0 +1,72 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"time"
+)
+
+// PatchRandSource seeds the global source of math/rand with the seed,
+// so that code using the top level functions of math/rand generates the
+// same values on every run, and reseeds it randomly when the test is
+// cleaned up. As the global source is shared, tests that patch it must
+// not run in parallel.
+//
+// It also returns a *rand.Rand with its own source seeded with the
+// seed, for code that takes its source of randomness as an argument,
+// which is unaffected by other users of math/rand. From Go 1.24 seeding
+// the global source does nothing unless the module's go version is
+// older or GODEBUG has randseednop=0, so code that is to be tested this
+// way should take a *rand.Rand.
+func (t *Test) PatchRandSource(seed int64) *rand.Rand {
+	rand.Seed(seed)
+	t.testingTB().Cleanup(func() {
+		rand.Seed(time.Now().UnixNano())
+	})
+	return rand.New(rand.NewSource(seed))
+}
+
+type generatesSequence struct{}
+
+// GeneratesSequence checker calls the obtained function, which takes no
+// arguments and returns a single value, once for each element of the
+// expected slice, and checks with DeepEquals that it returns those
+// values in order. With a seeded source of randomness it checks what
+// randomness dependent code generates.
+//
+//	r := t.PatchRandSource(42)
+//	t.Check(func() int { return r.Intn(6) }, checkers.GeneratesSequence, []int{5, 3, 5})
+var GeneratesSequence Checker = generatesSequence{}
+
+func (generatesSequence) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if len(extras) == 0 {
+		return errors.New("missing 'expected' value")
+	}
+	expected, extras := extras[0], extras[1:]
+	if err := unexpectedExtras("GeneratesSequence", extras); err != nil {
+		return err
+	}
+	want := reflect.ValueOf(expected)
+	if want.Kind() != reflect.Slice {
+		return fmt.Errorf("expected value must be a slice, got %s", describeType(expected))
+	}
+	f := reflect.ValueOf(obtained)
+	if f.Kind() != reflect.Func || f.IsNil() || f.Type().NumIn() != 0 || f.Type().NumOut() != 1 {
+		return fmt.Errorf("GeneratesSequence checker expected a function taking no arguments and returning one value, obtained was %s", describeType(obtained))
+	}
+	if !f.Type().Out(0).AssignableTo(want.Type().Elem()) {
+		return fmt.Errorf("function returns %s, which cannot be an element of expected type %s", f.Type().Out(0), want.Type())
+	}
+	got := reflect.MakeSlice(want.Type(), want.Len(), want.Len())
+	for i := 0; i < want.Len(); i++ {
+		got.Index(i).Set(f.Call(nil)[0])
+	}
+	return DeepEquals.Check(got.Interface(), expected)
+}
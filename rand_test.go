@@ -0,0 +1,106 @@
+// Add a copyright
+// Add a licence
+
+package checkers_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/howbazaar/checkers"
+)
+
+func TestPatchRandSource(t *testing.T) {
+	var first []int
+	for i := 0; i < 2; i++ {
+		ct := &checkers.Test{TB: t}
+		r := ct.PatchRandSource(42)
+		values := []int{rand.Intn(1000), rand.Intn(1000), rand.Intn(1000)}
+		if first == nil {
+			first = values
+		} else if err := checkers.DeepEquals.Check(values, first); err != nil {
+			t.Errorf("global source not seeded: %v", err)
+		}
+		if err := checkers.GeneratesSequence.Check(func() int { return r.Intn(1000) }, first); err != nil {
+			t.Errorf("returned source not seeded: %v", err)
+		}
+	}
+}
+
+func TestGeneratesSequence(t *testing.T) {
+	next := 0
+	counter := func() int {
+		next++
+		return next
+	}
+	for _, test := range []struct {
+		description string
+		obtained    interface{}
+		extras      []interface{}
+		err         string
+	}{
+		{
+			description: "sequence",
+			obtained:    counter,
+			extras:      []interface{}{[]int{1, 2, 3}},
+		}, {
+			description: "continues",
+			obtained:    counter,
+			extras:      []interface{}{[]int{4, 6}},
+			err: "mismatch at [1]: unequal; obtained 5; expected 6\n" +
+				"diff (-obtained +expected):\n" +
+				" []int{\n" +
+				" \t4,\n" +
+				"-\t5,\n" +
+				"+\t6,\n" +
+				" }",
+		}, {
+			description: "interface elements",
+			obtained:    func() error { return nil },
+			extras:      []interface{}{[]interface{}{nil, nil}},
+		}, {
+			description: "empty",
+			obtained:    counter,
+			extras:      []interface{}{[]int{}},
+		}, {
+			description: "wrong element type",
+			obtained:    counter,
+			extras:      []interface{}{[]string{"1"}},
+			err:         "function returns int, which cannot be an element of expected type []string",
+		}, {
+			description: "not a function",
+			obtained:    42,
+			extras:      []interface{}{[]int{42}},
+			err:         "GeneratesSequence checker expected a function taking no arguments and returning one value, obtained was type int",
+		}, {
+			description: "function taking arguments",
+			obtained:    rand.Intn,
+			extras:      []interface{}{[]int{42}},
+			err:         "GeneratesSequence checker expected a function taking no arguments and returning one value, obtained was type func(int) int",
+		}, {
+			description: "expected not a slice",
+			obtained:    counter,
+			extras:      []interface{}{1},
+			err:         "expected value must be a slice, got type int",
+		}, {
+			description: "missing expected",
+			obtained:    counter,
+			err:         "missing 'expected' value",
+		},
+	} {
+		err := checkers.GeneratesSequence.Check(test.obtained, test.extras...)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+		} else {
+			if test.err == "" {
+				t.Errorf("%s: unexpected error: %v", test.description, err)
+			} else {
+				if err.Error() != test.err {
+					t.Errorf("%s: error mismatch: \n\tobtained: %q\n\texpected: %q", test.description, err.Error(), test.err)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,198 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+)
+
+// defaultReadLimit is the most that the reader checkers read, unless
+// changed with ReadLimit.
+const defaultReadLimit = 1 << 20
+
+// ReaderOption is an extra value understood by the ReaderEquals and
+// ReaderMatches checkers.
+type ReaderOption struct {
+	limit int64
+}
+
+// ReadLimit returns a ReaderOption that sets the most bytes that are
+// read from the reader. A reader holding more is reported as a failure
+// rather than read in full. The default limit is 1MiB.
+func ReadLimit(n int64) ReaderOption {
+	return ReaderOption{limit: n}
+}
+
+// readerArgs separates the reader options from the other extra values,
+// returning the read limit they set.
+func readerArgs(extras []interface{}) ([]interface{}, int64) {
+	limit := int64(defaultReadLimit)
+	var rest []interface{}
+	for _, extra := range extras {
+		if option, ok := extra.(ReaderOption); ok {
+			limit = option.limit
+		} else {
+			rest = append(rest, extra)
+		}
+	}
+	return rest, limit
+}
+
+// readAll reads all of the reader, up to the limit.
+func readAll(checker string, obtained interface{}, limit int64) ([]byte, error) {
+	r, ok := obtained.(io.Reader)
+	if !ok {
+		return nil, fmt.Errorf("%s checker expected io.Reader, obtained was %s", checker, describeType(obtained))
+	}
+	// One more byte than the limit is read to find readers holding more,
+	// unless no reader could.
+	n := limit
+	if n < math.MaxInt64 {
+		n++
+	}
+	content, err := ioutil.ReadAll(io.LimitReader(r, n))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read: %v", err)
+	}
+	if int64(len(content)) > limit {
+		return nil, fmt.Errorf("reader has more than the limit of %d bytes", limit)
+	}
+	return content, nil
+}
+
+// expectedContent returns the expected value as bytes.
+func expectedContent(expected interface{}) ([]byte, error) {
+	switch expected := expected.(type) {
+	case string:
+		return []byte(expected), nil
+	case []byte:
+		return expected, nil
+	}
+	return nil, fmt.Errorf("expected value must be a string or []byte, got %s", describeType(expected))
+}
+
+// contentMismatch describes the difference in content, with a diff when
+// the content spans multiple lines, and where the content first differs.
+func contentMismatch(obtained, expected []byte) error {
+	return lazyFailure(func() string {
+		message := fmt.Sprintf("expected content %q, got %q", expected, obtained)
+		if isMultiline(string(obtained)) || isMultiline(string(expected)) {
+			message += "\ndiff (-obtained +expected):\n" + lineDiff(string(obtained), string(expected))
+		}
+		return message + "\n" + stringDiff(string(obtained), string(expected))
+	})
+}
+
+type readerEquals struct{}
+
+// ReaderEquals checker reads all of the obtained io.Reader and checks
+// that its content equals the expected string or []byte. A ReadLimit
+// option may follow the expected value.
+var ReaderEquals Checker = readerEquals{}
+
+func (readerEquals) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	extras, limit := readerArgs(extras)
+	if len(extras) == 0 {
+		return errors.New("missing 'expected' value")
+	}
+	expected, extras := extras[0], extras[1:]
+	if err := unexpectedExtras("ReaderEquals", extras); err != nil {
+		return err
+	}
+	want, err := expectedContent(expected)
+	if err != nil {
+		return err
+	}
+	content, err := readAll("ReaderEquals", obtained, limit)
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(content, want) {
+		return nil
+	}
+	return contentMismatch(content, want)
+}
+
+type readerMatches struct{}
+
+// ReaderMatches checker reads all of the obtained io.Reader and checks
+// that its content matches the expected pattern in full, as with
+// Matches. A ReadLimit option may follow the pattern.
+var ReaderMatches Checker = readerMatches{}
+
+func (readerMatches) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	extras, limit := readerArgs(extras)
+	if len(extras) == 0 {
+		return errors.New("missing 'expected' value")
+	}
+	expected, extras := extras[0], extras[1:]
+	if err := unexpectedExtras("ReaderMatches", extras); err != nil {
+		return err
+	}
+	pattern, ok := expected.(string)
+	if !ok {
+		return errors.New("expected value must be a string containing a regexp pattern")
+	}
+	re, err := compilePattern(pattern)
+	if err != nil {
+		return err
+	}
+	content, err := readAll("ReaderMatches", obtained, limit)
+	if err != nil {
+		return err
+	}
+	return matchRegexp(string(content), re)
+}
+
+type writesEqual struct{}
+
+// WritesEqual checker calls the obtained function with a buffer, and
+// checks that what it writes equals the expected string or []byte. The
+// function may be a func(io.Writer) or a func(io.Writer) error, for
+// which an error is reported as a failure.
+//
+//	t.Check(func(w io.Writer) error {
+//		return tmpl.Execute(w, data)
+//	}, checkers.WritesEqual, "expected output")
+var WritesEqual Checker = writesEqual{}
+
+func (writesEqual) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if len(extras) == 0 {
+		return errors.New("missing 'expected' value")
+	}
+	expected, extras := extras[0], extras[1:]
+	if err := unexpectedExtras("WritesEqual", extras); err != nil {
+		return err
+	}
+	want, err := expectedContent(expected)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	switch f := obtained.(type) {
+	case func(io.Writer):
+		f(&buf)
+	case func(io.Writer) error:
+		if err := f(&buf); err != nil {
+			return fmt.Errorf("function returned error: %v", err)
+		}
+	default:
+		return fmt.Errorf("WritesEqual checker expected func(io.Writer) or func(io.Writer) error, obtained was %s", describeType(obtained))
+	}
+	if bytes.Equal(buf.Bytes(), want) {
+		return nil
+	}
+	return contentMismatch(buf.Bytes(), want)
+}
@@ -0,0 +1,157 @@
+// Add a copyright
+// Add a licence
+
+package checkers_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/howbazaar/checkers"
+)
+
+func TestReaderCheckers(t *testing.T) {
+	for _, test := range []struct {
+		description string
+		checker     checkers.Checker
+		obtained    interface{}
+		extras      []interface{}
+		err         string
+	}{
+		{
+			description: "equal string",
+			checker:     checkers.ReaderEquals,
+			obtained:    strings.NewReader("hello"),
+			extras:      []interface{}{"hello"},
+		}, {
+			description: "equal bytes",
+			checker:     checkers.ReaderEquals,
+			obtained:    bytes.NewBufferString("hello"),
+			extras:      []interface{}{[]byte("hello")},
+		}, {
+			description: "unequal",
+			checker:     checkers.ReaderEquals,
+			obtained:    strings.NewReader("hello"),
+			extras:      []interface{}{"world"},
+			err: `expected content "world", got "hello"` + "\n" +
+				"strings differ at byte 0 (rune 0):\n" +
+				"\tobtained: \"hello\"\n" +
+				"\texpected: \"world\"\n" +
+				"\t           ^",
+		}, {
+			description: "unequal lines",
+			checker:     checkers.ReaderEquals,
+			obtained:    strings.NewReader("a\nb\nc"),
+			extras:      []interface{}{"a\nB\nc"},
+			err: "expected content \"a\\nB\\nc\", got \"a\\nb\\nc\"\ndiff (-obtained +expected):\n a\n-b\n+B\n c\n" +
+				"strings differ at byte 2 (rune 2):\n" +
+				"\tobtained: \"a\\nb\\nc\"\n" +
+				"\texpected: \"a\\nB\\nc\"\n" +
+				"\t              ^",
+		}, {
+			description: "over limit",
+			checker:     checkers.ReaderEquals,
+			obtained:    strings.NewReader("hello"),
+			extras:      []interface{}{"hello", checkers.ReadLimit(4)},
+			err:         "reader has more than the limit of 4 bytes",
+		}, {
+			description: "at limit",
+			checker:     checkers.ReaderEquals,
+			obtained:    strings.NewReader("hello"),
+			extras:      []interface{}{checkers.ReadLimit(5), "hello"},
+		}, {
+			description: "largest limit",
+			checker:     checkers.ReaderEquals,
+			obtained:    strings.NewReader("hello"),
+			extras:      []interface{}{"hello", checkers.ReadLimit(math.MaxInt64)},
+		}, {
+			description: "read error",
+			checker:     checkers.ReaderEquals,
+			obtained:    iotest.TimeoutReader(iotest.OneByteReader(strings.NewReader("hello"))),
+			extras:      []interface{}{"hello"},
+			err:         "unable to read: timeout",
+		}, {
+			description: "not a reader",
+			checker:     checkers.ReaderEquals,
+			obtained:    "hello",
+			extras:      []interface{}{"hello"},
+			err:         "ReaderEquals checker expected io.Reader, obtained was type string",
+		}, {
+			description: "bad expected",
+			checker:     checkers.ReaderEquals,
+			obtained:    strings.NewReader("hello"),
+			extras:      []interface{}{5},
+			err:         "expected value must be a string or []byte, got type int",
+		}, {
+			description: "matches",
+			checker:     checkers.ReaderMatches,
+			obtained:    strings.NewReader("hello"),
+			extras:      []interface{}{"h.*o"},
+		}, {
+			description: "does not match",
+			checker:     checkers.ReaderMatches,
+			obtained:    strings.NewReader("hello"),
+			extras:      []interface{}{"h.*x"},
+			err:         `"hello" did not match pattern "^h.*x$"`,
+		}, {
+			description: "matches too many",
+			checker:     checkers.ReaderMatches,
+			obtained:    strings.NewReader("hello"),
+			extras:      []interface{}{"h.*x", 1},
+			err:         "too many arguments to checker ReaderMatches, unexpected int(1)",
+		}, {
+			description: "writes",
+			checker:     checkers.WritesEqual,
+			obtained:    func(w io.Writer) { fmt.Fprint(w, "hello") },
+			extras:      []interface{}{"hello"},
+		}, {
+			description: "writes with error",
+			checker:     checkers.WritesEqual,
+			obtained:    func(w io.Writer) error { _, err := fmt.Fprint(w, "hello"); return err },
+			extras:      []interface{}{"hello"},
+		}, {
+			description: "writes something else",
+			checker:     checkers.WritesEqual,
+			obtained:    func(w io.Writer) { fmt.Fprint(w, "hello") },
+			extras:      []interface{}{"world"},
+			err: `expected content "world", got "hello"` + "\n" +
+				"strings differ at byte 0 (rune 0):\n" +
+				"\tobtained: \"hello\"\n" +
+				"\texpected: \"world\"\n" +
+				"\t           ^",
+		}, {
+			description: "write fails",
+			checker:     checkers.WritesEqual,
+			obtained:    func(w io.Writer) error { return errors.New("oops") },
+			extras:      []interface{}{""},
+			err:         "function returned error: oops",
+		}, {
+			description: "not a writing function",
+			checker:     checkers.WritesEqual,
+			obtained:    func() {},
+			extras:      []interface{}{""},
+			err:         "WritesEqual checker expected func(io.Writer) or func(io.Writer) error, obtained was type func()",
+		},
+	} {
+		err := test.checker.Check(test.obtained, test.extras...)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+		} else {
+			if test.err == "" {
+				t.Errorf("%s: unexpected error: %v", test.description, err)
+			} else {
+				if err.Error() != test.err {
+					t.Errorf("%s: error mismatch: \n\tobtained: %q\n\texpected: %q", test.description, err.Error(), test.err)
+				}
+			}
+		}
+	}
+}
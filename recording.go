@@ -0,0 +1,210 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// RecordingTB is a testing.TB that records the failures, skips and
+// log messages reported to it instead of acting on them, so that
+// custom checkers and suites can have their failure behaviour tested.
+//
+// Like a *testing.T, the FailNow, Fatal, Fatalf, SkipNow, Skip and Skipf
+// methods stop the calling goroutine, so code that may call them should
+// be run with the Run method. Methods of testing.TB added after Cleanup
+// are not implemented and panic if called.
+type RecordingTB struct {
+	testing.TB
+
+	mu       sync.Mutex
+	failed   bool
+	skipped  bool
+	errors   []string
+	logs     []string
+	skips    []string
+	cleanups []func()
+}
+
+// NewRecordingTB returns a new RecordingTB that has nothing recorded.
+func NewRecordingTB() *RecordingTB {
+	return &RecordingTB{}
+}
+
+// Run calls f with the RecordingTB on a new goroutine and waits for it
+// to finish, either by returning or by a call that stops the goroutine,
+// such as Fatal. Any functions registered with Cleanup are then called.
+func (r *RecordingTB) Run(f func(tb testing.TB)) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		f(r)
+	}()
+	<-done
+	r.runCleanups()
+}
+
+func (r *RecordingTB) runCleanups() {
+	for {
+		r.mu.Lock()
+		n := len(r.cleanups)
+		if n == 0 {
+			r.mu.Unlock()
+			return
+		}
+		cleanup := r.cleanups[n-1]
+		r.cleanups = r.cleanups[:n-1]
+		r.mu.Unlock()
+		cleanup()
+	}
+}
+
+// Errors returns the messages passed to Error, Errorf, Fatal and Fatalf.
+func (r *RecordingTB) Errors() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.errors...)
+}
+
+// Logs returns the messages passed to Log and Logf.
+func (r *RecordingTB) Logs() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.logs...)
+}
+
+// Skips returns the messages passed to Skip and Skipf.
+func (r *RecordingTB) Skips() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.skips...)
+}
+
+// Cleanup registers a function to be called by Run once its function
+// has finished. Cleanup functions are called in last added, first
+// called order.
+func (r *RecordingTB) Cleanup(f func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cleanups = append(r.cleanups, f)
+}
+
+// Error records a failure with the message formatted as by Log.
+func (r *RecordingTB) Error(args ...interface{}) {
+	r.fail(sprintln(args...))
+}
+
+// Errorf records a failure with the message formatted as by Logf.
+func (r *RecordingTB) Errorf(format string, args ...interface{}) {
+	r.fail(fmt.Sprintf(format, args...))
+}
+
+// Fail records a failure without a message.
+func (r *RecordingTB) Fail() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.failed = true
+}
+
+// FailNow records a failure and stops the calling goroutine.
+func (r *RecordingTB) FailNow() {
+	r.Fail()
+	runtime.Goexit()
+}
+
+// Failed reports whether a failure has been recorded.
+func (r *RecordingTB) Failed() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.failed
+}
+
+// Fatal is equivalent to Error followed by FailNow.
+func (r *RecordingTB) Fatal(args ...interface{}) {
+	r.Error(args...)
+	runtime.Goexit()
+}
+
+// Fatalf is equivalent to Errorf followed by FailNow.
+func (r *RecordingTB) Fatalf(format string, args ...interface{}) {
+	r.Errorf(format, args...)
+	runtime.Goexit()
+}
+
+// Helper does nothing.
+func (r *RecordingTB) Helper() {}
+
+// Log records the message, formatted with default formatting as by
+// fmt.Println without the final newline.
+func (r *RecordingTB) Log(args ...interface{}) {
+	r.log(sprintln(args...))
+}
+
+// Logf records the message, formatted as by fmt.Sprintf.
+func (r *RecordingTB) Logf(format string, args ...interface{}) {
+	r.log(fmt.Sprintf(format, args...))
+}
+
+// Name returns the name of the RecordingTB.
+func (r *RecordingTB) Name() string {
+	return "RecordingTB"
+}
+
+// Skip is equivalent to Log followed by SkipNow, and records the
+// message as the reason for skipping.
+func (r *RecordingTB) Skip(args ...interface{}) {
+	r.skip(sprintln(args...))
+}
+
+// Skipf is equivalent to Logf followed by SkipNow, and records the
+// message as the reason for skipping.
+func (r *RecordingTB) Skipf(format string, args ...interface{}) {
+	r.skip(fmt.Sprintf(format, args...))
+}
+
+// SkipNow records that the test was skipped and stops the calling
+// goroutine.
+func (r *RecordingTB) SkipNow() {
+	r.mu.Lock()
+	r.skipped = true
+	r.mu.Unlock()
+	runtime.Goexit()
+}
+
+// Skipped reports whether a skip has been recorded.
+func (r *RecordingTB) Skipped() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.skipped
+}
+
+func (r *RecordingTB) fail(message string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.failed = true
+	r.errors = append(r.errors, message)
+}
+
+func (r *RecordingTB) log(message string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.logs = append(r.logs, message)
+}
+
+func (r *RecordingTB) skip(message string) {
+	r.log(message)
+	r.mu.Lock()
+	r.skips = append(r.skips, message)
+	r.mu.Unlock()
+	r.SkipNow()
+}
+
+// sprintln formats the arguments as testing.T does for Log and Error.
+func sprintln(args ...interface{}) string {
+	return strings.TrimSuffix(fmt.Sprintln(args...), "\n")
+}
@@ -0,0 +1,117 @@
+// Add a copyright
+// Add a licence
+
+package checkers_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/howbazaar/checkers"
+)
+
+func TestRecordingTB(t *testing.T) {
+	for _, test := range []struct {
+		description string
+		run         func(tb testing.TB)
+		failed      bool
+		skipped     bool
+		errors      []string
+		logs        []string
+		skips       []string
+	}{
+		{
+			description: "nothing",
+			run:         func(tb testing.TB) {},
+		}, {
+			description: "error continues",
+			run: func(tb testing.TB) {
+				tb.Error("first", 1)
+				tb.Errorf("second %d", 2)
+			},
+			failed: true,
+			errors: []string{"first 1", "second 2"},
+		}, {
+			description: "fatal stops",
+			run: func(tb testing.TB) {
+				tb.Log("before")
+				tb.Fatalf("stop %s", "here")
+				tb.Log("after")
+			},
+			failed: true,
+			errors: []string{"stop here"},
+			logs:   []string{"before"},
+		}, {
+			description: "fail now stops",
+			run: func(tb testing.TB) {
+				tb.FailNow()
+				tb.Log("after")
+			},
+			failed: true,
+		}, {
+			description: "skip stops",
+			run: func(tb testing.TB) {
+				tb.Skip("not today")
+				tb.Error("after")
+			},
+			skipped: true,
+			logs:    []string{"not today"},
+			skips:   []string{"not today"},
+		},
+	} {
+		r := checkers.NewRecordingTB()
+		r.Run(test.run)
+		if r.Failed() != test.failed {
+			t.Errorf("%s: failed %v, expected %v", test.description, r.Failed(), test.failed)
+		}
+		if r.Skipped() != test.skipped {
+			t.Errorf("%s: skipped %v, expected %v", test.description, r.Skipped(), test.skipped)
+		}
+		for _, check := range []struct {
+			name               string
+			obtained, expected []string
+		}{
+			{"errors", r.Errors(), test.errors},
+			{"logs", r.Logs(), test.logs},
+			{"skips", r.Skips(), test.skips},
+		} {
+			if err := checkers.DeepEquals.Check(check.obtained, check.expected); err != nil {
+				t.Errorf("%s: %s: %v", test.description, check.name, err)
+			}
+		}
+	}
+}
+
+func TestRecordingTBCleanup(t *testing.T) {
+	var called []int
+	r := checkers.NewRecordingTB()
+	r.Run(func(tb testing.TB) {
+		tb.Cleanup(func() { called = append(called, 1) })
+		tb.Cleanup(func() { called = append(called, 2) })
+		tb.FailNow()
+	})
+	if fmt.Sprint(called) != "[2 1]" {
+		t.Errorf("unexpected cleanups: %v", called)
+	}
+}
+
+func TestRecordingTBAssert(t *testing.T) {
+	r := checkers.NewRecordingTB()
+	r.Run(func(tb testing.TB) {
+		ct := &checkers.Test{TB: tb}
+		ct.Check(1, checkers.Equals, 2)
+		ct.Assert("a", checkers.Equals, "b")
+		ct.Check(3, checkers.Equals, 4)
+	})
+	expected := []string{
+		"expected int value 2, got 1",
+		"expected string value b, got a\n" +
+			"strings differ at byte 0 (rune 0):\n" +
+			"\tobtained: \"a\"\n" +
+			"\texpected: \"b\"\n" +
+			"\t           ^",
+	}
+	if err := checkers.DeepEquals.Check(r.Errors(), expected); err != nil {
+		t.Errorf("errors: %v", err)
+	}
+}
@@ -0,0 +1,95 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"reflect"
+	"sync"
+)
+
+// redactedText is shown in place of values that are redacted.
+const redactedText = "<redacted>"
+
+// Values can be kept out of the descriptions of failures, so that logs
+// of failed tests do not show secrets such as passwords and tokens.
+// Struct fields tagged with `checkers:"redact"` are shown as
+// "<redacted>" wherever the checkers render the struct, and a mismatch
+// in such a field is reported only as the fields being unequal:
+//
+//	type Credentials struct {
+//		User     string
+//		Password string `checkers:"redact"`
+//	}
+//
+// Values of a type that is always secret can be redacted with Redact,
+// and secrets that turn up in other ways, such as within error
+// messages, can be removed by a function registered with WithRedactor.
+
+// isRedacted reports whether the struct field is tagged to be redacted.
+func isRedacted(field reflect.StructField) bool {
+	return field.Tag.Get("checkers") == "redact"
+}
+
+// Redact has values of the same type as example shown as "<redacted>"
+// in the descriptions of failures, as with a printer registered with
+// WithValuesPrinter, until the returned function is called.
+//
+//	t.Cleanup(checkers.Redact(oauth2.Token{}))
+func Redact(example interface{}) (remove func()) {
+	return WithValuesPrinter(example, func(interface{}) string {
+		return redactedText
+	})
+}
+
+// redactors holds the functions registered with WithRedactor.
+var redactors = struct {
+	sync.RWMutex
+	list []*redactor
+}{}
+
+type redactor struct {
+	redact func(string) string
+}
+
+// WithRedactor registers a function that is given the description of
+// each failure reported by Test.Check and Test.Assert, and so by
+// suites, and returns it with any secrets masked, until the returned
+// function is called.
+//
+//	t.Cleanup(checkers.WithRedactor(func(s string) string {
+//		return strings.ReplaceAll(s, token, "<token>")
+//	}))
+func WithRedactor(redact func(message string) string) (remove func()) {
+	if redact == nil {
+		panic("checkers: WithRedactor redactor is nil")
+	}
+	r := &redactor{redact}
+	redactors.Lock()
+	defer redactors.Unlock()
+	redactors.list = append(redactors.list, r)
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			redactors.Lock()
+			defer redactors.Unlock()
+			for i, other := range redactors.list {
+				if other == r {
+					redactors.list = append(redactors.list[:i:i], redactors.list[i+1:]...)
+					break
+				}
+			}
+		})
+	}
+}
+
+// redact returns the description of a failure with the registered
+// redactors applied, in the order they were registered.
+func redact(message string) string {
+	redactors.RLock()
+	defer redactors.RUnlock()
+	for _, r := range redactors.list {
+		message = r.redact(message)
+	}
+	return message
+}
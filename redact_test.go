@@ -0,0 +1,136 @@
+// Add a copyright
+// Add a licence
+
+package checkers_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/howbazaar/checkers"
+)
+
+type login struct {
+	User     string
+	Password string            `checkers:"redact"`
+	Tokens   map[string]string `checkers:"redact"`
+}
+
+type apiKey string
+
+func TestRedactedFields(t *testing.T) {
+	for _, test := range []struct {
+		description string
+		obtained    interface{}
+		expected    interface{}
+		err         string
+	}{
+		{
+			description: "equal",
+			obtained:    login{"bob", "hunter2", map[string]string{"a": "1"}},
+			expected:    login{"bob", "hunter2", map[string]string{"a": "1"}},
+		}, {
+			description: "redacted field differs",
+			obtained:    login{User: "bob", Password: "hunter2"},
+			expected:    login{User: "bob", Password: "swordfish"},
+			err:         "mismatch at .Password: unequal; obtained <redacted>; expected <redacted>",
+		}, {
+			description: "nested difference in redacted field",
+			obtained:    &login{Tokens: map[string]string{"api": "abc"}},
+			expected:    &login{Tokens: map[string]string{"api": "abd"}},
+			err:         "mismatch at .Tokens: unequal; obtained <redacted>; expected <redacted>",
+		}, {
+			description: "other field differs",
+			obtained:    []login{{"bob", "hunter2", nil}},
+			expected:    []login{{"alice", "hunter2", nil}},
+			err: "mismatch at [0].User: unequal; obtained \"bob\"; expected \"alice\"\n" +
+				"diff (-obtained +expected):\n" +
+				" []checkers_test.login{\n" +
+				" \tcheckers_test.login{\n" +
+				"-\t\tUser: \"bob\",\n" +
+				"+\t\tUser: \"alice\",\n" +
+				" \t\tPassword: <redacted>,\n" +
+				" \t\tTokens: <redacted>,\n" +
+				" \t},\n" +
+				" ...",
+		}, {
+			description: "whole value shown",
+			obtained:    []interface{}{login{"bob", "hunter2", nil}},
+			expected:    []interface{}{&login{"bob", "hunter2", nil}},
+			err: "mismatch at [0]: type mismatch checkers_test.login vs *checkers_test.login; " +
+				"obtained checkers_test.login{User: \"bob\", Password: <redacted>, Tokens: <redacted>}; " +
+				"expected &checkers_test.login{User: \"bob\", Password: <redacted>, Tokens: <redacted>}\n" +
+				"diff (-obtained +expected):\n" +
+				" []interface {}{\n" +
+				"-\tcheckers_test.login{\n" +
+				"+\t&checkers_test.login{\n" +
+				" \t\tUser: \"bob\",\n" +
+				" \t\tPassword: <redacted>,\n" +
+				" \t\tTokens: <redacted>,\n" +
+				" ...",
+		},
+	} {
+		err := checkers.DeepEquals.Check(test.obtained, test.expected)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+		} else {
+			if test.err == "" {
+				t.Errorf("%s: unexpected error: %v", test.description, err)
+			} else {
+				if err.Error() != test.err {
+					t.Errorf("%s: error mismatch: \n\tobtained: %q\n\texpected: %q", test.description, err.Error(), test.err)
+				}
+			}
+		}
+	}
+}
+
+func TestRedactedMismatches(t *testing.T) {
+	err := checkers.DeepEquals.Check(login{Password: "hunter2"}, login{Password: "swordfish"})
+	mismatches := checkers.Mismatches(err)
+	if err := checkers.DeepEquals.Check(mismatches, []checkers.Mismatch{{
+		Path:     ".Password",
+		Reason:   "unequal",
+		Obtained: "<redacted>",
+		Expected: "<redacted>",
+	}}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRedact(t *testing.T) {
+	remove := checkers.Redact(apiKey(""))
+	err := checkers.Equals.Check(apiKey("abc"), apiKey("abd"))
+	if err == nil || err.Error() != "expected checkers_test.apiKey value <redacted>, got <redacted>" {
+		t.Errorf("unexpected error: %v", err)
+	}
+	remove()
+	err = checkers.Equals.Check(apiKey("abc"), apiKey("abd"))
+	if err == nil || !strings.HasPrefix(err.Error(), "expected checkers_test.apiKey value abd, got abc\n") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestWithRedactor(t *testing.T) {
+	remove := checkers.WithRedactor(func(s string) string {
+		return strings.Replace(s, "s3cr3t", "<token>", -1)
+	})
+	r := checkers.NewRecordingTB()
+	r.Run(func(tb testing.TB) {
+		ct := &checkers.Test{TB: tb}
+		ct.Check(errors.New("bad token s3cr3t"), checkers.IsNil)
+		ct.Check("s3cr3t", checkers.Equals, "other")
+	})
+	remove()
+	for _, e := range r.Errors() {
+		if strings.Contains(e, "s3cr3t") {
+			t.Errorf("secret not redacted: %q", e)
+		}
+	}
+	if len(r.Errors()) != 2 {
+		t.Errorf("unexpected errors: %q", r.Errors())
+	}
+}
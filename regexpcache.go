@@ -0,0 +1,68 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"container/list"
+	"regexp"
+	"sync"
+)
+
+// maxCachedRegexps is the number of compiled patterns kept by the
+// regexp cache. Table driven tests tend to reuse a small number of
+// patterns many times, so only the most recently used are kept.
+const maxCachedRegexps = 256
+
+// regexpCache is a least recently used cache of compiled patterns,
+// safe for concurrent use.
+type regexpCache struct {
+	mu      sync.Mutex
+	max     int
+	order   *list.List // of *regexp.Regexp, most recently used first
+	entries map[string]*list.Element
+}
+
+func newRegexpCache(max int) *regexpCache {
+	return &regexpCache{
+		max:     max,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+var compiledRegexps = newRegexpCache(maxCachedRegexps)
+
+// compile returns the compiled pattern, from the cache when the pattern
+// has been compiled recently.
+func (c *regexpCache) compile(pattern string) (*regexp.Regexp, error) {
+	c.mu.Lock()
+	if e, ok := c.entries[pattern]; ok {
+		c.order.MoveToFront(e)
+		c.mu.Unlock()
+		return e.Value.(*regexp.Regexp), nil
+	}
+	c.mu.Unlock()
+
+	// Compile without holding the lock, so a slow pattern doesn't
+	// hold up other checks. Errors are not cached.
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[pattern]; ok {
+		// Compiled concurrently by another check.
+		c.order.MoveToFront(e)
+		return e.Value.(*regexp.Regexp), nil
+	}
+	c.entries[pattern] = c.order.PushFront(re)
+	if c.order.Len() > c.max {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*regexp.Regexp).String())
+	}
+	return re, nil
+}
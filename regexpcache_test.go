@@ -0,0 +1,35 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"testing"
+)
+
+func TestRegexpCache(t *testing.T) {
+	c := newRegexpCache(2)
+	a, err := c.compile("a")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if again, _ := c.compile("a"); again != a {
+		t.Fatalf("pattern not cached")
+	}
+	if _, err := c.compile("("); err == nil {
+		t.Fatalf("expected error for invalid pattern")
+	}
+	c.compile("b")
+	// "a" was used more recently than "b", so "b" is evicted.
+	c.compile("a")
+	c.compile("c")
+	if len(c.entries) != 2 || c.order.Len() != 2 {
+		t.Fatalf("cache not limited: %d entries", len(c.entries))
+	}
+	if _, ok := c.entries["b"]; ok {
+		t.Fatalf("least recently used pattern not evicted")
+	}
+	if again, _ := c.compile("a"); again != a {
+		t.Fatalf("recently used pattern evicted")
+	}
+}
@@ -0,0 +1,143 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// registry holds the checkers that can be looked up by name.
+var registry = struct {
+	sync.RWMutex
+	checkers map[string]Checker
+}{
+	checkers: map[string]Checker{
+		"IsNil":                     IsNil,
+		"IsNotNil":                  IsNotNil,
+		"IsZero":                    IsZero,
+		"Equals":                    Equals,
+		"EqualsConverted":           EqualsConverted,
+		"EqualsNumeric":             EqualsNumeric,
+		"DeepEquals":                DeepEquals,
+		"IsFalse":                   IsFalse,
+		"IsTrue":                    IsTrue,
+		"HasLen":                    HasLen,
+		"Matches":                   Matches,
+		"PanicMatches":              PanicMatches,
+		"RowCountEquals":            RowCountEquals,
+		"QueryReturns":              QueryReturns,
+		"HTTPRequested":             HTTPRequested,
+		"MetricEquals":              MetricEquals,
+		"MetricDelta":               MetricDelta,
+		"ReaderEquals":              ReaderEquals,
+		"ReaderMatches":             ReaderMatches,
+		"WritesEqual":               WritesEqual,
+		"StringContainsAllOf":       StringContainsAllOf,
+		"LinesEqualIgnoringSpace":   LinesEqualIgnoringSpace,
+		"TrimmedEquals":             TrimmedEquals,
+		"ExitCodeEquals":            ExitCodeEquals,
+		"StdoutMatches":             StdoutMatches,
+		"StderrMatches":             StderrMatches,
+		"DurationEquals":            DurationEquals,
+		"SizeEquals":                SizeEquals,
+		"MapOfSlicesEquivalent":     MapOfSlicesEquivalent,
+		"HeaderEquals":              HeaderEquals,
+		"ErrorChainMatches":         ErrorChainMatches,
+		"JoinedErrorsContain":       JoinedErrorsContain,
+		"AtomicEquals":              AtomicEquals,
+		"SyncMapHasKey":             SyncMapHasKey,
+		"ChanLenEquals":             ChanLenEquals,
+		"ChanCapEquals":             ChanCapEquals,
+		"ChanReceives":              ChanReceives,
+		"TimeInLocation":            TimeInLocation,
+		"TimeIsUTC":                 TimeIsUTC,
+		"MonotonicNonDecreasing":    MonotonicNonDecreasing,
+		"TextRoundTrips":            TextRoundTrips,
+		"StructTagsValid":           StructTagsValid,
+		"InterfaceFullyImplemented": InterfaceFullyImplemented,
+		"NoExportedFieldsOmitted":   NoExportedFieldsOmitted,
+		"CmdEquals":                 CmdEquals,
+		"GeneratesSequence":         GeneratesSequence,
+		"ErrorIs":                   ErrorIs,
+		"ErrorAs":                   ErrorAs,
+		"Contains":                  Contains,
+		"NumGoroutineAtMost":        NumGoroutineAtMost,
+		"HasKey":                    HasKey,
+		"HasValue":                  HasValue,
+		"MultipartHasPart":          MultipartHasPart,
+		"MultipartPartNames":        MultipartPartNames,
+		"GreaterThan":               GreaterThan,
+		"LessThan":                  LessThan,
+		"AtLeast":                   AtLeast,
+		"AtMost":                    AtMost,
+		"RequestFormEquals":         RequestFormEquals,
+		"Between":                   Between,
+		"JWTHasClaims":              JWTHasClaims,
+		"AlmostEqual":               AlmostEqual,
+		"JSONRoundTrips":            JSONRoundTrips,
+		"XMLRoundTrips":             XMLRoundTrips,
+		"TimeEquals":                TimeEquals,
+		"Before":                    Before,
+		"After":                     After,
+		"EventsInOrder":             EventsInOrder,
+		"DurationLessThan":          DurationLessThan,
+		"DurationGreaterThan":       DurationGreaterThan,
+		"CalledTimes":               CalledTimes,
+		"NeverCalled":               NeverCalled,
+		"IsEmpty":                   IsEmpty,
+		"IsNotEmpty":                IsNotEmpty,
+	},
+}
+
+// Register makes the checker available by name to Lookup, so that
+// tests defined as data can refer to it. The checkers of this package
+// are registered under their own names. Register panics if the checker
+// is nil or the name is already registered.
+func Register(name string, checker Checker) {
+	registry.Lock()
+	defer registry.Unlock()
+	if checker == nil {
+		panic("checkers: Register checker is nil")
+	}
+	if _, dup := registry.checkers[name]; dup {
+		panic("checkers: Register called twice for checker " + name)
+	}
+	registry.checkers[name] = checker
+}
+
+// Lookup returns the checker registered with the name.
+func Lookup(name string) (Checker, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	checker, ok := registry.checkers[name]
+	return checker, ok
+}
+
+// RegisteredNames returns the sorted names of the registered checkers.
+func RegisteredNames() []string {
+	registry.RLock()
+	defer registry.RUnlock()
+	names := make([]string, 0, len(registry.checkers))
+	for name := range registry.checkers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// checkerName returns the name the checker is registered with, or its
+// type if it is not registered, for describing the checker in failures.
+func checkerName(checker Checker) string {
+	if reflect.TypeOf(checker).Comparable() {
+		for _, name := range RegisteredNames() {
+			if found, _ := Lookup(name); found == checker {
+				return name
+			}
+		}
+	}
+	return fmt.Sprintf("%T", checker)
+}
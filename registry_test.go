@@ -0,0 +1,85 @@
+// Add a copyright
+// Add a licence
+
+package checkers_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/howbazaar/checkers"
+)
+
+func TestLookup(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		expected checkers.Checker
+	}{
+		{"Equals", checkers.Equals},
+		{"DeepEquals", checkers.DeepEquals},
+		{"Matches", checkers.Matches},
+		{"WritesEqual", checkers.WritesEqual},
+	} {
+		checker, ok := checkers.Lookup(test.name)
+		if !ok {
+			t.Errorf("%s: not registered", test.name)
+			continue
+		}
+		if checker != test.expected {
+			t.Errorf("%s: registered as %#v", test.name, checker)
+		}
+	}
+	if checker, ok := checkers.Lookup("NoSuchChecker"); ok {
+		t.Errorf("unexpected checker %#v", checker)
+	}
+}
+
+// startsWithA is registered once, when the tests start, as registering
+// it again would panic when the tests are repeated.
+var startsWithA = checkers.MatchesRegexp(regexp.MustCompile("^a"))
+
+func init() {
+	checkers.Register("checkers_test.StartsWithA", startsWithA)
+}
+
+func TestRegister(t *testing.T) {
+	if found, ok := checkers.Lookup("checkers_test.StartsWithA"); !ok || found != startsWithA {
+		t.Errorf("registered checker not found: %#v", found)
+	}
+	names := checkers.RegisteredNames()
+	found := false
+	for i, name := range names {
+		if i > 0 && names[i-1] >= name {
+			t.Errorf("names not sorted: %q", names)
+		}
+		found = found || name == "checkers_test.StartsWithA"
+	}
+	if !found {
+		t.Errorf("registered name missing from %q", names)
+	}
+
+	for _, test := range []struct {
+		description string
+		name        string
+		checker     checkers.Checker
+		panic       string
+	}{
+		{
+			description: "duplicate",
+			name:        "Equals",
+			checker:     checkers.Equals,
+			panic:       "checkers: Register called twice for checker Equals",
+		}, {
+			description: "nil",
+			name:        "checkers_test.Nil",
+			panic:       "checkers: Register checker is nil",
+		},
+	} {
+		err := checkers.PanicMatches.Check(func() {
+			checkers.Register(test.name, test.checker)
+		}, test.panic)
+		if err != nil {
+			t.Errorf("%s: %v", test.description, err)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+// Mismatch describes a single difference found by a deep comparison.
+type Mismatch struct {
+	// Path locates the difference within the compared values. The
+	// path of the values themselves is empty. Beneath that, the path
+	// is built from the following elements, in the way a Go
+	// expression would reach the value:
+	//
+	//	.Name     a struct field
+	//	[3]       an element of a slice or array
+	//	["key"]   a map entry, with the key in Go syntax (%#v)
+	//	name(p)   the result of the transformer called name applied
+	//	          to the value at path p
+	//
+	// Pointers and interfaces are followed implicitly and add nothing
+	// to the path, so a field reached through a pointer to a struct
+	// is written as ".Ptr.Field".
+	Path string
+	// Reason says how the values differ, for example "unequal" or
+	// "length mismatch, 2 vs 3".
+	Reason string
+	// Obtained and Expected hold the differing values. A value that
+	// is missing, such as an absent map entry, is nil. The values of
+	// struct fields tagged to be redacted are given as "<redacted>".
+	Obtained interface{}
+	Expected interface{}
+}
+
+// Reporter is told of each difference found by a deep comparison,
+// allowing tools to render or record mismatches in their own way.
+type Reporter interface {
+	Report(Mismatch)
+}
+
+// WithReporter has the comparison pass each mismatch to the reporter as
+// it is found, in addition to describing it in the returned error.
+func WithReporter(reporter Reporter) DeepEqualOption {
+	return func(c *deepEqualConfig) {
+		c.reporter = reporter
+	}
+}
+
+func (err *mismatchError) mismatch() Mismatch {
+	if err.redacted {
+		return Mismatch{
+			Path:     err.path,
+			Reason:   err.how,
+			Obtained: redactedText,
+			Expected: redactedText,
+		}
+	}
+	return Mismatch{
+		Path:     err.path,
+		Reason:   err.how,
+		Obtained: interfaceOf(err.v1),
+		Expected: interfaceOf(err.v2),
+	}
+}
+
+// Mismatches returns the structured records of the differences
+// described by an error returned from DeepEqual or the DeepEquals
+// checker. It returns nil for any other error.
+func Mismatches(err error) []Mismatch {
+	switch err := err.(type) {
+	case *mismatchError:
+		return []Mismatch{err.mismatch()}
+	case *mismatchesError:
+		var result []Mismatch
+		for _, e := range err.errs {
+			result = append(result, Mismatches(e)...)
+		}
+		return result
+	case *diffError:
+		return Mismatches(err.err)
+	case *commentedError:
+		return Mismatches(err.err)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// SeriesOption is an extra value understood by the
+// MonotonicNonDecreasing checker.
+type SeriesOption struct {
+	strict bool
+}
+
+// StrictlyIncreasing returns a SeriesOption that has each element of the
+// series required to be greater than the one before, rather than just
+// not less than it.
+func StrictlyIncreasing() SeriesOption {
+	return SeriesOption{strict: true}
+}
+
+type monotonicNonDecreasing struct{}
+
+// MonotonicNonDecreasing checker checks that the elements of the
+// obtained slice or array, of numbers or time.Time values, never
+// decrease. A StrictlyIncreasing option has them always increase. A
+// failure reports the first adjacent pair that is out of order.
+//
+//	t.Check(eventTimes, checkers.MonotonicNonDecreasing)
+var MonotonicNonDecreasing Checker = monotonicNonDecreasing{}
+
+func (monotonicNonDecreasing) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	var option SeriesOption
+	for i, extra := range extras {
+		o, ok := extra.(SeriesOption)
+		if !ok {
+			return unexpectedExtras("MonotonicNonDecreasing", extras[i:])
+		}
+		option.strict = option.strict || o.strict
+	}
+	v := reflect.ValueOf(obtained)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return fmt.Errorf("MonotonicNonDecreasing checker expected a slice or array, obtained was %s", describeType(obtained))
+	}
+	compare, ok := seriesComparison(v.Type().Elem())
+	if !ok {
+		return fmt.Errorf("MonotonicNonDecreasing checker expected numbers or times, obtained was %s", describeType(obtained))
+	}
+	for i := 1; i < v.Len(); i++ {
+		c := compare(v.Index(i-1), v.Index(i))
+		if c < 0 || (c == 0 && !option.strict) {
+			continue
+		}
+		previous, current := interfaceOf(v.Index(i-1)), interfaceOf(v.Index(i))
+		if c > 0 {
+			return failf("element %d (%v) is less than element %d (%v)", i, current, i-1, previous)
+		}
+		return failf("element %d (%v) is not greater than element %d (%v)", i, current, i-1, previous)
+	}
+	return nil
+}
+
+// seriesComparison returns a function that compares two values of the
+// type, returning a negative number when the first is less than the
+// second, zero when they are equal and a positive number otherwise.
+func seriesComparison(t reflect.Type) (func(a, b reflect.Value) int, bool) {
+	if t == timeType {
+		return func(a, b reflect.Value) int {
+			ta, tb := interfaceOf(a).(time.Time), interfaceOf(b).(time.Time)
+			switch {
+			case ta.Before(tb):
+				return -1
+			case ta.After(tb):
+				return 1
+			}
+			return 0
+		}, true
+	}
+	switch kindFamily(t.Kind()) {
+	case reflect.Int:
+		return func(a, b reflect.Value) int {
+			return compareOrdered(a.Int() < b.Int(), a.Int() == b.Int())
+		}, true
+	case reflect.Uint:
+		return func(a, b reflect.Value) int {
+			return compareOrdered(a.Uint() < b.Uint(), a.Uint() == b.Uint())
+		}, true
+	case reflect.Float64:
+		return func(a, b reflect.Value) int {
+			return compareOrdered(a.Float() < b.Float(), a.Float() == b.Float())
+		}, true
+	}
+	return nil, false
+}
+
+// compareOrdered converts the results of comparing two values with <
+// and == into the result of a comparison function. Values that are
+// neither less than nor equal to each other, which includes a NaN and
+// any number, are treated as out of order.
+func compareOrdered(less, equal bool) int {
+	switch {
+	case less:
+		return -1
+	case equal:
+		return 0
+	}
+	return 1
+}
@@ -0,0 +1,82 @@
+// Add a copyright
+// Add a licence
+
+package checkers_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/howbazaar/checkers"
+)
+
+func TestMonotonicNonDecreasing(t *testing.T) {
+	base := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	for _, test := range []struct {
+		description string
+		obtained    interface{}
+		extras      []interface{}
+		err         string
+	}{
+		{
+			description: "ints",
+			obtained:    []int{1, 2, 2, 3},
+		}, {
+			description: "empty",
+			obtained:    []float64{},
+		}, {
+			description: "durations array",
+			obtained:    [3]time.Duration{time.Second, time.Minute, time.Hour},
+			extras:      []interface{}{checkers.StrictlyIncreasing()},
+		}, {
+			description: "times",
+			obtained:    []time.Time{base, base, base.Add(time.Second)},
+		}, {
+			description: "decrease",
+			obtained:    []uint{1, 5, 7, 5},
+			err:         "element 3 (5) is less than element 2 (7)",
+		}, {
+			description: "not strictly increasing",
+			obtained:    []int{1, 2, 2, 3},
+			extras:      []interface{}{checkers.StrictlyIncreasing()},
+			err:         "element 2 (2) is not greater than element 1 (2)",
+		}, {
+			description: "times decrease",
+			obtained:    []time.Time{base, base.Add(-time.Second)},
+			err:         "element 1 (2024-03-01 11:59:59 +0000 UTC) is less than element 0 (2024-03-01 12:00:00 +0000 UTC)",
+		}, {
+			description: "nan",
+			obtained:    []float64{1, math.NaN()},
+			err:         "element 1 (NaN) is less than element 0 (1)",
+		}, {
+			description: "not numbers",
+			obtained:    []string{"a", "b"},
+			err:         "MonotonicNonDecreasing checker expected numbers or times, obtained was type []string",
+		}, {
+			description: "not a slice",
+			obtained:    1,
+			err:         "MonotonicNonDecreasing checker expected a slice or array, obtained was type int",
+		}, {
+			description: "unexpected extra",
+			obtained:    []int{1},
+			extras:      []interface{}{true},
+			err:         "too many arguments to checker MonotonicNonDecreasing, unexpected bool(true)",
+		},
+	} {
+		err := checkers.MonotonicNonDecreasing.Check(test.obtained, test.extras...)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+		} else {
+			if test.err == "" {
+				t.Errorf("%s: unexpected error: %v", test.description, err)
+			} else {
+				if err.Error() != test.err {
+					t.Errorf("%s: error mismatch: \n\tobtained: %q\n\texpected: %q", test.description, err.Error(), test.err)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,95 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"os"
+	"os/exec"
+	"os/signal"
+	"time"
+)
+
+// signalPollInterval is how often SignalHandled checks whether the
+// signal has been handled.
+const signalPollInterval = 10 * time.Millisecond
+
+// SignalHandled sends the signal to the current process and checks
+// that handled reports true within the timeout, such as once a context
+// is cancelled or cleanup has run. The test is marked as failed if not.
+//
+// While the signal is sent it is also delivered to a channel of
+// SignalHandled's own, so that the test binary isn't ended by the
+// signal if the code under test doesn't handle it.
+func (t *Test) SignalHandled(sig os.Signal, timeout time.Duration, handled func() bool) bool {
+	tb := t.testingTB()
+	tb.Helper()
+	received := make(chan os.Signal, 1)
+	signal.Notify(received, sig)
+	defer signal.Stop(received)
+
+	process, err := os.FindProcess(os.Getpid())
+	if err == nil {
+		err = process.Signal(sig)
+	}
+	if err != nil {
+		tb.Errorf("unable to send %v: %v", sig, err)
+		return false
+	}
+
+	deadline := time.Now().Add(timeout)
+	for !handled() {
+		if time.Now().After(deadline) {
+			tb.Errorf("%v not handled within %v", sig, timeout)
+			return false
+		}
+		time.Sleep(signalPollInterval)
+	}
+	return true
+}
+
+// SignalHandledByProcess sends the signal to the process started by
+// cmd, and checks that the process exits with the exit code within the
+// timeout. The test is marked as failed if not. The process is killed
+// if it is still running at the end of the timeout.
+func (t *Test) SignalHandledByProcess(cmd *exec.Cmd, sig os.Signal, timeout time.Duration, code int) bool {
+	tb := t.testingTB()
+	tb.Helper()
+	if cmd.Process == nil {
+		tb.Errorf("process not started")
+		return false
+	}
+	if err := cmd.Process.Signal(sig); err != nil {
+		tb.Errorf("unable to send %v: %v", sig, err)
+		return false
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+	var err error
+	select {
+	case err = <-done:
+	case <-time.After(timeout):
+		cmd.Process.Kill()
+		<-done
+		tb.Errorf("process did not exit within %v of %v", timeout, sig)
+		return false
+	}
+
+	exitCode := 0
+	if err != nil {
+		exitErr, ok := err.(*exec.ExitError)
+		if !ok {
+			tb.Errorf("unable to wait for process: %v", err)
+			return false
+		}
+		exitCode = exitErr.ExitCode()
+	}
+	if exitCode != code {
+		tb.Errorf("expected exit code %d after %v, got %d", code, sig, exitCode)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,84 @@
+// Add a copyright
+// Add a licence
+
+//go:build !windows
+// +build !windows
+
+package checkers_test
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/howbazaar/checkers"
+)
+
+func TestSignalHandled(t *testing.T) {
+	ct := &checkers.Test{TB: t}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	received := make(chan os.Signal, 1)
+	signal.Notify(received, syscall.SIGUSR1)
+	defer signal.Stop(received)
+	go func() {
+		<-received
+		cancel()
+	}()
+	ct.SignalHandled(syscall.SIGUSR1, time.Second, func() bool {
+		return ctx.Err() != nil
+	})
+}
+
+func TestSignalNotHandled(t *testing.T) {
+	r := checkers.NewRecordingTB()
+	r.Run(func(tb testing.TB) {
+		ct := &checkers.Test{TB: tb}
+		ct.SignalHandled(syscall.SIGUSR1, 50*time.Millisecond, func() bool {
+			return false
+		})
+	})
+	if err := checkers.DeepEquals.Check(r.Errors(), []string{
+		"user defined signal 1 not handled within 50ms",
+	}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSignalHandledByProcess(t *testing.T) {
+	start := func(script string) *exec.Cmd {
+		cmd := exec.Command("sh", "-c", script)
+		stdout, err := cmd.StdoutPipe()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := cmd.Start(); err != nil {
+			t.Fatal(err)
+		}
+		// Wait for the trap to be set.
+		stdout.Read(make([]byte, 1))
+		return cmd
+	}
+
+	ct := &checkers.Test{TB: t}
+	ct.SignalHandledByProcess(start("trap 'exit 3' TERM; echo; sleep 10 & wait"), syscall.SIGTERM, 5*time.Second, 3)
+
+	r := checkers.NewRecordingTB()
+	r.Run(func(tb testing.TB) {
+		ct := &checkers.Test{TB: tb}
+		ct.SignalHandledByProcess(start("trap 'exit 4' TERM; echo; sleep 10 & wait"), syscall.SIGTERM, 5*time.Second, 3)
+		ct.SignalHandledByProcess(start("trap '' TERM; echo; sleep 10"), syscall.SIGTERM, 50*time.Millisecond, 0)
+		ct.SignalHandledByProcess(exec.Command("true"), syscall.SIGTERM, time.Second, 0)
+	})
+	if err := checkers.DeepEquals.Check(r.Errors(), []string{
+		"expected exit code 3 after terminated, got 4",
+		"process did not exit within 50ms of terminated",
+		"process not started",
+	}); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,124 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// maxSimilarPairs bounds the number of element pairs compared when
+// looking for similar elements, as every obtained element may be
+// compared with every expected one.
+const maxSimilarPairs = 10000
+
+// similarElements looks for elements of obtained and expected, slices
+// or arrays of structs, that differ at the same index but look like an
+// element at another index of the other value, and describes each
+// pairing on its own line. Elements are paired with the unpaired
+// element sharing the most fields, where at least half their fields
+// are equal, so that results which are reordered, or have elements
+// missing or added, are described by what actually changed rather than
+// by their positions.
+// The empty string is returned when no elements could be paired.
+func similarElements(obtained, expected interface{}, config deepEqualConfig) string {
+	v1 := reflect.ValueOf(obtained)
+	v2 := reflect.ValueOf(expected)
+	if !v1.IsValid() || !v2.IsValid() || v1.Type() != v2.Type() {
+		return ""
+	}
+	switch v1.Kind() {
+	case reflect.Slice, reflect.Array:
+	default:
+		return ""
+	}
+	elem := v1.Type().Elem()
+	if elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+	if elem.Kind() != reflect.Struct || elem.NumField() == 0 || elem == timeType {
+		return ""
+	}
+	n1, n2 := v1.Len(), v2.Len()
+	if n1*n2 > maxSimilarPairs {
+		return ""
+	}
+
+	config.reporter = nil
+	config.maxMismatches = 1
+	equal := func(a, b reflect.Value) bool {
+		d := newDeepEqualer(nil)
+		d.deepEqualConfig = config
+		return d.deepValueEqual("", a, b, 0)
+	}
+
+	// Elements equal to the element at the same index are left alone.
+	used := make([]bool, n2)
+	var candidates []int
+	for i := 0; i < n1; i++ {
+		if i < n2 && equal(v1.Index(i), v2.Index(i)) {
+			used[i] = true
+			continue
+		}
+		candidates = append(candidates, i)
+	}
+
+	var lines []string
+	for _, i := range candidates {
+		s1, ok := structElem(v1.Index(i))
+		if !ok {
+			continue
+		}
+		best, bestDiffering := -1, []string(nil)
+		for j := 0; j < n2; j++ {
+			if used[j] || j == i {
+				continue
+			}
+			s2, ok := structElem(v2.Index(j))
+			if !ok {
+				continue
+			}
+			var differing []string
+			for f := 0; f < elem.NumField(); f++ {
+				if !equal(s1.Field(f), s2.Field(f)) {
+					differing = append(differing, elem.Field(f).Name)
+				}
+			}
+			if 2*len(differing) > elem.NumField() {
+				continue
+			}
+			if best == -1 || len(differing) < len(bestDiffering) {
+				best, bestDiffering = j, differing
+			}
+		}
+		if best == -1 {
+			continue
+		}
+		used[best] = true
+		line := fmt.Sprintf("obtained element %d looks like expected element %d", i, best)
+		switch len(bestDiffering) {
+		case 0:
+			line = fmt.Sprintf("obtained element %d equals expected element %d", i, best)
+		case 1:
+			line += " except field " + bestDiffering[0]
+		default:
+			line += " except fields " + strings.Join(bestDiffering, ", ")
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// structElem returns the struct held by an element, following a
+// pointer to it.
+func structElem(v reflect.Value) (reflect.Value, bool) {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return reflect.Value{}, false
+		}
+		v = v.Elem()
+	}
+	return v, v.Kind() == reflect.Struct
+}
@@ -0,0 +1,72 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"testing"
+)
+
+type similarStruct struct {
+	Name  string
+	Age   int
+	Email string
+}
+
+func TestSimilarElements(t *testing.T) {
+	alice := similarStruct{"alice", 30, "alice@example.com"}
+	bob := similarStruct{"bob", 25, "bob@example.com"}
+	carol := similarStruct{"carol", 41, "carol@example.com"}
+	olderBob := similarStruct{"bob", 26, "bob@example.com"}
+	for _, test := range []struct {
+		description string
+		obtained    interface{}
+		expected    interface{}
+		similar     string
+	}{
+		{
+			description: "reordered",
+			obtained:    []similarStruct{bob, alice},
+			expected:    []similarStruct{alice, bob},
+			similar: "obtained element 0 equals expected element 1\n" +
+				"obtained element 1 equals expected element 0",
+		}, {
+			description: "reordered and changed",
+			obtained:    []similarStruct{carol, olderBob, alice},
+			expected:    []similarStruct{alice, bob, carol},
+			similar: "obtained element 0 equals expected element 2\n" +
+				"obtained element 2 equals expected element 0",
+		}, {
+			description: "element missing",
+			obtained:    []similarStruct{alice, carol},
+			expected:    []similarStruct{alice, bob, carol},
+			similar:     "obtained element 1 equals expected element 2",
+		}, {
+			description: "pointers",
+			obtained:    []*similarStruct{&olderBob, nil},
+			expected:    []*similarStruct{&alice, &bob},
+			similar:     "obtained element 0 looks like expected element 1 except field Age",
+		}, {
+			description: "too different",
+			obtained:    []similarStruct{{"dave", 50, "dave@example.com"}},
+			expected:    []similarStruct{alice, bob},
+		}, {
+			description: "several fields differ",
+			obtained:    []similarStruct{{"x", 30, "y"}, alice},
+			expected:    []similarStruct{bob, {"z", 30, "w"}},
+		}, {
+			description: "not structs",
+			obtained:    []int{2, 1},
+			expected:    []int{1, 2},
+		}, {
+			description: "different types",
+			obtained:    []similarStruct{bob, alice},
+			expected:    []*similarStruct{&alice, &bob},
+		},
+	} {
+		similar := similarElements(test.obtained, test.expected, newDeepEqualConfig(nil))
+		if similar != test.similar {
+			t.Errorf("%s: similar elements mismatch: \n\tobtained: %q\n\texpected: %q", test.description, similar, test.similar)
+		}
+	}
+}
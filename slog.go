@@ -0,0 +1,292 @@
+// Add a copyright
+// Add a licence
+
+//go:build go1.21
+// +build go1.21
+
+package checkers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LogRecord is a log record captured by the handler from
+// NewSlogRecorder.
+type LogRecord struct {
+	Time    time.Time
+	Level   slog.Level
+	Message string
+	// Attrs holds the resolved values of the attributes of the record,
+	// including those added to the logger. The attributes in groups
+	// are keyed by the group names and their own key joined by dots,
+	// such as "request.method".
+	Attrs map[string]slog.Value
+}
+
+// String returns a single line description of the record.
+func (r LogRecord) String() string {
+	keys := make([]string, 0, len(r.Attrs))
+	for key := range r.Attrs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	parts := []string{r.Level.String(), fmt.Sprintf("%q", r.Message)}
+	for _, key := range keys {
+		parts = append(parts, key+"="+r.Attrs[key].String())
+	}
+	return strings.Join(parts, " ")
+}
+
+// SlogRecords holds the log records captured by the handler from
+// NewSlogRecorder. It is safe for concurrent use.
+type SlogRecords struct {
+	mu      sync.Mutex
+	records []LogRecord
+}
+
+// Records returns the records captured so far, oldest first.
+func (s *SlogRecords) Records() []LogRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]LogRecord(nil), s.records...)
+}
+
+// NewSlogRecorder returns an slog.Handler that captures all the
+// records logged with it, of every level, and the records it has
+// captured. The records can be checked with HasLogRecord and
+// NoLogsAbove.
+//
+//	handler, records := checkers.NewSlogRecorder()
+//	serve(slog.New(handler))
+//	t.Check(records, checkers.NoLogsAbove(slog.LevelInfo))
+func NewSlogRecorder() (slog.Handler, *SlogRecords) {
+	records := &SlogRecords{}
+	return &slogRecorder{records: records}, records
+}
+
+type slogRecorder struct {
+	records *SlogRecords
+	// attrs are those added with WithAttrs, already flattened.
+	attrs map[string]slog.Value
+	// prefix is the group names from WithGroup, each followed by a dot.
+	prefix string
+}
+
+func (h *slogRecorder) Enabled(context.Context, slog.Level) bool {
+	return true
+}
+
+func (h *slogRecorder) Handle(_ context.Context, r slog.Record) error {
+	record := LogRecord{
+		Time:    r.Time,
+		Level:   r.Level,
+		Message: r.Message,
+		Attrs:   make(map[string]slog.Value, len(h.attrs)+r.NumAttrs()),
+	}
+	for key, value := range h.attrs {
+		record.Attrs[key] = value
+	}
+	r.Attrs(func(attr slog.Attr) bool {
+		flattenAttr(record.Attrs, h.prefix, attr)
+		return true
+	})
+	h.records.mu.Lock()
+	defer h.records.mu.Unlock()
+	h.records.records = append(h.records.records, record)
+	return nil
+}
+
+func (h *slogRecorder) WithAttrs(attrs []slog.Attr) slog.Handler {
+	result := *h
+	result.attrs = make(map[string]slog.Value, len(h.attrs)+len(attrs))
+	for key, value := range h.attrs {
+		result.attrs[key] = value
+	}
+	for _, attr := range attrs {
+		flattenAttr(result.attrs, h.prefix, attr)
+	}
+	return &result
+}
+
+func (h *slogRecorder) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	result := *h
+	result.prefix = h.prefix + name + "."
+	return &result
+}
+
+// flattenAttr adds the attribute to the map, with the attributes of
+// groups keyed by their path, following the rules of slog.Handler.
+func flattenAttr(attrs map[string]slog.Value, prefix string, attr slog.Attr) {
+	value := attr.Value.Resolve()
+	if value.Kind() == slog.KindGroup {
+		group := value.Group()
+		if len(group) == 0 {
+			return
+		}
+		if attr.Key != "" {
+			prefix += attr.Key + "."
+		}
+		for _, a := range group {
+			flattenAttr(attrs, prefix, a)
+		}
+		return
+	}
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+	attrs[prefix+attr.Key] = value
+}
+
+// obtainedRecords returns the records of the obtained value.
+func obtainedRecords(checker string, obtained interface{}) ([]LogRecord, error) {
+	switch obtained := obtained.(type) {
+	case *SlogRecords:
+		return obtained.Records(), nil
+	case []LogRecord:
+		return obtained, nil
+	}
+	return nil, fmt.Errorf("%s checker expected *SlogRecords or []LogRecord, obtained was %s", checker, describeType(obtained))
+}
+
+// describeRecords lists the records for failure messages.
+func describeRecords(records []LogRecord) string {
+	if len(records) == 0 {
+		return "no records"
+	}
+	lines := make([]string, len(records))
+	for i, record := range records {
+		lines[i] = "\t" + record.String()
+	}
+	return "records:\n" + strings.Join(lines, "\n")
+}
+
+type hasLogRecord struct {
+	level   slog.Level
+	pattern string
+	attrs   map[string]interface{}
+}
+
+// HasLogRecord returns a checker that checks that the obtained
+// *SlogRecords or []LogRecord has a record of the level, with a message
+// that matches the pattern in full, as with Matches, and with the
+// attributes given. The attributes are keyed as in LogRecord.Attrs. An
+// attribute value that is a Checker is checked against the value of
+// the attribute, and any other value is compared with DeepEquals after
+// being converted as slog would, so int values compare with logged
+// int64 values.
+func HasLogRecord(level slog.Level, pattern string, attrs map[string]interface{}) Checker {
+	return hasLogRecord{
+		level:   level,
+		pattern: pattern,
+		attrs:   attrs,
+	}
+}
+
+func (c hasLogRecord) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if err := unexpectedExtras("HasLogRecord", extras); err != nil {
+		return err
+	}
+	re, err := compilePattern(c.pattern)
+	if err != nil {
+		return err
+	}
+	records, err := obtainedRecords("HasLogRecord", obtained)
+	if err != nil {
+		return err
+	}
+	for _, record := range records {
+		if record.Level == c.level && re.MatchString(record.Message) && c.attrsMatch(record) {
+			return nil
+		}
+	}
+	return lazyFailure(func() string {
+		return fmt.Sprintf("no %s record with message matching %q%s\n%s",
+			c.level, re.String(), c.describeAttrs(), describeRecords(records))
+	})
+}
+
+func (c hasLogRecord) attrsMatch(record LogRecord) bool {
+	for key, expected := range c.attrs {
+		value, ok := record.Attrs[key]
+		if !ok {
+			return false
+		}
+		if checker, ok := expected.(Checker); ok {
+			if checker.Check(value.Any()) != nil {
+				return false
+			}
+			continue
+		}
+		if ok, _ := DeepEqual(value.Any(), slog.AnyValue(expected).Resolve().Any()); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (c hasLogRecord) describeAttrs() string {
+	if len(c.attrs) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(c.attrs))
+	for key := range c.attrs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		expected := c.attrs[key]
+		if _, ok := expected.(Checker); ok {
+			parts[i] = key + " passing a checker"
+		} else {
+			parts[i] = fmt.Sprintf("%s=%v", key, expected)
+		}
+	}
+	return " and attributes " + strings.Join(parts, " ")
+}
+
+type noLogsAbove struct {
+	level slog.Level
+}
+
+// NoLogsAbove returns a checker that checks that the obtained
+// *SlogRecords or []LogRecord has no records of a level above the
+// level given.
+func NoLogsAbove(level slog.Level) Checker {
+	return noLogsAbove{level}
+}
+
+func (c noLogsAbove) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if err := unexpectedExtras("NoLogsAbove", extras); err != nil {
+		return err
+	}
+	records, err := obtainedRecords("NoLogsAbove", obtained)
+	if err != nil {
+		return err
+	}
+	var above []LogRecord
+	for _, record := range records {
+		if record.Level > c.level {
+			above = append(above, record)
+		}
+	}
+	if len(above) == 0 {
+		return nil
+	}
+	return lazyFailure(func() string {
+		return fmt.Sprintf("%d records above %s\n%s", len(above), c.level, describeRecords(above))
+	})
+}
@@ -0,0 +1,108 @@
+// Add a copyright
+// Add a licence
+
+//go:build go1.21
+// +build go1.21
+
+package checkers_test
+
+import (
+	"log/slog"
+	"regexp"
+	"testing"
+
+	"github.com/howbazaar/checkers"
+)
+
+func TestSlogRecorder(t *testing.T) {
+	handler, records := checkers.NewSlogRecorder()
+	logger := slog.New(handler)
+	logger.Debug("starting", "port", 8080)
+	logger.With("server", "web").WithGroup("request").Info("handled", "method", "GET", slog.Group("user", "id", 42))
+	logger.Warn("slow", slog.Group("empty"), "ms", 1500)
+
+	obtained := records.Records()
+	if len(obtained) != 3 {
+		t.Fatalf("expected 3 records, got %d", len(obtained))
+	}
+	for i, expected := range []string{
+		`DEBUG "starting" port=8080`,
+		`INFO "handled" request.method=GET request.user.id=42 server=web`,
+		`WARN "slow" ms=1500`,
+	} {
+		if obtained[i].String() != expected {
+			t.Errorf("record %d: expected %s, got %s", i, expected, obtained[i])
+		}
+	}
+
+	for _, test := range []struct {
+		description string
+		checker     checkers.Checker
+		obtained    interface{}
+		err         string
+	}{
+		{
+			description: "has record",
+			checker:     checkers.HasLogRecord(slog.LevelInfo, "hand.*", nil),
+			obtained:    records,
+		}, {
+			description: "has record with attributes",
+			checker: checkers.HasLogRecord(slog.LevelInfo, "handled", map[string]interface{}{
+				"server":          "web",
+				"request.user.id": 42,
+				"request.method":  checkers.MatchesRegexp(regexp.MustCompile("^(GET|HEAD)$")),
+			}),
+			obtained: obtained,
+		}, {
+			description: "level differs",
+			checker:     checkers.HasLogRecord(slog.LevelError, "slow", nil),
+			obtained:    records,
+			err: "no ERROR record with message matching \"^slow$\"\nrecords:\n" +
+				"\tDEBUG \"starting\" port=8080\n" +
+				"\tINFO \"handled\" request.method=GET request.user.id=42 server=web\n" +
+				"\tWARN \"slow\" ms=1500",
+		}, {
+			description: "attribute differs",
+			checker:     checkers.HasLogRecord(slog.LevelDebug, "starting", map[string]interface{}{"port": 80, "host": checkers.IsNil}),
+			obtained:    obtained[:1],
+			err: "no DEBUG record with message matching \"^starting$\" and attributes host passing a checker port=80\nrecords:\n" +
+				"\tDEBUG \"starting\" port=8080",
+		}, {
+			description: "no records",
+			checker:     checkers.HasLogRecord(slog.LevelDebug, "starting", nil),
+			obtained:    []checkers.LogRecord(nil),
+			err:         "no DEBUG record with message matching \"^starting$\"\nno records",
+		}, {
+			description: "not records",
+			checker:     checkers.HasLogRecord(slog.LevelDebug, "starting", nil),
+			obtained:    "starting",
+			err:         "HasLogRecord checker expected *SlogRecords or []LogRecord, obtained was type string",
+		}, {
+			description: "no logs above",
+			checker:     checkers.NoLogsAbove(slog.LevelWarn),
+			obtained:    records,
+		}, {
+			description: "logs above",
+			checker:     checkers.NoLogsAbove(slog.LevelDebug),
+			obtained:    records,
+			err: "2 records above DEBUG\nrecords:\n" +
+				"\tINFO \"handled\" request.method=GET request.user.id=42 server=web\n" +
+				"\tWARN \"slow\" ms=1500",
+		},
+	} {
+		err := test.checker.Check(test.obtained)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+		} else {
+			if test.err == "" {
+				t.Errorf("%s: unexpected error: %v", test.description, err)
+			} else {
+				if err.Error() != test.err {
+					t.Errorf("%s: error mismatch: \n\tobtained: %q\n\texpected: %q", test.description, err.Error(), test.err)
+				}
+			}
+		}
+	}
+}
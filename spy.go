@@ -0,0 +1,310 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Call is a call recorded by a Spy, with the arguments it was made
+// with. The arguments of a variadic function are recorded one by one,
+// as they were passed.
+type Call struct {
+	Args []interface{}
+}
+
+// String returns the arguments of the call in parentheses.
+func (c Call) String() string {
+	return formatArgs(c.Args)
+}
+
+// Spy records the calls made to a function replaced by NewSpy or
+// NewStub, for the CalledTimes, CalledWith and NeverCalled checkers to
+// check. It is safe for concurrent use.
+type Spy struct {
+	mu    sync.Mutex
+	calls []Call
+}
+
+// NewSpy replaces the function that fn points to, such as a field of a
+// configuration struct or a variable that the code under test calls in
+// place of another function, with one that records each call to the
+// returned Spy and then calls the original function. If the original
+// function is nil, the replacement returns zero values. It panics if fn
+// is not a non-nil pointer to a function.
+//
+//	notify := mailer.Notify
+//	spy := checkers.NewSpy(&notify)
+//	alertOwners(notify, repo)
+//	t.Check(spy, checkers.CalledWith("alice", checkers.Not(checkers.IsNil)))
+func NewSpy(fn interface{}) *Spy {
+	f := funcVariable("NewSpy", fn)
+	original := reflect.ValueOf(f.Interface())
+	return replaceFunc(f, func(args []reflect.Value) []reflect.Value {
+		if original.IsNil() {
+			return zeroResults(f.Type())
+		}
+		if f.Type().IsVariadic() {
+			return original.CallSlice(args)
+		}
+		return original.Call(args)
+	})
+}
+
+// NewStub replaces the function that fn points to with one that records
+// each call to the returned Spy and returns the results, which are
+// converted to the function's result types as PanicsWhenCalled converts
+// arguments. It panics if fn is not a non-nil pointer to a function, or
+// the results do not suit it.
+//
+//	var fetch func(url string) ([]byte, error)
+//	spy := checkers.NewStub(&fetch, nil, errors.New("offline"))
+func NewStub(fn interface{}, results ...interface{}) *Spy {
+	f := funcVariable("NewStub", fn)
+	t := f.Type()
+	if len(results) != t.NumOut() {
+		panic(fmt.Sprintf("checkers: NewStub needs %d results for %s, got %d", t.NumOut(), t, len(results)))
+	}
+	values := make([]reflect.Value, len(results))
+	for i, result := range results {
+		v, ok := convertValue(result, t.Out(i))
+		if !ok {
+			panic(fmt.Sprintf("checkers: NewStub result %d must be %s, got %s", i, t.Out(i), describe(result)))
+		}
+		// Results are returned with exactly the types of the function.
+		values[i] = reflect.New(t.Out(i)).Elem()
+		values[i].Set(v)
+	}
+	return replaceFunc(f, func([]reflect.Value) []reflect.Value {
+		return values
+	})
+}
+
+// funcVariable returns the function variable that fn points to.
+func funcVariable(name string, fn interface{}) reflect.Value {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Func {
+		panic(fmt.Sprintf("checkers: %s needs a non-nil pointer to a function, got %s", name, describe(fn)))
+	}
+	return v.Elem()
+}
+
+// replaceFunc sets the function variable to one that records its calls
+// to the returned Spy before calling impl.
+func replaceFunc(f reflect.Value, impl func(args []reflect.Value) []reflect.Value) *Spy {
+	spy := &Spy{}
+	t := f.Type()
+	f.Set(reflect.MakeFunc(t, func(args []reflect.Value) []reflect.Value {
+		spy.record(t, args)
+		return impl(args)
+	}))
+	return spy
+}
+
+func (s *Spy) record(t reflect.Type, args []reflect.Value) {
+	var call Call
+	for i, arg := range args {
+		if t.IsVariadic() && i == len(args)-1 {
+			for j := 0; j < arg.Len(); j++ {
+				call.Args = append(call.Args, arg.Index(j).Interface())
+			}
+			break
+		}
+		call.Args = append(call.Args, arg.Interface())
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = append(s.calls, call)
+}
+
+// Calls returns the calls recorded so far, in the order they were made.
+func (s *Spy) Calls() []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Call(nil), s.calls...)
+}
+
+func zeroResults(t reflect.Type) []reflect.Value {
+	results := make([]reflect.Value, t.NumOut())
+	for i := range results {
+		results[i] = reflect.Zero(t.Out(i))
+	}
+	return results
+}
+
+// obtainedSpy returns the obtained value as a *Spy.
+func obtainedSpy(checker string, obtained interface{}) (*Spy, error) {
+	spy, ok := obtained.(*Spy)
+	if !ok || spy == nil {
+		return nil, fmt.Errorf("%s checker expected a *Spy, obtained was %s", checker, describe(obtained))
+	}
+	return spy, nil
+}
+
+// describeCalls lists the calls for failure messages.
+func describeCalls(calls []Call) string {
+	if len(calls) == 0 {
+		return "no calls"
+	}
+	lines := make([]string, len(calls))
+	for i, call := range calls {
+		lines[i] = "\t" + call.String()
+	}
+	return "calls:\n" + strings.Join(lines, "\n")
+}
+
+// formatArgs renders the arguments, or the expected arguments, of a
+// call in parentheses. Checkers are shown by name.
+func formatArgs(args []interface{}) string {
+	parts := make([]string, len(args))
+	for i, arg := range args {
+		switch arg := arg.(type) {
+		case nil:
+			parts[i] = "nil"
+		case string:
+			parts[i] = fmt.Sprintf("%q", arg)
+		case Checker:
+			parts[i] = checkerName(arg)
+		default:
+			parts[i] = fmt.Sprintf("%v", display(arg))
+		}
+	}
+	return "(" + strings.Join(parts, ", ") + ")"
+}
+
+// argsMatch reports whether the arguments of a call match the expected
+// ones. An expected Checker must pass the argument, nil matches nil
+// arguments of any type, and other values must be deeply equal to the
+// argument once converted to its type as PanicsWhenCalled converts
+// arguments.
+func argsMatch(args, expected []interface{}) bool {
+	if len(args) != len(expected) {
+		return false
+	}
+	for i, want := range expected {
+		arg := args[i]
+		if checker, ok := want.(Checker); ok {
+			if checker.Check(arg) != nil {
+				return false
+			}
+			continue
+		}
+		if want == nil || arg == nil {
+			if (want == nil || isTypedNil(want)) != (arg == nil || isTypedNil(arg)) {
+				return false
+			}
+			continue
+		}
+		v, ok := convertValue(want, reflect.TypeOf(arg))
+		if !ok {
+			return false
+		}
+		if ok, _ := DeepEqual(arg, v.Interface()); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+type calledTimes struct{}
+
+// CalledTimes checker checks that the function of the obtained *Spy was
+// called the expected number of times.
+//
+//	t.Check(spy, checkers.CalledTimes, 2)
+var CalledTimes Checker = calledTimes{}
+
+func (calledTimes) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if len(extras) == 0 {
+		return errors.New("missing 'expected' value")
+	}
+	expected, extras := extras[0], extras[1:]
+	if err := unexpectedExtras("CalledTimes", extras); err != nil {
+		return err
+	}
+	n, ok := expected.(int)
+	if !ok || n < 0 {
+		return fmt.Errorf("expected value must be a non-negative int, got %s", describe(expected))
+	}
+	spy, err := obtainedSpy("CalledTimes", obtained)
+	if err != nil {
+		return err
+	}
+	calls := spy.Calls()
+	if len(calls) == n {
+		return nil
+	}
+	return lazyFailure(func() string {
+		return fmt.Sprintf("called %d times, expected %d\n%s", len(calls), n, describeCalls(calls))
+	})
+}
+
+type neverCalled struct{}
+
+// NeverCalled checker checks that the function of the obtained *Spy was
+// not called.
+var NeverCalled Checker = neverCalled{}
+
+func (neverCalled) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if err := unexpectedExtras("NeverCalled", extras); err != nil {
+		return err
+	}
+	spy, err := obtainedSpy("NeverCalled", obtained)
+	if err != nil {
+		return err
+	}
+	calls := spy.Calls()
+	if len(calls) == 0 {
+		return nil
+	}
+	return lazyFailure(func() string {
+		return fmt.Sprintf("called %d times, expected never\n%s", len(calls), describeCalls(calls))
+	})
+}
+
+type calledWith struct {
+	args []interface{}
+}
+
+// CalledWith returns a checker that checks that the function of the
+// obtained *Spy was called at least once with arguments matching args,
+// one for each argument. An argument that is a Checker, such as
+// Not(IsNil), must pass the argument it is matched with, nil matches
+// any nil argument, and other values must be deeply equal to their
+// argument, with numbers converted to its type when they can be without
+// changing their value.
+//
+//	t.Check(spy, checkers.CalledWith("alice", 3))
+func CalledWith(args ...interface{}) Checker {
+	return calledWith{args: args}
+}
+
+func (c calledWith) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if err := unexpectedExtras("CalledWith", extras); err != nil {
+		return err
+	}
+	spy, err := obtainedSpy("CalledWith", obtained)
+	if err != nil {
+		return err
+	}
+	calls := spy.Calls()
+	for _, call := range calls {
+		if argsMatch(call.Args, c.args) {
+			return nil
+		}
+	}
+	return lazyFailure(func() string {
+		return fmt.Sprintf("no call with arguments %s\n%s", formatArgs(c.args), describeCalls(calls))
+	})
+}
@@ -0,0 +1,182 @@
+// Add a copyright
+// Add a licence
+
+package checkers_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/howbazaar/checkers"
+)
+
+func TestSpy(t *testing.T) {
+	join := strings.Join
+	spy := checkers.NewSpy(&join)
+	if got := join([]string{"a", "b"}, "-"); got != "a-b" {
+		t.Errorf("spied function returned %q, expected %q", got, "a-b")
+	}
+	calls := spy.Calls()
+	if len(calls) != 1 || calls[0].String() != `([a b], "-")` {
+		t.Errorf("unexpected calls %v", calls)
+	}
+
+	sprintf := fmt.Sprintf
+	spy = checkers.NewSpy(&sprintf)
+	if got := sprintf("%d-%s", 1, "x"); got != "1-x" {
+		t.Errorf("spied variadic function returned %q, expected %q", got, "1-x")
+	}
+	if calls := spy.Calls(); len(calls) != 1 || calls[0].String() != `("%d-%s", 1, "x")` {
+		t.Errorf("unexpected variadic calls %v", calls)
+	}
+
+	var missing func(int) (string, error)
+	spy = checkers.NewSpy(&missing)
+	if s, err := missing(1); s != "" || err != nil {
+		t.Errorf("spy of nil function returned %q, %v", s, err)
+	}
+}
+
+func TestStub(t *testing.T) {
+	var fetch func(url string) ([]byte, error)
+	offline := errors.New("offline")
+	spy := checkers.NewStub(&fetch, nil, offline)
+	if data, err := fetch("https://example.com"); data != nil || err != offline {
+		t.Errorf("stub returned %v, %v", data, err)
+	}
+	if calls := spy.Calls(); len(calls) != 1 || calls[0].String() != `("https://example.com")` {
+		t.Errorf("unexpected calls %v", calls)
+	}
+
+	var size func() int64
+	checkers.NewStub(&size, 42)
+	if got := size(); got != 42 {
+		t.Errorf("stub returned %d, expected 42", got)
+	}
+
+	for _, test := range []struct {
+		description string
+		f           func()
+		panic       string
+	}{
+		{
+			description: "not a pointer",
+			f:           func() { checkers.NewStub(fetch, nil, nil) },
+			panic:       "checkers: NewStub needs a non-nil pointer to a function, got func(string) ([]uint8, error) value",
+		}, {
+			description: "wrong number of results",
+			f:           func() { checkers.NewStub(&fetch, nil) },
+			panic:       "checkers: NewStub needs 2 results for func(string) ([]uint8, error), got 1",
+		}, {
+			description: "wrong result type",
+			f:           func() { checkers.NewStub(&size, "42") },
+			panic:       "checkers: NewStub result 0 must be int64, got string value 42",
+		}, {
+			description: "pointer to a non-function",
+			f:           func() { checkers.NewSpy(new(int)) },
+			panic:       "checkers: NewSpy needs a non-nil pointer to a function, got *int value",
+		},
+	} {
+		func() {
+			defer func() {
+				got := fmt.Sprint(recover())
+				if !strings.HasPrefix(got, test.panic) {
+					t.Errorf("%s: panicked with %q, expected %q", test.description, got, test.panic)
+				}
+			}()
+			test.f()
+		}()
+	}
+}
+
+func TestSpyCheckers(t *testing.T) {
+	var send func(to string, attempts int, err error) bool
+	spy := checkers.NewStub(&send, true)
+	send("alice", 1, nil)
+	send("bob", 2, errors.New("timeout"))
+	unused := checkers.NewStub(&send, false)
+
+	for _, test := range []struct {
+		description string
+		checker     checkers.Checker
+		obtained    interface{}
+		extras      []interface{}
+		err         string
+	}{
+		{
+			description: "called times",
+			checker:     checkers.CalledTimes,
+			obtained:    spy,
+			extras:      []interface{}{2},
+		}, {
+			description: "called other times",
+			checker:     checkers.CalledTimes,
+			obtained:    spy,
+			extras:      []interface{}{1, checkers.Commentf("retries")},
+			err:         "called 2 times, expected 1\ncalls:\n\t(\"alice\", 1, nil)\n\t(\"bob\", 2, timeout)\ncomment: retries",
+		}, {
+			description: "called no times",
+			checker:     checkers.CalledTimes,
+			obtained:    unused,
+			extras:      []interface{}{1},
+			err:         "called 0 times, expected 1\nno calls",
+		}, {
+			description: "called times not an int",
+			checker:     checkers.CalledTimes,
+			obtained:    spy,
+			extras:      []interface{}{"2"},
+			err:         "expected value must be a non-negative int, got string value 2",
+		}, {
+			description: "never called",
+			checker:     checkers.NeverCalled,
+			obtained:    unused,
+		}, {
+			description: "called",
+			checker:     checkers.NeverCalled,
+			obtained:    spy,
+			err:         "called 2 times, expected never\ncalls:\n\t(\"alice\", 1, nil)\n\t(\"bob\", 2, timeout)",
+		}, {
+			description: "called with",
+			checker:     checkers.CalledWith("alice", 1, nil),
+			obtained:    spy,
+		}, {
+			description: "called with checkers",
+			checker:     checkers.CalledWith("bob", checkers.Not(checkers.IsNil), checkers.Not(checkers.IsNil)),
+			obtained:    spy,
+		}, {
+			description: "called with converted number",
+			checker:     checkers.CalledWith("bob", uint8(2), checkers.Not(checkers.IsNil)),
+			obtained:    spy,
+		}, {
+			description: "not called with",
+			checker:     checkers.CalledWith("bob", 2, checkers.IsNil),
+			obtained:    spy,
+			err:         "no call with arguments (\"bob\", 2, IsNil)\ncalls:\n\t(\"alice\", 1, nil)\n\t(\"bob\", 2, timeout)",
+		}, {
+			description: "called with fewer arguments",
+			checker:     checkers.CalledWith("alice"),
+			obtained:    unused,
+			err:         "no call with arguments (\"alice\")\nno calls",
+		}, {
+			description: "not a spy",
+			checker:     checkers.CalledWith("alice"),
+			obtained:    "alice",
+			err:         "CalledWith checker expected a *Spy, obtained was string value alice",
+		},
+	} {
+		err := test.checker.Check(test.obtained, test.extras...)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+		} else {
+			if test.err == "" {
+				t.Errorf("%s: unexpected error: %v", test.description, err)
+			} else if err.Error() != test.err {
+				t.Errorf("%s: error mismatch: \n\tobtained: %q\n\texpected: %q", test.description, err.Error(), test.err)
+			}
+		}
+	}
+}
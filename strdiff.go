@@ -0,0 +1,70 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// stringDiffContext is the number of runes shown either side of the
+// first difference between two strings.
+const stringDiffContext = 20
+
+// stringDiff describes where two strings first differ, giving the byte
+// and rune offsets of the difference and an excerpt of each string
+// around it with a caret under the first differing rune. The excerpts
+// are quoted, so white space such as trailing spaces, tabs and carriage
+// returns, and other invisible characters, can be told apart.
+func stringDiff(obtained, expected string) string {
+	offset, runes := 0, 0
+	for offset < len(obtained) && offset < len(expected) {
+		_, size := utf8.DecodeRuneInString(obtained[offset:])
+		// Compare the bytes rather than the decoded runes, so that
+		// differing invalid bytes are not taken to be the same.
+		if size > len(expected)-offset || obtained[offset:offset+size] != expected[offset:offset+size] {
+			break
+		}
+		offset += size
+		runes++
+	}
+
+	start := offset
+	for i := 0; i < stringDiffContext && start > 0; i++ {
+		_, size := utf8.DecodeLastRuneInString(obtained[:start])
+		start -= size
+	}
+	lead := ""
+	if start > 0 {
+		lead = "..."
+	}
+	prefix := obtained[start:offset]
+
+	// The quoted prefix, less its closing quote, is as wide as the
+	// column of the caret.
+	caret := len(lead) + utf8.RuneCountInString(strconv.Quote(prefix)) - 1
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "strings differ at byte %d (rune %d):\n", offset, runes)
+	fmt.Fprintf(&buf, "\tobtained: %s%s\n", lead, excerpt(prefix, obtained[offset:]))
+	fmt.Fprintf(&buf, "\texpected: %s%s\n", lead, excerpt(prefix, expected[offset:]))
+	fmt.Fprintf(&buf, "\t          %s^", strings.Repeat(" ", caret))
+	return buf.String()
+}
+
+// excerpt returns the prefix followed by the start of the rest of a
+// string, quoted, with an ellipsis if the string goes on further.
+func excerpt(prefix, rest string) string {
+	end := 0
+	for i := 0; i < stringDiffContext && end < len(rest); i++ {
+		_, size := utf8.DecodeRuneInString(rest[end:])
+		end += size
+	}
+	text := strconv.Quote(prefix + rest[:end])
+	if end < len(rest) {
+		text += "..."
+	}
+	return text
+}
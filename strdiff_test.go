@@ -0,0 +1,72 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"testing"
+)
+
+func TestStringDiff(t *testing.T) {
+	for _, test := range []struct {
+		description string
+		obtained    string
+		expected    string
+		diff        string
+	}{
+		{
+			description: "trailing space",
+			obtained:    "hello ",
+			expected:    "hello",
+			diff: "strings differ at byte 5 (rune 5):\n" +
+				"\tobtained: \"hello \"\n" +
+				"\texpected: \"hello\"\n" +
+				"\t                ^",
+		}, {
+			description: "line endings",
+			obtained:    "one\r\ntwo",
+			expected:    "one\ntwo",
+			diff: "strings differ at byte 3 (rune 3):\n" +
+				"\tobtained: \"one\\r\\ntwo\"\n" +
+				"\texpected: \"one\\ntwo\"\n" +
+				"\t              ^",
+		}, {
+			description: "invisible characters",
+			obtained:    "a\u200bb",
+			expected:    "ab",
+			diff: "strings differ at byte 1 (rune 1):\n" +
+				"\tobtained: \"a\\u200bb\"\n" +
+				"\texpected: \"ab\"\n" +
+				"\t            ^",
+		}, {
+			description: "multibyte runes",
+			obtained:    "héllo wörld",
+			expected:    "héllo world",
+			diff: "strings differ at byte 8 (rune 7):\n" +
+				"\tobtained: \"héllo wörld\"\n" +
+				"\texpected: \"héllo world\"\n" +
+				"\t                  ^",
+		}, {
+			description: "invalid bytes",
+			obtained:    "a\xffb",
+			expected:    "a\xfeb",
+			diff: "strings differ at byte 1 (rune 1):\n" +
+				"\tobtained: \"a\\xffb\"\n" +
+				"\texpected: \"a\\xfeb\"\n" +
+				"\t            ^",
+		}, {
+			description: "long strings",
+			obtained:    "the quick brown fox jumps over the lazy dog and keeps on running far away",
+			expected:    "the quick brown fox jumps over the lazy cat and keeps on running far away",
+			diff: "strings differ at byte 40 (rune 40):\n" +
+				"\tobtained: ...\"jumps over the lazy dog and keeps on run\"...\n" +
+				"\texpected: ...\"jumps over the lazy cat and keeps on run\"...\n" +
+				"\t                                  ^",
+		},
+	} {
+		diff := stringDiff(test.obtained, test.expected)
+		if diff != test.diff {
+			t.Errorf("%s: diff mismatch:\n\tobtained: %q\n\texpected: %q", test.description, diff, test.diff)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// TagOption is an extra value understood by the StructTagsValid
+// checker, adding a rule that the tags must follow.
+type TagOption struct {
+	required string
+	key      string
+	pattern  string
+}
+
+// RequireTag returns a TagOption that has every exported field required
+// to have a tag with the key.
+func RequireTag(key string) TagOption {
+	return TagOption{required: key}
+}
+
+// TagPattern returns a TagOption that has the name given in each tag
+// with the key, before any comma separated options, required to match
+// the regexp pattern in full. Names of "-" are not checked.
+func TagPattern(key, pattern string) TagOption {
+	return TagOption{key: key, pattern: pattern}
+}
+
+// tagPattern is a compiled TagPattern option.
+type tagPattern struct {
+	key     string
+	pattern string
+	re      *regexp.Regexp
+}
+
+// jsonTagOptions holds the options understood by encoding/json.
+var jsonTagOptions = map[string]bool{
+	"omitempty": true,
+	"omitzero":  true,
+	"string":    true,
+}
+
+type structTagsValid struct{}
+
+// StructTagsValid checker checks the tags of the fields of the obtained
+// struct type, given as a struct, a pointer to one or a reflect.Type.
+// Every tag must be in the conventional format of space separated
+// key:"value" pairs, and json tags must not name the same key twice nor
+// use options that encoding/json does not understand, such as a
+// misspelled omitempty. RequireTag and TagPattern options may add
+// further rules. A failure lists every problem found.
+//
+//	t.Check(User{}, checkers.StructTagsValid,
+//		checkers.RequireTag("json"),
+//		checkers.TagPattern("db", "[a-z][a-z0-9_]*"))
+var StructTagsValid Checker = structTagsValid{}
+
+func (structTagsValid) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	var required []string
+	var patterns []tagPattern
+	for i, extra := range extras {
+		option, ok := extra.(TagOption)
+		if !ok {
+			return unexpectedExtras("StructTagsValid", extras[i:])
+		}
+		if option.required != "" {
+			required = append(required, option.required)
+		}
+		if option.key != "" {
+			re, err := compilePattern(option.pattern)
+			if err != nil {
+				return err
+			}
+			patterns = append(patterns, tagPattern{option.key, option.pattern, re})
+		}
+	}
+	t, ok := obtained.(reflect.Type)
+	if !ok {
+		t = reflect.TypeOf(obtained)
+	}
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return fmt.Errorf("StructTagsValid checker expected a struct type, obtained was %s", describeType(obtained))
+	}
+
+	var problems []string
+	problemf := func(field reflect.StructField, format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf("field %s: ", field.Name)+fmt.Sprintf(format, args...))
+	}
+	jsonNames := make(map[string]string)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if err := validateStructTag(field.Tag); err != nil {
+			problemf(field, "malformed tag `%s`: %v", field.Tag, err)
+			continue
+		}
+		exported := field.PkgPath == ""
+		for _, key := range required {
+			if _, ok := field.Tag.Lookup(key); exported && !ok {
+				problemf(field, "missing %s tag", key)
+			}
+		}
+		for _, p := range patterns {
+			value, ok := field.Tag.Lookup(p.key)
+			if !ok {
+				continue
+			}
+			name := strings.Split(value, ",")[0]
+			if name != "-" && !p.re.MatchString(name) {
+				problemf(field, "%s name %q does not match pattern %q", p.key, name, p.pattern)
+			}
+		}
+		value, ok := field.Tag.Lookup("json")
+		if !ok || value == "-" {
+			continue
+		}
+		parts := strings.Split(value, ",")
+		for _, option := range parts[1:] {
+			if !jsonTagOptions[option] {
+				problemf(field, "unknown json option %q", option)
+			}
+		}
+		if name := parts[0]; name != "" && exported {
+			if other, dup := jsonNames[name]; dup {
+				problemf(field, "json name %q already used by field %s", name, other)
+			} else {
+				jsonNames[name] = field.Name
+			}
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return lazyFailure(func() string {
+		return fmt.Sprintf("%d struct tag problems in %s:\n\t%s", len(problems), t, strings.Join(problems, "\n\t"))
+	})
+}
+
+// validateStructTag checks that the tag is in the conventional format
+// understood by reflect.StructTag.Get.
+func validateStructTag(tag reflect.StructTag) error {
+	s := string(tag)
+	for s != "" {
+		i := 0
+		for i < len(s) && s[i] == ' ' {
+			i++
+		}
+		s = s[i:]
+		if s == "" {
+			break
+		}
+		i = 0
+		for i < len(s) && s[i] > ' ' && s[i] != ':' && s[i] != '"' && s[i] != 0x7f {
+			i++
+		}
+		if i == 0 {
+			return errors.New("bad syntax for key")
+		}
+		if i+1 >= len(s) || s[i] != ':' {
+			return fmt.Errorf("key %q has no value", s[:i])
+		}
+		if s[i+1] != '"' {
+			return fmt.Errorf("value of key %q is not quoted", s[:i])
+		}
+		key := s[:i]
+		s = s[i+1:]
+		i = 1
+		for i < len(s) && s[i] != '"' {
+			if s[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(s) {
+			return fmt.Errorf("value of key %q is not terminated", key)
+		}
+		if _, err := strconv.Unquote(s[:i+1]); err != nil {
+			return fmt.Errorf("value of key %q is not a valid quoted string", key)
+		}
+		s = s[i+1:]
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+// Add a copyright
+// Add a licence
+
+package checkers_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/howbazaar/checkers"
+)
+
+type taggedUser struct {
+	ID       int    `json:"id" db:"id"`
+	Name     string `json:"name,omitempty" db:"user_name"`
+	Password string `json:"-"`
+	internal string
+}
+
+// badlyTaggedUser is built at run time, as go vet would report its
+// tags.
+var badlyTaggedUser = reflect.StructOf([]reflect.StructField{
+	{Name: "ID", Type: reflect.TypeOf(0), Tag: `json:"id" db:"ID"`},
+	{Name: "Name", Type: reflect.TypeOf(""), Tag: `json:"name,omitmepty"`},
+	{Name: "Email", Type: reflect.TypeOf(""), Tag: `json:"id"`},
+	{Name: "Age", Type: reflect.TypeOf(0), Tag: `json: "age"`},
+	{Name: "Missing", Type: reflect.TypeOf(false)},
+})
+
+func TestStructTagsValid(t *testing.T) {
+	for _, test := range []struct {
+		description string
+		obtained    interface{}
+		extras      []interface{}
+		err         string
+	}{
+		{
+			description: "valid",
+			obtained:    taggedUser{},
+			extras:      []interface{}{checkers.RequireTag("json"), checkers.TagPattern("db", "[a-z][a-z0-9_]*")},
+		}, {
+			description: "pointer",
+			obtained:    &taggedUser{},
+		}, {
+			description: "type",
+			obtained:    reflect.TypeOf(taggedUser{}),
+		}, {
+			description: "problems",
+			obtained:    badlyTaggedUser,
+			err: "3 struct tag problems in " + badlyTaggedUser.String() + ":\n" +
+				"\tfield Name: unknown json option \"omitmepty\"\n" +
+				"\tfield Email: json name \"id\" already used by field ID\n" +
+				"\tfield Age: malformed tag `json: \"age\"`: value of key \"json\" is not quoted",
+		}, {
+			description: "problems with options",
+			obtained:    badlyTaggedUser,
+			extras:      []interface{}{checkers.RequireTag("json"), checkers.TagPattern("db", "[a-z][a-z0-9_]*")},
+			err: "5 struct tag problems in " + badlyTaggedUser.String() + ":\n" +
+				"\tfield ID: db name \"ID\" does not match pattern \"[a-z][a-z0-9_]*\"\n" +
+				"\tfield Name: unknown json option \"omitmepty\"\n" +
+				"\tfield Email: json name \"id\" already used by field ID\n" +
+				"\tfield Age: malformed tag `json: \"age\"`: value of key \"json\" is not quoted\n" +
+				"\tfield Missing: missing json tag",
+		}, {
+			description: "invalid pattern",
+			obtained:    taggedUser{},
+			extras:      []interface{}{checkers.TagPattern("db", "[")},
+			err:         `invalid regexp pattern "[": missing closing ]: "[" at position 0`,
+		}, {
+			description: "not a struct",
+			obtained:    []taggedUser{},
+			err:         "StructTagsValid checker expected a struct type, obtained was type []checkers_test.taggedUser",
+		}, {
+			description: "unexpected extra",
+			obtained:    taggedUser{},
+			extras:      []interface{}{"json"},
+			err:         `too many arguments to checker StructTagsValid, unexpected string("json")`,
+		},
+	} {
+		err := checkers.StructTagsValid.Check(test.obtained, test.extras...)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+		} else {
+			if test.err == "" {
+				t.Errorf("%s: unexpected error: %v", test.description, err)
+			} else {
+				if err.Error() != test.err {
+					t.Errorf("%s: error mismatch: \n\tobtained: %q\n\texpected: %q", test.description, err.Error(), test.err)
+				}
+			}
+		}
+	}
+}
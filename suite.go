@@ -8,19 +8,70 @@ import (
 	"reflect"
 	"regexp"
 	"testing"
+	"time"
 )
 
 var testMethodMatch = regexp.MustCompile(`^Test([A-Z]\w*)$`)
 
+// SuiteOption alters how RunSuite and RunSuiteTB run the test methods.
+type SuiteOption func(*suiteConfig)
+
+type suiteConfig struct {
+	minAssertions int
+	maxDuration   time.Duration
+	warnDuration  bool
+}
+
+// MinimumAssertions has each test method fail if it makes fewer than n
+// checks, as Test.RequireAssertions does. MinimumAssertions(1) catches
+// test methods that check nothing at all. When the methods are not run
+// as subtests, the checks of those run so far count together.
+func MinimumAssertions(n int) SuiteOption {
+	return func(c *suiteConfig) {
+		c.minAssertions = n
+	}
+}
+
+// MaxTestDuration has each test method, including the call to its
+// SetUpTest method, fail if it takes longer than d, as
+// Test.FailIfLongerThan does.
+func MaxTestDuration(d time.Duration) SuiteOption {
+	return func(c *suiteConfig) {
+		c.maxDuration = d
+		c.warnDuration = false
+	}
+}
+
+// WarnTestDuration has a warning logged for each test method, including
+// the call to its SetUpTest method, that takes longer than d, as
+// Test.WarnIfLongerThan does.
+func WarnTestDuration(d time.Duration) SuiteOption {
+	return func(c *suiteConfig) {
+		c.maxDuration = d
+		c.warnDuration = true
+	}
+}
+
 // RunSuite runs a collection of methods as subtests.
-func RunSuite(t *testing.T, suite interface{}) {
+func RunSuite(t *testing.T, suite interface{}, options ...SuiteOption) {
+	RunSuiteTB(t, suite, options...)
+}
+
+// RunSuiteTB runs a collection of methods as RunSuite does, for any
+// testing.TB. The methods are run as subtests when tb is a *testing.T,
+// and one after another using tb itself otherwise.
+func RunSuiteTB(tb testing.TB, suite interface{}, options ...SuiteOption) {
+	var config suiteConfig
+	for _, option := range options {
+		option(&config)
+	}
 	v := reflect.ValueOf(suite)
 	if v.Kind() != reflect.Ptr {
-		t.Fatalf("suite must be passed in with pointer, not value")
+		tb.Fatalf("suite must be passed in with pointer, not value")
 	}
-	// Find the *testing.T in the suite, and set it.
-	if ok := setTestingT(t, v); !ok {
-		t.Fatal("unable to initialize the suite *testing.T")
+	// Find the testing.TB in the suite, and set it.
+	if ok := setTestingT(tb, v); !ok {
+		tb.Fatal("unable to initialize the suite testing.TB")
 		return
 	}
 	// See if there is a method called SetUpTest, and if there is
@@ -32,7 +83,7 @@ func RunSuite(t *testing.T, suite interface{}) {
 		methodType := setup.Type()
 		if methodType.NumIn() != 0 {
 			fmt.Println("doesn't take no args")
-			t.Fatal("SetUpTest should take no arguments")
+			tb.Fatal("SetUpTest should take no arguments")
 		}
 	}
 
@@ -43,22 +94,46 @@ func RunSuite(t *testing.T, suite interface{}) {
 		// so remove that for the subtest name.
 		short := method.Name[4:]
 		testFunc := v.MethodByName(method.Name)
-		t.Run(short, func(*testing.T) {
+		method := method
+		runSubtest(tb, short, func(tb testing.TB) {
+			// The suite reports to the subtest while it runs.
+			setTestingT(tb, v)
+			test := &Test{TB: tb}
+			if config.minAssertions > 0 {
+				test.RequireAssertions(config.minAssertions)
+			}
+			switch {
+			case config.maxDuration <= 0:
+			case config.warnDuration:
+				test.WarnIfLongerThan(config.maxDuration)
+			default:
+				test.FailIfLongerThan(config.maxDuration)
+			}
 			if setup.IsValid() {
 				setup.Call(nil)
 			}
 			funcType := testFunc.Type()
 			if count := funcType.NumIn(); count != 0 {
-				t.Fatalf("Test method %q takes %d args, should take none", method.Name, count)
+				tb.Fatalf("Test method %q takes %d args, should take none", method.Name, count)
 			}
 			if count := funcType.NumOut(); count != 0 {
-				t.Fatalf("Test method %q returns %d values, should return none", method.Name, count)
+				tb.Fatalf("Test method %q returns %d values, should return none", method.Name, count)
 			}
 			testFunc.Call(nil)
 		})
 	}
 }
 
+// runSubtest runs f as a subtest of tb where tb supports them, and
+// directly with tb otherwise.
+func runSubtest(tb testing.TB, name string, f func(testing.TB)) {
+	if t, ok := tb.(*testing.T); ok {
+		t.Run(name, func(t *testing.T) { f(t) })
+		return
+	}
+	f(tb)
+}
+
 func findTestMethods(v reflect.Value) []reflect.Method {
 	result := []reflect.Method{}
 
@@ -74,7 +149,16 @@ func findTestMethods(v reflect.Value) []reflect.Method {
 	return result
 }
 
-func setTestingT(t *testing.T, v reflect.Value) bool {
+var (
+	testingTBType = reflect.TypeOf((*testing.TB)(nil)).Elem()
+	testType      = reflect.TypeOf(Test{})
+)
+
+// setTestingT sets the first field of the suite that is either a
+// testing.TB or of the same type as t, looking through embedded structs
+// and non-nil pointers. A Test has its TB set to t, and its T set to t
+// if it is a *testing.T and nil otherwise.
+func setTestingT(t testing.TB, v reflect.Value) bool {
 	switch v.Kind() {
 	case reflect.Ptr:
 		return setTestingT(t, v.Elem())
@@ -84,13 +168,23 @@ func setTestingT(t *testing.T, v reflect.Value) bool {
 		return false
 	}
 
+	if v.Type() == testType {
+		if !v.CanSet() {
+			return false
+		}
+		test := Test{TB: t}
+		test.T, _ = t.(*testing.T)
+		v.Set(reflect.ValueOf(test))
+		return true
+	}
+
 	tValue := reflect.ValueOf(t)
 	tType := tValue.Type()
 	fieldCount := v.NumField()
 
 	for i := 0; i < fieldCount; i++ {
 		field := v.Field(i)
-		if field.Type() == tType {
+		if field.Type() == tType || field.Type() == testingTBType {
 			if field.CanSet() {
 				field.Set(tValue)
 				return true
@@ -6,7 +6,9 @@ package checkers
 import (
 	"fmt"
 	"reflect"
+	"regexp"
 	"testing"
+	"time"
 )
 
 func TestSetTestingT(t *testing.T) {
@@ -49,5 +51,191 @@ func TestSetTestingT(t *testing.T) {
 			t.Fatalf("unexpected setting of the testing.T")
 		}
 	})
+	t.Run("testing.T field", func(t *testing.T) {
+		type Own struct {
+			T *testing.T
+		}
+		aT := &testing.T{}
+		s := &Own{}
+		ok := setTestingT(aT, reflect.ValueOf(s))
+		if !ok {
+			t.Fatalf("unable to set the testing.T")
+		}
+		if s.T != aT {
+			t.Fatalf("testing.T field not set")
+		}
+	})
+	t.Run("embed suite TB", func(t *testing.T) {
+		type Embed struct {
+			Test
+		}
+		aT := &testing.T{}
+		s := &Embed{}
+		ok := setTestingT(aT, reflect.ValueOf(s))
+		if !ok {
+			t.Fatalf("unable to set the testing.TB")
+		}
+		if s.TB != aT {
+			t.Fatalf("nested testing.TB not set")
+		}
+	})
+	t.Run("embed suite benchmark", func(t *testing.T) {
+		type Embed struct {
+			Test
+		}
+		aB := &testing.B{}
+		s := &Embed{Test{T: &testing.T{}}}
+		ok := setTestingT(aB, reflect.ValueOf(s))
+		if !ok {
+			t.Fatalf("unable to set the testing.TB")
+		}
+		if s.TB != aB || s.T != nil {
+			t.Fatalf("nested testing.TB not set alone")
+		}
+	})
+}
+
+type recordSuite struct {
+	Test
+	ran []string
+	tbs []testing.TB
+}
 
+func (s *recordSuite) TestFirst() {
+	s.ran = append(s.ran, "First")
+	s.tbs = append(s.tbs, s.TB)
+}
+
+func (s *recordSuite) TestSecond() {
+	s.ran = append(s.ran, "Second")
+	s.tbs = append(s.tbs, s.TB)
+}
+
+func TestRunSuite(t *testing.T) {
+	s := &recordSuite{}
+	RunSuite(t, s)
+	if fmt.Sprint(s.ran) != "[First Second]" {
+		t.Fatalf("unexpected methods run: %v", s.ran)
+	}
+	for _, tb := range s.tbs {
+		if tb == t {
+			t.Fatalf("test method not run as a subtest")
+		}
+	}
+}
+
+func TestRunSuiteTB(t *testing.T) {
+	s := &recordSuite{}
+	var b *testing.B
+	testing.Benchmark(func(aB *testing.B) {
+		if b != nil {
+			return
+		}
+		b = aB
+		RunSuiteTB(aB, s)
+	})
+	if fmt.Sprint(s.ran) != "[First Second]" {
+		t.Fatalf("unexpected methods run: %v", s.ran)
+	}
+	for _, tb := range s.tbs {
+		if tb != b {
+			t.Fatalf("test method not run with the benchmark")
+		}
+	}
+}
+
+func TestRunSuiteNotPointer(t *testing.T) {
+	r := NewRecordingTB()
+	r.Run(func(tb testing.TB) {
+		RunSuiteTB(tb, recordSuite{})
+	})
+	if !r.Failed() || fmt.Sprint(r.Errors()) != "[suite must be passed in with pointer, not value]" {
+		t.Fatalf("unexpected errors: %q", r.Errors())
+	}
+}
+
+type checkingSuite struct {
+	Test
+	checks int
+}
+
+func (s *checkingSuite) TestChecks() {
+	for i := 0; i < s.checks; i++ {
+		s.Check(i, Equals, i)
+	}
+}
+
+func TestRunSuiteMinimumAssertions(t *testing.T) {
+	for _, test := range []struct {
+		description string
+		checks      int
+		errors      string
+	}{
+		{
+			description: "enough checks",
+			checks:      2,
+			errors:      "[]",
+		}, {
+			description: "too few checks",
+			checks:      1,
+			errors:      "[test made 1 assertions, expected at least 2]",
+		}, {
+			description: "no checks",
+			errors:      "[test made 0 assertions, expected at least 2]",
+		},
+	} {
+		r := NewRecordingTB()
+		r.Run(func(tb testing.TB) {
+			RunSuiteTB(tb, &checkingSuite{checks: test.checks}, MinimumAssertions(2))
+		})
+		if fmt.Sprint(r.Errors()) != test.errors {
+			t.Errorf("%s: unexpected errors: %q", test.description, r.Errors())
+		}
+	}
+}
+
+type slowSuite struct {
+	Test
+}
+
+func (s *slowSuite) TestSlow() {
+	time.Sleep(time.Millisecond)
+}
+
+func TestRunSuiteMaxTestDuration(t *testing.T) {
+	for _, test := range []struct {
+		description string
+		option      SuiteOption
+		errors      string
+		logs        string
+	}{
+		{
+			description: "within budget",
+			option:      MaxTestDuration(time.Hour),
+			errors:      "[]",
+			logs:        "[]",
+		}, {
+			description: "over budget",
+			option:      MaxTestDuration(time.Nanosecond),
+			errors:      "[test took X, longer than its budget of 1ns]",
+			logs:        "[]",
+		}, {
+			description: "over budget warning",
+			option:      WarnTestDuration(time.Nanosecond),
+			errors:      "[]",
+			logs:        "[test took X, longer than its budget of 1ns]",
+		},
+	} {
+		r := NewRecordingTB()
+		r.Run(func(tb testing.TB) {
+			RunSuiteTB(tb, &slowSuite{}, test.option)
+		})
+		took := regexp.MustCompile(`took [^,]+,`)
+		if errors := took.ReplaceAllString(fmt.Sprint(r.Errors()), "took X,"); errors != test.errors {
+			t.Errorf("%s: unexpected errors: %q", test.description, r.Errors())
+		}
+		if logs := took.ReplaceAllString(fmt.Sprint(r.Logs()), "took X,"); logs != test.logs {
+			t.Errorf("%s: unexpected logs: %q", test.description, r.Logs())
+		}
+	}
 }
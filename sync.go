@@ -0,0 +1,86 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+type atomicEquals struct{}
+
+// AtomicEquals checker loads the value held by the obtained atomic
+// value, such as an *atomic.Int64, *atomic.Bool, *atomic.Value or
+// *atomic.Pointer, and checks that it equals the expected value. Any
+// value with a Load method taking no arguments and returning a single
+// result may be checked, so the value is read safely while other
+// goroutines update it. Numbers, strings and bools are compared as by
+// EqualsConverted, and other values as by DeepEquals.
+//
+//	t.Check(&requests, checkers.AtomicEquals, 3)
+//	t.Check(&ready, checkers.AtomicEquals, true)
+var AtomicEquals Checker = atomicEquals{}
+
+func (atomicEquals) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if len(extras) == 0 {
+		return errors.New("missing 'expected' value")
+	}
+	expected, extras := extras[0], extras[1:]
+	if err := unexpectedExtras("AtomicEquals", extras); err != nil {
+		return err
+	}
+	var load reflect.Value
+	if obtained != nil {
+		load = reflect.ValueOf(obtained).MethodByName("Load")
+	}
+	if !load.IsValid() || load.Type().NumIn() != 0 || load.Type().NumOut() != 1 || isTypedNil(obtained) {
+		return fmt.Errorf("AtomicEquals checker expected a value with a Load method, obtained was %s", describe(obtained))
+	}
+	loaded := interfaceOf(load.Call(nil)[0])
+	switch kindFamily(reflect.ValueOf(loaded).Kind()) {
+	case reflect.Bool, reflect.String, reflect.Int, reflect.Uint, reflect.Float64:
+		return EqualsConverted.Check(loaded, expected)
+	}
+	return DeepEquals.Check(loaded, expected)
+}
+
+type syncMapHasKey struct{}
+
+// SyncMapHasKey checker checks that the obtained *sync.Map holds the
+// expected key. A failure lists the keys the map does hold.
+var SyncMapHasKey Checker = syncMapHasKey{}
+
+func (syncMapHasKey) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if len(extras) == 0 {
+		return errors.New("missing 'expected' value")
+	}
+	key, extras := extras[0], extras[1:]
+	if err := unexpectedExtras("SyncMapHasKey", extras); err != nil {
+		return err
+	}
+	m, ok := obtained.(*sync.Map)
+	if !ok || m == nil {
+		return fmt.Errorf("SyncMapHasKey checker expected *sync.Map, obtained was %s", describe(obtained))
+	}
+	if _, ok := m.Load(key); ok {
+		return nil
+	}
+	return lazyFailure(func() string {
+		var keys []string
+		m.Range(func(k, _ interface{}) bool {
+			keys = append(keys, fmt.Sprintf("%#v", k))
+			return true
+		})
+		sort.Strings(keys)
+		return fmt.Sprintf("key %#v not found in sync.Map with keys [%s]", key, strings.Join(keys, ", "))
+	})
+}
@@ -0,0 +1,121 @@
+// Add a copyright
+// Add a licence
+
+//go:build go1.19
+// +build go1.19
+
+package checkers_test
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/howbazaar/checkers"
+)
+
+func TestSyncCheckers(t *testing.T) {
+	var count atomic.Int64
+	count.Store(3)
+	var ready atomic.Bool
+	var value atomic.Value
+	value.Store("started")
+	name := "alice"
+	var pointer atomic.Pointer[string]
+	pointer.Store(&name)
+	var m sync.Map
+	m.Store("b", 2)
+	m.Store("a", 1)
+
+	for _, test := range []struct {
+		description string
+		checker     checkers.Checker
+		obtained    interface{}
+		extras      []interface{}
+		err         string
+	}{
+		{
+			description: "int64",
+			checker:     checkers.AtomicEquals,
+			obtained:    &count,
+			extras:      []interface{}{3},
+		}, {
+			description: "int64 mismatch",
+			checker:     checkers.AtomicEquals,
+			obtained:    &count,
+			extras:      []interface{}{4},
+			err:         "expected int value 4, got 3",
+		}, {
+			description: "bool",
+			checker:     checkers.AtomicEquals,
+			obtained:    &ready,
+			extras:      []interface{}{false},
+		}, {
+			description: "value",
+			checker:     checkers.AtomicEquals,
+			obtained:    &value,
+			extras:      []interface{}{"started"},
+		}, {
+			description: "pointer",
+			checker:     checkers.AtomicEquals,
+			obtained:    &pointer,
+			extras:      []interface{}{&name},
+		}, {
+			description: "pointer mismatch",
+			checker:     checkers.AtomicEquals,
+			obtained:    &pointer,
+			extras:      []interface{}{(*string)(nil)},
+			err:         `mismatch at top level: validity mismatch; obtained "alice"; expected <nil>`,
+		}, {
+			description: "no load method",
+			checker:     checkers.AtomicEquals,
+			obtained:    3,
+			extras:      []interface{}{3},
+			err:         "AtomicEquals checker expected a value with a Load method, obtained was int value 3",
+		}, {
+			description: "nil atomic",
+			checker:     checkers.AtomicEquals,
+			obtained:    (*atomic.Int64)(nil),
+			extras:      []interface{}{0},
+			err:         "AtomicEquals checker expected a value with a Load method, obtained was nil *atomic.Int64",
+		}, {
+			description: "nil",
+			checker:     checkers.AtomicEquals,
+			obtained:    nil,
+			extras:      []interface{}{0},
+			err:         "AtomicEquals checker expected a value with a Load method, obtained was nil",
+		}, {
+			description: "map has key",
+			checker:     checkers.SyncMapHasKey,
+			obtained:    &m,
+			extras:      []interface{}{"a"},
+		}, {
+			description: "map missing key",
+			checker:     checkers.SyncMapHasKey,
+			obtained:    &m,
+			extras:      []interface{}{"c"},
+			err:         `key "c" not found in sync.Map with keys ["a", "b"]`,
+		}, {
+			description: "not a map",
+			checker:     checkers.SyncMapHasKey,
+			obtained:    map[string]int{},
+			extras:      []interface{}{"c"},
+			err:         "SyncMapHasKey checker expected *sync.Map, obtained was map[string]int value map[string]int{}",
+		},
+	} {
+		err := test.checker.Check(test.obtained, test.extras...)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+		} else {
+			if test.err == "" {
+				t.Errorf("%s: unexpected error: %v", test.description, err)
+			} else {
+				if err.Error() != test.err {
+					t.Errorf("%s: error mismatch: \n\tobtained: %q\n\texpected: %q", test.description, err.Error(), test.err)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,67 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// executableTemplate is implemented by the templates of both
+// text/template and html/template.
+type executableTemplate interface {
+	Name() string
+	Execute(w io.Writer, data interface{}) error
+}
+
+type templateRenders struct {
+	data    interface{}
+	checker Checker
+}
+
+// TemplateRenders returns a checker that checks that the obtained
+// template, a parsed *template.Template of either text/template or
+// html/template, executes with data without error. If checker is not
+// nil, the output, a string, must also pass it, with any extra values
+// given to the returned checker passed on to it. A failure to execute
+// the template is reported separately from output that does not pass
+// the checker.
+//
+//	tmpl := template.Must(template.New("greeting").Parse("Hello {{.Name}}"))
+//	t.Check(tmpl, checkers.TemplateRenders(user, checkers.Equals), "Hello alice")
+//	t.Check(tmpl, checkers.TemplateRenders(user, checkers.Matches), "Hello .*")
+func TemplateRenders(data interface{}, checker Checker) Checker {
+	return templateRenders{data: data, checker: checker}
+}
+
+func (c templateRenders) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if c.checker == nil {
+		if err := unexpectedExtras("TemplateRenders", extras); err != nil {
+			return err
+		}
+	}
+	tmpl, ok := obtained.(executableTemplate)
+	if !ok || isTypedNil(obtained) {
+		return fmt.Errorf("TemplateRenders checker expected a *template.Template, obtained was %s", describe(obtained))
+	}
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, c.data); err != nil {
+		if out.Len() == 0 {
+			return failf("template %q failed to execute: %v", tmpl.Name(), err)
+		}
+		return failf("template %q failed to execute: %v\noutput before failing: %q", tmpl.Name(), err, out.String())
+	}
+	if c.checker == nil {
+		return nil
+	}
+	if err := c.checker.Check(out.String(), extras...); err != nil {
+		return lazyFailure(func() string {
+			return fmt.Sprintf("output of template %q: %v", tmpl.Name(), err)
+		})
+	}
+	return nil
+}
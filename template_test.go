@@ -0,0 +1,98 @@
+// Add a copyright
+// Add a licence
+
+package checkers_test
+
+import (
+	htmltemplate "html/template"
+	"testing"
+	"text/template"
+
+	"github.com/howbazaar/checkers"
+)
+
+func TestTemplateRenders(t *testing.T) {
+	greeting := template.Must(template.New("greeting").Parse("Hello {{.Name}}"))
+	page := htmltemplate.Must(htmltemplate.New("page").Parse("<p>{{.Name}}</p>"))
+	broken := template.Must(template.New("broken").Parse("Hello {{.Name}}, you have {{.Count.Missing}}"))
+	user := struct {
+		Name  string
+		Count int
+	}{"<alice>", 3}
+	for _, test := range []struct {
+		description string
+		checker     checkers.Checker
+		obtained    interface{}
+		extras      []interface{}
+		err         string
+	}{
+		{
+			description: "text",
+			checker:     checkers.TemplateRenders(user, checkers.Equals),
+			obtained:    greeting,
+			extras:      []interface{}{"Hello <alice>"},
+		}, {
+			description: "html",
+			checker:     checkers.TemplateRenders(user, checkers.Equals),
+			obtained:    page,
+			extras:      []interface{}{"<p>&lt;alice&gt;</p>"},
+		}, {
+			description: "matches",
+			checker:     checkers.TemplateRenders(user, checkers.Matches),
+			obtained:    greeting,
+			extras:      []interface{}{"Hello .*"},
+		}, {
+			description: "executes",
+			checker:     checkers.TemplateRenders(user, nil),
+			obtained:    page,
+		}, {
+			description: "output mismatch",
+			checker:     checkers.TemplateRenders(user, checkers.Matches),
+			obtained:    page,
+			extras:      []interface{}{"<p>alice</p>", checkers.Commentf("escaped")},
+			err:         `output of template "page": "<p>&lt;alice&gt;</p>" did not match pattern "^<p>alice</p>$"` + "\ncomment: escaped",
+		}, {
+			description: "execution fails",
+			checker:     checkers.TemplateRenders(user, checkers.Equals),
+			obtained:    broken,
+			extras:      []interface{}{"Hello <alice>, you have 3"},
+			err: `template "broken" failed to execute: template: broken:1:34: executing "broken" at <.Count.Missing>: can't evaluate field Missing in type int` + "\n" +
+				`output before failing: "Hello <alice>, you have "`,
+		}, {
+			description: "execution fails without output",
+			checker:     checkers.TemplateRenders(nil, nil),
+			obtained:    template.Must(template.New("empty").Parse("{{template \"missing\"}}")),
+			err:         `template "empty" failed to execute: template: empty:1:11: executing "empty" at <{{template "missing"}}>: template "missing" not defined`,
+		}, {
+			description: "extras without checker",
+			checker:     checkers.TemplateRenders(user, nil),
+			obtained:    greeting,
+			extras:      []interface{}{"Hello"},
+			err:         `too many arguments to checker TemplateRenders, unexpected string("Hello")`,
+		}, {
+			description: "not a template",
+			checker:     checkers.TemplateRenders(user, checkers.Equals),
+			obtained:    "Hello {{.Name}}",
+			extras:      []interface{}{"Hello"},
+			err:         "TemplateRenders checker expected a *template.Template, obtained was string value Hello {{.Name}}",
+		}, {
+			description: "nil template",
+			checker:     checkers.TemplateRenders(user, nil),
+			obtained:    (*template.Template)(nil),
+			err:         "TemplateRenders checker expected a *template.Template, obtained was nil *template.Template",
+		},
+	} {
+		err := test.checker.Check(test.obtained, test.extras...)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+		} else {
+			if test.err == "" {
+				t.Errorf("%s: unexpected error: %v", test.description, err)
+			} else if err.Error() != test.err {
+				t.Errorf("%s: error mismatch: \n\tobtained: %q\n\texpected: %q", test.description, err.Error(), test.err)
+			}
+		}
+	}
+}
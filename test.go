@@ -8,14 +8,32 @@ import (
 )
 
 // Test is a simple wrapper around a testing.T to add Assert and Check methods.
+//
+// To use the same methods in benchmarks and fuzz targets, or with other
+// implementations of testing.TB, set TB instead of T. The methods of Test
+// report to TB when it is set, while the methods promoted from T, such as
+// Run and Parallel, are only available when T is set. RunSuite and
+// RunSuiteTB set TB, and T where there is one, for suites that embed Test.
 type Test struct {
 	*testing.T
+	TB testing.TB
+}
+
+// testingTB returns the testing.TB that the methods of Test report to.
+func (t *Test) testingTB() testing.TB {
+	if t.TB != nil {
+		return t.TB
+	}
+	return t.T
 }
 
 // Check will mark the test as a failure if the checker fails. The test continues.
 func (t *Test) Check(obtained interface{}, checker Checker, extras ...interface{}) bool {
+	tb := t.testingTB()
+	tb.Helper()
+	countAssertion(tb)
 	if err := checker.Check(obtained, extras...); err != nil {
-		t.Error(err.Error())
+		tb.Error(redact(err.Error()))
 		return false
 	}
 	return true
@@ -23,7 +41,9 @@ func (t *Test) Check(obtained interface{}, checker Checker, extras ...interface{
 
 // Assert expects to succeed, and if not, causes the test to fail immediately.
 func (t *Test) Assert(obtained interface{}, checker Checker, extras ...interface{}) {
+	tb := t.testingTB()
+	tb.Helper()
 	if ok := t.Check(obtained, checker, extras...); !ok {
-		t.FailNow()
+		tb.FailNow()
 	}
 }
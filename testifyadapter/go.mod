@@ -0,0 +1,10 @@
+module github.com/howbazaar/checkers/testifyadapter
+
+go 1.14
+
+replace github.com/howbazaar/checkers => ../
+
+require (
+	github.com/howbazaar/checkers v0.0.0-00010101000000-000000000000
+	github.com/stretchr/testify v1.9.0
+)
@@ -0,0 +1,116 @@
+// Add a copyright
+// Add a licence
+
+// Package testifyadapter adapts assertion functions from
+// github.com/stretchr/testify so they can be used as checkers. Any
+// comments made with checkers.Commentf are added to the failures of the
+// adapted assertions. It is a separate module so that only code using
+// it depends on testify.
+//
+// In the other direction no adapter is needed: *checkers.Test satisfies
+// both assert.TestingT and require.TestingT, so it can be passed
+// directly to testify assertions.
+package testifyadapter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/howbazaar/checkers"
+)
+
+var (
+	_ assert.TestingT  = (*checkers.Test)(nil)
+	_ require.TestingT = (*checkers.Test)(nil)
+)
+
+// Comparison is the signature of testify assertions that compare an
+// expected and an actual value, such as assert.Equal, assert.NotEqual
+// and assert.EqualValues.
+type Comparison func(t assert.TestingT, expected, actual interface{}, msgAndArgs ...interface{}) bool
+
+// ValueAssertion is the signature of testify assertions about a single
+// value, such as assert.Nil, assert.NotNil and assert.Empty.
+type ValueAssertion func(t assert.TestingT, object interface{}, msgAndArgs ...interface{}) bool
+
+// FromComparison returns a Checker that runs the testify comparison,
+// with the obtained value as the actual value and the first extra value
+// as the expected value.
+//
+//	t.Assert(obtained, testifyadapter.FromComparison(assert.EqualValues), expected)
+func FromComparison(fn Comparison) checkers.Checker {
+	return comparison{fn}
+}
+
+type comparison struct {
+	fn Comparison
+}
+
+func (c comparison) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := checkers.SplitComments(extras)
+	defer checkers.AddComments(&err, comments)
+	if len(extras) != 1 {
+		return fmt.Errorf("expected 1 extra value, got %d", len(extras))
+	}
+	var r recorder
+	if c.fn(&r, extras[0], obtained) {
+		return nil
+	}
+	return r.err()
+}
+
+// FromValueAssertion returns a Checker that runs the testify assertion
+// on the obtained value.
+//
+//	t.Assert(obtained, testifyadapter.FromValueAssertion(assert.Empty))
+func FromValueAssertion(fn ValueAssertion) checkers.Checker {
+	return valueAssertion{fn}
+}
+
+type valueAssertion struct {
+	fn ValueAssertion
+}
+
+func (c valueAssertion) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := checkers.SplitComments(extras)
+	defer checkers.AddComments(&err, comments)
+	if len(extras) != 0 {
+		return fmt.Errorf("expected no extra values, got %d", len(extras))
+	}
+	var r recorder
+	if c.fn(&r, obtained) {
+		return nil
+	}
+	return r.err()
+}
+
+// recorder is an assert.TestingT that keeps the failure messages.
+type recorder struct {
+	messages []string
+}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.messages = append(r.messages, fmt.Sprintf(format, args...))
+}
+
+// err returns the recorded failures as an error. Testify formats its
+// failures as labelled, indented sections, starting with the location
+// of the failure. Only the text of the error section onwards is kept.
+func (r *recorder) err() error {
+	if len(r.messages) == 0 {
+		return errors.New("assertion failed")
+	}
+	message := strings.Join(r.messages, "\n")
+	if i := strings.Index(message, "Error:"); i >= 0 {
+		message = message[i+len("Error:"):]
+	}
+	lines := strings.Split(strings.TrimSpace(message), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimLeft(line, " \t")
+	}
+	return errors.New(strings.Join(lines, "\n"))
+}
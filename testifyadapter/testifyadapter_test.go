@@ -0,0 +1,66 @@
+// Add a copyright
+// Add a licence
+
+package testifyadapter_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/howbazaar/checkers"
+	"github.com/howbazaar/checkers/testifyadapter"
+)
+
+func TestFromComparison(t *testing.T) {
+	equal := testifyadapter.FromComparison(assert.Equal)
+	if err := equal.Check(42, 42); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	err := equal.Check(42, 43)
+	want := "Not equal: \nexpected: 43\nactual  : 42"
+	if err == nil || err.Error() != want {
+		t.Errorf("unexpected error: %q, want %q", err, want)
+	}
+	err = equal.Check(42)
+	if err == nil || err.Error() != "expected 1 extra value, got 0" {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := equal.Check(42, 42, checkers.Commentf("answer")); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	err = equal.Check(42, 43, checkers.Commentf("answer"))
+	want = "Not equal: \nexpected: 43\nactual  : 42\ncomment: answer"
+	if err == nil || err.Error() != want {
+		t.Errorf("unexpected error: %q, want %q", err, want)
+	}
+}
+
+func TestFromValueAssertion(t *testing.T) {
+	empty := testifyadapter.FromValueAssertion(assert.Empty)
+	if err := empty.Check([]int{}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	err := empty.Check([]int{1})
+	want := "Should be empty, but was [1]"
+	if err == nil || err.Error() != want {
+		t.Errorf("unexpected error: %q, want %q", err, want)
+	}
+	err = empty.Check([]int{1}, 2)
+	if err == nil || err.Error() != "expected no extra values, got 1" {
+		t.Errorf("unexpected error: %v", err)
+	}
+	err = empty.Check([]int{1}, checkers.Commentf("queue"))
+	want = "Should be empty, but was [1]\ncomment: queue"
+	if err == nil || err.Error() != want {
+		t.Errorf("unexpected error: %q, want %q", err, want)
+	}
+}
+
+func TestTestIsTestingT(t *testing.T) {
+	ct := &checkers.Test{T: t}
+	assert.Equal(ct, 42, 42)
+	require.NotNil(ct, ct)
+	ct.Assert(1, testifyadapter.FromComparison(assert.EqualValues), int64(1))
+}
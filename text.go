@@ -0,0 +1,155 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type stringContainsAllOf struct{}
+
+// StringContainsAllOf checker checks that a string, or Stringer,
+// contains every one of the expected substrings, a []string, in any
+// order. A failure lists every substring that is missing.
+var StringContainsAllOf Checker = stringContainsAllOf{}
+
+func (stringContainsAllOf) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if len(extras) == 0 {
+		return errors.New("missing 'expected' value")
+	}
+	expected, extras := extras[0], extras[1:]
+	if err := unexpectedExtras("StringContainsAllOf", extras); err != nil {
+		return err
+	}
+	substrings, ok := expected.([]string)
+	if !ok {
+		return fmt.Errorf("expected value must be a []string, got %s", describeType(expected))
+	}
+	value, err := matchable(obtained)
+	if err != nil {
+		return err
+	}
+
+	var missing []string
+	for _, substring := range substrings {
+		if !strings.Contains(value, substring) {
+			missing = append(missing, fmt.Sprintf("%q", substring))
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	return failf("%q does not contain %d of %d substrings: %s",
+		value, len(missing), len(substrings), strings.Join(missing, ", "))
+}
+
+type linesEqualIgnoringSpace struct{}
+
+// LinesEqualIgnoringSpace checker checks that a string, or Stringer,
+// has the same lines as the expected string, ignoring the leading and
+// trailing white space of each line and any blank lines, so that
+// changes of indentation in generated text are not reported. A failure
+// shows a diff of the lines as they were compared.
+var LinesEqualIgnoringSpace Checker = linesEqualIgnoringSpace{}
+
+func (linesEqualIgnoringSpace) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if len(extras) == 0 {
+		return errors.New("missing 'expected' value")
+	}
+	expected, extras := extras[0], extras[1:]
+	if err := unexpectedExtras("LinesEqualIgnoringSpace", extras); err != nil {
+		return err
+	}
+	text, ok := expected.(string)
+	if !ok {
+		return fmt.Errorf("expected value must be a string, got %s", describeType(expected))
+	}
+	value, err := matchable(obtained)
+	if err != nil {
+		return err
+	}
+
+	obtainedLines := significantLines(value)
+	expectedLines := significantLines(text)
+	if obtainedLines == expectedLines {
+		return nil
+	}
+	return lazyFailure(func() string {
+		return "lines differ, ignoring white space:\ndiff (-obtained +expected):\n" +
+			lineDiff(obtainedLines, expectedLines)
+	})
+}
+
+// significantLines returns the lines of the text that are not blank,
+// with their leading and trailing white space removed.
+func significantLines(text string) string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// TextOption is an extra value understood by the TrimmedEquals checker.
+type TextOption struct {
+	collapseSpace bool
+}
+
+// CollapseSpace returns a TextOption that has each run of white space
+// within the strings compared as a single space.
+func CollapseSpace() TextOption {
+	return TextOption{collapseSpace: true}
+}
+
+type trimmedEquals struct{}
+
+// TrimmedEquals checker checks that a string, or Stringer, equals the
+// expected string once leading and trailing white space is removed from
+// both. A CollapseSpace option may follow the expected value.
+//
+//	t.Check(stdout, checkers.TrimmedEquals, "total: 3 files", checkers.CollapseSpace())
+var TrimmedEquals Checker = trimmedEquals{}
+
+func (trimmedEquals) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if len(extras) == 0 {
+		return errors.New("missing 'expected' value")
+	}
+	expected, extras := extras[0], extras[1:]
+	var option TextOption
+	for i, extra := range extras {
+		o, ok := extra.(TextOption)
+		if !ok {
+			return unexpectedExtras("TrimmedEquals", extras[i:])
+		}
+		option.collapseSpace = option.collapseSpace || o.collapseSpace
+	}
+	text, ok := expected.(string)
+	if !ok {
+		return fmt.Errorf("expected value must be a string, got %s", describeType(expected))
+	}
+	value, err := matchable(obtained)
+	if err != nil {
+		return err
+	}
+
+	value, text = strings.TrimSpace(value), strings.TrimSpace(text)
+	if option.collapseSpace {
+		value = strings.Join(strings.Fields(value), " ")
+		text = strings.Join(strings.Fields(text), " ")
+	}
+	if value == text {
+		return nil
+	}
+	return contentMismatch([]byte(value), []byte(text))
+}
@@ -0,0 +1,173 @@
+// Add a copyright
+// Add a licence
+
+package checkers_test
+
+import (
+	"testing"
+
+	"github.com/howbazaar/checkers"
+)
+
+func TestTextCheckers(t *testing.T) {
+	for _, test := range []struct {
+		description string
+		checker     checkers.Checker
+		obtained    interface{}
+		extras      []interface{}
+		err         string
+	}{
+		{
+			description: "contains all",
+			checker:     checkers.StringContainsAllOf,
+			obtained:    "func main() { fmt.Println() }",
+			extras:      []interface{}{[]string{"fmt.Println", "func main"}},
+		}, {
+			description: "contains all of none",
+			checker:     checkers.StringContainsAllOf,
+			obtained:    "anything",
+			extras:      []interface{}{[]string{}},
+		}, {
+			description: "contains all of stringer",
+			checker:     checkers.StringContainsAllOf,
+			obtained:    aStringer{"hello world"},
+			extras:      []interface{}{[]string{"world", "hello"}},
+		}, {
+			description: "missing substrings",
+			checker:     checkers.StringContainsAllOf,
+			obtained:    "hello world",
+			extras:      []interface{}{[]string{"hello", "there", "moon"}},
+			err:         `"hello world" does not contain 2 of 3 substrings: "there", "moon"`,
+		}, {
+			description: "contains all not a string",
+			checker:     checkers.StringContainsAllOf,
+			obtained:    42,
+			extras:      []interface{}{[]string{"4"}},
+			err:         "int(42) is neither a string nor has a 'String() string' method",
+		}, {
+			description: "contains all bad expected",
+			checker:     checkers.StringContainsAllOf,
+			obtained:    "hello",
+			extras:      []interface{}{"hello"},
+			err:         "expected value must be a []string, got type string",
+		}, {
+			description: "contains all missing expected",
+			checker:     checkers.StringContainsAllOf,
+			obtained:    "hello",
+			err:         "missing 'expected' value",
+		}, {
+			description: "contains all too many arguments",
+			checker:     checkers.StringContainsAllOf,
+			obtained:    "hello",
+			extras:      []interface{}{[]string{"h"}, "e"},
+			err:         `too many arguments to checker StringContainsAllOf, unexpected string("e")`,
+		}, {
+			description: "lines equal",
+			checker:     checkers.LinesEqualIgnoringSpace,
+			obtained:    "func f() {\n\treturn 1\n}\n",
+			extras:      []interface{}{"\nfunc f() {\n    return 1  \n\n}"},
+		}, {
+			description: "lines equal with windows line endings",
+			checker:     checkers.LinesEqualIgnoringSpace,
+			obtained:    "a\r\nb\r\n",
+			extras:      []interface{}{"a\nb"},
+		}, {
+			description: "lines differ",
+			checker:     checkers.LinesEqualIgnoringSpace,
+			obtained:    "func f() {\n\treturn 1\n}\n",
+			extras:      []interface{}{"func f() {\n  return 2\n}"},
+			err: "lines differ, ignoring white space:\n" +
+				"diff (-obtained +expected):\n" +
+				" func f() {\n" +
+				"-return 1\n" +
+				"+return 2\n" +
+				" }",
+		}, {
+			description: "space within lines is significant",
+			checker:     checkers.LinesEqualIgnoringSpace,
+			obtained:    "a  b",
+			extras:      []interface{}{"a b"},
+			err: "lines differ, ignoring white space:\n" +
+				"diff (-obtained +expected):\n" +
+				"-a  b\n" +
+				"+a b",
+		}, {
+			description: "lines bad expected",
+			checker:     checkers.LinesEqualIgnoringSpace,
+			obtained:    "a",
+			extras:      []interface{}{[]string{"a"}},
+			err:         "expected value must be a string, got type []string",
+		}, {
+			description: "lines not a string",
+			checker:     checkers.LinesEqualIgnoringSpace,
+			obtained:    nil,
+			extras:      []interface{}{"a"},
+			err:         "nil is neither a string nor has a 'String() string' method",
+		}, {
+			description: "trimmed equals",
+			checker:     checkers.TrimmedEquals,
+			obtained:    "  done\n",
+			extras:      []interface{}{"\tdone"},
+		}, {
+			description: "trimmed equals keeps inner space",
+			checker:     checkers.TrimmedEquals,
+			obtained:    "a  b\n",
+			extras:      []interface{}{"a b"},
+			err: `expected content "a b", got "a  b"` + "\n" +
+				"strings differ at byte 2 (rune 2):\n" +
+				"\tobtained: \"a  b\"\n" +
+				"\texpected: \"a b\"\n" +
+				"\t             ^",
+		}, {
+			description: "trimmed equals collapsing space",
+			checker:     checkers.TrimmedEquals,
+			obtained:    "total:\t3   files\n",
+			extras:      []interface{}{"total: 3\nfiles", checkers.CollapseSpace()},
+		}, {
+			description: "trimmed equals collapsing space differs",
+			checker:     checkers.TrimmedEquals,
+			obtained:    "total:\t3   files\n",
+			extras:      []interface{}{"total: 4 files", checkers.CollapseSpace()},
+			err: `expected content "total: 4 files", got "total: 3 files"` + "\n" +
+				"strings differ at byte 7 (rune 7):\n" +
+				"\tobtained: \"total: 3 files\"\n" +
+				"\texpected: \"total: 4 files\"\n" +
+				"\t                  ^",
+		}, {
+			description: "trimmed equals multiline",
+			checker:     checkers.TrimmedEquals,
+			obtained:    "a\nb\n",
+			extras:      []interface{}{"a\nc"},
+			err: "expected content \"a\\nc\", got \"a\\nb\"\n" +
+				"diff (-obtained +expected):\n" +
+				" a\n" +
+				"-b\n" +
+				"+c\n" +
+				"strings differ at byte 2 (rune 2):\n" +
+				"\tobtained: \"a\\nb\"\n" +
+				"\texpected: \"a\\nc\"\n" +
+				"\t              ^",
+		}, {
+			description: "trimmed equals unexpected option",
+			checker:     checkers.TrimmedEquals,
+			obtained:    "a",
+			extras:      []interface{}{"a", checkers.ReadLimit(1)},
+			err:         "too many arguments to checker TrimmedEquals, unexpected checkers.ReaderOption(checkers.ReaderOption{limit:1})",
+		},
+	} {
+		err := test.checker.Check(test.obtained, test.extras...)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+		} else {
+			if test.err == "" {
+				t.Errorf("%s: unexpected error: %v", test.description, err)
+			} else {
+				if err.Error() != test.err {
+					t.Errorf("%s: error mismatch: \n\tobtained: %q\n\texpected: %q", test.description, err.Error(), test.err)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,222 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// obtainedTime returns the obtained value as a time.Time.
+func obtainedTime(checker string, obtained interface{}) (time.Time, error) {
+	switch t := obtained.(type) {
+	case time.Time:
+		return t, nil
+	case *time.Time:
+		if t != nil {
+			return *t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%s checker expected time.Time, obtained was %s", checker, describe(obtained))
+}
+
+// describeZone describes the location of the time along with its zone
+// and offset at that time.
+func describeZone(t time.Time) string {
+	name, offset := t.Zone()
+	return fmt.Sprintf("%s (%s, offset %s)", t.Location(), name, time.Duration(offset)*time.Second)
+}
+
+type timeInLocation struct{}
+
+// TimeInLocation checker checks the location of the obtained time.Time,
+// separately from the instant it holds. The expected value is either a
+// *time.Location, which the time's location must have the same name
+// and offset as, a string holding the name of the location, such as
+// "Europe/London", or an int holding the offset of the zone in seconds
+// east of UTC.
+//
+//	t.Check(created, checkers.TimeInLocation, "America/New_York")
+var TimeInLocation Checker = timeInLocation{}
+
+func (timeInLocation) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if len(extras) == 0 {
+		return errors.New("missing 'expected' value")
+	}
+	expected, extras := extras[0], extras[1:]
+	if err := unexpectedExtras("TimeInLocation", extras); err != nil {
+		return err
+	}
+	switch expected := expected.(type) {
+	case *time.Location:
+		if expected == nil {
+			return errors.New("expected location must not be nil")
+		}
+	case string, int:
+	default:
+		return fmt.Errorf("expected value must be a *time.Location, location name or offset, got %s", describeType(expected))
+	}
+	t, err := obtainedTime("TimeInLocation", obtained)
+	if err != nil {
+		return err
+	}
+
+	_, offset := t.Zone()
+	switch expected := expected.(type) {
+	case *time.Location:
+		_, expectedOffset := t.In(expected).Zone()
+		if t.Location().String() == expected.String() && offset == expectedOffset {
+			return nil
+		}
+		return failf("expected time in %s, got %s", describeZone(t.In(expected)), describeZone(t))
+	case string:
+		if t.Location().String() == expected {
+			return nil
+		}
+		return failf("expected time in location %q, got %s", expected, describeZone(t))
+	default:
+		if offset == expected {
+			return nil
+		}
+		return failf("expected zone offset %s, got %s",
+			time.Duration(expected.(int))*time.Second, describeZone(t))
+	}
+}
+
+type timeIsUTC struct{}
+
+// TimeIsUTC checker checks that the obtained time.Time is in UTC.
+var TimeIsUTC Checker = timeIsUTC{}
+
+func (timeIsUTC) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if err := unexpectedExtras("TimeIsUTC", extras); err != nil {
+		return err
+	}
+	t, err := obtainedTime("TimeIsUTC", obtained)
+	if err != nil {
+		return err
+	}
+	if t.Location() == time.UTC {
+		return nil
+	}
+	return failf("expected time in UTC, got %s", describeZone(t))
+}
+
+type timeEquals struct{}
+
+// TimeEquals checker checks that the obtained time.Time is the same
+// instant as the expected one, as time.Time.Equal does, whatever their
+// locations and monotonic clock readings, which make times that are
+// equal fail DeepEquals. An optional time.Duration after the expected
+// time is a tolerance, the largest difference allowed between them.
+//
+//	t.Check(user.Created, checkers.TimeEquals, want.Created)
+//	t.Check(event.At, checkers.TimeEquals, time.Now(), time.Second)
+var TimeEquals Checker = timeEquals{}
+
+func (timeEquals) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if len(extras) == 0 {
+		return errors.New("missing 'expected' value")
+	}
+	expected, extras := extras[0], extras[1:]
+	var tolerance time.Duration
+	if len(extras) > 0 {
+		d, ok := extras[0].(time.Duration)
+		if !ok || d < 0 {
+			return fmt.Errorf("tolerance must be a non-negative time.Duration, got %s", describe(extras[0]))
+		}
+		tolerance, extras = d, extras[1:]
+	}
+	if err := unexpectedExtras("TimeEquals", extras); err != nil {
+		return err
+	}
+	want, ok := expected.(time.Time)
+	if !ok {
+		return fmt.Errorf("expected value must be a time.Time, got %s", describeType(expected))
+	}
+	t, err := obtainedTime("TimeEquals", obtained)
+	if err != nil {
+		return err
+	}
+	diff := t.Sub(want)
+	if diff >= -tolerance && diff <= tolerance {
+		return nil
+	}
+	// The monotonic clock readings are not shown, as they play no part
+	// in the comparison.
+	if tolerance == 0 {
+		return failf("obtained time %v is not equal to expected %v, differing by %s", t.Round(0), want.Round(0), timesApart(diff))
+	}
+	return failf("obtained time %v is not within %v of expected %v, differing by %s", t.Round(0), tolerance, want.Round(0), timesApart(diff))
+}
+
+// timesApart describes how far apart two times are from the difference
+// between them, which time.Time.Sub caps at the largest and smallest
+// durations, so that it must not be negated.
+func timesApart(diff time.Duration) string {
+	switch diff {
+	case math.MinInt64, math.MaxInt64:
+		return fmt.Sprintf("more than %v", time.Duration(math.MaxInt64))
+	}
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff.String()
+}
+
+type timeOrder struct {
+	name   string
+	before bool
+}
+
+// Before checker checks that the obtained time.Time is strictly before
+// the expected one. A failure shows both times and how far the obtained
+// time is after the expected one.
+//
+//	t.Check(token.IssuedAt, checkers.Before, token.ExpiresAt)
+var Before Checker = timeOrder{"Before", true}
+
+// After checker checks that the obtained time.Time is strictly after
+// the expected one, as Before checks that it is before.
+var After Checker = timeOrder{"After", false}
+
+func (c timeOrder) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if len(extras) == 0 {
+		return errors.New("missing 'expected' value")
+	}
+	expected, extras := extras[0], extras[1:]
+	if err := unexpectedExtras(c.name, extras); err != nil {
+		return err
+	}
+	want, ok := expected.(time.Time)
+	if !ok {
+		return fmt.Errorf("expected value must be a time.Time, got %s", describeType(expected))
+	}
+	t, err := obtainedTime(c.name, obtained)
+	if err != nil {
+		return err
+	}
+	relation, opposite := "before", "after"
+	if !c.before {
+		relation, opposite = opposite, relation
+	}
+	diff := t.Sub(want)
+	switch {
+	case c.before && diff < 0, !c.before && diff > 0:
+		return nil
+	case diff == 0:
+		return failf("obtained time %v is not %s %v, being the same instant", t.Round(0), relation, want.Round(0))
+	}
+	return failf("obtained time %v is not %s %v, being %s %s it", t.Round(0), relation, want.Round(0), timesApart(diff), opposite)
+}
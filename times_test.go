@@ -0,0 +1,292 @@
+// Add a copyright
+// Add a licence
+
+package checkers_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/howbazaar/checkers"
+)
+
+func TestTimeLocationCheckers(t *testing.T) {
+	est := time.FixedZone("EST", -5*60*60)
+	utc := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	inEST := utc.In(est)
+	for _, test := range []struct {
+		description string
+		checker     checkers.Checker
+		obtained    interface{}
+		extras      []interface{}
+		err         string
+	}{
+		{
+			description: "location",
+			checker:     checkers.TimeInLocation,
+			obtained:    inEST,
+			extras:      []interface{}{est},
+		}, {
+			description: "location by name",
+			checker:     checkers.TimeInLocation,
+			obtained:    &inEST,
+			extras:      []interface{}{"EST"},
+		}, {
+			description: "offset",
+			checker:     checkers.TimeInLocation,
+			obtained:    inEST,
+			extras:      []interface{}{-5 * 60 * 60},
+		}, {
+			description: "same instant wrong location",
+			checker:     checkers.TimeInLocation,
+			obtained:    utc,
+			extras:      []interface{}{est},
+			err:         "expected time in EST (EST, offset -5h0m0s), got UTC (UTC, offset 0s)",
+		}, {
+			description: "same name wrong offset",
+			checker:     checkers.TimeInLocation,
+			obtained:    inEST,
+			extras:      []interface{}{time.FixedZone("EST", 0)},
+			err:         "expected time in EST (EST, offset 0s), got EST (EST, offset -5h0m0s)",
+		}, {
+			description: "wrong name",
+			checker:     checkers.TimeInLocation,
+			obtained:    inEST,
+			extras:      []interface{}{"UTC"},
+			err:         `expected time in location "UTC", got EST (EST, offset -5h0m0s)`,
+		}, {
+			description: "wrong offset",
+			checker:     checkers.TimeInLocation,
+			obtained:    utc,
+			extras:      []interface{}{3600},
+			err:         "expected zone offset 1h0m0s, got UTC (UTC, offset 0s)",
+		}, {
+			description: "bad expected",
+			checker:     checkers.TimeInLocation,
+			obtained:    utc,
+			extras:      []interface{}{time.Hour},
+			err:         "expected value must be a *time.Location, location name or offset, got type time.Duration",
+		}, {
+			description: "nil location",
+			checker:     checkers.TimeInLocation,
+			obtained:    utc,
+			extras:      []interface{}{(*time.Location)(nil)},
+			err:         "expected location must not be nil",
+		}, {
+			description: "not a time",
+			checker:     checkers.TimeInLocation,
+			obtained:    "2024-03-01",
+			extras:      []interface{}{"UTC"},
+			err:         "TimeInLocation checker expected time.Time, obtained was string value 2024-03-01",
+		}, {
+			description: "utc",
+			checker:     checkers.TimeIsUTC,
+			obtained:    utc,
+		}, {
+			description: "zero time is utc",
+			checker:     checkers.TimeIsUTC,
+			obtained:    time.Time{},
+		}, {
+			description: "not utc",
+			checker:     checkers.TimeIsUTC,
+			obtained:    inEST,
+			err:         "expected time in UTC, got EST (EST, offset -5h0m0s)",
+		}, {
+			description: "zero offset is not utc",
+			checker:     checkers.TimeIsUTC,
+			obtained:    utc.In(time.FixedZone("GMT", 0)),
+			err:         "expected time in UTC, got GMT (GMT, offset 0s)",
+		}, {
+			description: "nil time",
+			checker:     checkers.TimeIsUTC,
+			obtained:    (*time.Time)(nil),
+			err:         "TimeIsUTC checker expected time.Time, obtained was nil *time.Time",
+		},
+	} {
+		err := test.checker.Check(test.obtained, test.extras...)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+		} else {
+			if test.err == "" {
+				t.Errorf("%s: unexpected error: %v", test.description, err)
+			} else {
+				if err.Error() != test.err {
+					t.Errorf("%s: error mismatch: \n\tobtained: %q\n\texpected: %q", test.description, err.Error(), test.err)
+				}
+			}
+		}
+	}
+}
+
+func TestTimeEquals(t *testing.T) {
+	est := time.FixedZone("EST", -5*60*60)
+	utc := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	now := time.Now()
+	for _, test := range []struct {
+		description string
+		obtained    interface{}
+		extras      []interface{}
+		err         string
+	}{
+		{
+			description: "other location",
+			obtained:    utc.In(est),
+			extras:      []interface{}{utc},
+		}, {
+			description: "monotonic clock reading",
+			obtained:    now,
+			extras:      []interface{}{now.Round(0)},
+		}, {
+			description: "pointer",
+			obtained:    &utc,
+			extras:      []interface{}{utc},
+		}, {
+			description: "not equal",
+			obtained:    utc.Add(1500 * time.Millisecond),
+			extras:      []interface{}{utc.In(est)},
+			err:         "obtained time 2024-03-01 12:00:01.5 +0000 UTC is not equal to expected 2024-03-01 07:00:00 -0500 EST, differing by 1.5s",
+		}, {
+			description: "within tolerance",
+			obtained:    utc.Add(-time.Second),
+			extras:      []interface{}{utc, time.Second},
+		}, {
+			description: "beyond tolerance",
+			obtained:    utc.Add(-2 * time.Second),
+			extras:      []interface{}{utc, time.Second, checkers.Commentf("created")},
+			err:         "obtained time 2024-03-01 11:59:58 +0000 UTC is not within 1s of expected 2024-03-01 12:00:00 +0000 UTC, differing by 2s\ncomment: created",
+		}, {
+			description: "far apart",
+			obtained:    time.Time{},
+			extras:      []interface{}{utc},
+			err:         "obtained time 0001-01-01 00:00:00 +0000 UTC is not equal to expected 2024-03-01 12:00:00 +0000 UTC, differing by more than 2562047h47m16.854775807s",
+		}, {
+			description: "far apart beyond tolerance",
+			obtained:    utc,
+			extras:      []interface{}{time.Time{}, time.Hour},
+			err:         "obtained time 2024-03-01 12:00:00 +0000 UTC is not within 1h0m0s of expected 0001-01-01 00:00:00 +0000 UTC, differing by more than 2562047h47m16.854775807s",
+		}, {
+			description: "negative tolerance",
+			obtained:    utc,
+			extras:      []interface{}{utc, -time.Second},
+			err:         "tolerance must be a non-negative time.Duration, got time.Duration value -1s",
+		}, {
+			description: "tolerance not a duration",
+			obtained:    utc,
+			extras:      []interface{}{utc, 1},
+			err:         "tolerance must be a non-negative time.Duration, got int value 1",
+		}, {
+			description: "expected not a time",
+			obtained:    utc,
+			extras:      []interface{}{"2024-03-01"},
+			err:         "expected value must be a time.Time, got type string",
+		}, {
+			description: "obtained not a time",
+			obtained:    "2024-03-01",
+			extras:      []interface{}{utc},
+			err:         `TimeEquals checker expected time.Time, obtained was string value 2024-03-01`,
+		}, {
+			description: "missing expected",
+			obtained:    utc,
+			err:         "missing 'expected' value",
+		},
+	} {
+		err := checkers.TimeEquals.Check(test.obtained, test.extras...)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+		} else {
+			if test.err == "" {
+				t.Errorf("%s: unexpected error: %v", test.description, err)
+			} else if err.Error() != test.err {
+				t.Errorf("%s: error mismatch: \n\tobtained: %q\n\texpected: %q", test.description, err.Error(), test.err)
+			}
+		}
+	}
+}
+
+func TestTimeOrder(t *testing.T) {
+	est := time.FixedZone("EST", -5*60*60)
+	utc := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	for _, test := range []struct {
+		description string
+		checker     checkers.Checker
+		obtained    interface{}
+		extras      []interface{}
+		err         string
+	}{
+		{
+			description: "before",
+			checker:     checkers.Before,
+			obtained:    utc.Add(-time.Nanosecond),
+			extras:      []interface{}{utc},
+		}, {
+			description: "before in another location",
+			checker:     checkers.Before,
+			obtained:    &utc,
+			extras:      []interface{}{utc.Add(time.Hour).In(est)},
+		}, {
+			description: "not before",
+			checker:     checkers.Before,
+			obtained:    utc.Add(90 * time.Second),
+			extras:      []interface{}{utc, checkers.Commentf("issued")},
+			err:         "obtained time 2024-03-01 12:01:30 +0000 UTC is not before 2024-03-01 12:00:00 +0000 UTC, being 1m30s after it\ncomment: issued",
+		}, {
+			description: "same instant not before",
+			checker:     checkers.Before,
+			obtained:    utc.In(est),
+			extras:      []interface{}{utc},
+			err:         "obtained time 2024-03-01 07:00:00 -0500 EST is not before 2024-03-01 12:00:00 +0000 UTC, being the same instant",
+		}, {
+			description: "after",
+			checker:     checkers.After,
+			obtained:    time.Now(),
+			extras:      []interface{}{utc},
+		}, {
+			description: "not after",
+			checker:     checkers.After,
+			obtained:    utc,
+			extras:      []interface{}{utc.Add(time.Millisecond)},
+			err:         "obtained time 2024-03-01 12:00:00 +0000 UTC is not after 2024-03-01 12:00:00.001 +0000 UTC, being 1ms before it",
+		}, {
+			description: "same instant not after",
+			checker:     checkers.After,
+			obtained:    utc,
+			extras:      []interface{}{utc},
+			err:         "obtained time 2024-03-01 12:00:00 +0000 UTC is not after 2024-03-01 12:00:00 +0000 UTC, being the same instant",
+		}, {
+			description: "far apart not after",
+			checker:     checkers.After,
+			obtained:    time.Time{},
+			extras:      []interface{}{utc},
+			err:         "obtained time 0001-01-01 00:00:00 +0000 UTC is not after 2024-03-01 12:00:00 +0000 UTC, being more than 2562047h47m16.854775807s before it",
+		}, {
+			description: "expected not a time",
+			checker:     checkers.After,
+			obtained:    utc,
+			extras:      []interface{}{0},
+			err:         "expected value must be a time.Time, got type int",
+		}, {
+			description: "obtained not a time",
+			checker:     checkers.Before,
+			obtained:    nil,
+			extras:      []interface{}{utc},
+			err:         "Before checker expected time.Time, obtained was nil",
+		},
+	} {
+		err := test.checker.Check(test.obtained, test.extras...)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+		} else {
+			if test.err == "" {
+				t.Errorf("%s: unexpected error: %v", test.description, err)
+			} else if err.Error() != test.err {
+				t.Errorf("%s: error mismatch: \n\tobtained: %q\n\texpected: %q", test.description, err.Error(), test.err)
+			}
+		}
+	}
+}
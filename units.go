@@ -0,0 +1,226 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type durationEquals struct{}
+
+// DurationEquals checker checks that the obtained duration equals the
+// expected one, where either may be a time.Duration or a string in the
+// notation understood by time.ParseDuration, such as "1h30m". Strings
+// are compared by the durations they stand for, so "90m" equals
+// "1h30m".
+//
+//	t.Check(config.Timeout, checkers.DurationEquals, "1h30m")
+var DurationEquals Checker = durationEquals{}
+
+func (durationEquals) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if len(extras) == 0 {
+		return errors.New("missing 'expected' value")
+	}
+	expected, extras := extras[0], extras[1:]
+	if err := unexpectedExtras("DurationEquals", extras); err != nil {
+		return err
+	}
+	want, err := durationOf(expected)
+	if err != nil {
+		return fmt.Errorf("expected value %v", err)
+	}
+	got, err := durationOf(obtained)
+	if err != nil {
+		return fmt.Errorf("obtained value %v", err)
+	}
+	if got == want {
+		return nil
+	}
+	return failf("expected duration %s, got %s", renderedAs(expected, want), renderedAs(obtained, got))
+}
+
+// durationOf returns the duration held or described by the value. Values
+// of types defined as time.Duration, or as int64, are taken to hold
+// durations too, while plain integers are not, as their units are
+// unknown.
+func durationOf(value interface{}) (time.Duration, error) {
+	switch value := value.(type) {
+	case time.Duration:
+		return value, nil
+	case string:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return 0, fmt.Errorf("%q is not a duration", value)
+		}
+		return d, nil
+	}
+	if v := reflect.ValueOf(value); v.Kind() == reflect.Int64 && v.Type().PkgPath() != "" {
+		return time.Duration(v.Int()), nil
+	}
+	return 0, fmt.Errorf("must be a time.Duration or string, got %s", describeType(value))
+}
+
+type durationBound struct {
+	name string
+	less bool
+}
+
+// DurationLessThan checker checks that the obtained duration is less
+// than the expected one, where either may be a time.Duration, a value
+// of a type defined as one, or a string as for DurationEquals. It suits
+// checks of latency:
+//
+//	t.Check(time.Since(start), checkers.DurationLessThan, "200ms")
+var DurationLessThan Checker = durationBound{"DurationLessThan", true}
+
+// DurationGreaterThan checker checks that the obtained duration is
+// greater than the expected one, given as for DurationLessThan.
+var DurationGreaterThan Checker = durationBound{"DurationGreaterThan", false}
+
+func (c durationBound) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if len(extras) == 0 {
+		return errors.New("missing 'expected' value")
+	}
+	expected, extras := extras[0], extras[1:]
+	if err := unexpectedExtras(c.name, extras); err != nil {
+		return err
+	}
+	bound, err := durationOf(expected)
+	if err != nil {
+		return fmt.Errorf("expected value %v", err)
+	}
+	got, err := durationOf(obtained)
+	if err != nil {
+		return fmt.Errorf("obtained value %v", err)
+	}
+	relation := "greater than"
+	if c.less {
+		relation = "less than"
+	}
+	switch {
+	case c.less && got < bound, !c.less && got > bound:
+		return nil
+	case got == bound:
+		return failf("obtained duration %s is not %s %s, being equal to it", renderedAs(obtained, got), relation, renderedAs(expected, bound))
+	case c.less:
+		return failf("obtained duration %s is not %s %s, exceeding it by %v", renderedAs(obtained, got), relation, renderedAs(expected, bound), got-bound)
+	}
+	return failf("obtained duration %s is not %s %s, falling short of it by %v", renderedAs(obtained, got), relation, renderedAs(expected, bound), bound-got)
+}
+
+// sizeUnits holds the multiplier of each unit understood by SizeEquals.
+var sizeUnits = map[string]float64{
+	"":    1,
+	"B":   1,
+	"KB":  1e3,
+	"MB":  1e6,
+	"GB":  1e9,
+	"TB":  1e12,
+	"PB":  1e15,
+	"KiB": 1 << 10,
+	"MiB": 1 << 20,
+	"GiB": 1 << 30,
+	"TiB": 1 << 40,
+	"PiB": 1 << 50,
+}
+
+type sizeEquals struct{}
+
+// SizeEquals checker checks that the obtained size in bytes equals the
+// expected one, where either may be an integer or a string holding a
+// number with an optional unit, such as "2.5MiB", "10 KB" or "512B".
+// Decimal units (KB, MB, ...) are powers of 1000 and binary units (KiB,
+// MiB, ...) powers of 1024.
+//
+//	t.Check(config.MaxUpload, checkers.SizeEquals, "2.5MiB")
+var SizeEquals Checker = sizeEquals{}
+
+func (sizeEquals) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if len(extras) == 0 {
+		return errors.New("missing 'expected' value")
+	}
+	expected, extras := extras[0], extras[1:]
+	if err := unexpectedExtras("SizeEquals", extras); err != nil {
+		return err
+	}
+	want, err := sizeOf(expected)
+	if err != nil {
+		return fmt.Errorf("expected value %v", err)
+	}
+	got, err := sizeOf(obtained)
+	if err != nil {
+		return fmt.Errorf("obtained value %v", err)
+	}
+	if got == want {
+		return nil
+	}
+	return failf("expected size %s, got %s", renderedAs(expected, want), renderedAs(obtained, got))
+}
+
+// sizeOf returns the number of bytes held or described by the value.
+func sizeOf(value interface{}) (int64, error) {
+	if s, ok := value.(string); ok {
+		return parseSize(s)
+	}
+	v := reflect.ValueOf(value)
+	switch kindFamily(v.Kind()) {
+	case reflect.Int:
+		return v.Int(), nil
+	case reflect.Uint:
+		if v.Uint() > math.MaxInt64 {
+			return 0, fmt.Errorf("%d is too large a size", v.Uint())
+		}
+		return int64(v.Uint()), nil
+	}
+	return 0, fmt.Errorf("must be an integer or string, got %s", describeType(value))
+}
+
+// parseSize parses a number with an optional unit as a number of bytes.
+func parseSize(s string) (int64, error) {
+	trimmed := strings.TrimSpace(s)
+	i := strings.IndexFunc(trimmed, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if i < 0 {
+		i = len(trimmed)
+	}
+	number, unit := trimmed[:i], strings.TrimSpace(trimmed[i:])
+	multiplier, ok := sizeUnits[unit]
+	if !ok {
+		return 0, fmt.Errorf("%q has unknown size unit %q", s, unit)
+	}
+	n, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a size", s)
+	}
+	size := n * multiplier
+	if size != math.Trunc(size) {
+		return 0, fmt.Errorf("%q is not a whole number of bytes", s)
+	}
+	if size >= math.MaxInt64 {
+		return 0, fmt.Errorf("%q is too large a size", s)
+	}
+	return int64(size), nil
+}
+
+// renderedAs describes a value along with the string it was parsed
+// from, if any.
+func renderedAs(value, parsed interface{}) string {
+	if s, ok := value.(string); ok {
+		return fmt.Sprintf("%v (%q)", parsed, s)
+	}
+	return fmt.Sprint(parsed)
+}
@@ -0,0 +1,174 @@
+// Add a copyright
+// Add a licence
+
+package checkers_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/howbazaar/checkers"
+)
+
+// timeout is a type defined as a time.Duration.
+type timeout time.Duration
+
+func TestUnitCheckers(t *testing.T) {
+	for _, test := range []struct {
+		description string
+		checker     checkers.Checker
+		obtained    interface{}
+		extras      []interface{}
+		err         string
+	}{
+		{
+			description: "duration",
+			checker:     checkers.DurationEquals,
+			obtained:    90 * time.Minute,
+			extras:      []interface{}{"1h30m"},
+		}, {
+			description: "duration strings",
+			checker:     checkers.DurationEquals,
+			obtained:    "90m",
+			extras:      []interface{}{"1h30m"},
+		}, {
+			description: "durations",
+			checker:     checkers.DurationEquals,
+			obtained:    time.Second,
+			extras:      []interface{}{1000 * time.Millisecond},
+		}, {
+			description: "duration mismatch",
+			checker:     checkers.DurationEquals,
+			obtained:    time.Hour,
+			extras:      []interface{}{"1h30m"},
+			err:         `expected duration 1h30m0s ("1h30m"), got 1h0m0s`,
+		}, {
+			description: "invalid duration",
+			checker:     checkers.DurationEquals,
+			obtained:    time.Hour,
+			extras:      []interface{}{"an hour"},
+			err:         `expected value "an hour" is not a duration`,
+		}, {
+			description: "duration of wrong type",
+			checker:     checkers.DurationEquals,
+			obtained:    3600,
+			extras:      []interface{}{"1h"},
+			err:         "obtained value must be a time.Duration or string, got type int",
+		}, {
+			description: "duration of defined type",
+			checker:     checkers.DurationEquals,
+			obtained:    timeout(time.Hour),
+			extras:      []interface{}{"1h"},
+		}, {
+			description: "duration less than",
+			checker:     checkers.DurationLessThan,
+			obtained:    150 * time.Millisecond,
+			extras:      []interface{}{"200ms"},
+		}, {
+			description: "duration not less than",
+			checker:     checkers.DurationLessThan,
+			obtained:    timeout(1500 * time.Millisecond),
+			extras:      []interface{}{time.Second, checkers.Commentf("latency")},
+			err:         "obtained duration 1.5s is not less than 1s, exceeding it by 500ms\ncomment: latency",
+		}, {
+			description: "equal duration not less than",
+			checker:     checkers.DurationLessThan,
+			obtained:    "1m",
+			extras:      []interface{}{"60s"},
+			err:         `obtained duration 1m0s ("1m") is not less than 1m0s ("60s"), being equal to it`,
+		}, {
+			description: "duration greater than",
+			checker:     checkers.DurationGreaterThan,
+			obtained:    time.Minute,
+			extras:      []interface{}{"59s"},
+		}, {
+			description: "duration not greater than",
+			checker:     checkers.DurationGreaterThan,
+			obtained:    2 * time.Millisecond,
+			extras:      []interface{}{"1h"},
+			err:         `obtained duration 2ms is not greater than 1h0m0s ("1h"), falling short of it by 59m59.998s`,
+		}, {
+			description: "duration bound of wrong type",
+			checker:     checkers.DurationGreaterThan,
+			obtained:    time.Second,
+			extras:      []interface{}{int64(1)},
+			err:         "expected value must be a time.Duration or string, got type int64",
+		}, {
+			description: "size",
+			checker:     checkers.SizeEquals,
+			obtained:    int64(2621440),
+			extras:      []interface{}{"2.5MiB"},
+		}, {
+			description: "size strings",
+			checker:     checkers.SizeEquals,
+			obtained:    "10 KB",
+			extras:      []interface{}{"10000B"},
+		}, {
+			description: "size without unit",
+			checker:     checkers.SizeEquals,
+			obtained:    uint32(512),
+			extras:      []interface{}{"512"},
+		}, {
+			description: "size mismatch",
+			checker:     checkers.SizeEquals,
+			obtained:    1000,
+			extras:      []interface{}{"1KiB"},
+			err:         `expected size 1024 ("1KiB"), got 1000`,
+		}, {
+			description: "unknown size unit",
+			checker:     checkers.SizeEquals,
+			obtained:    1000,
+			extras:      []interface{}{"1kb"},
+			err:         `expected value "1kb" has unknown size unit "kb"`,
+		}, {
+			description: "fractional bytes",
+			checker:     checkers.SizeEquals,
+			obtained:    1,
+			extras:      []interface{}{"1.5"},
+			err:         `expected value "1.5" is not a whole number of bytes`,
+		}, {
+			description: "largest size",
+			checker:     checkers.SizeEquals,
+			obtained:    "8388607TiB",
+			extras:      []interface{}{int64(8388607) << 40},
+		}, {
+			description: "size too large",
+			checker:     checkers.SizeEquals,
+			obtained:    1,
+			extras:      []interface{}{"8388608TiB"},
+			err:         `expected value "8388608TiB" is too large a size`,
+		}, {
+			description: "invalid size",
+			checker:     checkers.SizeEquals,
+			obtained:    "1.2.3MB",
+			extras:      []interface{}{1},
+			err:         `obtained value "1.2.3MB" is not a size`,
+		}, {
+			description: "size of wrong type",
+			checker:     checkers.SizeEquals,
+			obtained:    1.5,
+			extras:      []interface{}{"1"},
+			err:         "obtained value must be an integer or string, got type float64",
+		}, {
+			description: "missing expected",
+			checker:     checkers.SizeEquals,
+			obtained:    1,
+			err:         "missing 'expected' value",
+		},
+	} {
+		err := test.checker.Check(test.obtained, test.extras...)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+		} else {
+			if test.err == "" {
+				t.Errorf("%s: unexpected error: %v", test.description, err)
+			} else {
+				if err.Error() != test.err {
+					t.Errorf("%s: error mismatch: \n\tobtained: %q\n\texpected: %q", test.description, err.Error(), test.err)
+				}
+			}
+		}
+	}
+}
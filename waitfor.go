@@ -0,0 +1,127 @@
+// Add a copyright
+// Add a licence
+
+package checkers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// waitForPollInterval is how often WaitFor checks its condition.
+const waitForPollInterval = 10 * time.Millisecond
+
+// waitTimeout is how long Eventually and ChanReceives wait when they are
+// not given a context.
+const waitTimeout = 5 * time.Second
+
+// waitForObservations is how many of the last descriptions of the state
+// WaitFor includes in its failure.
+const waitForObservations = 5
+
+// WaitFor checks the condition repeatedly until it returns true or the
+// context is done. Alongside whether it is met, the condition returns a
+// description of the state it observed, and the last few of these are
+// included in the error returned when the context is done first, so
+// that a condition that is never met shows what was seen instead. The
+// Eventually checker uses it to retry other checkers.
+//
+//	err := checkers.WaitFor(ctx, func() (bool, string) {
+//		n := server.Connections()
+//		return n == 3, fmt.Sprintf("%d connections", n)
+//	})
+func WaitFor(ctx context.Context, condition func() (bool, string)) error {
+	ticker := time.NewTicker(waitForPollInterval)
+	defer ticker.Stop()
+
+	var observations []string
+	for poll := 1; ; poll++ {
+		ok, state := condition()
+		if ok {
+			return nil
+		}
+		observations = append(observations, fmt.Sprintf("poll %d: %s", poll, state))
+		if len(observations) > waitForObservations {
+			observations = observations[1:]
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("condition not met after %d polls: %v\nlast observations:\n\t%s",
+				poll, ctx.Err(), strings.Join(observations, "\n\t"))
+		case <-ticker.C:
+		}
+	}
+}
+
+type eventually struct {
+	checker Checker
+}
+
+// Eventually returns a checker that calls the obtained function, which
+// takes no arguments and returns one value, until the given checker
+// passes for the value it returns with the same extra values. The
+// checks are made with WaitFor, so a failure shows the last few values
+// returned as well as why the last of them failed.
+//
+//	t.Assert(queue.Len, checkers.Eventually(checkers.Equals), 0, t.ContextWithTimeout(time.Second))
+//
+// A context.Context among the extra values, such as one from
+// Test.ContextWithTimeout, sets how long to keep checking. Without one
+// the check fails after five seconds. As with Not, any error from the
+// given checker counts as it failing, including those for missing or
+// unexpected extra values, which are then only reported at the end.
+func Eventually(checker Checker) Checker {
+	return eventually{checker}
+}
+
+func (e eventually) Check(obtained interface{}, extras ...interface{}) (err error) {
+	extras, comments := splitComments(extras)
+	defer addComments(&err, comments)
+	if e.checker == nil {
+		return errors.New("Eventually needs a checker to retry, got nil")
+	}
+	extras, ctx := contextArg(extras)
+	f := reflect.ValueOf(obtained)
+	if f.Kind() != reflect.Func || f.Type().NumIn() != 0 || f.Type().NumOut() != 1 {
+		return fmt.Errorf("Eventually checker expected a function taking no arguments and returning one value, obtained was %s", describeType(obtained))
+	}
+	if f.IsNil() {
+		return errors.New("Eventually checker expected a function, obtained was nil")
+	}
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), waitTimeout)
+		defer cancel()
+	}
+
+	var last error
+	waitErr := WaitFor(ctx, func() (bool, string) {
+		value := f.Call(nil)[0].Interface()
+		if last = e.checker.Check(value, extras...); last == nil {
+			return true, ""
+		}
+		return false, describe(value)
+	})
+	if waitErr == nil {
+		return nil
+	}
+	return lazyFailure(func() string {
+		return last.Error() + "\n" + waitErr.Error()
+	})
+}
+
+// contextArg separates the first context.Context from the other extra
+// values, returning nil if there is none.
+func contextArg(extras []interface{}) ([]interface{}, context.Context) {
+	for i, extra := range extras {
+		if ctx, ok := extra.(context.Context); ok {
+			rest := append(append([]interface{}(nil), extras[:i]...), extras[i+1:]...)
+			return rest, ctx
+		}
+	}
+	return extras, nil
+}
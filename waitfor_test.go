@@ -0,0 +1,148 @@
+// Add a copyright
+// Add a licence
+
+package checkers_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/howbazaar/checkers"
+)
+
+func TestWaitFor(t *testing.T) {
+	polls := 0
+	err := checkers.WaitFor(context.Background(), func() (bool, string) {
+		polls++
+		return polls == 3, fmt.Sprintf("%d polls", polls)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if polls != 3 {
+		t.Errorf("condition polled %d times, expected 3", polls)
+	}
+}
+
+func TestWaitForTimeout(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	polls := 0
+	err := checkers.WaitFor(ctx, func() (bool, string) {
+		polls++
+		if polls == 7 {
+			cancel()
+		}
+		return false, fmt.Sprintf("%d connections", polls)
+	})
+	expected := "condition not met after 7 polls: context canceled\n" +
+		"last observations:\n" +
+		"\tpoll 3: 3 connections\n" +
+		"\tpoll 4: 4 connections\n" +
+		"\tpoll 5: 5 connections\n" +
+		"\tpoll 6: 6 connections\n" +
+		"\tpoll 7: 7 connections"
+	if err == nil {
+		t.Fatalf("expected error: %q", expected)
+	}
+	if err.Error() != expected {
+		t.Errorf("error mismatch: \n\tobtained: %q\n\texpected: %q", err.Error(), expected)
+	}
+}
+
+// countUp returns a function returning one more each time it is called.
+func countUp() func() int {
+	n := 0
+	return func() int {
+		n++
+		return n
+	}
+}
+
+func TestEventually(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, test := range []struct {
+		description string
+		checker     checkers.Checker
+		obtained    interface{}
+		extras      []interface{}
+		err         string
+	}{
+		{
+			description: "passes at once",
+			checker:     checkers.Eventually(checkers.Equals),
+			obtained:    func() string { return "ready" },
+			extras:      []interface{}{"ready"},
+		}, {
+			description: "passes after polls",
+			checker:     checkers.Eventually(checkers.Equals),
+			obtained:    countUp(),
+			extras:      []interface{}{3},
+		}, {
+			description: "passes with context",
+			checker:     checkers.Eventually(checkers.Equals),
+			obtained:    countUp(),
+			extras:      []interface{}{3, context.Background()},
+		}, {
+			description: "fails when context is done",
+			checker:     checkers.Eventually(checkers.Equals),
+			obtained:    countUp(),
+			extras:      []interface{}{3, cancelled},
+			err: "expected int value 3, got 1\n" +
+				"condition not met after 1 polls: context canceled\n" +
+				"last observations:\n" +
+				"\tpoll 1: int value 1",
+		}, {
+			description: "with comment",
+			checker:     checkers.Eventually(checkers.IsTrue),
+			obtained:    func() bool { return false },
+			extras:      []interface{}{cancelled, checkers.Commentf("waiting")},
+			err:         "(?s).*\tpoll 1: bool value false\ncomment: waiting",
+		}, {
+			description: "not a function",
+			checker:     checkers.Eventually(checkers.Equals),
+			obtained:    1,
+			extras:      []interface{}{1},
+			err:         "Eventually checker expected a function taking no arguments and returning one value, obtained was type int",
+		}, {
+			description: "function with arguments",
+			checker:     checkers.Eventually(checkers.Equals),
+			obtained:    func(int) int { return 1 },
+			extras:      []interface{}{1},
+			err:         `Eventually checker expected a function taking no arguments and returning one value, obtained was type func\(int\) int`,
+		}, {
+			description: "nil function",
+			checker:     checkers.Eventually(checkers.Equals),
+			obtained:    (func() int)(nil),
+			extras:      []interface{}{1},
+			err:         "Eventually checker expected a function, obtained was nil",
+		}, {
+			description: "nil checker",
+			checker:     checkers.Eventually(nil),
+			obtained:    func() int { return 1 },
+			err:         "Eventually needs a checker to retry, got nil",
+		},
+	} {
+		err := test.checker.Check(test.obtained, test.extras...)
+		if err == nil {
+			if test.err != "" {
+				t.Errorf("%s: expected error: %q", test.description, test.err)
+			}
+		} else {
+			if test.err == "" {
+				t.Errorf("%s: unexpected error: %v", test.description, err)
+			} else {
+				if err := checkers.Matches.Check(err.Error(), test.err); err != nil {
+					t.Errorf("%s: error mismatch: %v", test.description, err)
+				}
+			}
+		}
+	}
+}
+
+func TestEventuallyWithTestContext(t *testing.T) {
+	ct := &checkers.Test{T: t}
+	ct.Check(countUp(), checkers.Eventually(checkers.Equals), 3, ct.ContextWithTimeout(5*time.Second))
+}